@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/abuse"
+
+	"github.com/spf13/cobra"
+)
+
+var abuseCmd = &cobra.Command{
+	Use:   "abuse <domain>",
+	Short: "Find where to report abuse of a domain and its hosting",
+	Long: `Determine the abuse contacts for a domain: the registrar's abuse
+contact from RDAP, the abuse mailbox of every hosting network (RDAP, falling
+back to RIR WHOIS), and the abuse.net entry for the domain.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAbuse,
+}
+
+func init() {
+	rootCmd.AddCommand(abuseCmd)
+}
+
+func runAbuse(cmd *cobra.Command, args []string) {
+	env := setup()
+	domainArg := mustDomainArg(env, args[0])
+
+	finder := abuse.NewFinder(env.resolver(), env.whoisClient(), env.rdapClient())
+	report := finder.Find(domainArg)
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+		return
+	}
+
+	f := env.formatter
+	f.PrintTitle(domainArg + " (abuse contacts)")
+
+	for _, role := range []string{"registrar", "hosting", "abuse.net"} {
+		var contacts []abuse.Contact
+		for _, c := range report.Contacts {
+			if c.Role == role {
+				contacts = append(contacts, c)
+			}
+		}
+		if len(contacts) == 0 {
+			continue
+		}
+		f.PrintSection(strings.ToUpper(role))
+		for _, c := range contacts {
+			value := c.Email
+			if value == "" {
+				value = c.Phone
+			} else if c.Phone != "" {
+				value += " / " + c.Phone
+			}
+			f.PrintArrowItemWithProviderAndASN(value, c.Name, c.For+" via "+c.Source)
+		}
+	}
+
+	if len(report.Contacts) == 0 {
+		f.PrintDim("No abuse contacts found")
+	}
+	for _, e := range report.Errors {
+		f.PrintError(e)
+	}
+	f.Finish()
+}
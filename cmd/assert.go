@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/output"
+	"github.com/auduny/dnscrawler/pkg/provider"
+	"github.com/auduny/dnscrawler/pkg/redact"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assertA           []string
+	assertAAAA        []string
+	assertCNAME       string
+	assertNSProviders []string
+	assertMX          []string
+	assertTXT         []string
+	assertExact       bool
+)
+
+var assertCmd = &cobra.Command{
+	Use:   "assert <domain>",
+	Short: "Verify that live DNS matches expected values",
+	Long: `Check a domain's live DNS against expected values and exit with status 1 on
+any mismatch, e.g. as a verification step after a deploy:
+
+  dnscrawler assert example.com --a 1.2.3.4 --ns-provider Cloudflare --mx-contains outlook.com
+
+Expected A and AAAA addresses must all be present; with --exact no other
+addresses may be returned.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAssert,
+}
+
+func init() {
+	assertCmd.Flags().StringSliceVar(&assertA, "a", nil, "Expected A addresses")
+	assertCmd.Flags().StringSliceVar(&assertAAAA, "aaaa", nil, "Expected AAAA addresses")
+	assertCmd.Flags().StringVar(&assertCNAME, "cname", "", "Expected CNAME target")
+	assertCmd.Flags().StringSliceVar(&assertNSProviders, "ns-provider", nil, "Allowed DNS providers; every nameserver must match one")
+	assertCmd.Flags().StringSliceVar(&assertMX, "mx-contains", nil, "Substrings that must appear in some MX record")
+	assertCmd.Flags().StringSliceVar(&assertTXT, "txt-contains", nil, "Substrings that must appear in some TXT record")
+	assertCmd.Flags().BoolVar(&assertExact, "exact", false, "Fail if A/AAAA records contain addresses that weren't expected")
+	rootCmd.AddCommand(assertCmd)
+}
+
+// assertion is the outcome of one expected value
+type assertion struct {
+	Name   string `json:"name"`
+	Pass   bool   `json:"pass"`
+	Detail string `json:"detail"`
+}
+
+func runAssert(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+	checkOutputFormat(env, true)
+	domainArg := mustDomainArg(env, args[0])
+
+	if len(assertA)+len(assertAAAA)+len(assertNSProviders)+len(assertMX)+len(assertTXT) == 0 && assertCNAME == "" {
+		env.fatal("nothing to assert: give at least one of --a, --aaaa, --cname, --ns-provider, --mx-contains, --txt-contains")
+	}
+	for _, addr := range slices.Concat(assertA, assertAAAA) {
+		if _, err := netip.ParseAddr(addr); err != nil {
+			env.fatal(fmt.Sprintf("invalid address %q", addr))
+		}
+	}
+
+	c := env.crawler(crawler.Options{NoWhois: true, NoTrace: true})
+	result, err := env.result(c, domainArg)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+
+	results := evaluateAssertions(result)
+	if redactOutput {
+		redact.Value(results, result)
+	}
+	passed := true
+	for _, a := range results {
+		passed = passed && a.Pass
+	}
+
+	switch outputFormat {
+	case "json":
+		printJSON(formatter, result.Domain, map[string]any{"domain": result.Domain, "pass": passed, "assertions": results})
+	case "junit":
+		suite := output.JUnitSuite{Name: result.Domain}
+		for _, a := range results {
+			failure := ""
+			if !a.Pass {
+				failure = a.Detail
+			}
+			suite.AddCase(a.Name, a.Detail, failure)
+		}
+		report := &output.JUnitSuites{Name: "dnscrawler assert"}
+		report.Add(suite)
+		report.Write(os.Stdout)
+	default:
+		formatter.PrintTitle(result.Domain)
+		for _, a := range results {
+			status := "fail"
+			if a.Pass {
+				status = "pass"
+			}
+			formatter.PrintCheck(status, a.Name, "", a.Detail)
+		}
+		formatter.Finish()
+	}
+
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func evaluateAssertions(result *crawler.Result) []assertion {
+	var out []assertion
+	if !result.Registered {
+		return []assertion{{Name: "registered", Detail: "domain does not exist"}}
+	}
+
+	records := result.Records
+	if records.Failed() {
+		return []assertion{{Name: "records", Detail: records.Error}}
+	}
+
+	if len(assertA) > 0 {
+		out = append(out, assertAddrs("A", assertA, records.A))
+	}
+	if len(assertAAAA) > 0 {
+		out = append(out, assertAddrs("AAAA", assertAAAA, records.AAAA))
+	}
+	if assertCNAME != "" {
+		want := domain.Canonical(assertCNAME)
+		got := values(records.CNAME)
+		out = append(out, assertion{
+			Name:   "CNAME",
+			Pass:   len(got) > 0 && domain.Canonical(got[0]) == want,
+			Detail: describe(got),
+		})
+	}
+	if len(assertNSProviders) > 0 {
+		out = append(out, assertNameservers(result))
+	}
+	for _, want := range assertMX {
+		out = append(out, assertContains("MX "+want, want, values(records.MX)))
+	}
+	for _, want := range assertTXT {
+		out = append(out, assertContains("TXT "+want, want, values(records.TXT)))
+	}
+	return out
+}
+
+// assertAddrs checks that every expected address is present and, with
+// --exact, that nothing else is. Addresses are compared parsed, as an IPv6
+// address has many spellings.
+func assertAddrs(name string, want []string, records []crawler.Record) assertion {
+	got := values(records)
+	present := make(map[netip.Addr]bool)
+	for _, v := range got {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			present[addr] = true
+		}
+	}
+
+	var missing []string
+	expected := make(map[netip.Addr]bool)
+	for _, w := range want {
+		addr := netip.MustParseAddr(w)
+		expected[addr] = true
+		if !present[addr] {
+			missing = append(missing, w)
+		}
+	}
+	var extra []string
+	if assertExact {
+		for addr := range present {
+			if !expected[addr] {
+				extra = append(extra, addr.String())
+			}
+		}
+		sort.Strings(extra)
+	}
+
+	a := assertion{Name: name, Pass: len(missing) == 0 && len(extra) == 0, Detail: describe(got)}
+	if len(missing) > 0 {
+		a.Detail += "; missing " + strings.Join(missing, ", ")
+	}
+	if len(extra) > 0 {
+		a.Detail += "; unexpected " + strings.Join(extra, ", ")
+	}
+	return a
+}
+
+func assertNameservers(result *crawler.Result) assertion {
+	zone := result
+	if result.Root != nil {
+		zone = result.Root
+	}
+	a := assertion{Name: "NS provider"}
+	ns := zone.Nameservers
+	if ns == nil || ns.Failed() || len(ns.Servers) == 0 {
+		a.Detail = "no nameservers found"
+		return a
+	}
+
+	a.Pass = true
+	var found []string
+	for _, s := range ns.Servers {
+		found = append(found, fmt.Sprintf("%s [%s]", s.Name, cmp.Or(s.Provider, "unknown")))
+		if !provider.Listed(assertNSProviders, s.Provider) {
+			a.Pass = false
+		}
+	}
+	a.Detail = strings.Join(found, ", ")
+	return a
+}
+
+func assertContains(name, want string, got []string) assertion {
+	a := assertion{Name: name, Detail: describe(got)}
+	for _, v := range got {
+		if strings.Contains(strings.ToLower(v), strings.ToLower(want)) {
+			a.Pass = true
+		}
+	}
+	return a
+}
+
+func values(records []crawler.Record) []string {
+	out := make([]string, len(records))
+	for i, r := range records {
+		out[i] = r.Value
+	}
+	sort.Strings(out)
+	return out
+}
+
+func describe(got []string) string {
+	if len(got) == 0 {
+		return "no records"
+	}
+	return "got " + strings.Join(got, ", ")
+}
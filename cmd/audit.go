@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/auduny/dnscrawler/pkg/output"
+	"github.com/auduny/dnscrawler/pkg/policy"
+	"github.com/auduny/dnscrawler/pkg/redact"
+
+	"github.com/spf13/cobra"
+)
+
+var policyPath string
+
+var auditCmd = &cobra.Command{
+	Use:   "audit <domain>...",
+	Short: "Check domains against a compliance policy",
+	Long: `Evaluate domains against the rules of a YAML policy file, e.g. allowed DNS
+providers, required DMARC policy, registrar or minimum number of NS providers.
+
+The command exits with status 1 when any rule fails.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&policyPath, "policy", "", "Policy file (required)")
+	auditCmd.MarkFlagRequired("policy")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+
+	checkOutputFormat(env, true)
+
+	pol, err := policy.Load(policyPath)
+	if err != nil {
+		env.fatal(fmt.Sprintf("policy: %v", err))
+	}
+
+	domains, err := readDomains(args)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	jsonLines = len(domains) > 1 || slices.Contains(args, "-")
+
+	c := env.crawler(pol.Options())
+
+	junit := &output.JUnitSuites{Name: "dnscrawler audit"}
+	failed := false
+	for _, domainArg := range domains {
+		result, err := env.result(c, domainArg)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		report := pol.Evaluate(result)
+		if redactOutput {
+			redact.Value(&report, result)
+		}
+		failed = failed || !report.Pass
+
+		switch outputFormat {
+		case "junit":
+			suite := output.JUnitSuite{Name: report.Domain}
+			for _, o := range report.Outcomes {
+				failure := ""
+				if !o.Pass {
+					failure = o.Detail
+				}
+				suite.AddCase(o.Rule, o.Detail, failure)
+			}
+			junit.Add(suite)
+			continue
+		case "json":
+			printJSON(formatter, report.Domain, report)
+			continue
+		}
+		printAuditReport(formatter, report)
+	}
+
+	switch outputFormat {
+	case "text":
+		formatter.Finish()
+	case "junit":
+		junit.Write(os.Stdout)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printAuditReport(formatter *output.Formatter, report *policy.Report) {
+	formatter.PrintTitle(report.Domain)
+	passed := 0
+	for _, o := range report.Outcomes {
+		status := "fail"
+		if o.Pass {
+			status = "pass"
+			passed++
+		}
+		detail := o.Detail
+		if o.Description != "" {
+			detail = o.Description + ": " + detail
+		}
+		formatter.PrintCheck(status, o.Rule, "", detail)
+	}
+	formatter.PrintDim(formatter.Sprintf("%d/%d rules passed", passed, len(report.Outcomes)))
+}
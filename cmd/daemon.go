@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+)
+
+// defaultSchedule is used for groups without a schedule
+const defaultSchedule = "@hourly"
+
+var runNow bool
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Monitor domain groups continuously on their schedules",
+	Long: `Run as a long-lived service: every group in the config file is checked on
+its cron schedule (default @hourly), snapshots are persisted, policies are
+evaluated and alerts are sent to the group's notifiers.
+
+Schedules are standard five-field cron expressions or descriptors such as
+@daily and "@every 30m". The daemon stops on SIGINT or SIGTERM after the
+running checks finish.
+
+When telemetry is configured (telemetry.endpoint in the config file or the
+OTEL_EXPORTER_OTLP_* environment variables), every group check, crawl and
+lookup is traced and lookup metrics are exported over OTLP.`,
+	Args: cobra.NoArgs,
+	Run:  runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&stateDir, "state", "", "Store state as files in this directory instead of the configured state backend")
+	daemonCmd.Flags().BoolVar(&runNow, "run-now", false, "Check every group once at startup")
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) {
+	env := setup()
+	if offline {
+		env.fatal("daemon needs the network and can't run with --offline; monitor --offline shows what its checks stored")
+	}
+	if len(env.cfg.Groups) == 0 {
+		env.fatal("no groups to monitor (define groups in the config file)")
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	tel := env.telemetry()
+	m := env.monitor(stateDir)
+
+	check := func(g config.Group) {
+		alerts, errs := m.CheckGroup(g)
+		for _, err := range errs {
+			logger.Printf("group %s: %v", g.Name, err)
+		}
+		logger.Printf("group %s: checked %d domains, %d alerts", g.Name, len(g.Domains), len(alerts))
+	}
+
+	// Overlapping runs of the same group are skipped rather than queued
+	scheduler := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.PrintfLogger(logger))))
+	for _, g := range env.cfg.Groups {
+		schedule := g.Schedule
+		if schedule == "" {
+			schedule = defaultSchedule
+		}
+		if _, err := scheduler.AddFunc(schedule, func() { check(g) }); err != nil {
+			env.fatal(fmt.Sprintf("group %s: invalid schedule %q: %v", g.Name, schedule, err))
+		}
+		logger.Printf("group %s: %d domains, schedule %s", g.Name, len(g.Domains), schedule)
+	}
+
+	if runNow {
+		for _, g := range env.cfg.Groups {
+			check(g)
+		}
+	}
+
+	scheduler.Start()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-stop
+	logger.Printf("received %s, waiting for running checks", sig)
+	<-scheduler.Stop().Done()
+	m.Close()
+	if err := tel.Close(); err != nil {
+		logger.Printf("telemetry: %v", err)
+	}
+}
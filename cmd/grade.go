@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/grade"
+	"github.com/auduny/dnscrawler/pkg/output"
+	"github.com/auduny/dnscrawler/pkg/redact"
+
+	"github.com/spf13/cobra"
+)
+
+var minGrade string
+
+var gradeCmd = &cobra.Command{
+	Use:   "grade <domain>...",
+	Short: "Grade a domain's DNS, email and TLS hygiene",
+	Long: `Run DNSSEC, SPF, DMARC, nameserver redundancy, expiry, TLS, key strength
+and CAA checks and roll them into a letter grade with a scorecard and the
+fixes worth the most points.
+
+With --min-grade the command exits non-zero when any domain grades lower,
+so it can gate a CI pipeline.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runGrade,
+}
+
+func init() {
+	gradeCmd.Flags().StringVar(&minGrade, "min-grade", "", "Exit with status 1 if any domain grades below this letter (A-F)")
+	rootCmd.AddCommand(gradeCmd)
+}
+
+func runGrade(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+
+	checkOutputFormat(env, true)
+
+	if minGrade != "" && grade.Rank(minGrade) == 0 {
+		env.fatal(fmt.Sprintf("unknown grade %q", minGrade))
+	}
+
+	domains, err := readDomains(args)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	jsonLines = len(domains) > 1 || slices.Contains(args, "-")
+
+	c := env.crawler(grade.Options())
+
+	junit := &output.JUnitSuites{Name: "dnscrawler grade"}
+	belowMin := false
+	for _, domainArg := range domains {
+		result, err := env.result(c, domainArg)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		card := grade.Evaluate(result)
+		if redactOutput {
+			redact.Value(&card, result)
+		}
+		below := minGrade != "" && grade.Rank(card.Grade) < grade.Rank(minGrade)
+		belowMin = belowMin || below
+
+		switch outputFormat {
+		case "junit":
+			junit.Add(scorecardSuite(card, below))
+			continue
+		case "json":
+			printJSON(formatter, card.Domain, card)
+			continue
+		}
+		printScorecard(formatter, card)
+	}
+
+	switch outputFormat {
+	case "text":
+		formatter.Finish()
+	case "junit":
+		junit.Write(os.Stdout)
+	}
+	if belowMin {
+		os.Exit(1)
+	}
+}
+
+// scorecardSuite turns a scorecard into a JUnit suite. Only failed checks are
+// failures; warnings pass so they don't break the build on their own.
+func scorecardSuite(card *grade.Scorecard, belowMin bool) output.JUnitSuite {
+	suite := output.JUnitSuite{Name: card.Domain}
+	for _, check := range card.Checks {
+		detail := check.Detail
+		if check.Fix != "" {
+			detail += ". " + check.Fix
+		}
+		failure := ""
+		if check.Status == grade.Fail {
+			failure = check.Detail
+		}
+		suite.AddCase(check.Name, detail, failure)
+	}
+	if minGrade != "" {
+		failure := ""
+		if belowMin {
+			failure = fmt.Sprintf("grade %s is below %s", card.Grade, strings.ToUpper(minGrade))
+		}
+		suite.AddCase("Grade", fmt.Sprintf("%s (%d/100)", card.Grade, card.Score), failure)
+	}
+	return suite
+}
+
+func printScorecard(formatter *output.Formatter, card *grade.Scorecard) {
+	formatter.PrintTitle(card.Domain)
+	formatter.PrintKeyValue("GRADE", fmt.Sprintf("%s (%d/100)", card.Grade, card.Score))
+
+	formatter.PrintSection("SCORECARD")
+	for _, check := range card.Checks {
+		formatter.PrintCheck(string(check.Status), check.Name, fmt.Sprintf("%d/%d", check.Points, check.Max), check.Detail)
+	}
+
+	if len(card.Remediation) > 0 {
+		formatter.PrintSection("TOP FIXES")
+		for i, fix := range card.Remediation {
+			formatter.PrintArrowItem(fmt.Sprintf("%d. %s", i+1, fix))
+		}
+	}
+}
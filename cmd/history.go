@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/intel"
+
+	"github.com/spf13/cobra"
+)
+
+var historyTypes []string
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show historical data for a domain",
+}
+
+var historyDNSCmd = &cobra.Command{
+	Use:   "dns <domain>",
+	Short: "Show what a domain has resolved to historically (passive DNS)",
+	Long: `Query the configured passive DNS providers for the IPs, nameservers and
+mail servers a domain has historically resolved to.
+
+Providers need API keys in the config file (api_keys.securitytrails,
+api_keys.circl as "user:password").`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHistoryDNS,
+}
+
+func init() {
+	historyDNSCmd.Flags().StringSliceVarP(&historyTypes, "type", "t", []string{"A", "AAAA", "NS", "MX"}, "Record types to query")
+	historyCmd.AddCommand(historyDNSCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistoryDNS(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+	domainArg := mustDomainArg(env, args[0])
+
+	client := env.intelClient()
+	cfg := env.cfg
+
+	var sources []intel.PassiveDNS
+	if len(cfg.PassiveDNS.Sources) > 0 {
+		for _, name := range cfg.PassiveDNS.Sources {
+			src, err := client.NewPassiveDNS(name)
+			if err != nil {
+				formatter.PrintError(err.Error())
+				continue
+			}
+			sources = append(sources, src)
+		}
+	} else {
+		sources = client.PassiveDNSSources()
+	}
+	if len(sources) == 0 {
+		env.fatal("no passive DNS source configured (set api_keys.securitytrails or api_keys.circl)")
+	}
+
+	var records []intel.PassiveRecord
+	var failures []string
+	for _, src := range sources {
+		for _, t := range historyTypes {
+			found, err := src.History(domainArg, strings.ToUpper(t))
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s %s: %v", src.Name(), strings.ToUpper(t), err))
+				continue
+			}
+			records = append(records, found...)
+		}
+	}
+	intel.SortPassiveRecords(records)
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			Domain  string                `json:"domain"`
+			Records []intel.PassiveRecord `json:"records"`
+			Errors  []string              `json:"errors,omitempty"`
+		}{domainArg, records, failures})
+		return
+	}
+
+	formatter.PrintTitle(domainArg + " (DNS history)")
+	for _, f := range failures {
+		formatter.PrintError(f)
+	}
+
+	lastType := ""
+	for _, rec := range records {
+		if rec.Type != lastType {
+			formatter.PrintSection(rec.Type)
+			lastType = rec.Type
+		}
+		formatter.PrintRecordWithProviderAndASN("", rec.Value, rec.Source,
+			fmt.Sprintf("%s → %s", formatDay(rec.FirstSeen), formatDay(rec.LastSeen)))
+	}
+	if len(records) == 0 {
+		formatter.PrintDim("No historical records found")
+	}
+	formatter.Finish()
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/export"
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/output"
+	"github.com/auduny/dnscrawler/pkg/redact"
+	"github.com/auduny/dnscrawler/pkg/state"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stateDir       string
+	monitorGroup   string
+	monitorExports bool
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Check monitored domain groups once and send alerts",
+	Long: `Crawl every domain in the groups defined in the config file, compare each
+with its previous snapshot and send alerts for changes, upcoming expiry and
+failing health checks to the group's notifiers.
+
+Run it from cron, or see the daemon command for built-in scheduling.
+
+With --offline nothing is crawled: the alerts stored by the previous checks
+are shown instead or, with --exports, the documents the exporters were sent.`,
+	Args: cobra.NoArgs,
+	Run:  runMonitor,
+}
+
+func init() {
+	monitorCmd.Flags().StringVar(&stateDir, "state", "", "Store state as files in this directory instead of the configured state backend")
+	monitorCmd.Flags().StringVar(&monitorGroup, "group", "", "Only check this group")
+	monitorCmd.Flags().BoolVar(&monitorExports, "exports", false, "With --offline, print the stored results as the message queue exporters publish them, one JSON object per line")
+	rootCmd.AddCommand(monitorCmd)
+}
+
+func runMonitor(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+
+	var groups []config.Group
+	for _, g := range env.cfg.Groups {
+		if monitorGroup == "" || g.Name == monitorGroup {
+			groups = append(groups, g)
+		}
+	}
+	if len(groups) == 0 {
+		env.fatal("no groups to monitor (define groups in the config file)")
+	}
+
+	if monitorExports && !offline {
+		env.fatal("--exports only works with --offline")
+	}
+	if offline {
+		showStored(env, groups)
+		return
+	}
+
+	tel := env.telemetry()
+	defer tel.Close()
+	m := env.monitor(stateDir)
+	defer m.Close()
+	var all []notify.Alert
+	failed := false
+	for _, g := range groups {
+		alerts, errs := m.CheckGroup(g)
+		all = append(all, alerts...)
+		for _, err := range errs {
+			formatter.PrintError(err.Error())
+			failed = true
+		}
+	}
+
+	printMonitorAlerts(formatter, all)
+	if failed {
+		m.Close()
+		tel.Close()
+		os.Exit(1)
+	}
+}
+
+// showStored renders what the last checks of the groups stored, for
+// --offline: the alerts in each domain's history, or with --exports the
+// documents of its snapshot
+func showStored(env *environment, groups []config.Group) {
+	store := env.stateStore(stateDir)
+	defer store.Close()
+
+	var all []notify.Alert
+	failed := false
+	for _, g := range groups {
+		for _, name := range g.Domains {
+			result, err := monitor.LoadSnapshot(store, name)
+			if err != nil {
+				env.formatter.PrintError(env.formatter.Sprintf("%s: %v", name, err))
+				failed = true
+				continue
+			}
+			if monitorExports {
+				if err := printExport(store, g.Name, name, result); err != nil {
+					env.formatter.PrintError(env.formatter.Sprintf("%s: %v", name, err))
+					failed = true
+				}
+				continue
+			}
+			history, err := monitor.LoadHistory(store, name)
+			if err != nil {
+				env.formatter.PrintError(env.formatter.Sprintf("%s: %v", name, err))
+				failed = true
+				continue
+			}
+			if redactOutput {
+				redact.Value(history, result)
+			}
+			all = append(all, history...)
+		}
+	}
+
+	if !monitorExports {
+		printMonitorAlerts(env.formatter, all)
+	}
+	if failed {
+		store.Close()
+		os.Exit(1)
+	}
+}
+
+// printExport prints the stored snapshot of a domain as the message queue
+// exporters publish it
+func printExport(store state.Store, group, name string, result *crawler.Result) error {
+	if result == nil {
+		return fmt.Errorf("no stored result (results are stored by monitor)")
+	}
+	checked, err := monitor.SnapshotTime(store, name)
+	if err != nil {
+		return err
+	}
+	doc := export.Document{Time: checked, Group: group, Result: result, Problems: monitor.Health(result)}
+	if redactOutput {
+		redact.Value(&doc.Problems, result)
+		redact.Result(result)
+	}
+	line, err := export.JSON(doc)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(line))
+	return nil
+}
+
+// printMonitorAlerts prints the alerts of monitor in the output format
+func printMonitorAlerts(formatter *output.Formatter, all []notify.Alert) {
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(all)
+	} else {
+		printAlerts(formatter, all)
+	}
+}
+
+func printAlerts(formatter *output.Formatter, alerts []notify.Alert) {
+	if len(alerts) == 0 {
+		formatter.PrintDim("No alerts")
+		return
+	}
+	for _, a := range alerts {
+		msg := fmt.Sprintf("[%s] %s: %s", strings.ToUpper(a.Severity), a.Domain, a.Title)
+		if a.Severity == notify.Critical {
+			formatter.PrintError(msg)
+		} else {
+			formatter.PrintWarning(msg)
+		}
+		for _, d := range a.Details {
+			formatter.PrintDim(formatter.Sprintf("    %s", d))
+		}
+	}
+}
@@ -1,32 +1,123 @@
 package cmd
 
 import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/auduny/dnscrawler/pkg/batch"
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
 	"github.com/auduny/dnscrawler/pkg/dns"
 	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/filter"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/i18n"
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/notify"
 	"github.com/auduny/dnscrawler/pkg/output"
-	"github.com/auduny/dnscrawler/pkg/provider"
+	"github.com/auduny/dnscrawler/pkg/query"
+	"github.com/auduny/dnscrawler/pkg/redact"
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
 	"github.com/auduny/dnscrawler/pkg/whois"
 
 	"github.com/spf13/cobra"
 )
 
 var (
+	configPath       string
 	noWhois          bool
 	noTrace          bool
+	noASN            bool
+	noPTR            bool
+	onlySections     []string
+	skipSections     []string
+	summary          bool
+	oneline          bool
+	redactOutput     bool
+	sortOrder        string
+	timings          bool
+	probeTLS         bool
+	queryOCSP        bool
+	scanTLS          bool
+	jarm             bool
+	probeWeb         bool
+	checkIPv6        bool
+	compareWWW       bool
+	walkDeps         bool
+	checkSOA         bool
+	checkRecursion   bool
+	checkConsistency bool
+	checkNXDomain    bool
+	checkParking     bool
+	reverseIP        bool
+	threatIntel      bool
+	exposure         bool
+	reputation       bool
+	orgLookup        bool
+	blocklists       bool
+	checkDNSSEC      bool
+	checkCAA         bool
+	newDomainDays    int
+	verbose          bool
+	outputFormat     string
+	lang             string
+	filterExpr       string
+	queryExpr        string
 	providerPatterns []string
+	recordDir        string
+	replayDir        string
+	offline          bool
+	dryRun           bool
+	maxQPS           float64
+	workers          int
+	retries          int
+	targetRates      map[string]string
+	dnsTransport     string
+	resolvers        []string
+	resolverStrategy string
+	proxyURL         string
+	sourceIP         string
+	sourceInterface  string
+	whoisConns       int
+	dnsTimeout       time.Duration
+	whoisTimeout     time.Duration
+	traceTimeout     time.Duration
+	maxTime          time.Duration
+	failCertExpiry   string
+	raw              bool
+	recordTypes      []string
+	recordServer     string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "dnscrawler <domain>",
+	Use:   "dnscrawler <domain>... [@server]",
 	Short: "Get condensed DNS and WHOIS information for a domain",
 	Long: `dnscrawler provides a quick overview of DNS and WHOIS information
 for any domain, including authoritative nameservers, DNS trace,
-key records, and registration details.`,
-	Args: cobra.ExactArgs(1),
+key records, and registration details.
+
+Several domains may be given; "-" reads domains from stdin, one per line.
+Like dig, @server asks that authoritative server for the records instead of
+the recursive resolver.
+With --workers, domains are crawled concurrently and printed as they finish.
+Lookups are rate limited per target (--target-rate), and domains whose crawl
+hit a transient error such as a timeout are crawled again (--retries). On
+SIGINT, running crawls finish and their results are printed; press Ctrl-C
+again to quit at once.`,
+	Args: cobra.MinimumNArgs(1),
 	Run:  runCrawler,
 }
 
@@ -38,129 +129,1322 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default "+config.DefaultPath()+")")
+	rootCmd.Flags().StringSliceVar(&onlySections, "only", nil, "Only crawl these sections (e.g. whois,records); see --skip for the sections")
+	rootCmd.Flags().StringSliceVar(&skipSections, "skip", nil, "Skip these sections (e.g. trace,asn): "+strings.Join(crawler.Sections, ", "))
 	rootCmd.Flags().BoolVar(&noWhois, "no-whois", false, "Skip WHOIS lookup")
 	rootCmd.Flags().BoolVar(&noTrace, "no-trace", false, "Skip DNS trace")
+	rootCmd.Flags().BoolVar(&noASN, "no-asn", false, "Skip ASN lookups of nameserver and record IPs")
+	rootCmd.Flags().BoolVar(&noPTR, "no-ptr", false, "Skip reverse DNS of A/AAAA records")
+	for _, section := range []string{"whois", "trace", "asn", "ptr"} {
+		rootCmd.Flags().MarkDeprecated("no-"+section, "use --skip "+section)
+	}
+	rootCmd.Flags().BoolVar(&summary, "summary", false, "Print one line per domain; skips the trace, ASN and PTR lookups")
+	rootCmd.Flags().BoolVar(&oneline, "oneline", false, "Print domain, registrar, expiry, DNS, mail and hosting providers tab-separated, one line per domain; skips the trace")
+	rootCmd.Flags().BoolVar(&probeTLS, "tls", false, "Probe the HTTPS certificate")
+	rootCmd.Flags().BoolVar(&probeWeb, "web", false, "Fetch the website of the domain and its www name, following redirects")
+	rootCmd.Flags().BoolVar(&queryOCSP, "ocsp", false, "Ask the CA's OCSP responder whether the certificate is revoked; implies --tls")
+	rootCmd.Flags().BoolVar(&scanTLS, "tls-scan", false, "Test which TLS versions and ciphers each address accepts; implies --tls")
+	rootCmd.Flags().BoolVar(&jarm, "jarm", false, "Compute the JARM fingerprint of the HTTPS endpoints; implies --tls")
+	rootCmd.Flags().StringVar(&failCertExpiry, "fail-if-cert-expires-within", "",
+		"Exit 1 when a certificate of any endpoint expires within this time (e.g. 14d), or at the config's cert_expiry warning or critical level; implies --tls")
+	rootCmd.Flags().BoolVar(&checkIPv6, "ipv6", false, "Check that the AAAA addresses accept connections on ports 443 and 80")
+	rootCmd.Flags().BoolVar(&compareWWW, "www", false, "Compare the domain with its www name: hosting, redirects and certificates")
+	rootCmd.Flags().BoolVar(&walkDeps, "deps", false, "Analyze which external zones resolution depends on")
+	rootCmd.Flags().BoolVar(&checkDNSSEC, "dnssec", false, "Check DNSSEC signing and validation")
+	rootCmd.Flags().BoolVar(&checkCAA, "caa", false, "Look up CAA records")
+	rootCmd.Flags().BoolVar(&checkSOA, "soa", false, "Compare SOA serials across all authoritative servers")
+	rootCmd.Flags().BoolVar(&checkRecursion, "open-recursion", false, "Test authoritative servers for open recursion")
+	rootCmd.Flags().BoolVar(&checkConsistency, "consistency", false, "Compare A/AAAA/MX/TXT answers across authoritative servers")
+	rootCmd.Flags().BoolVar(&checkNXDomain, "nxdomain", false, "Check that nonexistent names return NXDOMAIN with a sane negative TTL")
+	rootCmd.Flags().BoolVar(&checkParking, "parking", false, "Flag parked and for-sale domains from their nameservers, addresses and landing page")
+	rootCmd.Flags().BoolVar(&reverseIP, "reverse-ip", false, "List other domains hosted on the same IPs")
+	rootCmd.Flags().BoolVar(&threatIntel, "intel", false, "Add threat intel from SecurityTrails/VirusTotal (needs API keys)")
+	rootCmd.Flags().IntVar(&newDomainDays, "new-domain-days", crawler.DefaultNewDomainDays, "Flag domains registered fewer than this many days ago")
+	rootCmd.Flags().BoolVar(&reputation, "reputation", false, "Check Google Safe Browsing and PhishTank for known-malicious listings")
+	rootCmd.Flags().BoolVar(&orgLookup, "org-lookup", false, "Look up the registrant's organization number in the business registry (.no, .dk, .fi)")
+	rootCmd.Flags().BoolVar(&blocklists, "blocklists", false, "Check the domain against Spamhaus DBL, SURBL and URIBL")
+	rootCmd.Flags().BoolVar(&exposure, "exposure", false, "Look up open ports and services of resolved IPs (Shodan/Censys)")
+	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Log every lookup to stderr")
+	rootCmd.Flags().StringSliceVarP(&recordTypes, "types", "t", nil,
+		"Record types to look up (default A, AAAA, MX, TXT and CNAME, or A, AAAA, MX, NS and TXT with --raw)")
+	rootCmd.Flags().StringVar(&recordServer, "server", "",
+		"Ask this authoritative server (host[:port]) for the records, without recursion; also given as @server like dig")
+	rootCmd.Flags().StringVar(&sortOrder, "sort", crawler.SortName, "Order of records and nameservers: "+strings.Join(crawler.Sorts, ", "))
+	rootCmd.Flags().BoolVar(&raw, "raw", false, "Only print the records of --types in zone-file format, like dig")
+	rootCmd.Flags().BoolVar(&timings, "timings", false, "Print the time spent per lookup kind and the slowest lookups to stderr")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "Language of the text output: "+strings.Join(i18n.Codes(), ", ")+" (default from LC_ALL, LC_MESSAGES or LANG)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, or junit (grade, audit and assert only)")
+	rootCmd.Flags().StringVar(&queryExpr, "query", "", "Only print the values this jq query selects from each result (e.g. '.records.mx[].value')")
+	rootCmd.PersistentFlags().BoolVar(&redactOutput, "redact", false, "Mask registrant names, organizations and contacts, internal IP addresses and TXT verification tokens")
+	rootCmd.Flags().StringVar(&filterExpr, "filter", "", "Only print domains matching this expression (e.g. 'whois.days_to_expiry < 60')")
 	rootCmd.Flags().StringArrayVarP(&providerPatterns, "provider", "p", nil,
 		"Custom provider pattern in format 'regex:name' (e.g., '\\.mycompany\\.com$:My Company')")
+	rootCmd.Flags().IntVarP(&workers, "workers", "w", 1, "Number of domains crawled concurrently")
+	rootCmd.Flags().IntVar(&retries, "retries", 0, "Crawl a domain again up to this many times after a transient error")
+	rootCmd.Flags().StringToStringVar(&targetRates, "target-rate", map[string]string{"whois": "1"},
+		"Lookups per second per target: whois (per TLD), dns, authoritative (per nameserver), blocklist, api")
+	rootCmd.PersistentFlags().StringVar(&dnsTransport, "dns-transport", "", "How to query the recursive resolver: udp, tcp or tls (default udp, tcp with --proxy)")
+	rootCmd.PersistentFlags().StringSliceVar(&resolvers, "resolver", nil,
+		"Recursive resolvers to query instead of 8.8.8.8, as host[:port][#tls-name]; repeat or separate with commas for a pool")
+	rootCmd.PersistentFlags().StringVar(&resolverStrategy, "resolver-strategy", "",
+		"How queries pick a resolver of the pool: "+strings.Join(dns.Strategies, ", ")+" (default round-robin)")
+	rootCmd.PersistentFlags().Float64Var(&maxQPS, "max-qps", 0, "Most DNS, WHOIS, HTTP queries and TLS handshakes per second across all lookups and workers (0 for no limit)")
+	rootCmd.PersistentFlags().StringVar(&sourceIP, "source-ip", "", "Send DNS, WHOIS, HTTP and TLS traffic from this local address")
+	rootCmd.PersistentFlags().StringVar(&sourceInterface, "interface", "", "Send DNS, WHOIS, HTTP and TLS traffic from the addresses of this network interface")
+	rootCmd.MarkFlagsMutuallyExclusive("source-ip", "interface")
+	rootCmd.PersistentFlags().StringVar(&proxyURL, "proxy", "", "Send WHOIS, HTTP, TLS and DNS-over-TCP/TLS traffic through this proxy (socks5://, socks5h:// or http://host:port)")
+	rootCmd.PersistentFlags().IntVar(&whoisConns, "whois-conns", whois.DefaultMaxConns, "Maximum concurrent connections to one WHOIS server")
+	rootCmd.PersistentFlags().DurationVar(&dnsTimeout, "dns-timeout", dns.DefaultTimeout, "Timeout of a single DNS query")
+	rootCmd.PersistentFlags().DurationVar(&whoisTimeout, "whois-timeout", whois.DefaultTimeout, "Timeout of a WHOIS query, connecting and reading")
+	rootCmd.PersistentFlags().DurationVar(&traceTimeout, "trace-timeout", dns.DefaultTraceTimeout, "Total time allowed for the DNS trace (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&maxTime, "max-time", 0, "Deadline for crawling one domain; lookups due later are skipped (0 for no limit)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record all DNS/WHOIS/HTTP responses into this directory")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Replay DNS/WHOIS/HTTP responses from this directory instead of the network")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Never touch the network: show the results stored by monitor, or crawl the --replay recordings")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the DNS queries, WHOIS servers and HTTP and TLS probes a crawl would perform, without performing them")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "offline")
+	rootCmd.MarkFlagsMutuallyExclusive("dry-run", "offline")
+	rootCmd.MarkFlagsMutuallyExclusive("summary", "oneline")
 }
 
 func runCrawler(cmd *cobra.Command, args []string) {
-	domainArg := strings.ToLower(strings.TrimSpace(args[0]))
-	// Remove protocol if present
-	domainArg = strings.TrimPrefix(domainArg, "http://")
-	domainArg = strings.TrimPrefix(domainArg, "https://")
-	// Remove path if present
-	if idx := strings.Index(domainArg, "/"); idx > 0 {
-		domainArg = domainArg[:idx]
+	env := setup()
+	formatter := env.formatter
+
+	checkOutputFormat(env, false)
+
+	var resultFilter *filter.Filter
+	if filterExpr != "" {
+		f, err := filter.Compile(filterExpr)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		resultFilter = f
+	}
+	var resultQuery *query.Query
+	if queryExpr != "" {
+		q, err := query.Compile(queryExpr)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		resultQuery = q
+	}
+
+	args, err := serverArg(args)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	domains, err := readDomains(args)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	jsonLines = len(domains) > 1 || slices.Contains(args, "-")
+	if err := dns.CheckRecordTypes(recordTypes); err != nil {
+		env.fatal(err.Error())
 	}
+	if err := crawler.CheckSort(sortOrder); err != nil {
+		env.fatal(err.Error())
+	}
+	if err := crawler.CheckSections(slices.Concat(onlySections, skipSections)); err != nil {
+		env.fatal(err.Error())
+	}
+	if raw {
+		types := recordTypes
+		if len(types) == 0 {
+			types = rawTypes
+		}
+		if dryRun {
+			for _, name := range domains {
+				plan := &crawler.Plan{Domain: domain.Canonical(name)}
+				for _, t := range types {
+					plan.Steps = append(plan.Steps, crawler.Step{Domain: plan.Domain, Section: crawler.SectionRecords,
+						Kind: "dns", Target: plan.Domain + " " + strings.ToUpper(t), Server: cmp.Or(recordServer, env.resolver().Upstream())})
+				}
+				printPlan(formatter, plan)
+			}
+			return
+		}
+		if !printRaw(env.resolver(), domains, types) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	var expiry *certExpiryCheck
+	if failCertExpiry != "" {
+		expiry, err = parseCertExpiry(failCertExpiry, monitor.DefaultAlerts.Merge(env.cfg.Alerts).CertExpiry)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		probeTLS = true
+	}
+
+	c := env.crawler(crawler.Options{
+		NoWhois:     noWhois,
+		NoTrace:     noTrace || summary || oneline,
+		NoASN:       noASN || summary,
+		NoPTR:       noPTR || summary,
+		TLS:         probeTLS || queryOCSP || scanTLS || jarm,
+		OCSP:        queryOCSP,
+		TLSScan:     scanTLS,
+		JARM:        jarm,
+		Web:         probeWeb,
+		IPv6:        checkIPv6,
+		WWW:         compareWWW,
+		Deps:        walkDeps,
+		SOA:         checkSOA,
+		Recursion:   checkRecursion,
+		Consistency: checkConsistency,
+		NXDomain:    checkNXDomain,
+		Parking:     checkParking,
+		ReverseIP:   reverseIP,
+		ThreatIntel: threatIntel,
+		Exposure:    exposure,
+		Reputation:  reputation,
+		Blocklists:  blocklists,
+		DNSSEC:      checkDNSSEC,
+		CAA:         checkCAA,
+		OrgLookup:   orgLookup,
+
+		NewDomainDays: newDomainDays,
+		RecordTypes:   recordTypes,
+		Sort:          sortOrder,
+		Only:          onlySections,
+		Skip:          skipSections,
+	})
 
-	formatter := output.New()
-	resolver := dns.NewResolver()
-	whoisClient := whois.NewClient()
+	intelClient := env.intelClient()
+	if c.Options.Runs(crawler.SectionReverseIP) {
+		source, err := intelClient.NewReverseIPSource(env.cfg.ReverseIP.Source)
+		if err != nil {
+			env.fatal(fmt.Sprintf("reverse IP: %v", err))
+		}
+		c.ReverseIP = source
+	}
+	if c.Options.Runs(crawler.SectionExposure) {
+		source, err := intelClient.NewExposureSource(env.cfg.Exposure.Source)
+		if err != nil {
+			env.fatal(fmt.Sprintf("exposure: %v", err))
+		}
+		c.Exposure = source
+	}
 
 	// Setup provider matchers
-	providerMatcher := provider.NewMatcher()
 	if len(providerPatterns) > 0 {
-		errs := providerMatcher.AddPatterns(providerPatterns)
+		errs := c.Providers.AddPatterns(providerPatterns)
 		for _, err := range errs {
-			formatter.PrintError(fmt.Sprintf("invalid pattern: %v", err))
+			formatter.PrintError(formatter.Sprintf("invalid pattern: %v", err))
 		}
 	}
-	infraMatcher := provider.NewInfraMatcher()
-	mailMatcher := provider.NewMailMatcher()
 
-	// Check if this is a subdomain
-	rootDomain := domain.GetRootDomain(domainArg)
-	isSubdomain := domain.IsSubdomain(domainArg)
+	if verbose {
+		c.Use(crawler.Hooks{
+			OnResult: func(ev crawler.Event) {
+				if ev.Cached {
+					fmt.Fprintf(os.Stderr, "%-12s %s (cached)\n", ev.Kind, ev.Target)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "%-12s %s (%s)\n", ev.Kind, ev.Target, ev.Duration.Round(time.Millisecond))
+			},
+			OnError: func(ev crawler.Event) {
+				fmt.Fprintf(os.Stderr, "%-12s %s failed: %v\n", ev.Kind, ev.Target, ev.Err)
+			},
+			OnProgress: func(ev crawler.Event) {
+				if step, ok := ev.Data.(dns.TraceStep); ok {
+					fmt.Fprintf(os.Stderr, "%-12s %s → %s\n", ev.Kind, step.Zone, step.Server)
+				}
+			},
+		})
+	} else if outputFormat == "text" {
+		// Show what is being looked up, so slow servers don't look like a hang
+		c.Use(crawler.Hooks{
+			OnQuery: func(ev crawler.Event) {
+				formatter.PrintStatus(fmt.Sprintf("%s: %s %s", ev.Domain, ev.Kind, ev.Target))
+			},
+			OnProgress: func(ev crawler.Event) {
+				if step, ok := ev.Data.(dns.TraceStep); ok {
+					formatter.PrintStatus(fmt.Sprintf("%s: trace %s → %s", ev.Domain, step.Zone, step.Server))
+				}
+			},
+		})
+	}
+
+	if dryRun {
+		for _, name := range domains {
+			printPlan(formatter, c.Plan(name))
+		}
+		return
+	}
+
+	var lookupTimings *crawler.Timings
+	if timings {
+		lookupTimings = &crawler.Timings{}
+		c.Use(lookupTimings.Hooks())
+	}
+
+	limits, err := parseTargetRates(targetRates)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	engine := &batch.Engine{Crawler: c, Workers: workers, Limits: limits, Retries: retries}
+
+	// The first signal stops new crawls; after it the default handling applies again
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	start := time.Now()
+	handle := func(result *crawler.Result) {
+		if expiry != nil {
+			formatter.Exclusive(func() {
+				expiry.check(result)
+			})
+		}
+		if resultFilter != nil {
+			match, err := resultFilter.Match(result)
+			if err != nil {
+				formatter.Exclusive(func() {
+					fmt.Fprintf(os.Stderr, "%s: filter: %v\n", result.Domain, err)
+				})
+				return
+			}
+			if !match {
+				return
+			}
+		}
+		if redactOutput {
+			redact.Result(result)
+		}
+		formatter.Exclusive(func() {
+			if resultQuery != nil {
+				printQuery(resultQuery, result)
+				return
+			}
+			printResult(formatter, result)
+		})
+	}
+	var stats batch.Stats
+	missing := 0
+	if offline && replayDir == "" {
+		for _, name := range domains {
+			result, err := env.result(c, name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				missing++
+				continue
+			}
+			handle(result)
+		}
+	} else {
+		stats = engine.Run(ctx, domains, handle)
+	}
+
+	formatter.Exclusive(func() {
+		if outputFormat == "text" && !summary && !oneline && resultQuery == nil {
+			formatter.Finish()
+		}
+	})
+	if lookupTimings != nil {
+		printTimings(lookupTimings, time.Since(start))
+	}
+	if stats.Failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d domains still had transient errors after %d retries\n", stats.Failed, stats.Crawled, retries)
+	}
+	if stats.Skipped > 0 {
+		fmt.Fprintf(os.Stderr, "interrupted: %d of %d domains not crawled\n", stats.Skipped, len(domains))
+		os.Exit(130)
+	}
+	if expiry != nil && expiry.report() || missing > 0 {
+		os.Exit(1)
+	}
+}
+
+// printTimings writes the time spent per lookup kind and the slowest lookups
+// to stderr. Lookups of concurrent crawls overlap, so the totals may add up
+// to more than the wall time.
+func printTimings(t *crawler.Timings, wall time.Duration) {
+	fmt.Fprintf(os.Stderr, "\nTIMINGS (wall time %s)\n", wall.Round(time.Millisecond))
+	fmt.Fprintf(os.Stderr, "%-12s %6s %6s %6s %10s %10s %10s\n", "KIND", "COUNT", "ERRORS", "CACHED", "TOTAL", "AVG", "MAX")
+	for _, k := range t.Kinds() {
+		var avg time.Duration
+		if k.Count > 0 {
+			avg = k.Total / time.Duration(k.Count)
+		}
+		fmt.Fprintf(os.Stderr, "%-12s %6d %6d %6d %10s %10s %10s\n", k.Kind, k.Count, k.Errors, k.Cached,
+			roundDuration(k.Total), roundDuration(avg), roundDuration(k.Max))
+	}
+
+	if slowest := t.Slowest(); len(slowest) > 0 {
+		fmt.Fprintln(os.Stderr, "\nSLOWEST LOOKUPS")
+		for _, ev := range slowest {
+			fmt.Fprintf(os.Stderr, "%10s  %-12s %s (%s)\n", roundDuration(ev.Duration), ev.Kind, ev.Target, ev.Domain)
+		}
+	}
+}
+
+// roundDuration keeps three significant digits of sub-second durations
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
+
+// parseTargetRates converts --target-rate values to lookups per second
+func parseTargetRates(rates map[string]string) (map[string]float64, error) {
+	limits := make(map[string]float64, len(rates))
+	for class, value := range rates {
+		switch class {
+		case "whois", "dns", "authoritative", "blocklist", "api":
+		default:
+			return nil, fmt.Errorf("--target-rate: unknown target %q", class)
+		}
+		qps, err := strconv.ParseFloat(value, 64)
+		if err != nil || qps < 0 {
+			return nil, fmt.Errorf("--target-rate: invalid rate %q for %s", value, class)
+		}
+		limits[class] = qps
+	}
+	return limits, nil
+}
+
+// certExpiryCheck collects the domains failing --fail-if-cert-expires-within
+type certExpiryCheck struct {
+	threshold config.Threshold
+	// min is the severity from which a domain fails
+	min      string
+	failures []string
+}
+
+// parseCertExpiry reads --fail-if-cert-expires-within: days ("14d"), a
+// duration ("336h"), or warning or critical to fail at that level of the
+// cert_expiry alert settings
+func parseCertExpiry(value string, alerts config.Threshold) (*certExpiryCheck, error) {
+	switch value {
+	case notify.Warning, notify.Critical:
+		return &certExpiryCheck{threshold: alerts, min: value}, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+	if err != nil {
+		d, durationErr := time.ParseDuration(value)
+		if durationErr != nil {
+			return nil, fmt.Errorf("--fail-if-cert-expires-within: invalid value %q, want days (14d), a duration or warning/critical", value)
+		}
+		days = int(math.Ceil(d.Hours() / 24))
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("--fail-if-cert-expires-within: %q is not in the future", value)
+	}
+	return &certExpiryCheck{threshold: config.Threshold{Critical: days}, min: notify.Critical}, nil
+}
+
+func (c *certExpiryCheck) check(result *crawler.Result) {
+	level, days, ok := monitor.CertExpiry(result, c.threshold)
+	if !ok || !notify.AtLeast(level, c.min) {
+		return
+	}
+	where := result.Domain
+	if _, ip, _ := result.TLS.SoonestExpiry(); ip != "" {
+		where += " (" + ip + ")"
+	}
+	if days < 0 {
+		c.failures = append(c.failures, fmt.Sprintf("%s: certificate expired %d days ago", where, -days))
+		return
+	}
+	c.failures = append(c.failures, fmt.Sprintf("%s: certificate expires in %d days", where, days))
+}
+
+// report writes the failures to stderr, returning whether there were any
+func (c *certExpiryCheck) report() bool {
+	for _, failure := range c.failures {
+		fmt.Fprintln(os.Stderr, failure)
+	}
+	return len(c.failures) > 0
+}
+
+// printResult renders a crawl result in the selected output format
+func printResult(formatter *output.Formatter, result *crawler.Result) {
+	if outputFormat == "json" {
+		printJSON(formatter, result.Domain, result)
+		return
+	}
+	if summary {
+		printSummary(formatter, result)
+		return
+	}
+	if oneline {
+		printOneline(result)
+		return
+	}
 
 	// If subdomain, first show root domain info
-	if isSubdomain {
-		printDomainInfo(formatter, resolver, whoisClient, providerMatcher, infraMatcher, mailMatcher, rootDomain, true)
+	if result.Root != nil {
+		printDomainInfo(formatter, result.Root, true)
 	}
 
 	// Show info for the requested domain
-	printDomainInfo(formatter, resolver, whoisClient, providerMatcher, infraMatcher, mailMatcher, domainArg, false)
+	printDomainInfo(formatter, result, false)
+}
 
-	formatter.Finish()
+// jsonLines makes -o json print each result as one compact line (NDJSON)
+// instead of an indented object, for runs over a list of domains
+var jsonLines bool
+
+// printJSON writes the -o json output of a domain, exiting when stdout
+// fails
+func printJSON(formatter *output.Formatter, domain string, v any) {
+	enc := json.NewEncoder(os.Stdout)
+	if !jsonLines {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		formatter.PrintError(formatter.Sprintf("%s: %v", domain, err))
+		os.Exit(1)
+	}
 }
 
-func printDomainInfo(formatter *output.Formatter, resolver *dns.Resolver, whoisClient *whois.Client, providerMatcher *provider.Matcher, infraMatcher *provider.Matcher, mailMatcher *provider.Matcher, domainName string, isRootContext bool) {
+// printPlan prints the steps a crawl would perform, by domain and section
+func printPlan(formatter *output.Formatter, plan *crawler.Plan) {
+	if outputFormat == "json" {
+		printJSON(formatter, plan.Domain, plan)
+		return
+	}
+	var name, section string
+	for _, step := range plan.Steps {
+		if step.Domain != name {
+			formatter.PrintTitle(step.Domain)
+			name, section = step.Domain, ""
+		}
+		if step.Section != section {
+			formatter.PrintSection(strings.ToUpper(step.Section))
+			section = step.Section
+		}
+		target := step.Target
+		if step.Server != "" {
+			target += " @ " + step.Server
+		}
+		if step.When != "" {
+			target += " (" + step.When + ")"
+		}
+		formatter.PrintRecord(step.Kind, target)
+	}
+}
+
+// rawTypes are the types --raw prints unless --types is given
+var rawTypes = []string{"A", "AAAA", "MX", "NS", "TXT"}
+
+// printRaw prints the answers for each type of every domain as complete
+// resource records, like dig +noall +answer. Failed queries go to stderr,
+// and make it return false.
+func printRaw(resolver *dns.Resolver, domains, types []string) bool {
+	ok := true
+	for _, name := range domains {
+		for _, t := range types {
+			rrs, err := resolver.LookupRaw(name, t)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", name, strings.ToUpper(t), err)
+				ok = false
+				continue
+			}
+			for _, rr := range rrs {
+				if redactOutput {
+					rr = redact.Line(rr)
+				}
+				fmt.Println(rr)
+			}
+		}
+	}
+	return ok
+}
+
+// printQuery prints the values a query selects from a result, one per line:
+// strings as they are, other values as JSON. With -o json, strings are JSON
+// too, like jq without -r.
+func printQuery(q *query.Query, result *crawler.Result) {
+	values, err := q.Run(result)
+	for _, v := range values {
+		if s, ok := v.(string); ok && outputFormat == "text" {
+			fmt.Println(s)
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: query: %v\n", result.Domain, err)
+			continue
+		}
+		fmt.Println(string(data))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: query: %v\n", result.Domain, err)
+	}
+}
+
+// printSummary renders a result as a single line: registrar, expiry and the
+// providers of the nameservers, addresses and mail servers
+func printSummary(formatter *output.Formatter, result *crawler.Result) {
+	var fields []string
+	if spoof := result.Spoofing; spoof != nil {
+		fields = append(fields, fmt.Sprintf("SPOOFING %s looks like %s", spoof.Unicode, spoof.Skeleton))
+	}
+	if !result.Registered {
+		formatter.PrintSummary(result.Domain, append(fields, strings.ToLower(notRegistered(formatter, result)))...)
+		return
+	}
+
+	if v := result.Parking; v != nil {
+		fields = append(fields, v.Label())
+	}
+
+	if w := result.Whois; w != nil && !w.Failed() {
+		if w.Registrar != "" {
+			fields = append(fields, w.Registrar)
+		}
+		if w.Expires != "" {
+			expires := w.Expires
+			if w.DaysToExpiry != nil {
+				expires = fmt.Sprintf("%s (%dd)", expires, *w.DaysToExpiry)
+			}
+			fields = append(fields, "expires "+expires)
+		}
+	}
+
+	if ns := dnsProviders(result); len(ns) > 0 {
+		fields = append(fields, "ns "+strings.Join(ns, ", "))
+	}
+	if web := webProviders(result); len(web) > 0 {
+		fields = append(fields, "web "+strings.Join(web, ", "))
+	}
+	if mail := mailProviders(result); len(mail) > 0 {
+		fields = append(fields, "mx "+strings.Join(mail, ", "))
+	}
+	formatter.PrintSummary(result.Domain, fields...)
+}
+
+// printOneline prints a result as one tab-separated line of fixed columns:
+// domain, registrar, expiry, DNS, mail and hosting providers. Missing
+// values are "-", so every line has the same columns for cut and awk.
+func printOneline(result *crawler.Result) {
+	registrar, expires := "", ""
+	if w := result.Whois; w != nil && !w.Failed() {
+		registrar, expires = w.Registrar, w.Expires
+	}
+	fields := []string{
+		result.Domain,
+		registrar,
+		expires,
+		strings.Join(dnsProviders(result), ","),
+		strings.Join(mailProviders(result), ","),
+		strings.Join(webProviders(result), ","),
+	}
+	for i, f := range fields {
+		fields[i] = cmp.Or(onelineReplacer.Replace(f), "-")
+	}
+	fmt.Println(strings.Join(fields, "\t"))
+}
+
+// onelineReplacer keeps values from breaking the columns of --oneline
+var onelineReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", "")
+
+// dnsProviders returns the distinct providers of the nameservers, or their
+// names when the provider is unknown
+func dnsProviders(result *crawler.Result) []string {
+	if result.Nameservers == nil {
+		return nil
+	}
+	var ns []string
+	for _, server := range result.Nameservers.Servers {
+		ns = append(ns, cmp.Or(server.Provider, server.Name))
+	}
+	return uniqueValues(ns)
+}
+
+// webProviders returns the distinct providers the CNAME and address records
+// point at, falling back to the AS and the value itself
+func webProviders(result *crawler.Result) []string {
+	if result.Records == nil {
+		return nil
+	}
+	var web []string
+	for _, rec := range slices.Concat(result.Records.CNAME, result.Records.A, result.Records.AAAA) {
+		web = append(web, cmp.Or(rec.Provider, rec.ASN, rec.Value))
+	}
+	return uniqueValues(web)
+}
+
+// mailProviders returns the distinct providers of the MX records
+func mailProviders(result *crawler.Result) []string {
+	if result.Records == nil {
+		return nil
+	}
+	var mail []string
+	for _, mx := range result.Records.MX {
+		mail = append(mail, cmp.Or(mx.Provider, mx.Value))
+	}
+	return uniqueValues(mail)
+}
+
+// uniqueValues drops repeated values, keeping the first occurrence
+func uniqueValues(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// checkOutputFormat exits unless --output names a format the command supports
+func checkOutputFormat(env *environment, junit bool) {
+	switch outputFormat {
+	case "text", "json":
+		return
+	case "junit":
+		if junit {
+			return
+		}
+		env.fatal("junit output is only supported by grade, audit and assert")
+	}
+	env.fatal(fmt.Sprintf("unknown output format %q", outputFormat))
+}
+
+// serverArg removes a dig-style @server from args, setting --server to it
+func serverArg(args []string) ([]string, error) {
+	var rest []string
+	for _, arg := range args {
+		server, ok := strings.CutPrefix(arg, "@")
+		if !ok {
+			rest = append(rest, arg)
+			continue
+		}
+		if server == "" || recordServer != "" && recordServer != server {
+			return nil, fmt.Errorf("give one server, as @server or with --server")
+		}
+		recordServer = server
+	}
+	if len(rest) == 0 {
+		return nil, fmt.Errorf("no domain given")
+	}
+	return rest, nil
+}
+
+// readDomains normalizes the domain arguments; "-" reads one domain per line from stdin.
+// It fails on the first invalid name, naming the stdin line it came from.
+func readDomains(args []string) ([]string, error) {
+	var domains []string
+	for _, arg := range args {
+		if arg != "-" {
+			name, err := normalizeDomainArg(arg)
+			if err != nil {
+				return nil, err
+			}
+			domains = append(domains, name)
+			continue
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for lineNo := 1; scanner.Scan(); lineNo++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, err := normalizeDomainArg(line)
+			if err != nil {
+				return nil, fmt.Errorf("stdin line %d: %v", lineNo, err)
+			}
+			domains = append(domains, name)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading stdin: %v", err)
+		}
+	}
+	return domains, nil
+}
+
+// normalizeDomainArg accepts a domain, a URL or an email address, reducing
+// it to the domain, and validates the result
+func normalizeDomainArg(arg string) (string, error) {
+	return domain.ParseInput(arg)
+}
+
+// mustDomainArg is normalizeDomainArg for commands taking a single domain
+func mustDomainArg(env *environment, arg string) string {
+	name, err := normalizeDomainArg(arg)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	return name
+}
+
+// openFixtures returns the fixture store selected by --record/--replay, or nil
+func openFixtures() (*fixture.Store, error) {
+	switch {
+	case recordDir != "":
+		return fixture.NewRecorder(recordDir)
+	case replayDir != "":
+		return fixture.NewReplayer(replayDir)
+	case offline:
+		return fixture.NewOffline(), nil
+	}
+	return nil, nil
+}
+
+// notRegistered describes a domain that isn't in DNS, with the registry's
+// availability answer when there is one
+func notRegistered(formatter *output.Formatter, result *crawler.Result) string {
+	avail := result.Availability
+	switch {
+	case avail == nil:
+		return formatter.Sprintf("Domain not registered")
+	case avail.Available:
+		return formatter.Sprintf("Domain not registered (%s: %s)", avail.Source, avail.Status)
+	}
+	return formatter.Sprintf("Domain not in DNS, but not available (%s: %s)", avail.Source, avail.Status)
+}
+
+func printDomainInfo(formatter *output.Formatter, result *crawler.Result, isRootContext bool) {
+	title := result.Domain
+	if display := domain.ToUnicode(result.Domain); display != result.Domain {
+		title += " (" + display + ")"
+	}
 	if isRootContext {
-		formatter.PrintTitle(domainName + " (root domain)")
+		formatter.PrintTitle(formatter.Sprintf("%s (root domain)", title))
 	} else {
-		formatter.PrintTitle(domainName)
+		formatter.PrintTitle(title)
+	}
+
+	// A lookalike name is worth flagging whether or not it is registered
+	if spoof := result.Spoofing; spoof != nil {
+		formatter.PrintBanner(formatter.Sprintf("POSSIBLE SPOOFING: %s looks like %s", spoof.Unicode, spoof.Skeleton))
+		if spoof.MixedScript {
+			formatter.PrintWarning(formatter.Sprintf("mixes scripts: %s", strings.Join(spoof.Scripts, ", ")))
+		}
+		for _, c := range spoof.Confusables {
+			formatter.PrintWarning(c)
+		}
 	}
 
 	// Check if domain exists
-	if !resolver.Exists(domainName) {
-		formatter.PrintDim("Domain not registered")
+	if !result.Registered {
+		formatter.PrintDim(notRegistered(formatter, result))
 		return
 	}
 
+	// Known-malicious listings go first so they can't be overlooked
+	if rep := result.Reputation; rep != nil && rep.Malicious {
+		for _, check := range rep.Checks {
+			if check.Listing != nil && check.Listed {
+				formatter.PrintBanner(formatter.Sprintf("MALICIOUS: listed by %s (%s)", check.Source, strings.Join(check.Threats, ", ")))
+			}
+		}
+	}
+	if w := result.Whois; w != nil && w.NewlyRegistered {
+		formatter.PrintBanner(formatter.Sprintf("NEWLY REGISTERED: created %s (%d days ago)", w.Created, *w.AgeDays))
+	}
+	if v := result.Parking; v != nil {
+		formatter.PrintBanner(strings.ToUpper(v.Label()))
+		for _, signal := range v.Signals {
+			formatter.PrintDim(formatter.Sprintf("%s %s", signal.Kind, signal.Value))
+		}
+	}
+	if contact := securityContact(result); contact != "" {
+		formatter.PrintKeyValue("SECURITY", contact)
+	}
+
 	// WHOIS Information
-	if !noWhois {
-		info, err := whoisClient.Lookup(domainName)
-		if err != nil {
+	if result.Whois != nil {
+		if result.Whois.Failed() {
 			formatter.PrintSection("WHOIS")
-			formatter.PrintError(fmt.Sprintf("lookup failed: %v", err))
+			formatter.PrintError(formatter.Sprintf("lookup failed: %s", result.Whois.Error))
 		} else {
-			printWhoisInfo(formatter, info)
+			printWhoisInfo(formatter, result.Whois)
 		}
 	}
 
 	// Nameservers
-	formatter.PrintSection("NAMESERVERS")
-	nameservers, err := resolver.GetNameservers(domainName)
-	if err != nil {
-		formatter.PrintError(fmt.Sprintf("lookup failed: %v", err))
-	} else if len(nameservers) == 0 {
-		formatter.PrintDim("No nameservers found")
-	} else {
-		for _, ns := range nameservers {
-			providerName := providerMatcher.Match(ns.Name)
-			nsDisplay := ns.Name
-			if ns.IP != "" {
-				nsDisplay = fmt.Sprintf("%s (%s)", ns.Name, ns.IP)
-			}
-			asn := ""
-			if ns.IP != "" {
-				if info := resolver.LookupASN(ns.IP); info != nil {
-					asn = info.ASN
-					if info.Org != "" {
-						asn = info.Org
-					}
+	if ns := result.Nameservers; ns != nil {
+		formatter.PrintSection("NAMESERVERS")
+		if ns.Failed() {
+			formatter.PrintError(formatter.Sprintf("lookup failed: %s", ns.Error))
+		} else if len(ns.Servers) == 0 {
+			formatter.PrintDim("No nameservers found")
+		} else {
+			for _, server := range ns.Servers {
+				nsDisplay := server.Name
+				if server.IP != "" {
+					nsDisplay = fmt.Sprintf("%s (%s)", server.Name, server.IP)
 				}
+				formatter.PrintArrowItemWithProviderAndASN(nsDisplay, server.Provider, server.ASN)
 			}
-			formatter.PrintArrowItemWithProviderAndASN(nsDisplay, providerName, asn)
 		}
 	}
 
-	// DNS Trace (skip for root context to reduce noise)
-	if !noTrace && !isRootContext {
+	// DNSSEC
+	if sec := result.DNSSEC; sec != nil {
+		formatter.PrintSection("DNSSEC")
+		switch {
+		case sec.Failed():
+			formatter.PrintError(formatter.Sprintf("check failed: %s", sec.Error))
+		case !sec.Signed:
+			formatter.PrintWarning("Zone is not signed")
+		case !sec.Validated:
+			formatter.PrintError("Zone is signed but does not validate")
+		default:
+			formatter.PrintKeyValue("STATUS", formatter.Sprintf("signed and validated"))
+		}
+		if sec.DNSSEC != nil && len(sec.Algorithms) > 0 {
+			formatter.PrintKeyValue("ALGORITHMS", strings.Join(sec.Algorithms, ", "))
+		}
+	}
+
+	// SOA serial consistency
+	if soa := result.SOA; soa != nil {
+		formatter.PrintSection("SOA")
+		if soa.Failed() {
+			formatter.PrintError(formatter.Sprintf("check failed: %s", soa.Error))
+		} else {
+			formatter.PrintKeyValue("PRIMARY", soa.Primary)
+			formatter.PrintKeyValue("SERIAL", fmt.Sprintf("%d", soa.Serial))
+			for _, server := range soa.Servers {
+				display := fmt.Sprintf("%s (%s)", server.Name, server.IP)
+				switch {
+				case server.NoRoute:
+					formatter.PrintDim(formatter.Sprintf("%s: no route from this host, not checked", display))
+				case server.Error != "":
+					formatter.PrintError(formatter.Sprintf("%s: %s", display, server.Error))
+				case server.Stale:
+					formatter.PrintError(formatter.Sprintf("%s serial %d is %d behind for at least %s, longer than the %s refresh interval", display, server.Serial, server.Behind, time.Duration(server.Lag)*time.Second, time.Duration(soa.Refresh)*time.Second))
+				case server.Behind > 0:
+					formatter.PrintWarning(formatter.Sprintf("%s serial %d is %d behind", display, server.Serial, server.Behind))
+				default:
+					formatter.PrintArrowItemWithProviderAndASN(display, "", fmt.Sprintf("serial %d", server.Serial))
+				}
+			}
+			if soa.Consistent {
+				formatter.PrintDim("All servers agree")
+			}
+		}
+	}
+
+	// Open recursion
+	if rec := result.Recursion; rec != nil {
+		formatter.PrintSection("RECURSION")
+		for _, server := range rec.Servers {
+			display := fmt.Sprintf("%s (%s)", server.Name, server.IP)
+			switch {
+			case server.Error != "":
+				formatter.PrintError(formatter.Sprintf("%s: %s", display, server.Error))
+			case server.Open:
+				formatter.PrintWarning(formatter.Sprintf("%s is an open resolver", display))
+			default:
+				formatter.PrintArrowItemWithProviderAndASN(display, "", formatter.Sprintf("refuses recursion"))
+			}
+		}
+	}
+
+	// Answer consistency
+	if cons := result.Consistency; cons != nil {
+		formatter.PrintSection("CONSISTENCY")
+		for _, tc := range cons.Types {
+			for _, server := range tc.Servers {
+				if !server.Differs {
+					continue
+				}
+				display := fmt.Sprintf("%s %s (%s)", tc.Type, server.Name, server.IP)
+				if server.Error != "" {
+					formatter.PrintError(formatter.Sprintf("%s: %s", display, server.Error))
+				} else {
+					formatter.PrintWarning(formatter.Sprintf("%s differs: %s", display, strings.Join(server.Answer, ", ")))
+				}
+			}
+		}
+		if cons.Consistent {
+			formatter.PrintDim("All servers return identical answers")
+		}
+	}
+
+	// DNS Trace
+	if trace := result.Trace; trace != nil {
 		formatter.PrintSection("DNS TRACE")
-		steps, err := resolver.Trace(domainName)
-		if err != nil {
-			formatter.PrintError(fmt.Sprintf("trace failed: %v", err))
-		} else if len(steps) == 0 {
+		// A timed-out trace still has the zones resolved before the deadline
+		if len(trace.Steps) > 0 {
+			formatter.PrintTree(traceTree(trace.Steps))
+		}
+		if trace.Failed() {
+			formatter.PrintError(formatter.Sprintf("trace failed: %s", trace.Error))
+		} else if len(trace.Steps) == 0 {
 			formatter.PrintDim("No trace data")
+		}
+	}
+
+	// DNS Records
+	if records := result.Records; records != nil {
+		formatter.PrintSection("RECORDS")
+		if records.Server != "" {
+			formatter.PrintDim(formatter.Sprintf("asked %s directly", records.Server))
+		}
+		if records.Failed() {
+			formatter.PrintError(formatter.Sprintf("lookup failed: %s", records.Error))
 		} else {
-			for _, step := range steps {
-				formatter.PrintTraceStep(step.Zone, step.Server)
+			printRecords(formatter, records)
+		}
+	}
+
+	// Co-hosted domains
+	if rev := result.ReverseIP; rev != nil {
+		formatter.PrintSection("SHARED HOSTING")
+		if rev.Failed() {
+			formatter.PrintError(formatter.Sprintf("lookup failed: %s", rev.Error))
+		}
+		for _, host := range rev.Hosts {
+			if host.Error != "" {
+				formatter.PrintError(formatter.Sprintf("%s: %s", host.IP, host.Error))
+				continue
+			}
+			formatter.PrintArrowItemWithProviderAndASN(host.IP, "", fmt.Sprintf("%d domains via %s", host.Total, rev.Source))
+			if len(host.Domains) > 0 {
+				shown := host.Domains[:min(len(host.Domains), 10)]
+				more := ""
+				if host.Total > len(shown) {
+					more = fmt.Sprintf(" (+%d more)", host.Total-len(shown))
+				}
+				formatter.PrintDim(formatter.Sprintf("    %s%s", strings.Join(shown, ", "), more))
 			}
 		}
 	}
 
-	// DNS Records
-	formatter.PrintSection("RECORDS")
-	records, err := resolver.GetRecords(domainName)
-	if err != nil {
-		formatter.PrintError(fmt.Sprintf("lookup failed: %v", err))
-	} else {
-		printRecords(formatter, resolver, infraMatcher, mailMatcher, records)
+	// Internet exposure
+	if exp := result.Exposure; exp != nil {
+		formatter.PrintSection("EXPOSURE")
+		if exp.Failed() {
+			formatter.PrintError(exp.Error)
+		}
+		for _, host := range exp.Hosts {
+			switch {
+			case host.Error != "":
+				formatter.PrintError(formatter.Sprintf("%s: %s", host.IP, host.Error))
+			case len(host.Ports) == 0:
+				formatter.PrintArrowItemWithProviderAndASN(host.IP, "", "no known open ports")
+			default:
+				ports := make([]string, len(host.Ports))
+				for i, p := range host.Ports {
+					ports[i] = fmt.Sprint(p)
+				}
+				formatter.PrintArrowItemWithProviderAndASN(host.IP, exp.Source, "ports "+strings.Join(ports, ", "))
+				for _, svc := range host.Services {
+					if svc.Name != "" {
+						formatter.PrintDim(formatter.Sprintf("    %d/%s %s", svc.Port, svc.Transport, svc.Name))
+					}
+				}
+				if len(host.Vulns) > 0 {
+					formatter.PrintWarning(formatter.Sprintf("%s has %d known vulnerabilities: %s", host.IP, len(host.Vulns),
+						strings.Join(host.Vulns[:min(len(host.Vulns), 5)], ", ")))
+				}
+			}
+		}
+	}
+
+	// Threat intelligence
+	if ti := result.ThreatIntel; ti != nil {
+		formatter.PrintSection("THREAT INTEL")
+		if ti.Failed() {
+			formatter.PrintDim(ti.Error)
+		}
+		for _, report := range ti.Reports {
+			printThreatReport(formatter, report)
+		}
+	}
+
+	// Safe-browsing reputation
+	if rep := result.Reputation; rep != nil {
+		formatter.PrintSection("REPUTATION")
+		if rep.Failed() {
+			formatter.PrintDim(rep.Error)
+		}
+		for _, check := range rep.Checks {
+			switch {
+			case check.Error != "":
+				formatter.PrintError(formatter.Sprintf("%s: %s", check.Source, check.Error))
+			case check.Listed:
+				formatter.PrintWarning(formatter.Sprintf("%s: %s", check.Source, strings.Join(check.Threats, ", ")))
+				if check.Detail != "" {
+					formatter.PrintDim(formatter.Sprintf("    %s", check.Detail))
+				}
+			default:
+				formatter.PrintKeyValue(strings.ToUpper(check.Source), formatter.Sprintf("not listed"))
+			}
+		}
+	}
+
+	// Domain blocklists
+	if bl := result.Blocklists; bl != nil {
+		formatter.PrintSection("BLOCKLISTS")
+		for _, list := range bl.Lists {
+			switch {
+			case list.Error != "":
+				formatter.PrintError(formatter.Sprintf("%s: %s", list.Name, list.Error))
+			case list.Listed:
+				formatter.PrintWarning(formatter.Sprintf("%s: listed (%s)", list.Name, strings.Join(list.Reasons, ", ")))
+			default:
+				formatter.PrintKeyValue(strings.ToUpper(list.Name), formatter.Sprintf("not listed"))
+			}
+		}
+	}
+
+	// NXDOMAIN hygiene
+	if nx := result.NXDomain; nx != nil {
+		formatter.PrintSection("NXDOMAIN")
+		if nx.Failed() {
+			formatter.PrintError(formatter.Sprintf("check failed: %s", nx.Error))
+		} else {
+			formatter.PrintKeyValue("PROBE", nx.Name)
+			formatter.PrintKeyValue("ANSWER", nx.Rcode)
+			if nx.HasSOA {
+				formatter.PrintKeyValue("NEGATIVE TTL", fmt.Sprintf("%ds", nx.NegativeTTL))
+			}
+			for _, finding := range nx.Findings {
+				formatter.PrintWarning(finding)
+			}
+		}
+	}
+
+	// Resolution dependencies
+	if deps := result.Dependencies; deps != nil {
+		formatter.PrintSection("DEPENDENCIES")
+		if deps.Failed() {
+			formatter.PrintError(formatter.Sprintf("analysis failed: %s", deps.Error))
+		} else if len(deps.Dependencies) == 0 {
+			formatter.PrintDim("No external dependencies")
+		} else {
+			formatter.PrintTree(dependencyTree(deps.Dependencies))
+		}
+		for _, dep := range deps.Dependencies {
+			if dep.Error != "" {
+				formatter.PrintWarning(formatter.Sprintf("nameservers of %s unknown, so are the zones it depends on: %s", dep.Zone, dep.Error))
+			}
+		}
+		for _, spof := range deps.SinglePoints {
+			formatter.PrintWarning(spof)
+		}
+	}
+
+	// Email authentication
+	if email := result.Email; email != nil && (email.SPF != "" || email.DMARC != "") {
+		formatter.PrintSection("EMAIL")
+		if email.SPF != "" {
+			formatter.PrintKeyValue("SPF", truncate(email.SPF))
+		}
+		if email.DMARC != "" {
+			formatter.PrintKeyValue("DMARC", truncate(email.DMARC))
+		}
+	}
+
+	// TLS certificate
+	if tls := result.TLS; tls != nil {
+		formatter.PrintSection("TLS")
+		if tls.Failed() {
+			formatter.PrintError(formatter.Sprintf("probe failed: %s", tls.Error))
+		} else {
+			formatter.PrintKeyValue("SUBJECT", tls.Subject)
+			formatter.PrintKeyValue("ISSUER", tls.Issuer)
+			formatter.PrintKeyValue("EXPIRES", formatter.Sprintf("%s (%d days)", tls.NotAfter.Format("2006-01-02"), tls.DaysToExpiry))
+			formatter.PrintKeyValue("KEY", tls.Key.String())
+			for _, weakness := range tls.Key.Weaknesses() {
+				formatter.PrintWarning(weakness)
+			}
+			printCertificateNames(formatter, tls)
+			if !tls.Valid {
+				formatter.PrintError(tls.VerifyError)
+			}
+			if len(tls.Chain) > 1 {
+				formatter.PrintDim("chain")
+				for _, cert := range tls.Chain {
+					formatter.PrintArrowItemWithProvider(fmt.Sprintf("%s (expires %s)", cert.Subject, cert.NotAfter.Format("2006-01-02")), cert.Source)
+				}
+			}
+			if tls.JARM != "" {
+				formatter.PrintKeyValue("JARM", tls.JARM)
+			}
+			if len(tls.Endpoints) > 0 {
+				formatter.PrintDim("endpoints")
+				for _, ep := range tls.Endpoints {
+					formatter.PrintArrowItemWithProvider(describeTLSEndpoint(ep), ep.Provider)
+				}
+				for _, difference := range tls.Differences {
+					formatter.PrintWarning(difference)
+				}
+			}
+			if o := tls.OCSP; o != nil {
+				formatter.PrintKeyValue("OCSP", describeOCSP(o))
+				for _, problem := range o.Problems() {
+					if o.Revoked() {
+						formatter.PrintError(problem)
+					} else {
+						formatter.PrintWarning(problem)
+					}
+				}
+			}
+			if len(tls.Scans) > 0 {
+				printTLSScans(formatter, tls.Scans)
+			}
+			if alpn := tls.ALPN; alpn != nil {
+				printALPN(formatter, alpn)
+			}
+		}
+	}
+
+	// Websites
+	if web := result.Web; web != nil {
+		formatter.PrintSection("WEB")
+		for _, site := range web.Endpoints {
+			printWebEndpoint(formatter, site)
+		}
+		printSecurityHeaders(formatter, web)
+		if pl := web.HSTSPreload; pl != nil {
+			formatter.PrintKeyValue("HSTS PRELOAD", describePreload(pl))
+			for _, problem := range pl.Problems {
+				formatter.PrintWarning(problem)
+			}
+		}
+		if wk := web.WellKnown; wk != nil {
+			formatter.PrintKeyValue("WELL-KNOWN", cmp.Or(strings.Join(wk.Found(), ", "), "none"))
+			if sec := wk.SecurityTxt; sec != nil {
+				if len(sec.Contact) == 0 {
+					formatter.PrintWarning("security.txt has no Contact")
+				}
+				if sec.Expired(time.Now()) {
+					formatter.PrintWarning(formatter.Sprintf("security.txt expired %s", sec.Expires.Format("2006-01-02")))
+				}
+			}
+		}
+	}
+
+	// IPv6 reachability
+	if v6 := result.IPv6; v6 != nil {
+		formatter.PrintSection("IPV6")
+		if v6.Failed() {
+			formatter.PrintError(formatter.Sprintf("check failed: %s", v6.Error))
+		}
+		for _, r := range v6.Addresses {
+			switch {
+			case r.Reachable():
+				ports := make([]string, len(r.Open))
+				for i, port := range r.Open {
+					ports[i] = strconv.Itoa(port)
+				}
+				formatter.PrintArrowItem(fmt.Sprintf("%s: open on %s", r.IP, strings.Join(ports, ", ")))
+			case r.NoRoute:
+				formatter.PrintDim(formatter.Sprintf("%s: not checked, no IPv6 route from here", r.IP))
+			default:
+				formatter.PrintError(formatter.Sprintf("%s: unreachable (%s)", r.IP, r.Error))
+			}
+		}
+		if down := v6.Unreachable(); len(down) > 0 {
+			if len(down) == len(v6.Addresses) {
+				formatter.PrintWarning("AAAA published but unreachable: clients preferring IPv6 fail or fall back slowly")
+			} else {
+				formatter.PrintWarning(formatter.Sprintf("%d of %d AAAA addresses unreachable", len(down), len(v6.Addresses)))
+			}
+		}
+	}
+
+	// Apex and www
+	if www := result.WWW; www != nil {
+		formatter.PrintSection("APEX / WWW")
+		if www.Failed() {
+			formatter.PrintError(formatter.Sprintf("check failed: %s", www.Error))
+		} else {
+			for _, h := range []crawler.WWWHost{www.Apex, www.WWW} {
+				formatter.PrintArrowItemWithProvider(describeWWWHost(h), strings.Join(h.Hosting, ", "))
+			}
+			if www.Canonical != "" {
+				formatter.PrintKeyValue("CANONICAL", www.Canonical)
+			}
+			for _, issue := range www.Issues {
+				formatter.PrintWarning(issue)
+			}
+		}
+	}
+
+	// CAA
+	if caa := result.CAA; caa != nil {
+		formatter.PrintSection("CAA")
+		switch {
+		case caa.Failed():
+			formatter.PrintError(formatter.Sprintf("lookup failed: %s", caa.Error))
+		case len(caa.Records) == 0:
+			formatter.PrintDim("No CAA records: any certificate authority may issue")
+		default:
+			for _, rec := range caa.Records {
+				formatter.PrintArrowItemWithProvider(rec, caa.Name)
+			}
+		}
+		if caa.IssuerPermitted != nil && *caa.IssuerPermitted {
+			formatter.PrintDim(formatter.Sprintf("the certificate's issuer, %s, is permitted", caa.Issuer))
+		}
+		for _, issue := range caa.Issues {
+			formatter.PrintWarning(issue)
+		}
+	}
+
+	// Plugin sections
+	for _, p := range result.Plugins {
+		title := p.Title
+		if title == "" {
+			title = p.Name
+		}
+		formatter.PrintSection(strings.ToUpper(title))
+		if p.Failed() {
+			formatter.PrintError(formatter.Sprintf("plugin failed: %s", p.Error))
+			continue
+		}
+		for _, item := range p.Items {
+			formatter.PrintKeyValue(strings.ToUpper(item.Key), item.Value)
+		}
 	}
 }
 
-func printWhoisInfo(formatter *output.Formatter, info *whois.Info) {
+func printThreatReport(formatter *output.Formatter, report crawler.ThreatReport) {
+	if report.Error != "" {
+		formatter.PrintError(formatter.Sprintf("%s: %s", report.Source, report.Error))
+		return
+	}
+	if v := report.Verdict; v != nil {
+		verdict := fmt.Sprintf("%d malicious, %d suspicious, %d harmless (reputation %d)",
+			v.Malicious, v.Suspicious, v.Harmless, v.Reputation)
+		if v.Flagged() {
+			formatter.PrintWarning(formatter.Sprintf("%s: %s", report.Source, verdict))
+		} else {
+			formatter.PrintKeyValue(strings.ToUpper(report.Source), verdict)
+		}
+		if len(v.Categories) > 0 {
+			formatter.PrintKeyValue("CATEGORIES", strings.Join(v.Categories, ", "))
+		}
+	}
+	for _, entry := range report.WhoisHistory {
+		period := fmt.Sprintf("%s → %s", formatDay(entry.From), formatDay(entry.To))
+		formatter.PrintArrowItemWithProviderAndASN(period+" "+entry.Registrar, report.Source, entry.Registrant)
+	}
+	if len(report.Related) > 0 {
+		shown := report.Related[:min(len(report.Related), 10)]
+		formatter.PrintKeyValue("RELATED", strings.Join(shown, ", "))
+	}
+	if report.RelatedError != "" {
+		formatter.PrintError(formatter.Sprintf("%s related domains: %s", report.Source, report.RelatedError))
+	}
+}
+
+func printWhoisInfo(formatter *output.Formatter, section *crawler.WhoisSection) {
+	info := section.Info
 	if info.Registry != "" {
 		formatter.PrintKeyValue("REGISTRY", info.Registry)
 	}
@@ -170,12 +1454,24 @@ func printWhoisInfo(formatter *output.Formatter, info *whois.Info) {
 	if info.Registrant != "" {
 		formatter.PrintKeyValue("REGISTRANT", info.Registrant)
 	}
+	if info.RegistrantOrgNumber != "" {
+		formatter.PrintKeyValue("ORG NUMBER", info.RegistrantOrgNumber)
+	}
+	if org := section.Organization; org != nil {
+		printOrganization(formatter, org)
+	}
 	if info.Created != "" {
 		formatter.PrintKeyValue("CREATED", info.Created)
 	}
+	if info.Updated != "" {
+		formatter.PrintKeyValue("UPDATED", info.Updated)
+	}
 	if info.Expires != "" {
 		formatter.PrintKeyValue("EXPIRES", info.Expires)
 	}
+	for _, tech := range info.TechContacts {
+		formatter.PrintKeyValue("TECH", strings.Join(slices.DeleteFunc([]string{cmp.Or(tech.Name, tech.Handle), tech.Email, tech.Phone}, func(s string) bool { return s == "" }), ", "))
+	}
 	if len(info.Status) > 0 {
 		// Show first few status codes
 		statusStr := strings.Join(info.Status, ", ")
@@ -187,70 +1483,359 @@ func printWhoisInfo(formatter *output.Formatter, info *whois.Info) {
 		}
 		formatter.PrintKeyValue("STATUS", statusStr)
 	}
+	if l := info.Locks; l != nil {
+		formatter.PrintKeyValue("LOCKS", describeLocks(l))
+	}
+	if l := info.Lifecycle; l != nil && l.Stage != whois.StageRegistered {
+		formatter.PrintKeyValue("LIFECYCLE", describeLifecycle(l))
+	}
 }
 
-func printRecords(formatter *output.Formatter, resolver *dns.Resolver, infraMatcher *provider.Matcher, mailMatcher *provider.Matcher, records *dns.Records) {
-	hasRecords := false
+// describeLifecycle summarizes the stage of a domain past its expiry date
+// and its estimated drop date
+func describeLifecycle(l *whois.Lifecycle) string {
+	drop := l.DropEarliest
+	if l.DropLatest != l.DropEarliest {
+		drop += " – " + l.DropLatest
+	}
+	return fmt.Sprintf("%s, drops %s (estimate)", strings.ToUpper(string(l.Stage)), drop)
+}
 
-	for _, cname := range records.CNAME {
-		prov := infraMatcher.Match(cname)
-		formatter.PrintRecordWithProvider("CNAME", cname, prov)
-		hasRecords = true
+// printALPN prints the protocols the HTTPS endpoint speaks and the alpn
+// hints of the HTTPS records
+func printALPN(formatter *output.Formatter, alpn *crawler.ALPNSection) {
+	if alpn.ALPN != nil {
+		protocols := strings.Join(alpn.Protocols, ", ")
+		if alpn.H3Unchecked {
+			protocols += " (h3 not checked through the proxy)"
+		}
+		formatter.PrintKeyValue("ALPN", protocols)
+	}
+	if alpn.Failed() {
+		formatter.PrintError(formatter.Sprintf("ALPN check failed: %s", alpn.Error))
 	}
+	for _, rec := range alpn.Records {
+		value := fmt.Sprintf("%d %s", rec.Priority, rec.Target)
+		if len(rec.ALPN) > 0 {
+			value += " alpn=" + strings.Join(rec.ALPN, ",")
+		}
+		if rec.NoDefaultALPN {
+			value += " no-default-alpn"
+		}
+		formatter.PrintKeyValue("HTTPS RR", value)
+	}
+	for _, m := range alpn.Mismatches {
+		formatter.PrintWarning(m)
+	}
+}
 
-	for _, a := range records.A {
-		prov := ""
-		if hostname := resolver.ReverseLookup(a); hostname != "" {
-			if p := infraMatcher.Match(hostname); p != "" {
-				prov = p
-			} else {
-				prov = strings.TrimRight(hostname, ".")
+// maxCertificateNames is how many names of a certificate are listed
+const maxCertificateNames = 10
+
+// printCertificateNames lists the names a certificate covers and the other
+// domains sharing it
+func printCertificateNames(formatter *output.Formatter, tls *crawler.TLSSection) {
+	names := fmt.Sprintf("%d", len(tls.DNSNames))
+	if tls.Wildcard {
+		names += ", wildcard"
+	}
+	formatter.PrintKeyValue("NAMES", names)
+	for i, name := range tls.DNSNames {
+		if i == maxCertificateNames {
+			formatter.PrintDim(formatter.Sprintf("and %d more", len(tls.DNSNames)-i))
+			break
+		}
+		formatter.PrintArrowItem(name)
+	}
+	if len(tls.SharedWith) > 0 {
+		shared := tls.SharedWith
+		if len(shared) > maxCertificateNames {
+			shared = append(slices.Clone(shared[:maxCertificateNames]), fmt.Sprintf("%d more", len(shared)-maxCertificateNames))
+		}
+		formatter.PrintWarning(formatter.Sprintf("shared with other domains: %s", strings.Join(shared, ", ")))
+	}
+}
+
+// printTLSScans lists the versions each address accepts, the cipher it
+// picks for each, and the outdated versions and weak ciphers
+func printTLSScans(formatter *output.Formatter, scans []crawler.TLSScan) {
+	formatter.PrintDim("versions")
+	for _, scan := range scans {
+		if scan.Error != "" {
+			formatter.PrintArrowItem(fmt.Sprintf("%s: %s", scan.Address, scan.Error))
+			continue
+		}
+		var accepted []string
+		for _, v := range scan.Versions {
+			if v.Accepted {
+				accepted = append(accepted, v.Version)
 			}
 		}
-		asn := ""
-		if info := resolver.LookupASN(a); info != nil {
-			asn = info.ASN
-			if info.Org != "" {
-				asn = info.Org
+		formatter.PrintArrowItem(fmt.Sprintf("%s: %s", scan.Address, strings.Join(accepted, ", ")))
+		for _, v := range scan.Versions {
+			if v.Accepted {
+				formatter.PrintDim(formatter.Sprintf("  %-8s %s", v.Version, v.Cipher))
 			}
 		}
-		formatter.PrintRecordWithProviderAndASN("A", a, prov, asn)
-		hasRecords = true
+		for _, issue := range scan.Issues() {
+			formatter.PrintWarning(formatter.Sprintf("%s: %s", scan.Address, issue))
+		}
 	}
+}
 
-	for _, aaaa := range records.AAAA {
-		prov := ""
-		if hostname := resolver.ReverseLookup(aaaa); hostname != "" {
-			if p := infraMatcher.Match(hostname); p != "" {
-				prov = p
+// describeOCSP tells whether the server staples and what the responder says
+func describeOCSP(o *tlsprobe.OCSP) string {
+	desc := "not stapled"
+	if o.Stapled && o.Status != "" {
+		desc = "stapled, " + o.Status
+		if o.NextUpdate != nil {
+			desc += ", until " + o.NextUpdate.Format("2006-01-02 15:04")
+		}
+	} else if o.Stapled {
+		desc = "stapled"
+	}
+	switch {
+	case o.Responder != nil:
+		desc += "; responder: " + o.Responder.Status
+	case o.ResponderError != "":
+		desc += "; responder: " + o.ResponderError
+	}
+	return desc
+}
+
+// traceTree nests each zone of a trace below its parent, annotated with
+// the server that answered for it
+func traceTree(steps []dns.TraceStep) *output.TreeNode {
+	root := &output.TreeNode{Label: steps[0].Zone, Annotation: steps[0].Server}
+	node := root
+	for _, step := range steps[1:] {
+		node = node.Add(step.Zone, "", step.Server)
+	}
+	return root
+}
+
+// dependencyTree nests each dependency below the zone it was reached from,
+// starting at the crawled domain's registrable domain
+func dependencyTree(deps []crawler.Dependency) *output.TreeNode {
+	root := &output.TreeNode{Label: deps[0].Path[0]}
+	nodes := map[string]*output.TreeNode{root.Label: root}
+	for _, dep := range deps {
+		parent := root
+		if n := len(dep.Path); n > 1 {
+			if p, ok := nodes[strings.Join(dep.Path[:n-1], " ")]; ok {
+				parent = p
+			}
+		}
+		annotation := dep.Via
+		if dep.Critical {
+			annotation += ", critical"
+		}
+		nodes[strings.Join(dep.Path, " ")] = parent.Add(dep.Zone, dep.Provider, annotation)
+	}
+	return root
+}
+
+// describeTLSEndpoint tells the certificate served on one address
+func describeTLSEndpoint(ep crawler.TLSEndpoint) string {
+	if ep.Error != "" {
+		return fmt.Sprintf("%s: %s", ep.IP, ep.Error)
+	}
+	desc := fmt.Sprintf("%s: expires %s (%d days)", ep.IP, ep.NotAfter.Format("2006-01-02"), ep.DaysToExpiry)
+	if !ep.Valid {
+		desc += ", " + ep.VerifyError
+	}
+	if ep.JARM != "" {
+		desc += ", JARM " + ep.JARM
+	}
+	return desc
+}
+
+// describeWWWHost summarizes how the apex or www name resolves and where
+// its site leads
+func describeWWWHost(h crawler.WWWHost) string {
+	var ips []string
+	for _, rec := range h.Addresses {
+		ips = append(ips, rec.Value)
+	}
+	value := h.Name + ": " + cmp.Or(strings.Join(ips, ", "), "no addresses")
+	if len(h.CNAME) > 0 {
+		value = fmt.Sprintf("%s: CNAME %s, %s", h.Name, strings.Join(h.CNAME, ", "), cmp.Or(strings.Join(ips, ", "), "no addresses"))
+	}
+	if h.FinalURL != "" {
+		value += " → " + h.FinalURL
+	}
+	return value
+}
+
+// securityContact returns where to report vulnerabilities of the domain,
+// from its security.txt, or "" when it has none
+func securityContact(result *crawler.Result) string {
+	if result.Web == nil || result.Web.WellKnown == nil || result.Web.WellKnown.SecurityTxt == nil {
+		return ""
+	}
+	sec := result.Web.WellKnown.SecurityTxt
+	contact := strings.Join(sec.Contact, ", ")
+	if contact != "" && sec.Expired(time.Now()) {
+		contact += " (expired security.txt)"
+	}
+	return contact
+}
+
+// printWebEndpoint prints where a host's website leads, the platform it is
+// hosted on next to it like a provider, and what it runs, its favicon hashes
+// and the redirects on the way below it
+func printWebEndpoint(formatter *output.Formatter, site crawler.WebEndpoint) {
+	if site.Endpoint == nil {
+		formatter.PrintError(formatter.Sprintf("%s: %s", site.Host, site.Error))
+		return
+	}
+	if site.Error != "" {
+		formatter.PrintError(formatter.Sprintf("%s: %s", site.Host, site.Error))
+	} else {
+		final := fmt.Sprintf("%s: %d %s", site.Host, site.Status, site.FinalURL)
+		if site.Server != "" {
+			final += " (" + site.Server + ")"
+		}
+		var platforms, stack []string
+		for _, t := range site.Technologies {
+			if t.Platform() {
+				platforms = append(platforms, t.Name)
 			} else {
-				prov = strings.TrimRight(hostname, ".")
+				stack = append(stack, t.Name)
 			}
 		}
-		asn := ""
-		if info := resolver.LookupASN(aaaa); info != nil {
-			asn = info.ASN
-			if info.Org != "" {
-				asn = info.Org
+		formatter.PrintArrowItemWithProvider(final, strings.Join(platforms, ", "))
+		if len(stack) > 0 {
+			formatter.PrintDim(formatter.Sprintf("  runs %s", strings.Join(stack, ", ")))
+		}
+		if site.Favicon != nil {
+			// The hashes to search Shodan (http.favicon.hash) and Censys by
+			formatter.PrintDim(formatter.Sprintf("  favicon mmh3 %d, md5 %s", site.Favicon.Hash, site.Favicon.MD5))
+		}
+	}
+	for _, hop := range site.Redirects {
+		formatter.PrintDim(formatter.Sprintf("  via %d %s", hop.Status, hop.URL))
+	}
+}
+
+// printSecurityHeaders prints the header checks of every final URL the
+// websites lead to
+func printSecurityHeaders(formatter *output.Formatter, web *crawler.WebSection) {
+	var finals []*httpprobe.Endpoint
+	for _, site := range web.Endpoints {
+		if site.Endpoint != nil && len(site.SecurityHeaders) > 0 &&
+			!slices.ContainsFunc(finals, func(e *httpprobe.Endpoint) bool { return e.FinalURL == site.FinalURL }) {
+			finals = append(finals, site.Endpoint)
+		}
+	}
+	for _, ep := range finals {
+		label := "security headers"
+		if len(finals) > 1 {
+			label += " of " + ep.FinalURL
+		}
+		formatter.PrintDim(label)
+		for _, check := range ep.SecurityHeaders {
+			status := "warn"
+			if check.Pass {
+				status = "pass"
 			}
+			formatter.PrintCheck(status, check.Name, "", check.Detail)
 		}
-		formatter.PrintRecordWithProviderAndASN("AAAA", aaaa, prov, asn)
-		hasRecords = true
 	}
+}
 
-	for _, mx := range records.MX {
-		// MX format is "priority hostname" — match against the hostname part
-		prov := mailMatcher.Match(mx)
-		formatter.PrintRecordWithProvider("MX", mx, prov)
-		hasRecords = true
+// describePreload summarizes a domain's HSTS preload status
+func describePreload(pl *httpprobe.Preload) string {
+	switch {
+	case pl.Status == "eligible" && pl.Unchecked:
+		return "eligible (run dnscrawler update hsts to check the list)"
+	case pl.Status == "eligible":
+		return "eligible, not on the list (submit at hstspreload.org)"
+	case pl.Status == "preloaded" && len(pl.Problems) > 0:
+		return "preloaded, but the site no longer meets the requirements"
+	case pl.Status == "missing" && pl.Unchecked:
+		return "not eligible (preload list not downloaded)"
+	case pl.Status == "missing":
+		return "not preloaded, not eligible"
 	}
+	return pl.Status
+}
 
-	for _, txt := range records.TXT {
-		formatter.PrintRecord("TXT", txt)
-		hasRecords = true
+// printOrganization prints the legal entity behind the registrant's
+// organization number
+func printOrganization(formatter *output.Formatter, org *crawler.OrganizationSection) {
+	if org.Failed() {
+		formatter.PrintKeyValue("LEGAL ENTITY", fmt.Sprintf("%s lookup failed: %s", org.Registry, org.Error))
+		return
+	}
+	entity := cmp.Or(org.Name, "(name not published)")
+	if org.Form != "" {
+		entity += " (" + org.Form + ")"
+	}
+	formatter.PrintKeyValue("LEGAL ENTITY", entity+", "+org.Registry)
+	if org.Address != "" {
+		formatter.PrintKeyValue("ADDRESS", org.Address)
+	}
+	if org.Dissolved {
+		formatter.PrintWarning(formatter.Sprintf("%s lists the registrant as dissolved, bankrupt or being wound up", org.Registry))
 	}
+}
 
-	if !hasRecords {
+// describeLocks summarizes a domain's transfer and registry lock
+func describeLocks(l *whois.Locks) string {
+	transfer, registry := "transfer lock", "registry lock"
+	if !l.TransferLock {
+		transfer = "no " + transfer
+	}
+	switch {
+	case l.RegistryLockPartial:
+		registry = "partial " + registry
+	case !l.RegistryLock:
+		registry = "no " + registry
+	}
+	return transfer + ", " + registry
+}
+
+func printRecords(formatter *output.Formatter, records *crawler.RecordsSection) {
+	if records.Empty() {
 		formatter.PrintDim("No records found")
+		return
+	}
+
+	for _, cname := range records.CNAME {
+		formatter.PrintRecordWithProvider("CNAME", cname.Value, cname.Provider)
+	}
+	for _, a := range records.A {
+		formatter.PrintRecordWithProviderAndASN("A", a.Value, a.Provider, a.ASN)
+	}
+	for _, aaaa := range records.AAAA {
+		formatter.PrintRecordWithProviderAndASN("AAAA", aaaa.Value, aaaa.Provider, aaaa.ASN)
+	}
+	for _, mx := range records.MX {
+		formatter.PrintRecordWithProvider("MX", mx.Value, mx.Provider)
+	}
+	for _, txt := range records.TXT {
+		formatter.PrintRecord("TXT", truncate(txt.Value))
+	}
+	for _, t := range slices.Sorted(maps.Keys(records.Other)) {
+		for _, rec := range records.Other[t] {
+			formatter.PrintRecord(t, truncate(rec.Value))
+		}
+	}
+}
+
+// truncate shortens long values (TXT records, policies) for terminal display
+func truncate(value string) string {
+	if len(value) > 60 {
+		return value[:57] + "..."
+	}
+	return value
+}
+
+// formatDay renders a date for history listings, or "?" when unknown
+func formatDay(t time.Time) string {
+	if t.IsZero() {
+		return "?"
 	}
+	return t.Format("2006-01-02")
 }
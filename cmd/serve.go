@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/api"
+	"github.com/auduny/dnscrawler/pkg/auth"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+	"github.com/auduny/dnscrawler/pkg/rpc"
+	"github.com/auduny/dnscrawler/pkg/ui"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var (
+	httpAddr         string
+	grpcAddr         string
+	watchMinInterval time.Duration
+	maxDomains       int
+	serveUI          bool
+	rateLimit        float64
+	rateBurst        int
+	trustProxy       []string
+	servePprof       bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the crawler over HTTP and gRPC",
+	Long: `Run a REST and a gRPC server exposing the crawler to other services.
+
+The REST API is described by the OpenAPI document served at /openapi.json.
+The gRPC service is defined in proto/dnscrawler/v1/dnscrawler.proto: Crawl
+streams one result per requested domain; Watch re-crawls domains on an
+interval and streams a result whenever one changes. Server reflection is
+enabled, so tools such as grpcurl work without the proto file.
+
+With --pprof, CPU, heap, goroutine and other profiles are served at
+/debug/pprof/ for "go tool pprof", e.g. to see where a busy server spends
+its time.
+
+With --ui, the HTTP server also serves a web dashboard at /ui/ showing the
+monitored groups, their latest snapshots and alert history from the state
+store, and a form for ad-hoc crawls.
+
+Pass an empty address to disable either server.
+
+When telemetry is configured (telemetry.endpoint in the config file or the
+OTEL_EXPORTER_OTLP_* environment variables), requests, crawls and lookups are
+traced and lookup metrics are exported over OTLP.`,
+	Args: cobra.NoArgs,
+	Run:  runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&httpAddr, "http-addr", ":8080", "Address to listen on for the REST API")
+	serveCmd.Flags().StringVar(&grpcAddr, "grpc-addr", ":9090", "Address to listen on for gRPC")
+	serveCmd.Flags().DurationVar(&watchMinInterval, "min-interval", rpc.DefaultMinInterval, "Shortest Watch interval clients may request")
+	serveCmd.Flags().IntVar(&maxDomains, "max-domains", 100, "Maximum domains per request (0 for no limit)")
+	serveCmd.Flags().Float64Var(&rateLimit, "rate-limit", 1, "Crawled domains per second allowed per client (0 for no limit)")
+	serveCmd.Flags().IntVar(&rateBurst, "burst", 20, "Domains a client may crawl at once before --rate-limit applies")
+	serveCmd.Flags().StringSliceVar(&trustProxy, "trust-proxy", nil, "Addresses or prefixes of reverse proxies whose X-Forwarded-For gives the client address")
+	serveCmd.Flags().BoolVar(&serveUI, "ui", false, "Serve the web dashboard at /ui/")
+	serveCmd.Flags().BoolVar(&servePprof, "pprof", false, "Serve Go runtime profiles at /debug/pprof/ (needs the read scope)")
+	serveCmd.Flags().StringVar(&stateDir, "state", "", "Read state from files in this directory instead of the configured state backend")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	env := setup()
+	env.requireNetwork("serve")
+	formatter := env.formatter
+
+	if httpAddr == "" && grpcAddr == "" {
+		env.fatal("nothing to serve: both --http-addr and --grpc-addr are empty")
+	}
+	if serveUI && httpAddr == "" {
+		env.fatal("--ui needs --http-addr")
+	}
+
+	authn, err := auth.New(env.cfg.Serve.Keys)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	if authn == nil {
+		formatter.PrintWarning("No API keys configured (serve.keys): anyone who can connect may crawl")
+	}
+
+	tel := env.telemetry()
+	defer tel.Close()
+
+	proxies, err := ratelimit.ParseProxies(trustProxy)
+	if err != nil {
+		env.fatal("--trust-proxy: " + err.Error())
+	}
+
+	c := env.crawler(crawler.Options{})
+	var limiter *ratelimit.Limiter
+	if rateLimit > 0 {
+		limiter = ratelimit.New(rateLimit, rateBurst)
+	}
+	errs := make(chan error, 2)
+
+	var httpServer *http.Server
+	if httpAddr != "" {
+		lis, err := net.Listen("tcp", httpAddr)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		svc := &api.Server{Crawler: c, MaxDomains: maxDomains, Limiter: limiter, TrustedProxies: proxies}
+		handler := svc.Handler()
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		mux.Handle("/v1/", authn.Require(auth.Crawl, handler))
+		if serveUI {
+			store := env.stateStore(stateDir)
+			defer store.Close()
+			dashboard := &ui.Server{Groups: env.cfg.Groups, Alerts: env.cfg.Alerts, Store: store}
+			mux.Handle("/ui/", http.StripPrefix("/ui", dashboard.Handler()))
+			mux.Handle("/ui/api/", authn.Require(auth.Read, http.StripPrefix("/ui", dashboard.Handler())))
+			mux.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+		}
+		if servePprof {
+			mux.Handle("/debug/pprof/", authn.Require(auth.Read, pprofHandler()))
+		}
+		httpServer = &http.Server{Handler: otelhttp.NewHandler(mux, "http"), ReadHeaderTimeout: 10 * time.Second}
+		formatter.PrintDim(formatter.Sprintf("HTTP listening on %s", lis.Addr()))
+		go func() {
+			if err := httpServer.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if grpcAddr != "" {
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		grpcServer = grpc.NewServer(
+			grpc.StatsHandler(otelgrpc.NewServerHandler()),
+			grpc.StreamInterceptor(authn.StreamInterceptor(auth.Crawl)),
+		)
+		svc := &rpc.Server{Crawler: c, MinInterval: watchMinInterval, MaxDomains: maxDomains, Limiter: limiter}
+		svc.Register(grpcServer)
+		reflection.Register(grpcServer)
+		formatter.PrintDim(formatter.Sprintf("gRPC listening on %s", lis.Addr()))
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-errs:
+		env.fatal(err.Error())
+	case <-stop:
+	}
+
+	formatter.PrintDim("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if httpServer != nil {
+		httpServer.Shutdown(ctx)
+	}
+	if grpcServer != nil {
+		// Watch streams only end when the client leaves, so don't wait for them forever
+		done := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+}
+
+// pprofHandler serves the net/http/pprof endpoints; the server doesn't use
+// http.DefaultServeMux, where the package registers them itself
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
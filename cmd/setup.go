@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/bind"
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/export"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/i18n"
+	"github.com/auduny/dnscrawler/pkg/intel"
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/output"
+	"github.com/auduny/dnscrawler/pkg/proxy"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+	"github.com/auduny/dnscrawler/pkg/rdap"
+	"github.com/auduny/dnscrawler/pkg/state"
+	"github.com/auduny/dnscrawler/pkg/telemetry"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// environment holds the configuration and clients shared by all commands
+type environment struct {
+	formatter *output.Formatter
+	cfg       *config.Config
+	fixtures  *fixture.Store
+	dns       *dns.Resolver // shared so every crawler reuses its connections
+	whois     *whois.Client // shared so the per-server connection cap is global
+	proxy     *proxy.Proxy  // nil for direct connections
+	source    *bind.Source  // nil to let the system choose the local address
+	snapshots state.Store   // opened on first use with --offline
+	limit     *ratelimit.Global
+
+	// lookupMetrics is set once telemetry export is started
+	lookupMetrics *crawler.Hooks
+}
+
+// setup loads the config file and fixture store, exiting on error
+func setup() *environment {
+	env := &environment{formatter: output.New()}
+	language, err := i18n.Select(lang)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	env.formatter.Lang = language
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		env.fatal(fmt.Sprintf("config: %v", err))
+	}
+	env.cfg = cfg
+	if err := domain.UseSuffixes(cfg.Suffixes.Custom, cfg.Suffixes.Private); err != nil {
+		env.fatal(fmt.Sprintf("config: suffixes: %v", err))
+	}
+
+	env.proxy, err = proxy.Parse(cmp.Or(proxyURL, cfg.Proxy))
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	ip, iface := cfg.SourceIP, cfg.Interface
+	if sourceIP != "" || sourceInterface != "" {
+		ip, iface = sourceIP, sourceInterface
+	}
+	env.source, err = bind.Parse(ip, iface)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	env.limit = ratelimit.NewGlobal(cmp.Or(maxQPS, cfg.MaxQPS))
+
+	fixtures, err := openFixtures()
+	if err != nil {
+		env.fatal(fmt.Sprintf("fixtures: %v", err))
+	}
+	env.fixtures = fixtures
+
+	return env
+}
+
+// fatal prints an error and exits
+func (e *environment) fatal(msg string) {
+	e.formatter.PrintError(msg)
+	os.Exit(1)
+}
+
+// requireNetwork exits when --offline is set, for commands that can't work
+// from stored results
+func (e *environment) requireNetwork(command string) {
+	if offline {
+		e.fatal(fmt.Sprintf("%s needs the network and can't run with --offline", command))
+	}
+}
+
+// result crawls a domain, or with --offline returns its stored snapshot
+func (e *environment) result(c *crawler.Crawler, name string) (*crawler.Result, error) {
+	if !offline || replayDir != "" {
+		return c.Crawl(name), nil
+	}
+	if e.snapshots == nil {
+		e.snapshots = e.stateStore("")
+	}
+	result, err := monitor.LoadSnapshot(e.snapshots, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("%s: no stored result to use offline (results are stored by monitor)", name)
+	}
+	return result, nil
+}
+
+// offlineDial fails the connections that don't go through the fixture
+// store, e.g. of TLS probes, with --offline
+func offlineDial(ctx context.Context, network, addr string) (net.Conn, error) {
+	return nil, fmt.Errorf("%w for %s %s", fixture.ErrOffline, network, addr)
+}
+
+// resolver returns the process-wide resolver, created on first use
+func (e *environment) resolver() *dns.Resolver {
+	if e.dns != nil {
+		return e.dns
+	}
+	var network string
+	switch dnsTransport {
+	case "":
+		network = "udp"
+	case "udp", "tcp":
+		network = dnsTransport
+	case "tls":
+		network = "tcp-tls"
+	default:
+		e.fatal(fmt.Sprintf("unknown --dns-transport %q (want udp, tcp or tls)", dnsTransport))
+	}
+	upstreams := e.cfg.Resolvers
+	if len(resolvers) > 0 {
+		upstreams = resolvers
+	}
+	strategy := cmp.Or(resolverStrategy, e.cfg.ResolverStrategy)
+	if err := dns.CheckUpstreams(upstreams, strategy); err != nil {
+		e.fatal(err.Error())
+	}
+	opts := []dns.Option{dns.WithFixtures(e.fixtures), dns.WithNetwork(network), dns.WithUpstreams(upstreams, strategy),
+		dns.WithTimeout(dnsTimeout), dns.WithTraceTimeout(traceTimeout), dns.WithLimit(e.limit)}
+	if recordServer != "" {
+		opts = append(opts, dns.WithAuthoritative(recordServer))
+	}
+	if e.proxy != nil {
+		// The proxy only carries streams; the resolver switches to TCP
+		if dnsTransport == "udp" {
+			e.fatal("--dns-transport udp can't go through --proxy; use tcp or tls")
+		}
+		opts = append(opts, dns.WithDialer(e.proxy.Dialer(dnsTimeout, e.source)))
+	}
+	if e.source != nil {
+		opts = append(opts, dns.WithSource(e.source.LocalAddr))
+	}
+	e.dns = dns.NewResolver(opts...)
+	return e.dns
+}
+
+// whoisClient returns the process-wide WHOIS client, created on first use
+func (e *environment) whoisClient() *whois.Client {
+	if e.whois == nil {
+		e.whois = whois.NewClient(e.whoisOptions()...)
+	}
+	return e.whois
+}
+
+// whoisOptions configure the WHOIS clients from the flags and config
+func (e *environment) whoisOptions() []whois.Option {
+	opts := []whois.Option{whois.WithFixtures(e.fixtures), whois.WithMaxConns(whoisConns),
+		whois.WithTimeout(whoisTimeout), whois.WithLimit(e.limit)}
+	if e.proxy != nil || e.source != nil {
+		opts = append(opts, whois.WithDialer(e.proxy.Dialer(whoisTimeout, e.source)))
+	}
+	return opts
+}
+
+// httpClient returns an HTTP client sending its requests with transport
+func (e *environment) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: e.transport(),
+	}
+}
+
+// transport returns an HTTP transport whose responses go through the
+// fixture store and whose requests go through the proxy, if one is set,
+// within --max-qps
+func (e *environment) transport() http.RoundTripper {
+	return e.fixtures.Transport(e.limit.Transport(e.proxy.Transport(e.source)))
+}
+
+// insecureTransport is transport without certificate verification, for
+// services with self-signed certificates
+func (e *environment) insecureTransport() http.RoundTripper {
+	base := e.proxy.Transport(e.source).(*http.Transport).Clone()
+	base.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return e.fixtures.Transport(e.limit.Transport(base))
+}
+
+func (e *environment) intelClient() *intel.Client {
+	c := intel.NewClient(e.cfg.APIKeys)
+	c.HTTP = e.httpClient(15 * time.Second)
+	return c
+}
+
+func (e *environment) rdapClient() *rdap.Client {
+	c := rdap.NewClient()
+	c.HTTP = e.httpClient(15 * time.Second)
+	return c
+}
+
+// crawler creates a crawler using the fixture-aware clients, the configured
+// threat and reputation sources and the configured plugins
+func (e *environment) crawler(opts crawler.Options) *crawler.Crawler {
+	c := crawler.New(opts)
+	c.Resolver = e.resolver()
+	c.Whois = e.whoisClient()
+	c.MaxTime = maxTime
+	if e.proxy != nil || e.source != nil {
+		c.TLS.DialContext = e.proxy.Dialer(c.TLS.Timeout, e.source).DialContext
+		c.Reach.DialContext = e.proxy.Dialer(c.Reach.Timeout, e.source).DialContext
+	}
+	if e.source != nil {
+		c.TLS.Source = e.source.LocalAddr
+	}
+	c.TLS.NoQUIC = e.proxy != nil
+	c.TLS.DialContext = e.limit.DialContext(c.TLS.DialContext)
+	c.TLS.Wait = e.limit.Wait
+	c.Reach.DialContext = e.limit.DialContext(c.Reach.DialContext)
+	c.HTTP.Transport = e.transport()
+	c.TLS.Transport = c.HTTP.Transport
+	c.TLS.Fixtures = e.fixtures
+	if offline {
+		c.TLS.DialContext = offlineDial
+		c.TLS.NoQUIC = true
+		c.Reach.DialContext = offlineDial
+	}
+	if opts.Runs(crawler.SectionWeb) {
+		preload, err := httpprobe.LoadPreloadList()
+		if err != nil {
+			e.formatter.PrintWarning(e.formatter.Sprintf("HSTS preload list: %v", err))
+		}
+		c.HTTP.Preload = preload
+	}
+	if len(e.cfg.Web.WellKnown) > 0 {
+		c.HTTP.WellKnownPaths = e.cfg.Web.WellKnown
+	}
+
+	intelClient := e.intelClient()
+	c.Threat = intelClient.ThreatSources()
+	c.Reputation = intelClient.ReputationSources()
+	c.OrgRegistry = intelClient.OrganizationRegistry
+	c.RDAP = e.rdapClient()
+
+	// Plugins may use the network on their own
+	if offline {
+		e.instrument(c)
+		return c
+	}
+	for _, p := range e.cfg.Plugins {
+		c.Plugins = append(c.Plugins, crawler.Plugin{
+			Name:    p.Name,
+			Command: p.Command,
+			Args:    p.Args,
+			Timeout: p.Timeout,
+		})
+	}
+	e.instrument(c)
+	return c
+}
+
+// telemetry starts OpenTelemetry export when it is configured, returning nil
+// otherwise. Crawlers created afterwards record lookup metrics.
+func (e *environment) telemetry() *telemetry.Telemetry {
+	t, err := telemetry.Setup(context.Background(), e.cfg.Telemetry)
+	if err != nil {
+		e.fatal(err.Error())
+	}
+	if t != nil {
+		hooks, err := telemetry.Hooks()
+		if err != nil {
+			e.fatal(fmt.Sprintf("telemetry: %v", err))
+		}
+		e.lookupMetrics = &hooks
+	}
+	return t
+}
+
+// instrument adds the lookup metrics hooks to c when telemetry is running
+func (e *environment) instrument(c *crawler.Crawler) {
+	if e.lookupMetrics != nil {
+		c.Use(*e.lookupMetrics)
+	}
+}
+
+// notifiers creates every notifier defined in the config, by name. Digest
+// queues are kept in store.
+func (e *environment) notifiers(store state.Store) map[string]notify.Notifier {
+	env := notify.Env{
+		HTTP:  e.httpClient(15 * time.Second),
+		SMTP:  e.cfg.SMTP,
+		State: store,
+	}
+	notifiers := make(map[string]notify.Notifier)
+	for name, nc := range e.cfg.Notifiers {
+		n, err := notify.New(name, nc, env)
+		if err != nil {
+			e.fatal(err.Error())
+		}
+		notifiers[name] = n
+	}
+	return notifiers
+}
+
+// stateStore opens the configured state backend. A non-empty dir selects the
+// file backend in that directory; without any configuration the file backend
+// is used in the user cache dir.
+func (e *environment) stateStore(dir string) state.Store {
+	cfg := e.cfg.State
+	if dir != "" {
+		cfg = config.State{Backend: "file", Path: dir}
+	}
+	// Redis and S3 are reached over the network
+	if backend := strings.ToLower(cfg.Backend); offline && backend != "" && backend != "file" && backend != "sqlite" {
+		e.fatal(fmt.Sprintf("the %s state backend needs the network; --offline reads file or sqlite state only", cfg.Backend))
+	}
+	if (cfg.Backend == "" || cfg.Backend == "file") && cfg.Path == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			e.fatal(fmt.Sprintf("state directory: %v", err))
+		}
+		cfg.Path = filepath.Join(cache, "dnscrawler", "state")
+	}
+
+	store, err := state.Open(cfg)
+	if err != nil {
+		e.fatal(err.Error())
+	}
+	return store
+}
+
+// monitor creates a Monitor using the configured state store and notifiers
+func (e *environment) monitor(stateDir string) *monitor.Monitor {
+	e.requireNetwork("monitoring")
+	store := e.stateStore(stateDir)
+
+	c := crawler.New(crawler.Options{})
+	c.Resolver = e.resolver()
+	c.Whois = e.whoisClient()
+	c.MaxTime = maxTime
+	if e.proxy != nil || e.source != nil {
+		c.TLS.DialContext = e.proxy.Dialer(c.TLS.Timeout, e.source).DialContext
+	}
+	if e.source != nil {
+		c.TLS.Source = e.source.LocalAddr
+	}
+	c.TLS.NoQUIC = e.proxy != nil
+	c.TLS.DialContext = e.limit.DialContext(c.TLS.DialContext)
+	c.TLS.Wait = e.limit.Wait
+	c.HTTP.Transport = e.transport()
+	c.TLS.Transport = c.HTTP.Transport
+	c.TLS.Fixtures = e.fixtures
+	e.instrument(c)
+	ct, err := e.intelClient().NewCTSource(e.cfg.CT.Source)
+	if err != nil {
+		e.fatal(err.Error())
+	}
+	return &monitor.Monitor{
+		Crawler:   c,
+		Store:     store,
+		Notifiers: e.notifiers(store),
+		Alerts:    e.cfg.Alerts,
+		Exporters: e.exporters(),
+		CT:        ct,
+		CTIssuers: e.cfg.CT.Issuers,
+		Redact:    redactOutput,
+	}
+}
+
+// exporters creates every exporter defined in the config
+func (e *environment) exporters() []export.Exporter {
+	var exporters []export.Exporter
+	for _, cfg := range e.cfg.Exporters {
+		client := e.httpClient(30 * time.Second)
+		if cfg.Insecure {
+			client.Transport = e.insecureTransport()
+		}
+		x, err := export.New(cfg, client)
+		if err != nil {
+			e.fatal(err.Error())
+		}
+		exporters = append(exporters, x)
+	}
+	return exporters
+}
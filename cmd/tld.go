@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/tld"
+
+	"github.com/spf13/cobra"
+)
+
+var tldCmd = &cobra.Command{
+	Use:   "tld <tld>",
+	Short: "Report on a top-level domain",
+	Long: `Report on a TLD itself: the registry operator, its WHOIS server and RDAP
+service from IANA's root zone database and RDAP bootstrap registry, the
+TLD's nameservers, the DNSSEC state of the TLD zone, and the IDN tables the
+registry has published, i.e. the languages it accepts internationalized
+names for.
+
+The TLD can be given as "no", ".no" or in Unicode.`,
+	Example: "  dnscrawler tld .no",
+	Args:    cobra.ExactArgs(1),
+	Run:     runTLD,
+}
+
+func init() {
+	rootCmd.AddCommand(tldCmd)
+}
+
+func runTLD(cmd *cobra.Command, args []string) {
+	env := setup()
+	checkOutputFormat(env, false)
+
+	name, err := domain.Normalize(strings.TrimPrefix(strings.TrimSpace(args[0]), "."))
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	if domain.LabelCount(name) != 1 {
+		env.fatal(fmt.Sprintf("%s is not a TLD; did you mean %s?", name, domain.TLD(name)))
+	}
+
+	inspector := tld.NewInspector(env.resolver(), env.whoisClient(), env.rdapClient(), env.httpClient(30*time.Second))
+	report := inspector.Inspect(name)
+
+	if outputFormat == "json" {
+		printJSON(env.formatter, report.TLD, report)
+		return
+	}
+	printTLDReport(env, report)
+}
+
+func printTLDReport(env *environment, r *tld.Report) {
+	f := env.formatter
+	title := "." + r.TLD
+	if r.Unicode != "" {
+		title += " (." + r.Unicode + ")"
+	}
+	f.PrintTitle(title + " (" + r.Type + " TLD)")
+
+	// Without an answer from IANA there is nothing to say about the registry
+	if r.Operator != "" {
+		f.PrintSection("REGISTRY")
+		f.PrintKeyValue("OPERATOR", r.Operator)
+		if r.Status != "" {
+			f.PrintKeyValue("STATUS", r.Status)
+		}
+		if r.Created != "" {
+			f.PrintKeyValue("CREATED", r.Created)
+		}
+		if r.Changed != "" {
+			f.PrintKeyValue("CHANGED", r.Changed)
+		}
+		if r.RegistrationURL != "" {
+			f.PrintKeyValue("POLICY", r.RegistrationURL)
+		}
+		f.PrintKeyValue("WHOIS", cmp.Or(r.WhoisServer, "none"))
+	}
+	rdapFailed := slices.ContainsFunc(r.Errors, func(e string) bool { return strings.HasPrefix(e, "RDAP") })
+	if !rdapFailed {
+		f.PrintKeyValue("RDAP", cmp.Or(strings.Join(r.RDAP, ", "), "none"))
+	}
+
+	if len(r.Nameservers) > 0 {
+		f.PrintSection("NAMESERVERS")
+		for _, ns := range r.Nameservers {
+			if len(ns.Addrs) > 0 {
+				f.PrintArrowItem(fmt.Sprintf("%s (%s)", ns.Name, strings.Join(ns.Addrs, ", ")))
+			} else {
+				f.PrintArrowItem(ns.Name)
+			}
+		}
+	}
+
+	if sec := r.DNSSEC; sec != nil {
+		f.PrintSection("DNSSEC")
+		switch {
+		case !sec.Signed():
+			f.PrintWarning("Zone is not signed")
+		case !sec.Validated:
+			f.PrintError("Zone is signed but does not validate")
+		default:
+			f.PrintKeyValue("STATUS", f.Sprintf("signed and validated"))
+		}
+		if len(sec.Algorithms) > 0 {
+			f.PrintKeyValue("ALGORITHMS", strings.Join(sec.Algorithms, ", "))
+		}
+	}
+
+	if idn := r.IDN; idn != nil {
+		f.PrintSection("IDN")
+		if len(idn.Tables) == 0 {
+			f.PrintDim("No IDN tables published; ASCII names only")
+		}
+		for _, table := range idn.Tables {
+			f.PrintArrowItemWithProvider(table.Language+" "+table.Version, table.URL)
+		}
+	}
+
+	for _, e := range r.Errors {
+		f.PrintError(e)
+	}
+	f.Finish()
+}
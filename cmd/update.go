@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update data files shipped with dnscrawler",
+}
+
+var updatePSLCmd = &cobra.Command{
+	Use:   "psl",
+	Short: "Download the latest Public Suffix List",
+	Long: `Download the Public Suffix List from publicsuffix.org into the user cache
+directory. dnscrawler uses it instead of the snapshot built into the binary
+to find the registrable domain of a name (example.co.uk for www.example.co.uk).
+
+The download is skipped when the cached list is still current.`,
+	Args: cobra.NoArgs,
+	Run:  runUpdatePSL,
+}
+
+var updateHSTSCmd = &cobra.Command{
+	Use:   "hsts",
+	Short: "Download the HSTS preload list",
+	Long: `Download Chromium's HSTS preload list, which the other browsers build
+theirs from, into the user cache directory. --web checks it to tell whether
+a domain is preloaded; without it, a domain is only reported as eligible or
+not.`,
+	Args: cobra.NoArgs,
+	Run:  runUpdateHSTS,
+}
+
+func init() {
+	updateCmd.AddCommand(updatePSLCmd)
+	updateCmd.AddCommand(updateHSTSCmd)
+	rootCmd.AddCommand(updateCmd)
+}
+
+func runUpdatePSL(cmd *cobra.Command, args []string) {
+	env := setup()
+	formatter := env.formatter
+
+	before := domain.CurrentSuffixList()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	info, updated, err := domain.UpdateSuffixList(ctx, env.httpClient(time.Minute))
+	if err != nil {
+		env.fatal(err.Error())
+	}
+
+	if !updated {
+		formatter.PrintKeyValue("PSL", "already up to date")
+	} else {
+		formatter.PrintKeyValue("PSL", "updated")
+		formatter.PrintKeyValue("PREVIOUS", describeSuffixList(before.Version, before.Len(), before.Source))
+	}
+	formatter.PrintKeyValue("CURRENT", describeSuffixList(info.Version, info.Rules, domain.SuffixListCachePath()))
+	formatter.PrintKeyValue("FETCHED", info.Fetched.Local().Format(time.DateTime))
+}
+
+func describeSuffixList(version string, rules int, source string) string {
+	if version == "" {
+		version = "unversioned"
+	}
+	return fmt.Sprintf("%s, %d rules (%s)", version, rules, source)
+}
+
+func runUpdateHSTS(cmd *cobra.Command, args []string) {
+	env := setup()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	list, err := httpprobe.UpdatePreloadList(ctx, env.httpClient(time.Minute))
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	env.formatter.PrintKeyValue("HSTS", "updated")
+	env.formatter.PrintKeyValue("CURRENT", fmt.Sprintf("%d names (%s)", list.Len(), httpprobe.PreloadListCachePath()))
+}
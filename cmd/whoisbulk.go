@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/batch"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bulkCheckpoint string
+	bulkInterval   time.Duration
+	bulkJitter     float64
+	bulkWorkers    int
+	bulkRetries    int
+)
+
+var whoisBulkCmd = &cobra.Command{
+	Use:   "whois-bulk <domain>...",
+	Short: "Look up the WHOIS data of many domains, resumably",
+	Long: `Look up the WHOIS data of a list of domains, e.g. for a portfolio audit,
+without the DNS lookups of a crawl. "-" reads domains from stdin, one per line.
+
+Queries to each WHOIS server are spaced by --interval, randomized
+by --jitter, so they stay under the registries' abuse protection. With
+--checkpoint, every result is appended to a file as it arrives; run the same
+command again after an interruption, a crash or the laptop going to sleep and
+only the domains not in the file yet, and those that failed with a transient
+error, are looked up. The output covers all domains, from the file or new.`,
+	Example: `  dnscrawler whois-bulk --checkpoint audit.ndjson - < estate.txt
+  dnscrawler whois-bulk --checkpoint audit.ndjson -o json - < estate.txt > audit.json`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runWhoisBulk,
+}
+
+func init() {
+	whoisBulkCmd.Flags().StringVar(&bulkCheckpoint, "checkpoint", "", "Append results to this file and skip the domains already in it")
+	whoisBulkCmd.Flags().DurationVar(&bulkInterval, "interval", batch.DefaultWhoisInterval, "Mean spacing of queries to one registry")
+	whoisBulkCmd.Flags().Float64Var(&bulkJitter, "jitter", batch.DefaultWhoisJitter, "Randomize each spacing by up to this fraction of --interval (0 for none)")
+	whoisBulkCmd.Flags().IntVarP(&bulkWorkers, "workers", "w", 4, "Number of concurrent lookups; each registry is still paced")
+	whoisBulkCmd.Flags().IntVar(&bulkRetries, "retries", 2, "Repeat a lookup up to this many times after a transient error")
+	rootCmd.AddCommand(whoisBulkCmd)
+}
+
+func runWhoisBulk(cmd *cobra.Command, args []string) {
+	env := setup()
+	checkOutputFormat(env, false)
+	formatter := env.formatter
+
+	domains, err := readDomains(args)
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	engine := &batch.WhoisEngine{
+		Options:    env.whoisOptions(),
+		Workers:    bulkWorkers,
+		Interval:   bulkInterval,
+		Jitter:     bulkJitter,
+		Retries:    bulkRetries,
+		Checkpoint: bulkCheckpoint,
+	}
+
+	// The first signal stops new lookups; after it the default handling applies again
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	n := 0
+	stats, err := engine.Run(ctx, domains, func(r *batch.WhoisResult) {
+		n++
+		if outputFormat == "json" {
+			json.NewEncoder(os.Stdout).Encode(r)
+			return
+		}
+		printWhoisSummary(env, r)
+		formatter.PrintStatus(fmt.Sprintf("%d/%d looked up", n, len(domains)))
+	})
+	if outputFormat == "text" {
+		formatter.Finish()
+	}
+	if err != nil {
+		env.fatal(err.Error())
+	}
+	if stats.Resumed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d domains resumed from %s\n", stats.Resumed, len(domains), bulkCheckpoint)
+	}
+	if stats.Failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d domains still had transient errors after %d retries\n", stats.Failed, stats.Crawled, bulkRetries)
+	}
+	if stats.Skipped > 0 {
+		msg := fmt.Sprintf("interrupted: %d of %d domains not looked up", stats.Skipped, len(domains))
+		if bulkCheckpoint != "" {
+			msg += "; run again with the same --checkpoint to resume"
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(130)
+	}
+}
+
+// printWhoisSummary renders a bulk result as one line: registrar and
+// expiry, or why there are none
+func printWhoisSummary(env *environment, r *batch.WhoisResult) {
+	var fields []string
+	switch w := r.Whois; {
+	case !r.Registered:
+		fields = append(fields, "not registered")
+	case r.Error != "":
+		fields = append(fields, "error: "+r.Error)
+	default:
+		if w.Registrar != "" {
+			fields = append(fields, w.Registrar)
+		}
+		if w.Expires != "" {
+			expires := w.Expires
+			if w.DaysToExpiry != nil {
+				expires = fmt.Sprintf("%s (%dd)", expires, *w.DaysToExpiry)
+			}
+			fields = append(fields, "expires "+expires)
+		}
+	}
+	env.formatter.PrintSummary(r.Domain, fields...)
+}
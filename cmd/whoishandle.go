@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/whois"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	handleServer string
+	handleTLD    string
+	handleRaw    bool
+)
+
+var whoisHandleCmd = &cobra.Command{
+	Use:   "whois-handle <handle>",
+	Short: "Look up a registrar, contact or nic-handle at a WHOIS server",
+	Long: `Query a WHOIS server for an object handle, such as the registrar and
+contact handles in a domain's WHOIS answer or an RIR nic-handle.
+
+The server is picked by the handle's suffix (REG42-NORID goes to
+whois.norid.no, AB123-RIPE to whois.ripe.net), or given with --server, or
+with --tld as the WHOIS server of that TLD's registry. Answers of registries
+whose format is known are parsed; other answers are printed as they are.`,
+	Example: `  dnscrawler whois-handle REG42-NORID
+  dnscrawler whois-handle EXA98765R-NORID -o json
+  dnscrawler whois-handle ABC123 --tld dk`,
+	Args: cobra.ExactArgs(1),
+	Run:  runWhoisHandle,
+}
+
+func init() {
+	whoisHandleCmd.Flags().StringVar(&handleServer, "server", "", "WHOIS server to query (host or host:port)")
+	whoisHandleCmd.Flags().StringVar(&handleTLD, "tld", "", "Query the WHOIS server of this TLD's registry")
+	whoisHandleCmd.Flags().BoolVar(&handleRaw, "raw", false, "Print the raw answer as well")
+	whoisHandleCmd.MarkFlagsMutuallyExclusive("server", "tld")
+	rootCmd.AddCommand(whoisHandleCmd)
+}
+
+func runWhoisHandle(cmd *cobra.Command, args []string) {
+	env := setup()
+	checkOutputFormat(env, false)
+	client := env.whoisClient()
+
+	server := handleServer
+	if handleTLD != "" {
+		record, err := client.LookupTLD(strings.TrimPrefix(handleTLD, "."))
+		if err != nil {
+			env.fatal(err.Error())
+		}
+		if record.WhoisServer == "" {
+			env.fatal(fmt.Sprintf(".%s has no WHOIS server", record.TLD))
+		}
+		server = record.WhoisServer
+	}
+
+	obj, err := client.LookupHandle(args[0], server)
+	if errors.Is(err, whois.ErrNoHandleServer) {
+		env.fatal(err.Error() + "; give one with --server or --tld")
+	}
+	if err != nil {
+		env.fatal(err.Error())
+	}
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(obj)
+		return
+	}
+
+	f := env.formatter
+	f.PrintTitle(obj.Handle + " (" + obj.Server + ")")
+	if obj.Parsed {
+		for _, kv := range [][2]string{
+			{"TYPE", obj.Type},
+			{"NAME", obj.Name},
+			{"ORGANIZATION", obj.Organization},
+			{"EMAIL", obj.Email},
+			{"PHONE", obj.Phone},
+			{"ADDRESS", strings.Join(obj.Address, ", ")},
+		} {
+			if kv[1] != "" {
+				f.PrintKeyValue(kv[0], kv[1])
+			}
+		}
+	}
+	if !obj.Parsed || handleRaw {
+		f.PrintSection("RAW")
+		for _, line := range strings.Split(strings.TrimRight(obj.Raw, "\r\n"), "\n") {
+			f.PrintDim(strings.TrimRight(line, "\r"))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/whois"
+
+	"github.com/spf13/cobra"
+)
+
+var whoisHistoryNoLookup bool
+
+var whoisHistoryCmd = &cobra.Command{
+	Use:   "whois-history <domain>",
+	Short: "Show how a domain's WHOIS data changed over time",
+	Long: `Look up a domain's WHOIS data, add it to the WHOIS history in the state
+store, and show when the registrar, registrant, nameservers, statuses or
+dates changed. The history grows with every run of this command and every
+monitor check of the domain, so changes show up with the time they were
+first seen and the last time the old data was seen before.`,
+	Example: "  dnscrawler whois-history example.com",
+	Args:    cobra.ExactArgs(1),
+	Run:     runWhoisHistory,
+}
+
+func init() {
+	whoisHistoryCmd.Flags().StringVar(&stateDir, "state", "", "Store state as files in this directory instead of the configured state backend")
+	whoisHistoryCmd.Flags().BoolVar(&whoisHistoryNoLookup, "no-lookup", false, "Show the stored history without looking up WHOIS now")
+	rootCmd.AddCommand(whoisHistoryCmd)
+}
+
+func runWhoisHistory(cmd *cobra.Command, args []string) {
+	env := setup()
+	checkOutputFormat(env, false)
+	domainArg := mustDomainArg(env, args[0])
+
+	store := env.stateStore(stateDir)
+	defer store.Close()
+
+	var lookupErr error
+	// Offline, the stored history is all there is
+	if !whoisHistoryNoLookup && !offline {
+		info, err := env.whoisClient().Lookup(domainArg)
+		switch {
+		case errors.Is(err, whois.ErrNotRegistered):
+			lookupErr = fmt.Errorf("%s is not registered", domainArg)
+		case err != nil:
+			lookupErr = err
+		case info.QueryError != "":
+			lookupErr = errors.New(info.QueryError)
+		default:
+			if _, err := monitor.RecordWhois(store, domainArg, info, time.Now().UTC()); err != nil {
+				env.fatal(fmt.Sprintf("saving WHOIS history: %v", err))
+			}
+		}
+	}
+
+	history, err := monitor.LoadWhoisHistory(store, domainArg)
+	if err != nil {
+		env.fatal(fmt.Sprintf("loading WHOIS history: %v", err))
+	}
+	changes := monitor.WhoisChanges(history)
+
+	if outputFormat == "json" {
+		out := struct {
+			Domain  string                  `json:"domain"`
+			Error   string                  `json:"error,omitempty"`
+			History []monitor.WhoisSnapshot `json:"history"`
+			Changes []monitor.WhoisChange   `json:"changes"`
+		}{Domain: domainArg, History: history, Changes: changes}
+		if lookupErr != nil {
+			out.Error = lookupErr.Error()
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(out)
+		return
+	}
+
+	f := env.formatter
+	f.PrintTitle(domainArg + " (WHOIS history)")
+	if lookupErr != nil {
+		f.PrintWarning(f.Sprintf("lookup failed: %s", lookupErr))
+	}
+	if len(history) == 0 {
+		f.PrintDim("No WHOIS history recorded")
+		return
+	}
+
+	cur := history[len(history)-1]
+	f.PrintSection("CURRENT")
+	for _, kv := range [][2]string{
+		{"REGISTRAR", cur.Registrar},
+		{"REGISTRANT", cur.Registrant},
+		{"NAMESERVERS", strings.Join(cur.NameServers, ", ")},
+		{"STATUS", strings.Join(cur.Status, ", ")},
+		{"CREATED", cur.Created},
+		{"EXPIRES", cur.Expires},
+	} {
+		if kv[1] != "" {
+			f.PrintKeyValue(kv[0], kv[1])
+		}
+	}
+	f.PrintKeyValue("SEEN", fmt.Sprintf("%s – %s", formatSeen(cur.FirstSeen), formatSeen(cur.LastSeen)))
+
+	f.PrintSection("CHANGES")
+	if len(changes) == 0 {
+		f.PrintDim(f.Sprintf("none since %s", formatSeen(history[0].FirstSeen)))
+		return
+	}
+	for _, c := range slices.Backward(changes) {
+		f.PrintKeyValue("DETECTED", fmt.Sprintf("%s (old data last seen %s)", formatSeen(c.Detected), formatSeen(c.Since)))
+		for _, change := range c.Changes {
+			f.PrintArrowItem(change)
+		}
+	}
+}
+
+// formatSeen formats a history time in local time to the minute
+func formatSeen(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
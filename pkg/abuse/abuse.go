@@ -0,0 +1,168 @@
+// Package abuse determines where to report abuse of a domain and its hosting.
+package abuse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/rdap"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// Contact is an abuse reporting address
+type Contact struct {
+	For    string `json:"for"`  // the domain or IP the contact is responsible for
+	Role   string `json:"role"` // registrar, hosting or abuse.net
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Phone  string `json:"phone,omitempty"`
+	Source string `json:"source"` // rdap, whois or abuse.net
+}
+
+// Report collects all abuse contacts found for a domain
+type Report struct {
+	Domain   string    `json:"domain"`
+	Contacts []Contact `json:"contacts"`
+	Errors   []string  `json:"errors,omitempty"`
+}
+
+// Finder discovers abuse contacts
+type Finder struct {
+	RDAP     *rdap.Client
+	Resolver *dns.Resolver
+	Whois    *whois.Client
+}
+
+func NewFinder(resolver *dns.Resolver, whoisClient *whois.Client, rdapClient *rdap.Client) *Finder {
+	return &Finder{
+		RDAP:     rdapClient,
+		Resolver: resolver,
+		Whois:    whoisClient,
+	}
+}
+
+// Find looks up the registrar's abuse contact, the abuse contacts of every
+// hosting IP, and the abuse.net entry for the domain.
+func (f *Finder) Find(domain string) *Report {
+	report := &Report{Domain: domain}
+
+	if contacts, err := f.registrar(domain); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("registrar: %v", err))
+	} else {
+		report.Contacts = append(report.Contacts, contacts...)
+	}
+
+	for _, ip := range f.Resolver.LookupAddrs(domain) {
+		contacts, err := f.hosting(ip)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", ip, err))
+			continue
+		}
+		report.Contacts = append(report.Contacts, contacts...)
+	}
+
+	if contact, err := f.abuseNet(domain); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("abuse.net: %v", err))
+	} else if contact != nil {
+		report.Contacts = append(report.Contacts, *contact)
+	}
+
+	return report
+}
+
+// registrar returns the abuse contact published in the domain's RDAP record
+func (f *Finder) registrar(domain string) ([]Contact, error) {
+	obj, err := f.RDAP.Domain(domain)
+	if err != nil {
+		return nil, err
+	}
+	return rdapContacts(obj, domain, "registrar"), nil
+}
+
+// hosting returns the network's abuse contact, from RDAP or else the RIR's WHOIS
+func (f *Finder) hosting(ip string) ([]Contact, error) {
+	if obj, err := f.RDAP.IP(ip); err == nil {
+		if contacts := rdapContacts(obj, ip, "hosting"); len(contacts) > 0 {
+			return contacts, nil
+		}
+	}
+
+	raw, err := f.Whois.Query(ip, "")
+	if err != nil {
+		return nil, err
+	}
+	var contacts []Contact
+	for _, email := range whoisAbuseEmails(raw) {
+		contacts = append(contacts, Contact{For: ip, Role: "hosting", Email: email, Source: "whois"})
+	}
+	if len(contacts) == 0 {
+		return nil, fmt.Errorf("no abuse contact published")
+	}
+	return contacts, nil
+}
+
+// abuseNet queries the abuse.net contact database
+func (f *Finder) abuseNet(domain string) (*Contact, error) {
+	raw, err := f.Whois.Query(domain, "whois.abuse.net")
+	if err != nil {
+		return nil, err
+	}
+	// Response format: "abuse@example.com (for example.com)"
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || !strings.Contains(line, "@") {
+			continue
+		}
+		email := strings.Fields(line)[0]
+		return &Contact{For: domain, Role: "abuse.net", Email: email, Source: "abuse.net"}, nil
+	}
+	return nil, nil
+}
+
+func rdapContacts(obj *rdap.Object, target, role string) []Contact {
+	var contacts []Contact
+	seen := make(map[string]bool)
+	for _, e := range obj.FindRole("abuse") {
+		vc := e.Contact()
+		if vc.Email == "" && vc.Phone == "" {
+			continue
+		}
+		if seen[vc.Email+vc.Phone] {
+			continue
+		}
+		seen[vc.Email+vc.Phone] = true
+		contacts = append(contacts, Contact{
+			For:    target,
+			Role:   role,
+			Name:   vc.Name,
+			Email:  vc.Email,
+			Phone:  vc.Phone,
+			Source: "rdap",
+		})
+	}
+	return contacts
+}
+
+var (
+	// Field names RIRs use for the abuse mailbox
+	abuseFieldRe = regexp.MustCompile(`(?im)^\s*(?:abuse-mailbox|OrgAbuseEmail|abuse-c-email|e-mail-abuse)\s*:\s*(\S+@\S+)`)
+	// RIPE-style comment: "% Abuse contact for '1.2.3.0 - 1.2.3.255' is 'abuse@example.net'"
+	abuseCommentRe = regexp.MustCompile(`(?i)abuse contact for .* is '([^']+@[^']+)'`)
+)
+
+func whoisAbuseEmails(raw string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, re := range []*regexp.Regexp{abuseCommentRe, abuseFieldRe} {
+		for _, m := range re.FindAllStringSubmatch(raw, -1) {
+			email := strings.ToLower(m[1])
+			if !seen[email] {
+				seen[email] = true
+				emails = append(emails, email)
+			}
+		}
+	}
+	return emails
+}
@@ -0,0 +1,602 @@
+//go:build go1.22
+
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.8.0 DO NOT EDIT.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for Check.
+const (
+	CheckBlocklists  Check = "blocklists"
+	CheckCaa         Check = "caa"
+	CheckConsistency Check = "consistency"
+	CheckDeps        Check = "deps"
+	CheckDnssec      Check = "dnssec"
+	CheckExposure    Check = "exposure"
+	CheckIntel       Check = "intel"
+	CheckIpv6        Check = "ipv6"
+	CheckJarm        Check = "jarm"
+	CheckNxdomain    Check = "nxdomain"
+	CheckOcsp        Check = "ocsp"
+	CheckOrg         Check = "org"
+	CheckParking     Check = "parking"
+	CheckRecursion   Check = "recursion"
+	CheckReputation  Check = "reputation"
+	CheckReverseIp   Check = "reverse-ip"
+	CheckSoa         Check = "soa"
+	CheckTls         Check = "tls"
+	CheckTlsScan     Check = "tls-scan"
+	CheckTrace       Check = "trace"
+	CheckWeb         Check = "web"
+	CheckWww         Check = "www"
+)
+
+// Valid indicates whether the value is a known member of the Check enum.
+func (e Check) Valid() bool {
+	switch e {
+	case CheckBlocklists:
+		return true
+	case CheckCaa:
+		return true
+	case CheckConsistency:
+		return true
+	case CheckDeps:
+		return true
+	case CheckDnssec:
+		return true
+	case CheckExposure:
+		return true
+	case CheckIntel:
+		return true
+	case CheckIpv6:
+		return true
+	case CheckJarm:
+		return true
+	case CheckNxdomain:
+		return true
+	case CheckOcsp:
+		return true
+	case CheckOrg:
+		return true
+	case CheckParking:
+		return true
+	case CheckRecursion:
+		return true
+	case CheckReputation:
+		return true
+	case CheckReverseIp:
+		return true
+	case CheckSoa:
+		return true
+	case CheckTls:
+		return true
+	case CheckTlsScan:
+		return true
+	case CheckTrace:
+		return true
+	case CheckWeb:
+		return true
+	case CheckWww:
+		return true
+	default:
+		return false
+	}
+}
+
+// Check A section of the crawl that only runs when asked for, named as in --only
+type Check string
+
+// CrawlOptions defines model for CrawlOptions.
+type CrawlOptions struct {
+	Checks        *[]Check `json:"checks,omitempty"`
+	NewDomainDays *int     `json:"new_domain_days,omitempty"`
+	NoWhois       *bool    `json:"no_whois,omitempty"`
+}
+
+// CrawlRequest defines model for CrawlRequest.
+type CrawlRequest struct {
+	Domains []string      `json:"domains"`
+	Options *CrawlOptions `json:"options,omitempty"`
+}
+
+// CrawlResponse defines model for CrawlResponse.
+type CrawlResponse struct {
+	Results []Result `json:"results"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Health defines model for Health.
+type Health struct {
+	// Status Example: ok
+	Status string `json:"status"`
+}
+
+// LookupEvent A lookup that finished during a crawl
+type LookupEvent struct {
+	// Cached Answered from data already collected in this crawl
+	Cached *bool `json:"cached,omitempty"`
+
+	// Data The lookup's result
+	Data *any `json:"data,omitempty"`
+
+	// Domain Domain being crawled; the registrable domain while a subdomain's root is crawled
+	Domain     string `json:"domain"`
+	DurationMs int64  `json:"duration_ms"`
+
+	// Error Set when the lookup failed
+	Error *string `json:"error,omitempty"`
+
+	// Kind Lookup type, e.g. whois, nameservers, trace, records or tls
+	//
+	// Example: whois
+	Kind string `json:"kind"`
+
+	// Target What was queried (the domain, an IP, ...)
+	Target string `json:"target"`
+}
+
+// Result A crawl result as printed by -o json. Sections are omitted when not requested; a section that failed has an error field.
+type Result = crawler.Result
+
+// Section A result section; see the JSON output of the command for its fields
+type Section struct {
+	// Error Why the section could not be collected
+	Error                *string                `json:"error,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// BadRequest defines model for BadRequest.
+type BadRequest = Error
+
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = Error
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = Error
+
+// GetCrawlParams defines parameters for GetCrawl.
+type GetCrawlParams struct {
+	// Check Optional checks to run; may be repeated
+	Check *[]Check `form:"check,omitempty" json:"check,omitempty"`
+
+	// NoWhois Skip the WHOIS lookup
+	NoWhois *bool `form:"no_whois,omitempty" json:"no_whois,omitempty"`
+
+	// NewDomainDays Age in days below which a domain is flagged as newly registered
+	NewDomainDays *int `form:"new_domain_days,omitempty" json:"new_domain_days,omitempty"`
+}
+
+// GetCrawlEventsParams defines parameters for GetCrawlEvents.
+type GetCrawlEventsParams struct {
+	// Check Optional checks to run; may be repeated
+	Check *[]Check `form:"check,omitempty" json:"check,omitempty"`
+
+	// NoWhois Skip the WHOIS lookup
+	NoWhois *bool `form:"no_whois,omitempty" json:"no_whois,omitempty"`
+
+	// NewDomainDays Age in days below which a domain is flagged as newly registered
+	NewDomainDays *int `form:"new_domain_days,omitempty" json:"new_domain_days,omitempty"`
+}
+
+// PostCrawlJSONRequestBody defines body for PostCrawl for application/json ContentType.
+type PostCrawlJSONRequestBody = CrawlRequest
+
+// Getter for additional properties for Section. Returns the specified
+// element and whether it was found
+func (a Section) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Section
+func (a *Section) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Section to handle AdditionalProperties
+func (a *Section) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["error"]; found {
+		err = json.Unmarshal(raw, &a.Error)
+		if err != nil {
+			return fmt.Errorf("error reading 'error': %w", err)
+		}
+		delete(object, "error")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Section to handle AdditionalProperties
+func (a Section) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Error != nil {
+		object["error"], err = json.Marshal(a.Error)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'error': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// GetHealth Liveness check
+	// (GET /healthz)
+	GetHealth(w http.ResponseWriter, r *http.Request)
+	// GetOpenAPI This document
+	// (GET /openapi.json)
+	GetOpenAPI(w http.ResponseWriter, r *http.Request)
+	// PostCrawl Crawl several domains
+	// (POST /v1/crawl)
+	PostCrawl(w http.ResponseWriter, r *http.Request)
+	// GetCrawl Crawl a single domain
+	// (GET /v1/crawl/{domain})
+	GetCrawl(w http.ResponseWriter, r *http.Request, domain string, params GetCrawlParams)
+	// GetCrawlEvents Crawl a single domain, streaming progress
+	// (GET /v1/crawl/{domain}/events)
+	GetCrawlEvents(w http.ResponseWriter, r *http.Request, domain string, params GetCrawlEventsParams)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOpenAPI operation middleware
+func (siw *ServerInterfaceWrapper) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOpenAPI(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostCrawl operation middleware
+func (siw *ServerInterfaceWrapper) PostCrawl(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostCrawl(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCrawl operation middleware
+func (siw *ServerInterfaceWrapper) GetCrawl(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "domain", r.PathValue("domain"), &domain, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "domain", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCrawlParams
+
+	// ------------- Optional query parameter "check" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "check", r.URL.Query(), &params.Check, runtime.BindQueryParameterOptions{Type: "array", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "check"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "check", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "no_whois" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "no_whois", r.URL.Query(), &params.NoWhois, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "no_whois"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "no_whois", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "new_domain_days" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "new_domain_days", r.URL.Query(), &params.NewDomainDays, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "new_domain_days"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "new_domain_days", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCrawl(w, r, domain, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCrawlEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetCrawlEvents(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "domain", r.PathValue("domain"), &domain, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "domain", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCrawlEventsParams
+
+	// ------------- Optional query parameter "check" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "check", r.URL.Query(), &params.Check, runtime.BindQueryParameterOptions{Type: "array", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "check"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "check", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "no_whois" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "no_whois", r.URL.Query(), &params.NoWhois, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "no_whois"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "no_whois", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "new_domain_days" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "new_domain_days", r.URL.Query(), &params.NewDomainDays, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "new_domain_days"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "new_domain_days", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCrawlEvents(w, r, domain, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of [http.ServeMux].
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	http.Handler
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc(http.MethodGet+" "+options.BaseURL+"/healthz", wrapper.GetHealth)
+	m.HandleFunc(http.MethodGet+" "+options.BaseURL+"/openapi.json", wrapper.GetOpenAPI)
+	m.HandleFunc(http.MethodPost+" "+options.BaseURL+"/v1/crawl", wrapper.PostCrawl)
+	m.HandleFunc(http.MethodGet+" "+options.BaseURL+"/v1/crawl/{domain}", wrapper.GetCrawl)
+	m.HandleFunc(http.MethodGet+" "+options.BaseURL+"/v1/crawl/{domain}/events", wrapper.GetCrawlEvents)
+
+	return m
+}
@@ -0,0 +1,4 @@
+package api
+
+//go:generate oapi-codegen -config server.cfg.yaml openapi.json
+//go:generate oapi-codegen -config client/client.cfg.yaml openapi.json
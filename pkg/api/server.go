@@ -0,0 +1,264 @@
+// Package api serves the crawler as a REST API. The API is described by
+// openapi.json; the server interface and the client in api/client are
+// generated from it with `go generate`, so handlers can't drift from the spec.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/auduny/dnscrawler/pkg/auth"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// Server implements the generated ServerInterface
+type Server struct {
+	// Crawler is copied for every request and given the request's options
+	Crawler *crawler.Crawler
+	// MaxDomains limits the domains of a single request; zero means no limit
+	MaxDomains int
+	// Limiter rate limits crawls per API key, or per address without keys, one
+	// token per domain; nil disables it
+	Limiter *ratelimit.Limiter
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For gives
+	// the client address
+	TrustedProxies []netip.Prefix
+}
+
+var _ ServerInterface = (*Server)(nil)
+
+// Handler returns the HTTP handler for all API routes
+func (s *Server) Handler() http.Handler {
+	return HandlerWithOptions(s, StdHTTPServerOptions{
+		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			writeError(w, http.StatusBadRequest, err)
+		},
+	})
+}
+
+func (s *Server) GetCrawl(w http.ResponseWriter, r *http.Request, name string, params GetCrawlParams) {
+	normalized, err := domain.Normalize(name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts := CrawlOptions{Checks: params.Check, NoWhois: params.NoWhois, NewDomainDays: params.NewDomainDays}
+	c, err := s.crawler(opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !s.allow(w, r, 1) {
+		return
+	}
+	writeJSON(w, http.StatusOK, c.CrawlContext(r.Context(), normalized))
+}
+
+func (s *Server) GetCrawlEvents(w http.ResponseWriter, r *http.Request, name string, params GetCrawlEventsParams) {
+	normalized, err := domain.Normalize(name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts := CrawlOptions{Checks: params.Check, NoWhois: params.NoWhois, NewDomainDays: params.NewDomainDays}
+	c, err := s.crawler(opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+	if !s.allow(w, r, 1) {
+		return
+	}
+
+	// Hooks run on the crawling goroutine; this one owns the response
+	ctx := r.Context()
+	events := make(chan LookupEvent, 16)
+	send := func(ev crawler.Event) {
+		e := LookupEvent{Domain: ev.Domain, Kind: ev.Kind, Target: ev.Target, DurationMs: ev.Duration.Milliseconds()}
+		if ev.Cached {
+			e.Cached = &ev.Cached
+		}
+		if ev.Err != nil {
+			msg := ev.Err.Error()
+			e.Error = &msg
+		} else {
+			e.Data = &ev.Data
+		}
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	}
+	c.Use(crawler.Hooks{OnResult: send, OnError: send})
+
+	done := make(chan *crawler.Result, 1)
+	go func() {
+		done <- c.CrawlContext(r.Context(), normalized)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case e := <-events:
+			writeEvent(w, "lookup", e)
+			flusher.Flush()
+		case result := <-done:
+			// Lookups finished before the result may still be queued
+			for len(events) > 0 {
+				writeEvent(w, "lookup", <-events)
+			}
+			writeEvent(w, "result", result)
+			flusher.Flush()
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Server) PostCrawl(w http.ResponseWriter, r *http.Request) {
+	var req CrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+	if len(req.Domains) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no domains given"))
+		return
+	}
+	if s.MaxDomains > 0 && len(req.Domains) > s.MaxDomains {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("at most %d domains per request", s.MaxDomains))
+		return
+	}
+	domains := make([]string, len(req.Domains))
+	for i, name := range req.Domains {
+		normalized, err := domain.Normalize(name)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		domains[i] = normalized
+	}
+
+	var opts CrawlOptions
+	if req.Options != nil {
+		opts = *req.Options
+	}
+	c, err := s.crawler(opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !s.allow(w, r, len(domains)) {
+		return
+	}
+
+	resp := CrawlResponse{Results: make([]Result, 0, len(domains))}
+	for _, name := range domains {
+		if r.Context().Err() != nil {
+			return
+		}
+		resp.Results = append(resp.Results, *c.CrawlContext(r.Context(), name))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Health{Status: "ok"})
+}
+
+func (s *Server) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+// allow takes n tokens from the client's rate limit. When the client is over
+// its limit it writes the error response and returns false.
+func (s *Server) allow(w http.ResponseWriter, r *http.Request, n int) bool {
+	if s.Limiter == nil {
+		return true
+	}
+	client := ratelimit.ClientIP(r, s.TrustedProxies)
+	if name, ok := auth.KeyName(r.Context()); ok {
+		client = "key:" + name
+	}
+	ok, wait := s.Limiter.Allow(client, n)
+	if ok {
+		return true
+	}
+	if wait == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%d domains exceed the rate limit burst of %d", n, s.Limiter.Burst()))
+		return false
+	}
+	retry := ratelimit.RetryAfter(wait)
+	w.Header().Set("Retry-After", retry)
+	writeError(w, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, retry in %s seconds", retry))
+	return false
+}
+
+// crawler returns a copy of the server's crawler with the requested options
+func (s *Server) crawler(opts CrawlOptions) (*crawler.Crawler, error) {
+	o := crawler.Options{NoTrace: true}
+	if opts.NoWhois != nil {
+		o.NoWhois = *opts.NoWhois
+	}
+	if opts.NewDomainDays != nil {
+		o.NewDomainDays = *opts.NewDomainDays
+	}
+	if opts.Checks != nil {
+		checks := make([]string, len(*opts.Checks))
+		for i, check := range *opts.Checks {
+			if !check.Valid() {
+				return nil, fmt.Errorf("unknown check %q", check)
+			}
+			checks[i] = string(check)
+		}
+		// Checks are named after the sections they add to the default ones
+		for _, section := range crawler.Sections {
+			if o.Runs(section) || slices.Contains(checks, section) {
+				o.Only = append(o.Only, section)
+			}
+		}
+	}
+
+	return s.Crawler.WithOptions(o), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeEvent writes a server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(Error{Error: err.Error()})
+		event = "error"
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Error: err.Error()})
+}
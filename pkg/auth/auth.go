@@ -0,0 +1,184 @@
+// Package auth checks the API keys of requests to the API server. Keys come
+// from the config file and grant scopes: read for stored results, crawl for
+// triggering crawls.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scopes
+const (
+	Read  = "read"
+	Crawl = "crawl"
+)
+
+// Authenticator validates API keys. A nil Authenticator allows everything.
+type Authenticator struct {
+	keys []key
+}
+
+type key struct {
+	name   string
+	hash   [sha256.Size]byte
+	scopes []string
+}
+
+type contextKey struct{}
+
+// New creates an authenticator for the configured keys, or returns nil when
+// there are none
+func New(keys []config.ServeKey) (*Authenticator, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	a := &Authenticator{}
+	names := make(map[string]bool)
+	for _, k := range keys {
+		if k.Name == "" {
+			return nil, fmt.Errorf("serve.keys: key without a name")
+		}
+		if names[k.Name] {
+			return nil, fmt.Errorf("serve.keys: duplicate name %q", k.Name)
+		}
+		names[k.Name] = true
+		if len(k.Key) < 16 {
+			return nil, fmt.Errorf("serve.keys: key %s is shorter than 16 characters", k.Name)
+		}
+		for _, s := range k.Scopes {
+			if s != Read && s != Crawl {
+				return nil, fmt.Errorf("serve.keys: key %s has unknown scope %q", k.Name, s)
+			}
+		}
+		a.keys = append(a.keys, key{name: k.Name, hash: sha256.Sum256([]byte(k.Key)), scopes: k.Scopes})
+	}
+	return a, nil
+}
+
+// authenticate returns the name and scopes of the key matching token
+func (a *Authenticator) authenticate(token string) (string, []string, bool) {
+	// Comparing fixed-size hashes keeps the comparison constant-time
+	hash := sha256.Sum256([]byte(token))
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			return k.name, k.scopes, true
+		}
+	}
+	return "", nil, false
+}
+
+// check authenticates token and verifies it grants scope
+func (a *Authenticator) check(token, scope string) (string, error) {
+	if token == "" {
+		return "", errUnauthenticated
+	}
+	name, scopes, ok := a.authenticate(token)
+	if !ok {
+		return "", errUnauthenticated
+	}
+	if !slices.Contains(scopes, scope) {
+		return name, fmt.Errorf("key %s lacks the %s scope", name, scope)
+	}
+	return name, nil
+}
+
+var errUnauthenticated = fmt.Errorf("missing or invalid API key")
+
+// KeyName returns the name of the key that authenticated the request, if any
+func KeyName(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(contextKey{}).(string)
+	return name, ok
+}
+
+// Require wraps next so it is only reached with a key granting scope. The key
+// is taken from the Authorization bearer token, the X-API-Key header or, for
+// clients such as EventSource that can't set headers, the access_token query
+// parameter.
+func (a *Authenticator) Require(scope string, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := a.check(requestToken(r), scope)
+		switch {
+		case err == errUnauthenticated:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dnscrawler"`)
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		case err != nil:
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, name)))
+	})
+}
+
+func requestToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, token, _ := strings.Cut(h, " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	if token := r.Header.Get("X-API-Key"); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// StreamInterceptor requires a key granting scope on every gRPC stream. The
+// key is sent as "authorization: Bearer <key>" metadata.
+func (a *Authenticator) StreamInterceptor(scope string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		// Only the crawler service is protected; reflection stays open
+		if a == nil || !strings.HasPrefix(info.FullMethod, "/dnscrawler.") {
+			return handler(srv, ss)
+		}
+		var token string
+		if md, ok := metadata.FromIncomingContext(ss.Context()); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				scheme, t, _ := strings.Cut(values[0], " ")
+				if strings.EqualFold(scheme, "Bearer") {
+					token = strings.TrimSpace(t)
+				}
+			}
+		}
+		name, err := a.check(token, scope)
+		switch {
+		case err == errUnauthenticated:
+			return status.Error(codes.Unauthenticated, err.Error())
+		case err != nil:
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), contextKey{}, name)})
+	}
+}
+
+// authedStream carries the key name in the stream's context
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,252 @@
+// Package batch crawls large lists of domains with a bounded pool of workers,
+// per-target rate limits and retries for transient failures.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+)
+
+// DefaultRetryDelay is the wait before the first retry; it doubles with every attempt
+const DefaultRetryDelay = 5 * time.Second
+
+// Engine crawls domains concurrently
+type Engine struct {
+	Crawler *crawler.Crawler
+	// Workers is the number of concurrent crawls; default 1
+	Workers int
+	// Limits holds the lookups per second allowed per target of each class
+	// (see Target); classes without a limit are unlimited
+	Limits map[string]float64
+	// Retries is how often a domain whose crawl hit a transient error is
+	// crawled again before its last result is kept
+	Retries int
+	// RetryDelay is the wait before the first retry; default DefaultRetryDelay
+	RetryDelay time.Duration
+}
+
+// Stats summarizes a run
+type Stats struct {
+	Crawled int // results emitted
+	Retried int // crawls repeated after a transient error
+	Failed  int // results emitted with a transient error after the last attempt
+	Skipped int // domains not crawled because the run was interrupted
+	Resumed int // results read from a checkpoint instead of crawled again
+}
+
+type job struct {
+	domain  string
+	attempt int
+	ready   time.Time       // earliest time of the next attempt
+	result  *crawler.Result // result of the previous attempt
+}
+
+type outcome struct {
+	job
+	err error // first transient error seen during the crawl
+}
+
+// Run crawls the domains and passes every result to emit, in the order the
+// crawls finish. emit is only called from the goroutine running Run.
+//
+// When ctx is cancelled no further crawls are started: Run waits for the
+// running ones, emits their results and the last results of domains waiting
+// for a retry, and returns. Domains never crawled are counted as skipped.
+func (e *Engine) Run(ctx context.Context, domains []string, emit func(*crawler.Result)) Stats {
+	workers := max(e.Workers, 1)
+	delay := e.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	base := e.Crawler.WithOptions(e.Crawler.Options)
+	if len(e.Limits) > 0 {
+		base.Use(throttle(ctx, e.Limits))
+	}
+
+	jobs := make(chan job)
+	done := make(chan outcome)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				done <- crawl(ctx, base, j)
+			}
+		}()
+	}
+
+	var stats Stats
+	var retries []job // ordered by ready
+	next, running := 0, 0
+	cancelled := ctx.Done()
+	for {
+		// Pick the job to hand out: due retries first, then new domains
+		var send chan job
+		var j job
+		var wake <-chan time.Time
+		if ctx.Err() == nil {
+			switch {
+			case len(retries) > 0 && !retries[0].ready.After(time.Now()):
+				send, j = jobs, retries[0]
+			case next < len(domains):
+				send, j = jobs, job{domain: domains[next]}
+			case len(retries) > 0:
+				wake = time.After(time.Until(retries[0].ready))
+			}
+		}
+		if send == nil && wake == nil && running == 0 {
+			break
+		}
+
+		select {
+		case send <- j:
+			running++
+			if j.attempt > 0 {
+				retries = retries[1:]
+			} else {
+				next++
+			}
+		case o := <-done:
+			running--
+			if o.err != nil && o.attempt < e.Retries {
+				stats.Retried++
+				o.attempt++
+				o.ready = time.Now().Add(delay << (o.attempt - 1))
+				i, _ := slices.BinarySearchFunc(retries, o.ready, func(r job, t time.Time) int {
+					return r.ready.Compare(t)
+				})
+				retries = slices.Insert(retries, i, o.job)
+				continue
+			}
+			if o.err != nil {
+				stats.Failed++
+			}
+			stats.Crawled++
+			emit(o.result)
+		case <-wake:
+		case <-cancelled:
+			cancelled = nil
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Interrupted: keep what was crawled, even if a retry was pending
+	for _, r := range retries {
+		stats.Failed++
+		stats.Crawled++
+		emit(r.result)
+	}
+	stats.Skipped = len(domains) - next
+	return stats
+}
+
+// crawl runs one attempt, noting the first transient lookup error
+func crawl(ctx context.Context, base *crawler.Crawler, j job) outcome {
+	var mu sync.Mutex
+	var transient error
+	c := base.WithOptions(base.Options)
+	c.Use(crawler.Hooks{
+		OnError: func(ev crawler.Event) {
+			if !Transient(ev.Err) {
+				return
+			}
+			mu.Lock()
+			if transient == nil {
+				transient = fmt.Errorf("%s %s: %w", ev.Kind, ev.Target, ev.Err)
+			}
+			mu.Unlock()
+		},
+	})
+	// Cancelling the run stops new crawls, not the lookups of running ones
+	j.result = c.CrawlContext(context.WithoutCancel(ctx), j.domain)
+	return outcome{job: j, err: transient}
+}
+
+// throttle returns hooks that delay each lookup until its target has a token
+func throttle(ctx context.Context, limits map[string]float64) crawler.Hooks {
+	limiters := make(map[string]*ratelimit.Limiter, len(limits))
+	for class, qps := range limits {
+		if qps > 0 {
+			limiters[class] = ratelimit.New(qps, max(1, int(qps)))
+		}
+	}
+	return crawler.Hooks{
+		OnQuery: func(ev crawler.Event) {
+			class, key := Target(ev)
+			if l := limiters[class]; l != nil {
+				// On cancellation the running crawls finish without waiting
+				l.Wait(ctx, key)
+			}
+		},
+	}
+}
+
+// Target returns the rate-limit class of a lookup and the key of the server
+// it goes to within that class:
+//
+//	whois          WHOIS lookups, per TLD
+//	dns            lookups through the resolver, shared
+//	authoritative  queries sent directly to nameservers, per nameserver IP
+//	blocklist      DNS blocklists, per list
+//	api            third-party APIs (reverse IP, exposure, threat, reputation), per service
+//
+// TLS probes and plugins have no class.
+func Target(ev crawler.Event) (class, key string) {
+	switch ev.Kind {
+	case "whois":
+		return "whois", domain.TLD(ev.Target)
+	case "soa", "rrset":
+		_, ip, _ := strings.Cut(ev.Target, "@")
+		return "authoritative", ip
+	case "recursion":
+		return "authoritative", ev.Target
+	case "blocklist":
+		return "blocklist", ev.Target
+	case "reverseip", "exposure":
+		return "api", ev.Kind
+	case "threat", "reputation":
+		return "api", ev.Target
+	case "tls", "plugin":
+		return "", ""
+	}
+	return "dns", ""
+}
+
+// Transient reports whether err is likely to go away when retried: timeouts,
+// refused or reset connections, SERVFAIL answers and rate limiting
+func Transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// Most clients flatten their errors into strings
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"timeout", "timed out", "connection refused", "connection reset",
+		"servfail", "temporar", "try again", "too many requests", "rate limit", "429"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+const (
+	// DefaultWhoisInterval is the mean spacing of queries to one registry
+	DefaultWhoisInterval = 2 * time.Second
+	// DefaultWhoisJitter is how far a spacing may deviate from the mean, as
+	// a fraction of it
+	DefaultWhoisJitter = 0.5
+)
+
+// WhoisEngine looks up the WHOIS data of many domains, as for a portfolio
+// audit. Queries to each WHOIS server are spaced by a randomized delay, so
+// they don't arrive at the steady rate that registries' abuse protection
+// looks for, and every result is appended to a checkpoint file, so an
+// interrupted run can be resumed instead of started over.
+type WhoisEngine struct {
+	// Options configure the WHOIS client of a run, which paces every query
+	// to a server: the lookups, the IANA queries finding the registries'
+	// servers and the handle lookups of the registries' parsers
+	Options []whois.Option
+	// Workers is the number of concurrent lookups; default 1. Lookups to the
+	// same registry are spaced regardless.
+	Workers int
+	// Interval is the mean spacing of queries to one server; default
+	// DefaultWhoisInterval
+	Interval time.Duration
+	// Jitter randomizes each spacing within Interval × (1 ± Jitter); zero
+	// means none
+	Jitter float64
+	// Retries is how often a lookup failing with a transient error is
+	// repeated before its error is kept
+	Retries int
+	// RetryDelay is the wait before the first retry; default DefaultRetryDelay
+	RetryDelay time.Duration
+	// Checkpoint is the file results are appended to, one JSON object per
+	// line. Domains already in it are not looked up again, except those
+	// that ended with a transient error. Empty disables checkpointing.
+	Checkpoint string
+}
+
+// WhoisResult is the outcome of one domain's lookup
+type WhoisResult struct {
+	Domain     string      `json:"domain"`
+	Registered bool        `json:"registered"`
+	Whois      *whois.Info `json:"whois,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Checked    time.Time   `json:"checked"`
+	// Resumed is set on results read from the checkpoint
+	Resumed bool `json:"-"`
+}
+
+// transient reports whether the lookup should be repeated on resume
+func (r *WhoisResult) transient() bool {
+	return r.Error != "" && Transient(errors.New(r.Error))
+}
+
+// Run looks up the domains and passes every result to emit: first those
+// read from the checkpoint, then the new ones in the order they finish.
+// emit is only called from the goroutine running Run.
+//
+// When ctx is cancelled no further queries are sent; Run waits for the
+// running ones, checkpoints and emits them, and returns. Domains never
+// looked up are counted as skipped.
+func (e *WhoisEngine) Run(ctx context.Context, domains []string, emit func(*WhoisResult)) (Stats, error) {
+	var stats Stats
+	var checkpoint *os.File
+	todo := domains
+	if e.Checkpoint != "" {
+		done, f, err := openCheckpoint(e.Checkpoint)
+		if err != nil {
+			return stats, err
+		}
+		defer f.Close()
+		checkpoint = f
+		todo = nil
+		for _, name := range domains {
+			if r, ok := done[name]; ok && !r.transient() {
+				stats.Resumed++
+				emit(r)
+				continue
+			}
+			todo = append(todo, name)
+		}
+	}
+
+	pace := newPacer(ctx, cmp.Or(e.Interval, DefaultWhoisInterval), e.Jitter)
+	client := whois.NewClient(append(slices.Clone(e.Options), whois.WithPace(pace.wait))...)
+	jobs := make(chan string)
+	results := make(chan whoisOutcome)
+	var wg sync.WaitGroup
+	for range max(e.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				results <- e.lookup(ctx, client, name)
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, name := range todo {
+			select {
+			case jobs <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var writeErr error
+	for o := range results {
+		r := o.result
+		stats.Retried += o.retries
+		if r == nil {
+			continue
+		}
+		if checkpoint != nil && writeErr == nil {
+			writeErr = appendCheckpoint(checkpoint, r)
+		}
+		stats.Crawled++
+		if r.transient() {
+			stats.Failed++
+		}
+		emit(r)
+	}
+	stats.Skipped = len(todo) - stats.Crawled
+	return stats, writeErr
+}
+
+type whoisOutcome struct {
+	result  *WhoisResult
+	retries int
+}
+
+// lookup queries one domain, retrying transient errors; the client waits
+// for each server's turn
+func (e *WhoisEngine) lookup(ctx context.Context, client *whois.Client, name string) whoisOutcome {
+	delay := cmp.Or(e.RetryDelay, DefaultRetryDelay)
+	var r *WhoisResult
+	for attempt := 0; ; attempt++ {
+		// A run cancelled before or while waiting for a server keeps the
+		// previous attempt's result; a domain not queried at all is skipped
+		if ctx.Err() != nil {
+			return whoisOutcome{result: r, retries: max(attempt-1, 0)}
+		}
+		next := &WhoisResult{Domain: name, Registered: true, Checked: time.Now().UTC()}
+		info, err := client.Lookup(name)
+		switch {
+		case errors.Is(err, whois.ErrNotRegistered):
+			next.Registered = false
+		case err != nil:
+			next.Error = err.Error()
+		case info.QueryError != "":
+			next.Whois, next.Error = info, info.QueryError
+		default:
+			next.Whois = info
+		}
+		if next.Error != "" && ctx.Err() != nil {
+			return whoisOutcome{result: r, retries: max(attempt-1, 0)}
+		}
+		r = next
+		if !r.transient() || attempt >= e.Retries {
+			return whoisOutcome{result: r, retries: attempt}
+		}
+		sleep(ctx, delay<<attempt)
+	}
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// pacer spaces the queries to each WHOIS server by a jittered interval
+type pacer struct {
+	ctx      context.Context // of the run; ends every wait
+	interval time.Duration
+	jitter   float64
+
+	mu   sync.Mutex
+	next map[string]time.Time // server -> earliest time of its next query
+}
+
+func newPacer(ctx context.Context, interval time.Duration, jitter float64) *pacer {
+	return &pacer{ctx: ctx, interval: interval, jitter: min(max(jitter, 0), 1), next: make(map[string]time.Time)}
+}
+
+// wait reserves the server's next slot and sleeps until it is due. It
+// returns the run's error when the run is cancelled first.
+func (p *pacer) wait(server string) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	now := time.Now()
+	at := p.next[server]
+	if at.Before(now) {
+		at = now
+	}
+	spacing := float64(p.interval) * (1 + p.jitter*(2*rand.Float64()-1))
+	p.next[server] = at.Add(time.Duration(spacing))
+	p.mu.Unlock()
+	if !sleep(p.ctx, time.Until(at)) {
+		return p.ctx.Err()
+	}
+	return nil
+}
+
+// openCheckpoint reads the results in a checkpoint file, the last result
+// per domain, and opens it for appending. A line cut short by a crash is
+// truncated away.
+func openCheckpoint(path string) (map[string]*WhoisResult, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	done := make(map[string]*WhoisResult)
+	var complete int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		// Only whole lines; a partial last line is left unread
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		return 0, nil, nil
+	})
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		complete += int64(len(scanner.Bytes())) + 1
+		var r WhoisResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("checkpoint: %s line %d: %v", path, lineNo, err)
+		}
+		r.Resumed = true
+		done[r.Domain] = &r
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	if err := f.Truncate(complete); err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	if _, err := f.Seek(complete, 0); err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	return done, f, nil
+}
+
+// appendCheckpoint writes a result as one line and syncs it to disk, so it
+// survives a crash or power loss
+func appendCheckpoint(f *os.File, r *WhoisResult) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	return f.Sync()
+}
@@ -0,0 +1,141 @@
+// Package bind sends outgoing connections from a chosen local address or
+// network interface, for multi-homed hosts and for looking at split-horizon
+// DNS from a specific source network.
+package bind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// Source is the local address of outgoing connections: one per address
+// family, so both IPv4 and IPv6 destinations can be reached from an
+// interface with addresses of both
+type Source struct {
+	IPv4 netip.Addr
+	IPv6 netip.Addr
+	// Name is the IP or interface the source was parsed from
+	Name string
+}
+
+// Parse returns the source for a local IP or, when ip is empty, for the
+// addresses of the network interface iface. Both empty returns nil,
+// meaning the system picks the source address.
+func Parse(ip, iface string) (*Source, error) {
+	if ip != "" && iface != "" {
+		return nil, fmt.Errorf("source: give either an IP or an interface, not both")
+	}
+	if ip != "" {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return nil, fmt.Errorf("source: %q is not an IP address", ip)
+		}
+		s := &Source{Name: ip}
+		s.add(addr)
+		return s, nil
+	}
+	if iface == "" {
+		return nil, nil
+	}
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("source: %s: %w", iface, err)
+	}
+	s := &Source{Name: iface}
+	for _, a := range addrs {
+		if prefix, err := netip.ParsePrefix(a.String()); err == nil {
+			s.add(prefix.Addr())
+		}
+	}
+	if !s.IPv4.IsValid() && !s.IPv6.IsValid() {
+		return nil, fmt.Errorf("source: %s has no usable address", iface)
+	}
+	return s, nil
+}
+
+// add keeps the first usable address of each family; link-local IPv6
+// addresses only reach the local link
+func (s *Source) add(addr netip.Addr) {
+	addr = addr.Unmap()
+	switch {
+	case addr.Is4() && !s.IPv4.IsValid():
+		s.IPv4 = addr
+	case addr.Is6() && !addr.IsLinkLocalUnicast() && !s.IPv6.IsValid():
+		s.IPv6 = addr
+	}
+}
+
+// String returns the IP or interface of the source
+func (s *Source) String() string {
+	return s.Name
+}
+
+// LocalAddr returns the local address for connecting to addr over network
+// ("udp" or "tcp", possibly with a "4" or "6" suffix): the source's address
+// of addr's family, or of either family when addr is a host name. It
+// returns nil for a nil Source, letting the system choose, and fails when
+// the source has no address of the family rather than sending from another.
+func (s *Source) LocalAddr(network, addr string) (net.Addr, error) {
+	if s == nil {
+		return nil, nil
+	}
+	local := s.IPv4
+	if !local.IsValid() {
+		local = s.IPv6
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if remote, err := netip.ParseAddr(host); err == nil {
+		family := "IPv4"
+		local = s.IPv4
+		if remote.Unmap().Is6() {
+			family = "IPv6"
+			local = s.IPv6
+		}
+		if !local.IsValid() {
+			return nil, fmt.Errorf("source %s has no %s address to reach %s from", s.Name, family, addr)
+		}
+	}
+	switch network {
+	case "udp", "udp4", "udp6":
+		return net.UDPAddrFromAddrPort(netip.AddrPortFrom(local, 0)), nil
+	default:
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(local, 0)), nil
+	}
+}
+
+// Dialer connects from a source
+type Dialer struct {
+	Source  *Source
+	Timeout time.Duration
+}
+
+// Dialer returns a dialer connecting from the source with timeout. A nil
+// Source returns a dialer leaving the choice to the system.
+func (s *Source) Dialer(timeout time.Duration) *Dialer {
+	return &Dialer{Source: s, Timeout: timeout}
+}
+
+// Dial connects to addr from the source
+func (d *Dialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext connects to addr from the source
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	local, err := d.Source.LocalAddr(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	nd := &net.Dialer{Timeout: d.Timeout, LocalAddr: local}
+	return nd.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,313 @@
+// Package config loads the dnscrawler configuration file.
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the top-level structure of the configuration file
+type Config struct {
+	Plugins []Plugin `yaml:"plugins"`
+
+	// APIKeys holds credentials for external services, keyed by service name
+	// (e.g. securitytrails, virustotal, shodan)
+	APIKeys map[string]string `yaml:"api_keys"`
+
+	ReverseIP  ReverseIP  `yaml:"reverse_ip"`
+	PassiveDNS PassiveDNS `yaml:"passive_dns"`
+	Exposure   Exposure   `yaml:"exposure"`
+	Web        Web        `yaml:"web"`
+	// CT configures the certificate transparency watch of groups with ct
+	CT CT `yaml:"ct"`
+
+	// Notifiers are named alert sinks referenced by groups
+	Notifiers map[string]Notifier `yaml:"notifiers"`
+	// Groups are the sets of domains checked by `monitor`
+	Groups []Group `yaml:"groups"`
+	// State selects where snapshots and notifier queues are stored
+	State State `yaml:"state"`
+	// Alerts sets thresholds and severities for all groups
+	Alerts Alerts `yaml:"alerts"`
+	// SMTP is the mail server used by email notifiers
+	SMTP SMTP `yaml:"smtp"`
+	// Serve configures the API server of `serve`
+	Serve Serve `yaml:"serve"`
+	// Exporters receive every result crawled by `monitor` and `daemon`
+	Exporters []Exporter `yaml:"exporters"`
+	// Telemetry exports traces and metrics of `serve`, `monitor` and `daemon`
+	Telemetry Telemetry `yaml:"telemetry"`
+	// Suffixes adds registrable boundaries to the Public Suffix List
+	Suffixes Suffixes `yaml:"suffixes"`
+	// Proxy is the SOCKS5 or HTTP proxy outbound connections go through,
+	// e.g. socks5h://127.0.0.1:9050; --proxy overrides it
+	Proxy string `yaml:"proxy"`
+	// SourceIP is the local address outbound connections are sent from, on
+	// hosts with several; --source-ip overrides it
+	SourceIP string `yaml:"source_ip"`
+	// Interface is the network interface outbound connections are sent
+	// from, instead of SourceIP; --interface overrides it
+	Interface string `yaml:"interface"`
+	// Resolvers are the recursive resolvers queried instead of 8.8.8.8, as
+	// host[:port][#tls-name]; --resolver overrides them
+	Resolvers []string `yaml:"resolvers"`
+	// ResolverStrategy picks the resolver of each query: round-robin (the
+	// default), fastest or random; --resolver-strategy overrides it
+	ResolverStrategy string `yaml:"resolver_strategy"`
+	// MaxQPS caps the DNS, WHOIS and HTTP queries per second of the whole
+	// process; --max-qps overrides it
+	MaxQPS float64 `yaml:"max_qps"`
+}
+
+// Suffixes extends the Public Suffix List used to find a name's root domain
+type Suffixes struct {
+	// Custom are organization-internal suffixes, e.g. internal.corp.example:
+	// every name directly below one is a domain of its own. "*.name" and
+	// "!name" rules work as in the list.
+	Custom []string `yaml:"custom"`
+	// Private also applies the list's private section (github.io, ...)
+	Private bool `yaml:"private"`
+}
+
+// Telemetry configures OpenTelemetry export over OTLP. Unset fields fall back
+// to the standard OTEL_EXPORTER_OTLP_* environment variables.
+type Telemetry struct {
+	// Endpoint is the collector, e.g. localhost:4317 (grpc) or
+	// http://localhost:4318 (http); setting it enables export
+	Endpoint    string            `yaml:"endpoint"`
+	Protocol    string            `yaml:"protocol"` // grpc (default) or http
+	Insecure    bool              `yaml:"insecure"` // plain-text connection to the collector
+	Headers     map[string]string `yaml:"headers"`  // sent with every export, e.g. an APM token
+	ServiceName string            `yaml:"service_name"`
+	// Interval is how often metrics are exported; default 1m
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Exporter ships crawl results to an external system
+type Exporter struct {
+	Type string `yaml:"type"` // elasticsearch (also OpenSearch), kafka or nats
+	URL  string `yaml:"url"`  // Elasticsearch or NATS server URL
+
+	// Brokers are the Kafka bootstrap servers
+	Brokers []string `yaml:"brokers"`
+	// Topic receives results (Kafka topic or NATS subject); default dnscrawler.results
+	Topic string `yaml:"topic"`
+	// EventsTopic receives alerts; default dnscrawler.events
+	EventsTopic string `yaml:"events_topic"`
+	// SASL is the Kafka SASL mechanism used with Username and Password:
+	// plain, scram-sha-256 or scram-sha-512
+	SASL string `yaml:"sasl"`
+	TLS  bool   `yaml:"tls"` // connect to Kafka over TLS
+
+	// Index receives the Elasticsearch documents; default dnscrawler-results
+	Index    string `yaml:"index"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	APIKey   string `yaml:"api_key"`  // Elasticsearch API key, instead of username/password
+	Insecure bool   `yaml:"insecure"` // skip TLS certificate verification
+}
+
+// Serve holds settings of the API server
+type Serve struct {
+	// Keys are the API keys accepted by the server; without keys the API is open
+	Keys []ServeKey `yaml:"keys"`
+}
+
+// ServeKey is an API key and what it grants
+type ServeKey struct {
+	Name   string   `yaml:"name"`   // identifies the client in logs and rate limits
+	Key    string   `yaml:"key"`    // sent as "Authorization: Bearer <key>"
+	Scopes []string `yaml:"scopes"` // read (dashboard data) and/or crawl (trigger crawls)
+}
+
+// Notifier is an alert sink
+type Notifier struct {
+	Type string `yaml:"type"` // slack, teams, discord or email
+	URL  string `yaml:"url"`  // incoming webhook URL
+
+	// To lists the recipients of an email notifier
+	To []string `yaml:"to"`
+	// Digest collects alerts and sends them at most once per interval; zero sends immediately
+	Digest time.Duration `yaml:"digest"`
+}
+
+// State selects the backend for monitoring state
+type State struct {
+	Backend string `yaml:"backend"` // file (default), sqlite, redis or s3
+	Path    string `yaml:"path"`    // directory (file) or database file (sqlite)
+	URL     string `yaml:"url"`     // redis://[user:password@]host:port/db
+	Prefix  string `yaml:"prefix"`  // key prefix (redis, s3)
+
+	Bucket    string `yaml:"bucket"`
+	Endpoint  string `yaml:"endpoint"` // default s3.amazonaws.com
+	Region    string `yaml:"region"`
+	AccessKey string `yaml:"access_key"` // default: AWS environment, credentials file or instance role
+	SecretKey string `yaml:"secret_key"`
+	Insecure  bool   `yaml:"insecure"` // plain HTTP, e.g. for a local MinIO
+}
+
+// SMTP holds mail server settings
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // default 587, or 465 with tls
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	// TLS connects with implicit TLS; otherwise STARTTLS is used when offered
+	TLS bool `yaml:"tls"`
+}
+
+// Group is a set of monitored domains sharing checks and notifiers
+type Group struct {
+	Name    string   `yaml:"name"`
+	Domains []string `yaml:"domains"`
+	Notify  []string `yaml:"notify"` // names of entries in Notifiers
+	TLS     bool     `yaml:"tls"`    // also watch the HTTPS certificate
+	// HighValue marks domains that must have a transfer and a registry
+	// lock; a domain lacking one raises a lock alert
+	HighValue bool `yaml:"high_value"`
+	// CT watches certificate transparency logs for new certificates issued
+	// for the domains and their subdomains
+	CT bool `yaml:"ct"`
+
+	// Schedule is a cron expression or descriptor (e.g. "0 6 * * *", "@every 30m")
+	// used by the daemon; default @hourly
+	Schedule string `yaml:"schedule"`
+	// Policy is a policy file whose failing rules raise alerts
+	Policy string `yaml:"policy"`
+	// Routes send alerts of at least a severity to additional notifiers
+	Routes []Route `yaml:"routes"`
+	// Alerts overrides the global thresholds and severities for this group
+	Alerts Alerts `yaml:"alerts"`
+}
+
+// Route sends alerts of at least Severity to the listed notifiers
+type Route struct {
+	Severity string   `yaml:"severity"` // info, warning or critical
+	Notify   []string `yaml:"notify"`
+}
+
+// Alerts configures when alerts are raised and how severe they are.
+// Zero values keep the defaults.
+type Alerts struct {
+	DomainExpiry Threshold `yaml:"domain_expiry"`
+	CertExpiry   Threshold `yaml:"cert_expiry"`
+	// Severities maps alert kinds (change, health, policy, lock, caa, ct) to
+	// a severity, or "none" to mute the kind
+	Severities map[string]string `yaml:"severities"`
+}
+
+// Threshold holds the remaining days below which an expiry alert is raised
+type Threshold struct {
+	Warning  int `yaml:"warning" json:"warning"`
+	Critical int `yaml:"critical" json:"critical"`
+}
+
+// Merge returns a copy of a with the non-zero settings of b applied on top
+func (a Alerts) Merge(b Alerts) Alerts {
+	if b.DomainExpiry.Warning != 0 {
+		a.DomainExpiry.Warning = b.DomainExpiry.Warning
+	}
+	if b.DomainExpiry.Critical != 0 {
+		a.DomainExpiry.Critical = b.DomainExpiry.Critical
+	}
+	if b.CertExpiry.Warning != 0 {
+		a.CertExpiry.Warning = b.CertExpiry.Warning
+	}
+	if b.CertExpiry.Critical != 0 {
+		a.CertExpiry.Critical = b.CertExpiry.Critical
+	}
+	severities := make(map[string]string, len(a.Severities)+len(b.Severities))
+	for k, v := range a.Severities {
+		severities[k] = v
+	}
+	for k, v := range b.Severities {
+		severities[k] = v
+	}
+	a.Severities = severities
+	return a
+}
+
+// Exposure selects the data source for open port lookups
+type Exposure struct {
+	Source string `yaml:"source"` // shodan, censys or internetdb (default: shodan with a key, else internetdb)
+}
+
+// CT selects the certificate transparency source and the certificate
+// authorities expected to issue for monitored domains
+type CT struct {
+	Source string `yaml:"source"` // certspotter (default) or crtsh
+	// Issuers are the expected CAs, matched case-insensitively against the
+	// issuer name, e.g. "Let's Encrypt". When set, only certificates from
+	// other CAs alert; otherwise every new certificate does.
+	Issuers []string `yaml:"issuers"`
+}
+
+// Web configures the website checks of --web
+type Web struct {
+	// WellKnown are the /.well-known paths looked for, e.g. security.txt or
+	// matrix/server; default security.txt, mta-sts.txt, matrix/server,
+	// apple-app-site-association and openid-configuration
+	WellKnown []string `yaml:"well_known"`
+}
+
+// PassiveDNS selects the passive DNS providers queried by `history dns`.
+// When empty, every provider with a configured API key is used.
+type PassiveDNS struct {
+	Sources []string `yaml:"sources"`
+}
+
+// ReverseIP selects the data source for reverse-IP lookups
+type ReverseIP struct {
+	Source string `yaml:"source"` // hackertarget (default) or securitytrails
+}
+
+// Plugin is an external command that adds a custom section to the report.
+// It receives the crawl result as JSON on stdin and prints a section as JSON on stdout.
+type Plugin struct {
+	Name    string        `yaml:"name"`
+	Command string        `yaml:"command"`
+	Args    []string      `yaml:"args"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// DefaultPath returns the location of the configuration file when --config is not given
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dnscrawler", "config.yaml")
+}
+
+// Load reads the configuration file at path. If path is empty the default
+// location is used, and a missing default file yields an empty configuration.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
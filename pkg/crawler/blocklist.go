@@ -0,0 +1,37 @@
+package crawler
+
+import "github.com/auduny/dnscrawler/pkg/dns"
+
+// BlocklistSection holds the domain's status on DNS-based domain blocklists
+type BlocklistSection struct {
+	Status
+	// Listed is set when any blocklist lists the domain
+	Listed bool             `json:"listed"`
+	Lists  []BlocklistEntry `json:"lists"`
+}
+
+// BlocklistEntry is one blocklist's answer, or the error it returned
+type BlocklistEntry struct {
+	*dns.Listing
+	Name  string `json:"name"`
+	Zone  string `json:"zone"`
+	Error string `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlBlocklists(name string) *BlocklistSection {
+	section := &BlocklistSection{}
+	for _, bl := range dns.DomainBlocklists {
+		entry := BlocklistEntry{Name: bl.Name, Zone: bl.Zone}
+		listing, err := observe(c, name, "blocklist", bl.Zone, func() (*dns.Listing, error) {
+			return c.Resolver.CheckBlocklist(name, bl)
+		})
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Listing = listing
+			section.Listed = section.Listed || listing.Listed
+		}
+		section.Lists = append(section.Lists, entry)
+	}
+	return section
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
+)
+
+// certificateAuthority is a CA as named in certificates and in CAA records
+type certificateAuthority struct {
+	issuer  *regexp.Regexp // on the issuer organization or common name
+	domains []string       // the issuer domains CAA records name it by
+}
+
+var certificateAuthorities = []certificateAuthority{
+	{regexp.MustCompile(`(?i)let's encrypt|\bISRG\b`), []string{"letsencrypt.org"}},
+	{regexp.MustCompile(`(?i)google trust services`), []string{"pki.goog", "google.com"}},
+	{regexp.MustCompile(`(?i)digicert|geotrust|rapidssl|thawte|symantec|encryption everywhere`), []string{"digicert.com", "geotrust.com", "rapidssl.com", "thawte.com", "symantec.com", "digicert.ne.jp"}},
+	{regexp.MustCompile(`(?i)sectigo|comodo|usertrust`), []string{"sectigo.com", "comodoca.com", "comodo.com", "usertrust.com", "trust-provider.com"}},
+	{regexp.MustCompile(`(?i)zerossl`), []string{"sectigo.com", "zerossl.com"}},
+	{regexp.MustCompile(`(?i)amazon`), []string{"amazon.com", "amazontrust.com", "awstrust.com", "amazonaws.com"}},
+	{regexp.MustCompile(`(?i)microsoft`), []string{"microsoft.com", "digicert.com"}},
+	{regexp.MustCompile(`(?i)globalsign`), []string{"globalsign.com"}},
+	{regexp.MustCompile(`(?i)entrust`), []string{"entrust.net", "affirmtrust.com"}},
+	{regexp.MustCompile(`(?i)godaddy|starfield`), []string{"godaddy.com", "starfieldtech.com"}},
+	{regexp.MustCompile(`(?i)buypass`), []string{"buypass.com", "buypass.no"}},
+	{regexp.MustCompile(`(?i)\bssl\.com\b|ssl corporation`), []string{"ssl.com"}},
+	{regexp.MustCompile(`(?i)certum|asseco|unizeto`), []string{"certum.pl", "certum.eu"}},
+	{regexp.MustCompile(`(?i)harica|hellenic academic`), []string{"harica.gr"}},
+}
+
+// crossCheck compares the CAs the records permit to issue for name with the
+// issuer of the certificate served on it. CAA is only checked at issuance,
+// so a mismatch is either a certificate issued before the records changed,
+// which won't renew, or records missing a CA in use.
+func (s *CAASection) crossCheck(name string, info *tlsprobe.Info) {
+	if s.Failed() || info == nil || !info.Valid && !info.MissingIntermediates {
+		return
+	}
+	s.Issuer = info.Issuer
+
+	// Wildcard certificates fall under issuewild when there are any
+	tag := "issue"
+	_, parent, _ := strings.Cut(name, ".")
+	if !slices.Contains(info.DNSNames, name) && slices.Contains(info.DNSNames, "*."+parent) {
+		tag = "issuewild"
+		if !slices.ContainsFunc(s.Records, func(rec string) bool { t, _ := parseCAA(rec); return t == tag }) {
+			tag = "issue"
+		}
+	}
+	restricted := false
+	for _, rec := range s.Records {
+		t, value := parseCAA(rec)
+		if t != tag {
+			continue
+		}
+		restricted = true
+		ca, _, _ := strings.Cut(value, ";")
+		if ca = strings.ToLower(strings.TrimSpace(ca)); ca != "" && !slices.Contains(s.Permitted, ca) {
+			s.Permitted = append(s.Permitted, ca)
+		}
+	}
+	// Without issue records, any CA may issue
+	if !restricted {
+		return
+	}
+
+	i := slices.IndexFunc(certificateAuthorities, func(ca certificateAuthority) bool { return ca.issuer.MatchString(info.Issuer) })
+	if i < 0 {
+		s.Issues = append(s.Issues, fmt.Sprintf("can't tell whether the CAA records permit %s, the issuer of the served certificate", info.Issuer))
+		return
+	}
+	permitted := slices.ContainsFunc(certificateAuthorities[i].domains, func(d string) bool { return slices.Contains(s.Permitted, d) })
+	s.IssuerPermitted = &permitted
+	if !permitted {
+		allowed := "forbid issuance"
+		if len(s.Permitted) > 0 {
+			allowed = "only permit " + strings.Join(s.Permitted, ", ")
+		}
+		s.Issues = append(s.Issues, fmt.Sprintf("the served certificate is issued by %s, but the CAA records (%s) %s: renewals will fail, or the records miss a CA in use", info.Issuer, tag, allowed))
+	}
+}
+
+// parseCAA splits a record as LookupCAA formats it, `flag tag "value"`
+func parseCAA(rec string) (tag, value string) {
+	fields := strings.SplitN(rec, " ", 3)
+	if len(fields) < 3 {
+		return "", ""
+	}
+	value, err := strconv.Unquote(fields[2])
+	if err != nil {
+		value = fields[2]
+	}
+	return strings.ToLower(fields[1]), value
+}
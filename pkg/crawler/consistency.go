@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"strings"
+)
+
+// consistencyTypes are the record types compared across authoritative servers
+var consistencyTypes = []string{"A", "AAAA", "MX", "TXT"}
+
+// ConsistencySection compares the answers each authoritative server gives
+type ConsistencySection struct {
+	Status
+	Consistent bool              `json:"consistent"`
+	Types      []TypeConsistency `json:"types"`
+}
+
+// TypeConsistency holds the per-server answers for one record type
+type TypeConsistency struct {
+	Type string `json:"type"`
+	// Majority is the answer returned by most servers
+	Majority []string            `json:"majority"`
+	Servers  []ConsistencyServer `json:"servers"`
+}
+
+// ConsistencyServer is one server's answer for a record type
+type ConsistencyServer struct {
+	Name    string   `json:"name"`
+	IP      string   `json:"ip"`
+	Answer  []string `json:"answer,omitempty"`
+	Differs bool     `json:"differs"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlConsistency(name string, nameservers *NameserverSection) *ConsistencySection {
+	section := &ConsistencySection{Consistent: true}
+	addrs := c.nameserverAddrs(name, nameservers)
+
+	for _, qtype := range consistencyTypes {
+		tc := TypeConsistency{Type: qtype}
+		votes := make(map[string]int)
+
+		for _, addr := range addrs {
+			if addr.IP == "" {
+				continue
+			}
+			server := ConsistencyServer{Name: addr.Name, IP: addr.IP}
+			answer, err := observe(c, name, "rrset", qtype+" "+name+"@"+addr.IP, func() ([]string, error) {
+				return c.Resolver.QueryRRset(name, qtype, addr.IP)
+			})
+			if err != nil {
+				server.Error = err.Error()
+			} else {
+				server.Answer = answer
+				votes[answerKey(answer)]++
+			}
+			tc.Servers = append(tc.Servers, server)
+		}
+
+		majority, best := "", -1
+		for key, n := range votes {
+			// Break ties deterministically so output is stable
+			if n > best || (n == best && key < majority) {
+				majority, best = key, n
+			}
+		}
+
+		for i := range tc.Servers {
+			s := &tc.Servers[i]
+			if s.Error != "" || answerKey(s.Answer) != majority {
+				s.Differs = true
+				section.Consistent = false
+			}
+			if !s.Differs && tc.Majority == nil {
+				tc.Majority = s.Answer
+			}
+		}
+		section.Types = append(section.Types, tc)
+	}
+
+	return section
+}
+
+func answerKey(answer []string) string {
+	return strings.Join(answer, "\n")
+}
@@ -0,0 +1,772 @@
+// Package crawler orchestrates the individual lookups for a domain and
+// aggregates them into a single Result.
+package crawler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/intel"
+	"github.com/auduny/dnscrawler/pkg/parking"
+	"github.com/auduny/dnscrawler/pkg/provider"
+	"github.com/auduny/dnscrawler/pkg/rdap"
+	"github.com/auduny/dnscrawler/pkg/reach"
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
+	"github.com/auduny/dnscrawler/pkg/whois"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options selects which sections of the crawl are run
+type Options struct {
+	NoWhois     bool
+	NoTrace     bool
+	NoASN       bool // skip ASN lookups of nameserver and record IPs
+	NoPTR       bool // skip reverse DNS of A/AAAA records
+	TLS         bool
+	OCSP        bool // ask the CA's OCSP responder whether the certificate is revoked
+	TLSScan     bool // test the TLS versions and ciphers each address accepts
+	JARM        bool // fingerprint the TLS endpoints with JARM
+	Web         bool // fetch the website of the domain and its www name
+	IPv6        bool // check that the AAAA addresses accept connections
+	WWW         bool // compare a registrable domain with its www name
+	Deps        bool // walk the resolution dependency graph
+	SOA         bool // compare SOA serials across authoritative servers
+	Recursion   bool // test authoritative servers for open recursion
+	Consistency bool // compare answers across authoritative servers
+	NXDomain    bool // check how nonexistent names are answered
+	Parking     bool // look for the traces of a parked or for-sale domain
+	ReverseIP   bool // list other domains hosted on the same IPs
+	ThreatIntel bool // query threat-intelligence sources
+	Exposure    bool // look up open ports and services of resolved IPs
+	Reputation  bool // check safe-browsing and phishing lists
+	Blocklists  bool // check domain blocklists (Spamhaus DBL, SURBL, URIBL)
+	DNSSEC      bool // check DS/DNSKEY records and validation
+	CAA         bool // look up CAA records
+	OrgLookup   bool // look up the registrant's organization number in its country's business registry
+
+	// NewDomainDays is the age in days below which a domain is flagged as
+	// newly registered; zero means DefaultNewDomainDays
+	NewDomainDays int
+	// RecordTypes are the types of the records section; nil means
+	// dns.DefaultRecordTypes
+	RecordTypes []string
+	// Sort is the order of the records and nameservers: SortName (the
+	// default) or SortProvider
+	Sort string
+
+	// Only and Skip select sections by name (see Sections and Runs),
+	// overriding the options above
+	Only []string
+	Skip []string
+}
+
+// DefaultNewDomainDays is the default newly-registered window
+const DefaultNewDomainDays = 30
+
+// Crawler runs lookups for a domain. The exported fields may be replaced
+// after New to customize how individual lookups are performed.
+type Crawler struct {
+	Resolver  *dns.Resolver
+	Whois     *whois.Client
+	TLS       *tlsprobe.Prober
+	HTTP      *httpprobe.Prober
+	Reach     *reach.Checker
+	Providers *provider.Matcher // nameserver hostnames
+	Infra     *provider.Matcher // PTR names and CNAME targets
+	Mail      *provider.Matcher // MX hostnames
+	Options   Options
+	Plugins   []Plugin
+
+	// ReverseIP lists co-hosted domains; required when Options.ReverseIP is set
+	ReverseIP intel.ReverseIPSource
+	// Threat lists the sources queried when Options.ThreatIntel is set
+	Threat []intel.ThreatSource
+	// Exposure looks up open ports; required when Options.Exposure is set
+	Exposure intel.ExposureSource
+	// Reputation lists the sources checked when Options.Reputation is set
+	Reputation []intel.ReputationSource
+	// OrgRegistry returns the business registry for a TLD's organization
+	// numbers; used when Options.OrgLookup is set
+	OrgRegistry func(tld string) (intel.OrganizationRegistry, bool)
+	// RDAP is asked for the registrant's organization number when WHOIS
+	// doesn't publish it, as Norid's doesn't; used with OrgRegistry
+	RDAP *rdap.Client
+
+	// MaxTime bounds a whole crawl, the root context and plugins included;
+	// zero means no limit. Lookups due after the deadline are not started
+	// and fail with context.DeadlineExceeded.
+	MaxTime time.Duration
+
+	hooks []Hooks
+	ctx   context.Context // parent of the lookup spans, set per crawl
+}
+
+// New creates a Crawler with default clients and the built-in provider patterns
+func New(opts Options) *Crawler {
+	return &Crawler{
+		Resolver:  dns.NewResolver(),
+		Whois:     whois.NewClient(),
+		TLS:       tlsprobe.NewProber(),
+		HTTP:      httpprobe.NewProber(),
+		Reach:     reach.NewChecker(),
+		Providers: provider.NewMatcher(),
+		Infra:     provider.NewInfraMatcher(),
+		Mail:      provider.NewMailMatcher(),
+		Options:   opts,
+	}
+}
+
+// Crawl collects all enabled sections for a domain. When given a subdomain,
+// the registrable domain is crawled as well and attached as Result.Root.
+// The name may be in FQDN form or mixed case; Result.Domain is canonical
+// (see domain.Canonical).
+func (c *Crawler) Crawl(name string) *Result {
+	return c.CrawlContext(context.Background(), name)
+}
+
+// CrawlContext is like Crawl, recording the crawl as a span under the one in
+// ctx. Once ctx is done, the remaining lookups fail with its error.
+func (c *Crawler) CrawlContext(ctx context.Context, name string) *Result {
+	name = domain.Canonical(name)
+	if c.MaxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.MaxTime)
+		defer cancel()
+	}
+	ctx, span := tracer.Start(ctx, "crawl", trace.WithAttributes(attrDomain.String(name)))
+	defer span.End()
+
+	c = c.withContext(ctx)
+
+	var root *Result
+	if domain.IsSubdomain(name) {
+		root = c.crawl(domain.GetRootDomain(name), true)
+	}
+
+	result := c.crawl(name, false)
+	result.Root = root
+	result.Spoofing = domain.CheckSpoofing(name)
+	c.RunPlugins(result)
+	span.SetAttributes(attrRegistered.Bool(result.Registered))
+	return result
+}
+
+func (c *Crawler) crawl(name string, isRootContext bool) *Result {
+	ctx, span := tracer.Start(c.context(), "crawl.domain", trace.WithAttributes(
+		attrDomain.String(name),
+		attrRootContext.Bool(isRootContext),
+	))
+	defer span.End()
+	c = c.withContext(ctx)
+
+	result := &Result{Domain: name}
+
+	exists, err := observe(c, name, "exists", name, func() (bool, error) {
+		return c.Resolver.Exists(name), nil
+	})
+	// Like the resolver on network errors, assume the domain exists when
+	// the check didn't run, so each section reports the error
+	if err == nil && !exists {
+		if c.Options.Runs(SectionWhois) {
+			result.Availability = c.crawlAvailability(name)
+		}
+		return result
+	}
+	result.Registered = true
+
+	var asn *ASNSection
+	if c.Options.Runs(SectionASN) {
+		asn = &ASNSection{IPs: make(map[string]*dns.ASNInfo)}
+	}
+
+	if c.Options.Runs(SectionWhois) {
+		whoisSection, registered := c.crawlWhois(name)
+		// The registry knows best: a domain it reports as free isn't
+		// registered, whatever DNS answered
+		if !registered {
+			result.Registered = false
+			return result
+		}
+		result.Whois = whoisSection
+	}
+
+	if c.Options.needsNameservers() {
+		result.Nameservers = c.crawlNameservers(name, asn)
+	}
+
+	// DNSSEC is a property of the zone, so subdomains rely on the root context
+	if c.Options.Runs(SectionDNSSEC) && !domain.IsSubdomain(name) {
+		result.DNSSEC = c.crawlDNSSEC(name)
+	}
+
+	if c.Options.Runs(SectionSOA) && len(result.Nameservers.Servers) > 0 {
+		result.SOA = c.crawlSOA(name, result.Nameservers)
+	}
+
+	if c.Options.Runs(SectionRecursion) && len(result.Nameservers.Servers) > 0 {
+		result.Recursion = c.crawlRecursion(name, result.Nameservers)
+	}
+
+	if c.Options.Runs(SectionConsistency) && len(result.Nameservers.Servers) > 0 {
+		result.Consistency = c.crawlConsistency(name, result.Nameservers)
+	}
+
+	// Trace is skipped for the root context to reduce noise
+	if c.Options.Runs(SectionTrace) && !isRootContext {
+		result.Trace = c.crawlTrace(name)
+	}
+
+	if c.Options.needsRecords() {
+		result.Records = c.crawlRecords(name, asn)
+	}
+
+	if c.Options.Runs(SectionReverseIP) && !isRootContext {
+		result.ReverseIP = c.crawlReverseIP(name, result.Records)
+	}
+
+	if c.Options.Runs(SectionExposure) && !isRootContext {
+		result.Exposure = c.crawlExposure(name, result.Records)
+	}
+
+	if c.Options.Runs(SectionIntel) && !isRootContext {
+		result.ThreatIntel = c.crawlThreatIntel(name)
+	}
+
+	if c.Options.Runs(SectionReputation) && !isRootContext {
+		result.Reputation = c.crawlReputation(name)
+	}
+
+	if c.Options.Runs(SectionBlocklists) {
+		result.Blocklists = c.crawlBlocklists(name)
+	}
+
+	if c.Options.Runs(SectionNXDomain) {
+		result.NXDomain = c.crawlNXDomain(name)
+	}
+	result.ASN = asn
+
+	if c.Options.Runs(SectionDeps) && !isRootContext {
+		result.Dependencies = c.crawlDependencies(name, result.Nameservers, result.Records.resolved())
+	}
+	if c.Options.Runs(SectionEmail) {
+		result.Email = c.crawlEmail(name, result.Records.resolved())
+	}
+
+	if c.Options.Runs(SectionTLS) && !isRootContext {
+		result.TLS = c.crawlTLS(name, result.Records)
+	}
+
+	if c.Options.Runs(SectionWeb) && !isRootContext {
+		result.Web = c.crawlWeb(name)
+	}
+
+	if addrs := result.Records.IPv6(); c.Options.Runs(SectionIPv6) && !isRootContext && len(addrs) > 0 {
+		result.IPv6 = c.crawlIPv6(name, addrs)
+	}
+
+	if c.Options.Runs(SectionWWW) && !isRootContext && !domain.IsSubdomain(name) {
+		result.WWW = c.crawlWWW(name, result)
+	}
+
+	if c.Options.Runs(SectionParking) && !isRootContext {
+		result.Parking = c.crawlParking(name, result)
+	}
+
+	if c.Options.Runs(SectionCAA) && !isRootContext {
+		result.CAA = c.crawlCAA(name)
+		if result.TLS != nil && !result.TLS.Failed() {
+			result.CAA.crossCheck(name, result.TLS.Info)
+		}
+	}
+
+	// Nameservers and records looked up only for other sections aren't
+	// reported
+	if !c.Options.Runs(SectionNameservers) {
+		result.Nameservers = nil
+	}
+	if !c.Options.Runs(SectionRecords) {
+		result.Records = nil
+	}
+	return result
+}
+
+// crawlWhois looks up the domain's WHOIS data; registered is false when the
+// registry answered that the domain isn't registered
+func (c *Crawler) crawlWhois(name string) (section *WhoisSection, registered bool) {
+	info, err := observe(c, name, "whois", name, func() (*whois.Info, error) {
+		return c.Whois.Lookup(name)
+	})
+	if errors.Is(err, whois.ErrNotRegistered) {
+		return nil, false
+	}
+	if err != nil {
+		return &WhoisSection{Status: Status{Error: err.Error()}}, true
+	}
+
+	window := c.Options.NewDomainDays
+	if window <= 0 {
+		window = DefaultNewDomainDays
+	}
+	section = &WhoisSection{
+		Info:            info,
+		NewlyRegistered: info.AgeDays != nil && *info.AgeDays < window,
+	}
+	if c.Options.Runs(SectionOrg) {
+		section.Organization = c.crawlOrganization(name, info)
+	}
+	return section, true
+}
+
+// crawlOrganization looks up the registrant's organization number in the
+// business registry of the domain's country; nil when there is none or the
+// number isn't published. A number found over RDAP is added to info.
+func (c *Crawler) crawlOrganization(name string, info *whois.Info) *OrganizationSection {
+	if c.OrgRegistry == nil {
+		return nil
+	}
+	registry, ok := c.OrgRegistry(domain.TLD(name))
+	if !ok {
+		return nil
+	}
+	if info.RegistrantOrgNumber == "" && c.RDAP != nil {
+		obj, err := observe(c, name, "rdap", name, func() (*rdap.Object, error) {
+			return c.RDAP.Domain(name)
+		})
+		if err == nil {
+			info.RegistrantOrgNumber = obj.RegistrantOrgNumber()
+		}
+	}
+	number := info.RegistrantOrgNumber
+	if number == "" {
+		return nil
+	}
+	section := &OrganizationSection{Registry: registry.Name()}
+	org, err := observe(c, name, "organization", number, func() (*intel.Organization, error) {
+		return registry.Organization(number)
+	})
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	section.Organization = org
+	return section
+}
+
+// crawlAvailability asks the registry's availability service about a domain
+// that isn't in DNS, which may still be registered; nil when the registry
+// has no such service or it fails
+func (c *Crawler) crawlAvailability(name string) *whois.Availability {
+	if _, ok := whois.ParserFor(domain.TLD(name)).(whois.AvailabilityChecker); !ok {
+		return nil
+	}
+	avail, err := observe(c, name, "availability", name, func() (*whois.Availability, error) {
+		return c.Whois.CheckAvailability(name)
+	})
+	if err != nil {
+		return nil
+	}
+	return avail
+}
+
+func (c *Crawler) crawlNameservers(name string, asn *ASNSection) *NameserverSection {
+	nameservers, err := observe(c, name, "nameservers", name, func() ([]dns.Nameserver, error) {
+		return c.Resolver.GetNameservers(name)
+	})
+	if err != nil {
+		return &NameserverSection{Status: Status{Error: err.Error()}}
+	}
+
+	section := &NameserverSection{}
+	for _, ns := range nameservers {
+		section.Servers = append(section.Servers, Nameserver{
+			Nameserver: ns,
+			Provider:   c.Providers.Match(ns.Name),
+			ASN:        c.lookupASN(name, ns.IP, asn),
+		})
+	}
+	sortNameservers(section.Servers, c.Options.Sort)
+	return section
+}
+
+func (c *Crawler) crawlTrace(name string) *TraceSection {
+	steps, err := observe(c, name, "trace", name, func() ([]dns.TraceStep, error) {
+		return c.Resolver.TraceEach(name, func(step dns.TraceStep) {
+			progress(c, name, "trace", name, step)
+		})
+	})
+	if err != nil {
+		// A timed-out trace keeps the zones resolved before the deadline
+		return &TraceSection{Status: Status{Error: err.Error()}, Steps: steps}
+	}
+	return &TraceSection{Steps: steps}
+}
+
+func (c *Crawler) crawlRecords(name string, asn *ASNSection) *RecordsSection {
+	types := lookupTypes(c.Options)
+	records, err := observe(c, name, "records", name, func() (*dns.Records, error) {
+		return c.Resolver.GetRecords(name, types...)
+	})
+	server := c.Resolver.Authoritative()
+	if err != nil {
+		return &RecordsSection{Status: Status{Error: err.Error()}, Server: server}
+	}
+
+	section := &RecordsSection{Server: server}
+	// Records resolved only for the other sections aren't listed
+	list := func(qtype string, listed, unlisted *[]Record) *[]Record {
+		if hasType(recordTypes(c.Options), qtype) {
+			return listed
+		}
+		return unlisted
+	}
+	cname := list("CNAME", &section.CNAME, &section.unlisted.CNAME)
+	for _, value := range records.CNAME {
+		*cname = append(*cname, Record{Value: value, Provider: c.Infra.Match(value)})
+	}
+	a := list("A", &section.A, &section.unlisted.A)
+	for _, value := range records.A {
+		*a = append(*a, c.addressRecord(name, value, asn))
+	}
+	aaaa := list("AAAA", &section.AAAA, &section.unlisted.AAAA)
+	for _, value := range records.AAAA {
+		*aaaa = append(*aaaa, c.addressRecord(name, value, asn))
+	}
+	mx := list("MX", &section.MX, &section.unlisted.MX)
+	for _, value := range records.MX {
+		// MX format is "priority hostname" — match against the hostname part
+		*mx = append(*mx, Record{Value: value, Provider: c.Mail.Match(value)})
+	}
+	txt := list("TXT", &section.TXT, &section.unlisted.TXT)
+	for _, value := range records.TXT {
+		*txt = append(*txt, Record{Value: value})
+	}
+	for t, values := range records.Other {
+		if section.Other == nil {
+			section.Other = make(map[string][]Record)
+		}
+		for _, value := range values {
+			section.Other[t] = append(section.Other[t], Record{Value: value})
+		}
+	}
+	unlisted := section.unlisted
+	for _, recs := range [][]Record{section.CNAME, section.A, section.AAAA, section.MX, section.TXT,
+		unlisted.CNAME, unlisted.A, unlisted.AAAA, unlisted.MX, unlisted.TXT} {
+		sortRecords(recs, c.Options.Sort)
+	}
+	for _, recs := range section.Other {
+		sortRecords(recs, c.Options.Sort)
+	}
+	return section
+}
+
+// addressRecord enriches an A/AAAA value with reverse DNS, provider and ASN
+func (c *Crawler) addressRecord(name, ip string, asn *ASNSection) Record {
+	rec := Record{Value: ip}
+	var hostname string
+	if c.Options.Runs(SectionPTR) {
+		hostname, _ = observe(c, name, "ptr", ip, func() (string, error) {
+			return c.Resolver.ReverseLookup(ip), nil
+		})
+	}
+	if hostname != "" {
+		rec.PTR = hostname
+		if p := c.Infra.Match(hostname); p != "" {
+			rec.Provider = p
+		} else {
+			rec.Provider = strings.TrimRight(hostname, ".")
+		}
+	}
+	rec.ASN = c.lookupASN(name, ip, asn)
+	return rec
+}
+
+// lookupASN resolves the ASN for ip, recording it in the ASN section. A nil
+// section means ASN lookups are disabled.
+func (c *Crawler) lookupASN(name, ip string, asn *ASNSection) string {
+	if ip == "" || asn == nil {
+		return ""
+	}
+	info, ok := asn.IPs[ip]
+	if ok {
+		cached(c, name, "asn", ip, info)
+	} else {
+		info, _ = observe(c, name, "asn", ip, func() (*dns.ASNInfo, error) {
+			return c.Resolver.LookupASN(ip), nil
+		})
+		if info != nil {
+			asn.IPs[ip] = info
+		}
+	}
+	return info.Label()
+}
+
+// maxSPFRedirects bounds the redirect= chain followed, within the ten DNS
+// lookups SPF allows
+const maxSPFRedirects = 10
+
+func (c *Crawler) crawlEmail(name string, records *RecordsSection) *EmailSection {
+	section := &EmailSection{}
+	for _, txt := range records.TXT {
+		if strings.HasPrefix(strings.ToLower(txt.Value), "v=spf1") {
+			section.SPF = txt.Value
+			break
+		}
+	}
+	// Without an all mechanism the policy is the redirect= target's, which
+	// may redirect in turn
+	record := section.SPF
+	for range maxSPFRedirects {
+		all, target := spfTerms(record)
+		if all != "" || target == "" {
+			break
+		}
+		txts, _ := observe(c, name, "spf", target, func() ([]string, error) {
+			return c.Resolver.LookupTXT(target, domain.GetRootDomain(name)), nil
+		})
+		record = ""
+		for _, txt := range txts {
+			if strings.HasPrefix(strings.ToLower(txt), "v=spf1") {
+				record = txt
+				break
+			}
+		}
+		section.SPFRedirect = record
+	}
+	dmarc, _ := observe(c, name, "dmarc", "_dmarc."+name, func() ([]string, error) {
+		return c.Resolver.LookupTXT("_dmarc."+name, name), nil
+	})
+	for _, txt := range dmarc {
+		if strings.HasPrefix(strings.ToUpper(txt), "V=DMARC1") {
+			section.DMARC = txt
+			break
+		}
+	}
+	return section
+}
+
+// crawlWeb fetches the website of the domain and, for registrable
+// domains, of its www name, and looks for the domain's /.well-known files
+func (c *Crawler) crawlWeb(name string) *WebSection {
+	hosts := []string{name}
+	if !domain.IsSubdomain(name) {
+		hosts = append(hosts, "www."+name)
+	}
+	section := &WebSection{}
+	for _, host := range hosts {
+		ep, err := observe(c, name, "http", host, func() (*httpprobe.Endpoint, error) {
+			return c.HTTP.Probe(host)
+		})
+		site := WebEndpoint{Host: host, Endpoint: ep}
+		if err != nil {
+			site.Error = err.Error()
+		} else if host == name && !domain.IsSubdomain(name) {
+			section.HSTSPreload = c.HTTP.CheckPreload(name, ep)
+		}
+		section.Endpoints = append(section.Endpoints, site)
+	}
+	section.WellKnown, _ = observe(c, name, "http", "https://"+name+"/.well-known/", func() (*httpprobe.WellKnown, error) {
+		return c.HTTP.DiscoverWellKnown(name), nil
+	})
+	return section
+}
+
+// crawlParking looks for the traces of a parked domain in the sections
+// collected, and in what a nonexistent name below the domain resolves to,
+// as parking services answer for every name
+func (c *Crawler) crawlParking(name string, result *Result) *parking.Verdict {
+	var in parking.Input
+	if ns := result.Nameservers; ns != nil {
+		for _, server := range ns.Servers {
+			in.Nameservers = append(in.Nameservers, server.Name)
+		}
+	}
+	for _, rec := range result.Records.Addresses() {
+		in.Addresses = append(in.Addresses, rec.Value)
+	}
+	if records := result.Records.resolved(); records != nil {
+		for _, rec := range records.CNAME {
+			in.CNAMEs = append(in.CNAMEs, rec.Value)
+		}
+	}
+	switch {
+	case result.NXDomain != nil && result.NXDomain.NXProbe != nil:
+		in.Wildcard = result.NXDomain.Answers
+	case len(in.Addresses) > 0:
+		probe, err := observe(c, name, "nxdomain", name, func() (*dns.NXProbe, error) {
+			return c.Resolver.ProbeNonexistent(name)
+		})
+		if err == nil {
+			in.Wildcard = probe.Answers
+		}
+	}
+	if result.Web != nil {
+		for _, site := range result.Web.Endpoints {
+			if site.Host == name && site.Endpoint != nil {
+				in.Page = site.Body()
+			}
+		}
+	}
+	return parking.Detect(in)
+}
+
+// crawlIPv6 connects to every AAAA address, as a name whose AAAA records
+// point nowhere breaks for clients preferring IPv6
+func (c *Crawler) crawlIPv6(name string, records []Record) *IPv6Section {
+	section := &IPv6Section{}
+	for _, rec := range records {
+		r, err := observe(c, name, "connect", rec.Value, func() (*reach.Result, error) {
+			return c.Reach.Check(rec.Value), nil
+		})
+		if err != nil {
+			section.Error = err.Error()
+			break
+		}
+		section.Addresses = append(section.Addresses, r)
+	}
+	return section
+}
+
+// scanTLS tests the TLS versions and ciphers of each address of the name,
+// or of the name itself when its addresses aren't known
+func (c *Crawler) scanTLS(name string, records *RecordsSection) []TLSScan {
+	addrs := []string{name}
+	if known := records.Addresses(); len(known) > 0 {
+		addrs = nil
+		for _, rec := range known {
+			addrs = append(addrs, rec.Value)
+		}
+	}
+	var scans []TLSScan
+	for _, addr := range addrs {
+		scan := TLSScan{Address: addr}
+		var err error
+		scan.Scan, err = observe(c, name, "tls", addr+" scan", func() (*tlsprobe.Scan, error) {
+			return c.TLS.ScanAddr(name, addr)
+		})
+		if err != nil {
+			scan.Error = err.Error()
+		}
+		scans = append(scans, scan)
+	}
+	return scans
+}
+
+// sharedWith returns the registrable domains of a certificate's names other
+// than that of name, sorted
+func sharedWith(name string, dnsNames []string) []string {
+	own := domain.GetRootDomain(name)
+	var others []string
+	for _, n := range dnsNames {
+		registrable := domain.GetRootDomain(strings.TrimPrefix(n, "*."))
+		if registrable != own && !slices.Contains(others, registrable) {
+			others = append(others, registrable)
+		}
+	}
+	slices.Sort(others)
+	return others
+}
+
+// crawlTLS probes the certificate of the name and, when it resolves to
+// several addresses, the one served on each of them
+func (c *Crawler) crawlTLS(name string, records *RecordsSection) *TLSSection {
+	info, err := observe(c, name, "tls", name, func() (*tlsprobe.Info, error) {
+		return c.TLS.Probe(name)
+	})
+	if err != nil {
+		return &TLSSection{Status: Status{Error: err.Error()}}
+	}
+	if c.Options.Runs(SectionOCSP) && info.OCSP != nil {
+		info.OCSP.Responder, err = observe(c, name, "tls", name+" ocsp", func() (*tlsprobe.Revocation, error) {
+			return c.TLS.QueryOCSP(info)
+		})
+		if err != nil {
+			info.OCSP.ResponderError = err.Error()
+		}
+	}
+	section := &TLSSection{Info: info, ALPN: c.crawlALPN(name), SharedWith: sharedWith(name, info.DNSNames)}
+	if c.Options.Runs(SectionTLSScan) {
+		section.Scans = c.scanTLS(name, records)
+	}
+	if c.Options.Runs(SectionJARM) {
+		section.JARM = c.jarm(name, name)
+	}
+	addrs := records.Addresses()
+	if len(addrs) < 2 {
+		return section
+	}
+	for _, rec := range addrs {
+		ep := TLSEndpoint{IP: rec.Value, Provider: rec.Provider}
+		ep.Info, err = observe(c, name, "tls", rec.Value, func() (*tlsprobe.Info, error) {
+			return c.TLS.ProbeAddr(name, rec.Value)
+		})
+		if err != nil {
+			ep.Error = err.Error()
+		} else if c.Options.Runs(SectionJARM) {
+			ep.JARM = c.jarm(name, rec.Value)
+		}
+		section.Endpoints = append(section.Endpoints, ep)
+	}
+	section.Differences = endpointDifferences(name, info, section.Endpoints)
+	return section
+}
+
+// jarm fingerprints the TLS endpoint of name on addr, "" when it fails
+func (c *Crawler) jarm(name, addr string) string {
+	fingerprint, _ := observe(c, name, "tls", addr+" jarm", func() (string, error) {
+		return c.TLS.JARM(name, addr)
+	})
+	return fingerprint
+}
+
+// crawlALPN finds the protocols the HTTPS endpoint speaks and compares them
+// with the alpn hints of the domain's HTTPS records, which clients use to
+// go straight to HTTP/3
+func (c *Crawler) crawlALPN(name string) *ALPNSection {
+	alpn, err := observe(c, name, "tls", name+" alpn", func() (*tlsprobe.ALPN, error) {
+		return c.TLS.ProbeALPN(name)
+	})
+	if err != nil {
+		return &ALPNSection{Status: Status{Error: err.Error()}}
+	}
+	section := &ALPNSection{ALPN: alpn}
+	section.Records, err = observe(c, name, "https", name, func() ([]dns.HTTPSRecord, error) {
+		return c.Resolver.LookupHTTPS(name)
+	})
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+
+	// Hints of the records serving the name itself; the endpoint of other
+	// targets isn't probed
+	var hints []string
+	var own bool
+	for _, rec := range section.Records {
+		if !rec.Alias() && rec.Target == "." {
+			own = true
+			hints = append(hints, rec.ALPN...)
+		}
+	}
+	if !own {
+		if alpn.Speaks("h3") {
+			section.Mismatches = append(section.Mismatches, "speaks h3, but no HTTPS record advertises it; clients only find it through Alt-Svc")
+		}
+		return section
+	}
+	for _, protocol := range []string{"h3", "h2"} {
+		hinted := slices.Contains(hints, protocol)
+		switch {
+		case hinted && !alpn.Speaks(protocol) && !(protocol == "h3" && alpn.H3Unchecked):
+			section.Mismatches = append(section.Mismatches, "HTTPS record advertises "+protocol+", but the server doesn't negotiate it")
+		case !hinted && alpn.Speaks(protocol):
+			section.Mismatches = append(section.Mismatches, "speaks "+protocol+", but the HTTPS record doesn't advertise it")
+		}
+	}
+	return section
+}
@@ -0,0 +1,38 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// BenchmarkCrawl runs a full default crawl of www.example.com (the subdomain
+// and its root context) against the responses in testdata/replay, so it
+// measures the crawler's own overhead rather than the network
+func BenchmarkCrawl(b *testing.B) {
+	store, err := fixture.NewReplayer("testdata/replay")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bc := range []struct {
+		name string
+		opts Options
+	}{
+		{"default", Options{}},
+		{"summary", Options{NoTrace: true, NoASN: true, NoPTR: true}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			c := New(bc.opts)
+			c.Resolver = dns.NewResolver(dns.WithFixtures(store))
+			c.Whois = whois.NewClient(whois.WithFixtures(store))
+			for b.Loop() {
+				result := c.Crawl("www.example.com")
+				if result.Root == nil || result.Root.Whois.Failed() || len(result.Records.A) != 2 {
+					b.Fatalf("unexpected result: %+v", result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+)
+
+// maxDependencyDepth bounds how far nameserver zones are followed
+const maxDependencyDepth = 3
+
+// DependencySection lists the external zones a domain's resolution relies on
+type DependencySection struct {
+	Status
+	Dependencies []Dependency `json:"dependencies"`
+	// SinglePoints describes critical dependencies in plain language
+	SinglePoints []string `json:"single_points_of_failure,omitempty"`
+}
+
+// Dependency is an external zone reached through NS, CNAME or MX records
+type Dependency struct {
+	Zone     string   `json:"zone"`
+	Via      string   `json:"via"`  // ns, cname or mx
+	Path     []string `json:"path"` // zones from the crawled domain to this one
+	Provider string   `json:"provider,omitempty"`
+	// Critical is set when every path to a working answer goes through this zone
+	Critical bool `json:"critical"`
+	// Error is set when the nameservers of the zone couldn't be looked up,
+	// so the zones it depends on in turn are unknown
+	Error string `json:"error,omitempty"`
+}
+
+type dependencyWalker struct {
+	c       *Crawler
+	name    string
+	visited map[string]bool
+	section *DependencySection
+}
+
+func (c *Crawler) crawlDependencies(name string, nameservers *NameserverSection, records *RecordsSection) *DependencySection {
+	root := domain.GetRootDomain(name)
+	w := &dependencyWalker{
+		c:       c,
+		name:    name,
+		visited: map[string]bool{root: true},
+		section: &DependencySection{},
+	}
+
+	// A delegated subdomain has its own NS set; otherwise its zone is the root's
+	found, err := w.walk(name, []string{root}, true, 0)
+	if err == nil && found == 0 && name != root {
+		_, err = w.walk(root, []string{root}, true, 0)
+	}
+	if err != nil {
+		return &DependencySection{Status: Status{Error: err.Error()}}
+	}
+
+	for _, cname := range records.CNAME {
+		zone := domain.GetRootDomain(cname.Value)
+		if w.visited[zone] {
+			continue
+		}
+		w.add(Dependency{Zone: zone, Via: "cname", Path: []string{root, zone}, Provider: cname.Provider, Critical: true})
+		if _, err := w.walk(zone, []string{root, zone}, true, 1); err != nil {
+			w.fail(zone, err)
+		}
+	}
+
+	mxZones := make(map[string]int)
+	var mxOrder []string
+	for _, mx := range records.MX {
+		host := mx.Value
+		if idx := strings.LastIndex(host, " "); idx >= 0 {
+			host = host[idx+1:]
+		}
+		if host == "" || host == "." {
+			continue
+		}
+		zone := domain.GetRootDomain(host)
+		if zone == root {
+			continue
+		}
+		if mxZones[zone] == 0 {
+			mxOrder = append(mxOrder, zone)
+		}
+		mxZones[zone]++
+	}
+	for _, zone := range mxOrder {
+		w.add(Dependency{
+			Zone:     zone,
+			Via:      "mx",
+			Path:     []string{root, zone},
+			Provider: c.Mail.Match(zone),
+			Critical: mxZones[zone] == len(records.MX),
+		})
+	}
+
+	w.summarize(nameservers)
+	return w.section
+}
+
+// walk follows the nameservers of zone, recording every external zone they
+// live in. It returns the number of nameservers found for zone.
+func (w *dependencyWalker) walk(zone string, path []string, critical bool, depth int) (int, error) {
+	hosts, err := observe(w.c, w.name, "ns", zone, func() ([]string, error) {
+		return w.c.Resolver.LookupNS(zone)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Group nameserver hosts by the registrable zone they belong to
+	counts := make(map[string]int)
+	providers := make(map[string]string)
+	var order []string
+	for _, host := range hosts {
+		nsZone := domain.GetRootDomain(host)
+		if counts[nsZone] == 0 {
+			order = append(order, nsZone)
+			providers[nsZone] = w.c.Providers.Match(host)
+		}
+		counts[nsZone]++
+	}
+
+	for _, nsZone := range order {
+		if w.visited[nsZone] || strings.HasSuffix(zone, "."+nsZone) || zone == nsZone {
+			continue
+		}
+		nsPath := append(append([]string{}, path...), nsZone)
+		isCritical := critical && counts[nsZone] == len(hosts)
+		w.add(Dependency{Zone: nsZone, Via: "ns", Path: nsPath, Provider: providers[nsZone], Critical: isCritical})
+		if depth+1 < maxDependencyDepth {
+			if _, err := w.walk(nsZone, nsPath, isCritical, depth+1); err != nil {
+				w.fail(nsZone, err)
+			}
+		}
+	}
+	return len(hosts), nil
+}
+
+func (w *dependencyWalker) add(dep Dependency) {
+	w.visited[dep.Zone] = true
+	w.section.Dependencies = append(w.section.Dependencies, dep)
+}
+
+// fail records that the nameservers of the dependency on zone couldn't be
+// looked up
+func (w *dependencyWalker) fail(zone string, err error) {
+	for i := range w.section.Dependencies {
+		if w.section.Dependencies[i].Zone == zone {
+			w.section.Dependencies[i].Error = err.Error()
+		}
+	}
+}
+
+// summarize turns critical dependencies into human-readable findings
+func (w *dependencyWalker) summarize(nameservers *NameserverSection) {
+	for _, dep := range w.section.Dependencies {
+		if !dep.Critical {
+			continue
+		}
+		switch dep.Via {
+		case "ns", "cname":
+			w.section.SinglePoints = append(w.section.SinglePoints,
+				fmt.Sprintf("resolution of %s depends on %s", w.name, dep.Zone))
+		case "mx":
+			w.section.SinglePoints = append(w.section.SinglePoints,
+				fmt.Sprintf("mail delivery for %s depends on %s", w.name, dep.Zone))
+		}
+	}
+
+	// Nameservers spread over several zones can still share one operator
+	if nameservers == nil || len(nameservers.Servers) < 2 {
+		return
+	}
+	provider := nameservers.Servers[0].Provider
+	for _, ns := range nameservers.Servers[1:] {
+		if ns.Provider != provider {
+			return
+		}
+	}
+	if provider != "" {
+		w.section.SinglePoints = append(w.section.SinglePoints,
+			fmt.Sprintf("all nameservers are operated by %s", provider))
+	}
+}
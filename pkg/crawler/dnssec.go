@@ -0,0 +1,53 @@
+package crawler
+
+import "github.com/auduny/dnscrawler/pkg/dns"
+
+// DNSSECSection reports whether the zone is signed and validates
+type DNSSECSection struct {
+	Status
+	*dns.DNSSEC
+	Signed bool `json:"signed"`
+}
+
+// CAASection lists the CAA records restricting certificate issuance
+type CAASection struct {
+	Status
+	// Name is where the records were found, which may be a parent of the domain
+	Name    string   `json:"name,omitempty"`
+	Records []string `json:"records,omitempty"`
+	// Issuer is the CA of the certificate served on the domain, when the
+	// TLS check ran and the certificate is valid
+	Issuer string `json:"issuer,omitempty"`
+	// Permitted are the issuer domains of the CAA records that apply to
+	// that certificate, nil when they don't restrict issuance
+	Permitted []string `json:"permitted,omitempty"`
+	// IssuerPermitted tells whether the records permit Issuer, nil when
+	// they don't restrict issuance or the CA isn't known
+	IssuerPermitted *bool    `json:"issuer_permitted,omitempty"`
+	Issues          []string `json:"issues,omitempty"`
+}
+
+func (c *Crawler) crawlDNSSEC(name string) *DNSSECSection {
+	info, err := observe(c, name, "dnssec", name, func() (*dns.DNSSEC, error) {
+		return c.Resolver.LookupDNSSEC(name)
+	})
+	if err != nil {
+		return &DNSSECSection{Status: Status{Error: err.Error()}}
+	}
+	return &DNSSECSection{DNSSEC: info, Signed: info.Signed()}
+}
+
+func (c *Crawler) crawlCAA(name string) *CAASection {
+	type caa struct {
+		name    string
+		records []string
+	}
+	found, err := observe(c, name, "caa", name, func() (caa, error) {
+		at, records, err := c.Resolver.LookupCAA(name)
+		return caa{at, records}, err
+	})
+	if err != nil {
+		return &CAASection{Status: Status{Error: err.Error()}}
+	}
+	return &CAASection{Name: found.name, Records: found.records}
+}
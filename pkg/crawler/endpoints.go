@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
+)
+
+// endpointDifferences compares the certificate each address serves with the
+// one served by name, e.g. an origin whose certificate wasn't renewed along
+// with the others. Separately issued certificates with the same issuer,
+// expiry and names aren't a difference.
+func endpointDifferences(name string, info *tlsprobe.Info, endpoints []TLSEndpoint) []string {
+	var differences []string
+	for _, ep := range endpoints {
+		if ep.Info == nil {
+			differences = append(differences, fmt.Sprintf("%s fails: %s, while %s serves a certificate", ep.IP, ep.Error, name))
+			continue
+		}
+		var diffs []string
+		if ep.Valid != info.Valid {
+			if ep.Valid {
+				diffs = append(diffs, "valid")
+			} else {
+				diffs = append(diffs, "invalid: "+ep.VerifyError)
+			}
+		}
+		if ep.Issuer != info.Issuer {
+			diffs = append(diffs, fmt.Sprintf("issued by %s instead of %s", ep.Issuer, info.Issuer))
+		}
+		if expiry, own := ep.NotAfter.Format("2006-01-02"), info.NotAfter.Format("2006-01-02"); expiry != own {
+			diffs = append(diffs, fmt.Sprintf("expires %s (%d days) instead of %s", expiry, ep.DaysToExpiry, own))
+		}
+		if missing := setDiff(info.DNSNames, ep.DNSNames); len(missing) > 0 {
+			diffs = append(diffs, "lacks "+strings.Join(missing, ", "))
+		}
+		if extra := setDiff(ep.DNSNames, info.DNSNames); len(extra) > 0 {
+			diffs = append(diffs, "also covers "+strings.Join(extra, ", "))
+		}
+		if len(diffs) > 0 {
+			differences = append(differences, fmt.Sprintf("%s serves a different certificate: %s", ep.IP, strings.Join(diffs, "; ")))
+		}
+	}
+	return differences
+}
+
+// setDiff returns the elements of a missing from b
+func setDiff(a, b []string) []string {
+	var diff []string
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,38 @@
+package crawler
+
+import "github.com/auduny/dnscrawler/pkg/intel"
+
+// ExposureSection lists the internet-facing services of the domain's addresses
+type ExposureSection struct {
+	Status
+	Source string         `json:"source"`
+	Hosts  []HostExposure `json:"hosts"`
+}
+
+// HostExposure is the exposure of a single IP
+type HostExposure struct {
+	IP string `json:"ip"`
+	*intel.Exposure
+	Error string `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlExposure(name string, records *RecordsSection) *ExposureSection {
+	if c.Exposure == nil {
+		return &ExposureSection{Status: Status{Error: "no exposure source configured"}}
+	}
+
+	section := &ExposureSection{Source: c.Exposure.Name()}
+	for _, rec := range records.Addresses() {
+		host := HostExposure{IP: rec.Value}
+		exp, err := observe(c, name, "exposure", rec.Value, func() (*intel.Exposure, error) {
+			return c.Exposure.Exposure(rec.Value)
+		})
+		if err != nil {
+			host.Error = err.Error()
+		} else {
+			host.Exposure = exp
+		}
+		section.Hosts = append(section.Hosts, host)
+	}
+	return section
+}
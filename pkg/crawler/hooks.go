@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Event describes a single lookup performed during a crawl
+type Event struct {
+	Domain   string        // domain being crawled
+	Kind     string        // lookup type: exists, whois, organization, nameservers, ns, addrs, soa, recursion, rrset, trace, records, reverseip, exposure, threat, reputation, blocklist, nxdomain, dnssec, caa, ptr, asn, dmarc, tls, https, http, connect, plugin
+	Target   string        // what was queried (the domain, an IP, ...)
+	Data     any           // lookup result, set for OnResult; the partial result for OnProgress
+	Err      error         // lookup error, set for OnError
+	Duration time.Duration // time spent, set for OnResult and OnError
+	Cached   bool          // answered from data already collected in this crawl; only OnResult fires
+}
+
+// Hooks are callbacks invoked around every lookup. Any of them may be nil.
+// They run synchronously on the crawling goroutine, so slow hooks slow the crawl.
+type Hooks struct {
+	OnQuery  func(Event)
+	OnResult func(Event)
+	OnError  func(Event)
+	// OnProgress reports partial results of long lookups before they finish;
+	// currently each step of the trace (a dns.TraceStep)
+	OnProgress func(Event)
+}
+
+// Use registers hooks; hooks run in the order they were added
+func (c *Crawler) Use(h Hooks) {
+	c.hooks = append(c.hooks, h)
+}
+
+// WithOptions returns a copy of the crawler using opts. Hooks added to the
+// copy don't affect the original, so servers can derive one per request.
+func (c *Crawler) WithOptions(opts Options) *Crawler {
+	cp := *c
+	cp.Options = opts
+	cp.hooks = slices.Clip(c.hooks)
+	return &cp
+}
+
+// observe runs a lookup, firing the registered hooks around it. Once the
+// crawl's context is done, lookups fail with its error without running.
+func observe[T any](c *Crawler, name, kind, target string, lookup func() (T, error)) (T, error) {
+	ev := Event{Domain: name, Kind: kind, Target: target}
+	if err := c.context().Err(); err != nil {
+		var zero T
+		err = fmt.Errorf("lookup skipped: %w", err)
+		ev.Err = err
+		for _, h := range c.hooks {
+			if h.OnError != nil {
+				h.OnError(ev)
+			}
+		}
+		return zero, err
+	}
+	for _, h := range c.hooks {
+		if h.OnQuery != nil {
+			h.OnQuery(ev)
+		}
+	}
+
+	span := startLookup(c, ev)
+	start := time.Now()
+	data, err := lookup()
+	ev.Duration = time.Since(start)
+	endLookup(span, err)
+
+	if err != nil {
+		ev.Err = err
+		for _, h := range c.hooks {
+			if h.OnError != nil {
+				h.OnError(ev)
+			}
+		}
+		return data, err
+	}
+
+	ev.Data = data
+	for _, h := range c.hooks {
+		if h.OnResult != nil {
+			h.OnResult(ev)
+		}
+	}
+	return data, nil
+}
+
+// progress reports a partial result of a running lookup
+func progress(c *Crawler, name, kind, target string, data any) {
+	ev := Event{Domain: name, Kind: kind, Target: target, Data: data}
+	for _, h := range c.hooks {
+		if h.OnProgress != nil {
+			h.OnProgress(ev)
+		}
+	}
+}
+
+// cached reports a lookup answered without querying, e.g. an IP whose ASN was
+// already resolved earlier in the crawl
+func cached(c *Crawler, name, kind, target string, data any) {
+	_, span := tracer.Start(c.context(), kind, trace.WithAttributes(
+		attrDomain.String(name),
+		attrKind.String(kind),
+		attrTarget.String(target),
+		attrCached.Bool(true),
+	))
+	span.End()
+
+	ev := Event{Domain: name, Kind: kind, Target: target, Data: data, Cached: true}
+	for _, h := range c.hooks {
+		if h.OnResult != nil {
+			h.OnResult(ev)
+		}
+	}
+}
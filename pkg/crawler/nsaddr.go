@@ -0,0 +1,26 @@
+package crawler
+
+// nsAddr is a single address of an authoritative nameserver
+type nsAddr struct {
+	Name string
+	IP   string
+}
+
+// nameserverAddrs resolves every IPv4 and IPv6 address of the given nameservers.
+// Nameservers without addresses are returned with an empty IP.
+func (c *Crawler) nameserverAddrs(name string, nameservers *NameserverSection) []nsAddr {
+	var addrs []nsAddr
+	for _, ns := range nameservers.Servers {
+		ips, _ := observe(c, name, "addrs", ns.Name, func() ([]string, error) {
+			return c.Resolver.LookupAddrs(ns.Name), nil
+		})
+		if len(ips) == 0 {
+			addrs = append(addrs, nsAddr{Name: ns.Name})
+			continue
+		}
+		for _, ip := range ips {
+			addrs = append(addrs, nsAddr{Name: ns.Name, IP: ip})
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+)
+
+// Negative caching bounds considered sane (RFC 2308 suggests 1-3 hours)
+const (
+	minNegativeTTL = 60
+	maxNegativeTTL = 86400
+)
+
+// NXDomainSection reports how the zone answers for names that don't exist
+type NXDomainSection struct {
+	Status
+	*dns.NXProbe
+	Wildcard bool     `json:"wildcard"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+func (c *Crawler) crawlNXDomain(name string) *NXDomainSection {
+	probe, err := observe(c, name, "nxdomain", name, func() (*dns.NXProbe, error) {
+		return c.Resolver.ProbeNonexistent(name)
+	})
+	if err != nil {
+		return &NXDomainSection{Status: Status{Error: err.Error()}}
+	}
+
+	section := &NXDomainSection{NXProbe: probe}
+	switch {
+	case probe.Rcode == "NXDOMAIN":
+		// Expected answer
+	case len(probe.Answers) > 0:
+		section.Wildcard = true
+		section.Findings = append(section.Findings,
+			fmt.Sprintf("nonexistent names resolve (wildcard or redirect) to %v", probe.Answers))
+	default:
+		section.Findings = append(section.Findings,
+			fmt.Sprintf("nonexistent name answered %s instead of NXDOMAIN", probe.Rcode))
+	}
+
+	switch {
+	case !probe.HasSOA && len(probe.Answers) == 0:
+		section.Findings = append(section.Findings, "negative answer carries no SOA, so it cannot be cached")
+	case probe.HasSOA && probe.NegativeTTL < minNegativeTTL:
+		section.Findings = append(section.Findings,
+			fmt.Sprintf("negative TTL %ds is very low; resolvers will re-query often", probe.NegativeTTL))
+	case probe.HasSOA && probe.NegativeTTL > maxNegativeTTL:
+		section.Findings = append(section.Findings,
+			fmt.Sprintf("negative TTL %ds is very high; new names will take long to appear", probe.NegativeTTL))
+	}
+
+	return section
+}
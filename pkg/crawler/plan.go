@@ -0,0 +1,276 @@
+package crawler
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// Plan is what a crawl of a domain would send where, as listed by
+// Crawler.Plan
+type Plan struct {
+	Domain string `json:"domain"`
+	Steps  []Step `json:"steps"`
+}
+
+// Step is one operation of a crawl
+type Step struct {
+	// Domain is the name crawled: the domain, or the registrable domain
+	// crawled as its root context
+	Domain string `json:"domain"`
+	// Section is one of Sections, exists for the check every crawl starts
+	// with, or plugins
+	Section string `json:"section"`
+	// Kind is how the step goes out: dns, whois, http, tls, tcp, api (a
+	// third-party service over HTTP) or command (a plugin)
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	// Server is where the step is sent, when it doesn't follow from Target
+	Server string `json:"server,omitempty"`
+	// When is the condition the step depends on, or what it is repeated
+	// for; the values only an answer can tell are in angle brackets
+	When string `json:"when,omitempty"`
+}
+
+// Plan lists the operations Crawl would perform for name with the crawler's
+// options and sources, without performing any. Steps built on earlier
+// answers, such as the PTR lookups of the domain's addresses, are listed
+// once with the answers they are repeated for.
+func (c *Crawler) Plan(name string) *Plan {
+	name = domain.Canonical(name)
+	p := &planner{c: c, upstream: c.Resolver.Upstream(), records: c.Resolver.Upstream()}
+	if server := c.Resolver.Authoritative(); server != "" {
+		p.records = server + ", without recursion"
+	}
+	if domain.IsSubdomain(name) {
+		p.crawl(domain.GetRootDomain(name), true)
+	}
+	p.crawl(name, false)
+	for _, plugin := range c.Plugins {
+		p.add(name, "plugins", "command", strings.Join(append([]string{plugin.Command}, plugin.Args...), " "), "", "")
+	}
+	return &Plan{Domain: name, Steps: p.steps}
+}
+
+// planner collects the steps of Crawler.Plan, mirroring Crawler.crawl
+type planner struct {
+	c        *Crawler
+	upstream string // the recursive resolver
+	records  string // where the records of the crawled names are asked
+	steps    []Step
+}
+
+func (p *planner) add(name, section, kind, target, server, when string) {
+	p.steps = append(p.steps, Step{Domain: name, Section: section, Kind: kind, Target: target, Server: server, When: when})
+}
+
+// query adds a DNS query to the recursive resolver
+func (p *planner) query(name, section, qname, qtype, when string) {
+	p.add(name, section, "dns", qname+" "+qtype, p.upstream, when)
+}
+
+// queryRecords adds a DNS query for the records of a crawled name
+func (p *planner) queryRecords(name, section, qname, qtype string) {
+	p.add(name, section, "dns", qname+" "+qtype, p.records, "")
+}
+
+// probe adds the requests httpprobe.Prober.Probe sends for host, all of
+// them depending on cond when it is set
+func (p *planner) probe(name, section, host, cond string) {
+	p.add(name, section, "http", "http://"+host+"/", "", cond)
+	p.add(name, section, "http", "https://"+host+"/", "", conditions(cond, "if http:// fails"))
+	p.add(name, section, "http", "<redirect target>", "", conditions(cond, "for each redirect"))
+	p.add(name, section, "http", "<final site>/favicon.ico", "", conditions(cond, "if the site answers"))
+}
+
+// nxProbe adds the queries dns.Resolver.ProbeNonexistent sends for name,
+// all of them depending on cond when it is set
+func (p *planner) nxProbe(name, section, cond string) {
+	p.query(name, section, "<"+name+" or the closest parent with nameservers>", "NS", cond)
+	p.query(name, section, "<nameserver>", "A and AAAA", conditions(cond, "for each nameserver"))
+	p.add(name, section, "dns", "dnscrawler-<random label>."+name+" A", "<nameserver address>:53", conditions(cond, "until one answers"))
+}
+
+// conditions joins the conditions of a step that are set
+func conditions(conds ...string) string {
+	return strings.Join(slices.DeleteFunc(conds, func(s string) bool { return s == "" }), ", ")
+}
+
+func (p *planner) crawl(name string, isRootContext bool) {
+	o := p.c.Options
+	tld := domain.TLD(name)
+
+	p.query(name, "exists", name, "NS", "")
+	if o.Runs(SectionWhois) {
+		if _, ok := whois.ParserFor(tld).(whois.AvailabilityChecker); ok {
+			p.add(name, SectionWhois, "whois", name+" availability", "the ."+tld+" registry", "if the name isn't in DNS")
+		}
+		p.add(name, SectionWhois, "whois", tld, "whois.iana.org", "")
+		p.add(name, SectionWhois, "whois", name, "the ."+tld+" server named by IANA", "")
+		p.add(name, SectionWhois, "whois", name, "<registrar server>", "if the registry refers to one")
+		if _, ok := whois.ParserFor(tld).(whois.Enricher); ok {
+			p.add(name, SectionWhois, "whois", "<handle>", "the ."+tld+" registry", "for each contact handle of the answer")
+		}
+		if o.Runs(SectionOrg) && p.c.OrgRegistry != nil {
+			if registry, ok := p.c.OrgRegistry(tld); ok {
+				if p.c.RDAP != nil {
+					p.add(name, SectionOrg, "rdap", name, "the ."+tld+" RDAP service", "if WHOIS doesn't list the registrant's number")
+				}
+				p.add(name, SectionOrg, "api", "<organization number>", registry.Name(), "if WHOIS or RDAP lists the registrant's number")
+			}
+		}
+	}
+
+	if o.needsNameservers() {
+		p.query(name, SectionNameservers, name, "NS", "")
+		p.query(name, SectionNameservers, "<nameserver>", "A", "for each nameserver")
+	}
+	if o.Runs(SectionDNSSEC) && !domain.IsSubdomain(name) {
+		p.query(name, SectionDNSSEC, name, "DS", "")
+		p.query(name, SectionDNSSEC, name, "DNSKEY", "")
+	}
+	// The addresses of the nameservers are resolved once, by the first
+	// section asking the servers themselves
+	for _, section := range []string{SectionSOA, SectionRecursion, SectionConsistency} {
+		if o.Runs(section) {
+			p.query(name, section, "<nameserver>", "A and AAAA", "for each nameserver")
+			break
+		}
+	}
+	if o.Runs(SectionSOA) {
+		p.add(name, SectionSOA, "dns", name+" SOA", "<nameserver address>:53", "for each nameserver address")
+	}
+	if o.Runs(SectionRecursion) {
+		p.add(name, SectionRecursion, "dns", "www.iana.org A", "<nameserver address>:53", "for each nameserver address")
+	}
+	if o.Runs(SectionConsistency) {
+		for _, qtype := range consistencyTypes {
+			p.add(name, SectionConsistency, "dns", name+" "+qtype, "<nameserver address>:53", "for each nameserver address")
+		}
+	}
+	if o.Runs(SectionTrace) && !isRootContext {
+		p.add(name, SectionTrace, "dns", name+" NS", "the root servers, then the nameservers of each zone", "")
+	}
+
+	if o.needsRecords() {
+		for _, qtype := range lookupTypes(o) {
+			p.queryRecords(name, SectionRecords, name, strings.ToUpper(qtype))
+		}
+		if o.Runs(SectionPTR) {
+			p.query(name, SectionPTR, "<address>", "PTR", "for each A and AAAA record")
+		}
+	}
+	if o.Runs(SectionASN) {
+		when := "for each A and AAAA record"
+		if o.needsNameservers() {
+			when = "for each nameserver address and A and AAAA record"
+		}
+		p.query(name, SectionASN, "<address>.origin.asn.cymru.com", "TXT", when)
+		p.query(name, SectionASN, "AS<number>.asn.cymru.com", "TXT", "for each AS found")
+	}
+	if o.Runs(SectionReverseIP) && !isRootContext && p.c.ReverseIP != nil {
+		p.add(name, SectionReverseIP, "api", "<address>", p.c.ReverseIP.Name(), "for each A and AAAA record")
+	}
+	if o.Runs(SectionExposure) && !isRootContext && p.c.Exposure != nil {
+		p.add(name, SectionExposure, "api", "<address>", p.c.Exposure.Name(), "for each A and AAAA record")
+	}
+	if o.Runs(SectionIntel) && !isRootContext {
+		for _, src := range p.c.Threat {
+			p.add(name, SectionIntel, "api", name, src.Name(), "")
+		}
+	}
+	if o.Runs(SectionReputation) && !isRootContext {
+		for _, src := range p.c.Reputation {
+			p.add(name, SectionReputation, "api", name, src.Name(), "")
+		}
+	}
+	if o.Runs(SectionBlocklists) {
+		for _, bl := range dns.DomainBlocklists {
+			p.query(name, SectionBlocklists, name+"."+bl.Zone, "A", "")
+		}
+	}
+	if o.Runs(SectionNXDomain) {
+		p.nxProbe(name, SectionNXDomain, "")
+	}
+	if o.Runs(SectionDeps) && !isRootContext {
+		p.query(name, SectionDeps, domain.GetRootDomain(name), "NS", "")
+		p.query(name, SectionDeps, "<zone>", "NS", "for each zone the nameservers, CNAME and MX records are in")
+	}
+	if o.Runs(SectionEmail) {
+		when := "if the SPF record has no all mechanism, for each redirect up to " + strconv.Itoa(maxSPFRedirects)
+		if p.records != p.upstream {
+			// Only names in the zone are asked of the authoritative server
+			when += "; those in " + domain.GetRootDomain(name) + " of " + p.records
+		}
+		p.add(name, SectionEmail, "dns", "<redirect= domain> TXT", p.upstream, when)
+		p.queryRecords(name, SectionEmail, "_dmarc."+name, "TXT")
+	}
+
+	if o.Runs(SectionTLS) && !isRootContext {
+		p.add(name, SectionTLS, "tls", name+":443", "", "")
+		p.add(name, SectionTLS, "tls", name+":443 h2 and http/1.1", "", "")
+		if !p.c.TLS.NoQUIC {
+			p.add(name, SectionTLS, "tls", name+":443 h3 over QUIC", "", "")
+		}
+		p.query(name, SectionTLS, name, "HTTPS", "")
+		p.add(name, SectionTLS, "tls", "<address>:443", "", "for each A and AAAA record, if there are several")
+		if o.Runs(SectionOCSP) {
+			p.add(name, SectionOCSP, "http", "<OCSP responder>", "", "if the certificate names one")
+		}
+		if o.Runs(SectionTLSScan) {
+			p.add(name, SectionTLSScan, "tls", "<address>:443 versions and ciphers", "", "for each A and AAAA record")
+		}
+		if o.Runs(SectionJARM) {
+			p.add(name, SectionJARM, "tls", name+":443 JARM", "", "")
+			p.add(name, SectionJARM, "tls", "<address>:443 JARM", "", "for each A and AAAA record, if there are several")
+		}
+	}
+
+	web := o.Runs(SectionWeb) && !isRootContext
+	if web {
+		hosts := []string{name}
+		if !domain.IsSubdomain(name) {
+			hosts = append(hosts, "www."+name)
+		}
+		for _, host := range hosts {
+			p.probe(name, SectionWeb, host, "")
+		}
+		for _, path := range p.c.HTTP.WellKnownPaths {
+			p.add(name, SectionWeb, "http", httpprobe.WellKnownURL(name, path), "", "")
+		}
+	}
+	if o.Runs(SectionIPv6) && !isRootContext {
+		for _, port := range p.c.Reach.Ports {
+			p.add(name, SectionIPv6, "tcp", "<address>:"+strconv.Itoa(port), "", "for each AAAA record")
+		}
+	}
+	if o.Runs(SectionWWW) && !isRootContext && !domain.IsSubdomain(name) {
+		www := "www." + name
+		for _, qtype := range lookupTypes(o) {
+			p.queryRecords(name, SectionWWW, www, strings.ToUpper(qtype))
+		}
+		if !web {
+			for _, host := range []string{name, www} {
+				p.probe(name, SectionWWW, host, "if "+host+" has addresses")
+			}
+		}
+		if !o.Runs(SectionTLS) {
+			p.add(name, SectionWWW, "tls", name+":443", "", "if "+name+" has addresses")
+		}
+		p.add(name, SectionWWW, "tls", www+":443", "", "if "+www+" has addresses")
+	}
+	if o.Runs(SectionParking) && !isRootContext && !o.Runs(SectionNXDomain) {
+		p.nxProbe(name, SectionParking, "if the name has addresses")
+	}
+	if o.Runs(SectionCAA) && !isRootContext {
+		p.query(name, SectionCAA, name, "CAA", "")
+		if zone := domain.GetRootDomain(name); zone != name {
+			p.query(name, SectionCAA, "<parent names up to "+zone+">", "CAA", "until one has CAA records")
+		}
+	}
+}
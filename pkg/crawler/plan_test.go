@@ -0,0 +1,116 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// TestPlan checks that every lookup a crawl makes against the responses in
+// testdata/replay is listed by Plan
+func TestPlan(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		domain string
+		opts   Options
+	}{
+		{"default", "www.example.com", Options{}},
+		{"web", "www.example.com", Options{Web: true, NoTrace: true}},
+		{"www", "example.com", Options{Only: []string{SectionRecords, SectionWWW}}},
+		{"parking", "www.example.com", Options{Parking: true, NoTrace: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := fixture.NewReplayer("testdata/replay")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var mu sync.Mutex
+			var lookups [][2]string
+			store.OnLookup(func(kind, key string) {
+				mu.Lock()
+				defer mu.Unlock()
+				lookups = append(lookups, [2]string{kind, key})
+			})
+
+			c := New(tc.opts)
+			c.Resolver = dns.NewResolver(dns.WithFixtures(store))
+			c.Whois = whois.NewClient(whois.WithFixtures(store))
+			c.HTTP.Transport = store.Transport(nil)
+			c.TLS.Fixtures = store
+			plan := c.Plan(tc.domain)
+			c.Crawl(tc.domain)
+
+			for _, l := range lookups {
+				if !planned(plan, l[0], l[1]) {
+					t.Errorf("%s %q is not in the plan", l[0], l[1])
+				}
+			}
+		})
+	}
+}
+
+// placeholder matches the values of a plan step only an answer can tell
+var placeholder = regexp.MustCompile(`<[^>]*>`)
+
+// planned reports whether a step of plan matches a lookup, by the key
+// the fixture store gives it
+func planned(plan *Plan, kind, key string) bool {
+	target := key
+	switch kind {
+	case "dns":
+		// "8.8.8.8:53 example.com. TXT rd=true"
+		fields := strings.Fields(key)
+		qname := strings.TrimSuffix(fields[1], ".")
+		target = qname + " " + fields[2]
+		// The trace asks every zone from the root down
+		if fields[3] == "rd=false" {
+			for _, s := range plan.Steps {
+				traced := strings.Fields(s.Target)[0]
+				if s.Section == SectionTrace && (qname == "" || traced == qname || strings.HasSuffix(traced, "."+qname)) {
+					return true
+				}
+			}
+		}
+	case "whois":
+		// "whois.iana.org com" when asked of a given server
+		if _, query, ok := strings.Cut(key, " "); ok {
+			target = query
+		}
+	case "http":
+		target = strings.TrimPrefix(key, "GET ")
+	case "tls":
+		// "example.com@192.0.2.10 TLS 1.2-TLS 1.3 h2", matched by the
+		// endpoint alone
+		_, endpoint, _ := strings.Cut(key, "@")
+		target, _, _ = strings.Cut(endpoint, " ")
+		target += ":443"
+	}
+	for _, s := range plan.Steps {
+		stepKind := s.Kind
+		if stepKind == "api" || stepKind == "rdap" {
+			stepKind = "http"
+		}
+		if stepKind != kind {
+			continue
+		}
+		stepTarget := s.Target
+		if kind == "tls" {
+			stepTarget = strings.Fields(stepTarget)[0]
+		}
+		// A step that is all placeholder, such as a redirect target,
+		// would match any lookup of its kind
+		if placeholder.FindString(stepTarget) == stepTarget {
+			continue
+		}
+		pattern := strings.ReplaceAll(placeholder.ReplaceAllString(regexp.QuoteMeta(stepTarget), ".+"), " A and AAAA", " (A|AAAA)")
+		if regexp.MustCompile("^" + pattern + "$").MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
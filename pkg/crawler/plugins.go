@@ -0,0 +1,86 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Plugin is an external command that contributes a custom section to the
+// report. It is given the Result as JSON on stdin and must print a
+// PluginSection-shaped JSON object ({"title": ..., "items": [...]}) on stdout.
+type Plugin struct {
+	Name    string
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+// PluginSection is the output of a single plugin
+type PluginSection struct {
+	Status
+	Name  string       `json:"name"`
+	Title string       `json:"title,omitempty"`
+	Items []PluginItem `json:"items,omitempty"`
+}
+
+// PluginItem is one key/value line of a plugin section
+type PluginItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RunPlugins executes every configured plugin against result and attaches their sections
+func (c *Crawler) RunPlugins(result *Result) {
+	if len(c.Plugins) == 0 || !result.Registered {
+		return
+	}
+
+	input, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	for _, p := range c.Plugins {
+		section, err := observe(c, result.Domain, "plugin", p.Name, func() (*PluginSection, error) {
+			return runPlugin(p, input)
+		})
+		if err != nil {
+			section = &PluginSection{Status: Status{Error: err.Error()}}
+		}
+		section.Name = p.Name
+		result.Plugins = append(result.Plugins, *section)
+	}
+}
+
+func runPlugin(p Plugin, input []byte) (*PluginSection, error) {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	section := &PluginSection{}
+	if err := json.Unmarshal(stdout.Bytes(), section); err != nil {
+		return nil, fmt.Errorf("invalid plugin output: %v", err)
+	}
+	return section, nil
+}
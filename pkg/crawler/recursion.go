@@ -0,0 +1,42 @@
+package crawler
+
+import "fmt"
+
+// RecursionSection reports which authoritative servers answer recursive queries for anyone
+type RecursionSection struct {
+	Status
+	Servers []RecursionServer `json:"servers"`
+	// Open lists the addresses acting as open resolvers
+	Open []string `json:"open,omitempty"`
+}
+
+// RecursionServer is the recursion test outcome for one authoritative address
+type RecursionServer struct {
+	Name  string `json:"name"`
+	IP    string `json:"ip"`
+	Open  bool   `json:"open"`
+	Error string `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlRecursion(name string, nameservers *NameserverSection) *RecursionSection {
+	section := &RecursionSection{}
+
+	for _, addr := range c.nameserverAddrs(name, nameservers) {
+		if addr.IP == "" {
+			continue
+		}
+		server := RecursionServer{Name: addr.Name, IP: addr.IP}
+		open, err := observe(c, name, "recursion", addr.IP, func() (bool, error) {
+			return c.Resolver.TestRecursion(addr.IP)
+		})
+		if err != nil {
+			server.Error = err.Error()
+		} else if open {
+			server.Open = true
+			section.Open = append(section.Open, fmt.Sprintf("%s (%s)", addr.Name, addr.IP))
+		}
+		section.Servers = append(section.Servers, server)
+	}
+
+	return section
+}
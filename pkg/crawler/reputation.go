@@ -0,0 +1,40 @@
+package crawler
+
+import "github.com/auduny/dnscrawler/pkg/intel"
+
+// ReputationSection holds safe-browsing verdicts for the domain
+type ReputationSection struct {
+	Status
+	// Malicious is set when any source lists the domain
+	Malicious bool         `json:"malicious"`
+	Checks    []Reputation `json:"checks"`
+}
+
+// Reputation is one source's listing, or the error it returned
+type Reputation struct {
+	*intel.Listing
+	Source string `json:"source"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlReputation(name string) *ReputationSection {
+	if len(c.Reputation) == 0 {
+		return &ReputationSection{Status: Status{Error: "no reputation sources configured"}}
+	}
+
+	section := &ReputationSection{}
+	for _, src := range c.Reputation {
+		entry := Reputation{Source: src.Name()}
+		listing, err := observe(c, name, "reputation", src.Name(), func() (*intel.Listing, error) {
+			return src.Check(name)
+		})
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Listing = listing
+			section.Malicious = section.Malicious || listing.Listed
+		}
+		section.Checks = append(section.Checks, entry)
+	}
+	return section
+}
@@ -0,0 +1,374 @@
+package crawler
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/intel"
+	"github.com/auduny/dnscrawler/pkg/parking"
+	"github.com/auduny/dnscrawler/pkg/reach"
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// Result aggregates everything learned about a single domain. Each section
+// carries either its data or the error that prevented it from being collected,
+// so partial failures survive into machine-readable output.
+type Result struct {
+	Domain     string `json:"domain"`
+	Registered bool   `json:"registered"`
+
+	// Availability is the registry's availability service's answer for a
+	// domain that isn't in DNS, see whois.Client.CheckAvailability
+	Availability *whois.Availability `json:"availability,omitempty"`
+
+	// Spoofing is set when the name looks like another, see domain.CheckSpoofing
+	Spoofing *domain.Spoofing `json:"spoofing,omitempty"`
+
+	// Parking is set when the domain looks parked, see parking.Detect
+	Parking *parking.Verdict `json:"parking,omitempty"`
+
+	// Root holds the registrable domain's result when Domain is a subdomain
+	Root *Result `json:"root,omitempty"`
+
+	Whois       *WhoisSection       `json:"whois,omitempty"`
+	Nameservers *NameserverSection  `json:"nameservers,omitempty"`
+	DNSSEC      *DNSSECSection      `json:"dnssec,omitempty"`
+	SOA         *SOASection         `json:"soa,omitempty"`
+	Recursion   *RecursionSection   `json:"recursion,omitempty"`
+	Consistency *ConsistencySection `json:"consistency,omitempty"`
+	Trace       *TraceSection       `json:"trace,omitempty"`
+	Records     *RecordsSection     `json:"records,omitempty"`
+	ReverseIP   *ReverseIPSection   `json:"reverse_ip,omitempty"`
+	Exposure    *ExposureSection    `json:"exposure,omitempty"`
+	ThreatIntel *ThreatIntelSection `json:"threat_intel,omitempty"`
+	Reputation  *ReputationSection  `json:"reputation,omitempty"`
+	Blocklists  *BlocklistSection   `json:"blocklists,omitempty"`
+	NXDomain    *NXDomainSection    `json:"nxdomain,omitempty"`
+	ASN         *ASNSection         `json:"asn,omitempty"`
+	Email       *EmailSection       `json:"email,omitempty"`
+	TLS         *TLSSection         `json:"tls,omitempty"`
+	Web         *WebSection         `json:"web,omitempty"`
+	IPv6        *IPv6Section        `json:"ipv6,omitempty"`
+	WWW         *WWWSection         `json:"www,omitempty"`
+	CAA         *CAASection         `json:"caa,omitempty"`
+
+	Dependencies *DependencySection `json:"dependencies,omitempty"`
+
+	// Plugins holds sections contributed by external plugins
+	Plugins []PluginSection `json:"plugins,omitempty"`
+}
+
+// Status is embedded in every section and records why it failed, if it did
+type Status struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Failed reports whether the section could not be collected
+func (s Status) Failed() bool {
+	return s.Error != ""
+}
+
+type WhoisSection struct {
+	Status
+	*whois.Info
+	// NewlyRegistered is set when the domain is younger than Options.NewDomainDays
+	NewlyRegistered bool `json:"newly_registered"`
+	// Organization is set when Options.OrgLookup is and the registry
+	// published an organization number
+	Organization *OrganizationSection `json:"organization,omitempty"`
+}
+
+// OrganizationSection is the registrant's entry in the business registry
+// of the domain's country
+type OrganizationSection struct {
+	Status
+	Registry string `json:"registry"`
+	*intel.Organization
+}
+
+type NameserverSection struct {
+	Status
+	Servers []Nameserver `json:"servers"`
+}
+
+// Nameserver is an authoritative server enriched with provider and ASN details
+type Nameserver struct {
+	dns.Nameserver
+	Provider string `json:"provider,omitempty"`
+	ASN      string `json:"asn,omitempty"`
+}
+
+type TraceSection struct {
+	Status
+	Steps []dns.TraceStep `json:"steps"`
+}
+
+type RecordsSection struct {
+	Status
+	// Server is the authoritative server the records were asked of, empty
+	// when they come from the recursive resolver
+	Server string `json:"server,omitempty"`
+
+	A     []Record `json:"a,omitempty"`
+	AAAA  []Record `json:"aaaa,omitempty"`
+	CNAME []Record `json:"cname,omitempty"`
+	MX    []Record `json:"mx,omitempty"`
+	TXT   []Record `json:"txt,omitempty"`
+	// Other holds the records of the other types asked for, by type
+	Other map[string][]Record `json:"other,omitempty"`
+
+	// unlisted are the records resolved for the other sections when the
+	// types asked for leave theirs out
+	unlisted struct{ A, AAAA, CNAME, MX, TXT []Record }
+}
+
+// resolved returns the section with the records resolved for the other
+// sections added to those listed; nil for a nil section
+func (s *RecordsSection) resolved() *RecordsSection {
+	if s == nil {
+		return nil
+	}
+	all := *s
+	all.A = slices.Concat(s.A, s.unlisted.A)
+	all.AAAA = slices.Concat(s.AAAA, s.unlisted.AAAA)
+	all.CNAME = slices.Concat(s.CNAME, s.unlisted.CNAME)
+	all.MX = slices.Concat(s.MX, s.unlisted.MX)
+	all.TXT = slices.Concat(s.TXT, s.unlisted.TXT)
+	return &all
+}
+
+// Empty reports whether no records of any type were found
+func (s *RecordsSection) Empty() bool {
+	return len(s.A) == 0 && len(s.AAAA) == 0 && len(s.CNAME) == 0 && len(s.MX) == 0 && len(s.TXT) == 0 && len(s.Other) == 0
+}
+
+// Addresses returns the A and AAAA records that are addresses, leaving out
+// the CNAME targets the resolver lists with them; nil for a nil section
+func (s *RecordsSection) Addresses() []Record {
+	return slices.Concat(s.IPv4(), s.IPv6())
+}
+
+// IPv4 returns the A records that are IPv4 addresses, including those
+// resolved for the other sections only
+func (s *RecordsSection) IPv4() []Record {
+	if s == nil {
+		return nil
+	}
+	return filterAddrs(slices.Concat(s.A, s.unlisted.A), func(a netip.Addr) bool { return a.Is4() })
+}
+
+// IPv6 returns the AAAA records that are IPv6 addresses, including those
+// resolved for the other sections only
+func (s *RecordsSection) IPv6() []Record {
+	if s == nil {
+		return nil
+	}
+	return filterAddrs(slices.Concat(s.AAAA, s.unlisted.AAAA), func(a netip.Addr) bool { return a.Is6() && !a.Is4In6() })
+}
+
+func filterAddrs(records []Record, keep func(netip.Addr) bool) []Record {
+	var out []Record
+	for _, rec := range records {
+		if a, err := netip.ParseAddr(rec.Value); err == nil && keep(a) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// Record is a single DNS record value with optional enrichment
+type Record struct {
+	Value    string `json:"value"`
+	Provider string `json:"provider,omitempty"`
+	PTR      string `json:"ptr,omitempty"`
+	ASN      string `json:"asn,omitempty"`
+}
+
+// ASNSection maps every IP seen during the crawl to its autonomous system
+type ASNSection struct {
+	Status
+	IPs map[string]*dns.ASNInfo `json:"ips"`
+}
+
+// EmailSection summarizes the domain's mail authentication policy
+type EmailSection struct {
+	Status
+	SPF string `json:"spf,omitempty"`
+	// SPFRedirect is the record the SPF record's redirect= modifier leads
+	// to, which holds the policy when the SPF record has no all mechanism
+	SPFRedirect string `json:"spf_redirect,omitempty"`
+	DMARC       string `json:"dmarc,omitempty"`
+}
+
+// SPFPolicy returns the qualifier of the SPF all mechanism ("-", "~", "?"
+// or "+"), followed through redirect=, or "" if there is none
+func (s *EmailSection) SPFPolicy() string {
+	if all, _ := spfTerms(s.SPF); all != "" {
+		return all
+	}
+	all, _ := spfTerms(s.SPFRedirect)
+	return all
+}
+
+// spfTerms returns the qualifier of an SPF record's all mechanism and the
+// target of its redirect= modifier
+func spfTerms(record string) (all, redirect string) {
+	for _, term := range strings.Fields(record) {
+		lower := strings.ToLower(term)
+		switch {
+		case strings.HasPrefix(lower, "redirect="):
+			redirect = term[len("redirect="):]
+		case lower == "all":
+			all = "+"
+		case len(lower) == 4 && strings.ContainsRune("+-~?", rune(lower[0])) && lower[1:] == "all":
+			all = lower[:1]
+		}
+	}
+	return all, redirect
+}
+
+// DMARCPolicy returns the lowercased p= tag of the DMARC record, or "" if there is none
+func (s *EmailSection) DMARCPolicy() string {
+	return s.dmarcTag("p")
+}
+
+// DMARCSubdomainPolicy returns the policy the DMARC record sets for
+// subdomains: its sp= tag, or its p= tag when there is none
+func (s *EmailSection) DMARCSubdomainPolicy() string {
+	if sp := s.dmarcTag("sp"); sp != "" {
+		return sp
+	}
+	return s.DMARCPolicy()
+}
+
+// dmarcTag returns the lowercased value of a tag of the DMARC record
+func (s *EmailSection) dmarcTag(name string) string {
+	for _, tag := range strings.Split(s.DMARC, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(tag), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.ToLower(strings.TrimSpace(v))
+		}
+	}
+	return ""
+}
+
+// DMARCPolicy returns the DMARC policy receivers apply to the domain and
+// the tag it comes from: the p= of the domain's own record or, for a
+// subdomain without one, the subdomain policy of the registrable domain's
+// record (RFC 7489 section 6.6.3). Both are "" when neither has a record.
+func (r *Result) DMARCPolicy() (policy, tag string) {
+	if r.Email != nil && r.Email.DMARC != "" {
+		return r.Email.DMARCPolicy(), "p"
+	}
+	if r.Root == nil || r.Root.Email == nil || r.Root.Email.DMARC == "" {
+		return "", ""
+	}
+	if sp := r.Root.Email.dmarcTag("sp"); sp != "" {
+		return sp, "sp"
+	}
+	return r.Root.Email.DMARCPolicy(), "p"
+}
+
+type TLSSection struct {
+	Status
+	*tlsprobe.Info
+	ALPN *ALPNSection `json:"alpn,omitempty"`
+	// Endpoints are the certificates served on each address, when the
+	// name has more than one
+	Endpoints []TLSEndpoint `json:"endpoints,omitempty"`
+	// Differences are where the certificates of the endpoints differ from
+	// the one served by name
+	Differences []string `json:"differences,omitempty"`
+	// SharedWith are the registrable domains of the certificate's names
+	// other than the crawled one's, a sign of shared or multi-tenant hosting
+	SharedWith []string `json:"shared_with,omitempty"`
+	// Scans are the TLS versions and ciphers each address accepts, when
+	// scanned
+	Scans []TLSScan `json:"scans,omitempty"`
+	// JARM fingerprints the endpoint's TLS configuration, for clustering it
+	// with other servers, e.g. known malicious ones
+	JARM string `json:"jarm,omitempty"`
+}
+
+// TLSScan is the version and cipher scan of one address
+type TLSScan struct {
+	Address string `json:"address"`
+	Error   string `json:"error,omitempty"`
+	*tlsprobe.Scan
+}
+
+// TLSEndpoint is the certificate served on one address
+type TLSEndpoint struct {
+	IP       string `json:"ip"`
+	Provider string `json:"provider,omitempty"`
+	Error    string `json:"error,omitempty"`
+	JARM     string `json:"jarm,omitempty"`
+	*tlsprobe.Info
+}
+
+// SoonestExpiry returns the days until the first of the certificates
+// expires, and the address serving it, "" when it is the one probed by
+// name. ok is false when no certificate was fetched.
+func (s *TLSSection) SoonestExpiry() (days int, ip string, ok bool) {
+	if s.Info != nil {
+		days, ok = s.DaysToExpiry, true
+	}
+	for _, ep := range s.Endpoints {
+		if ep.Info != nil && (!ok || ep.DaysToExpiry < days) {
+			days, ip, ok = ep.DaysToExpiry, ep.IP, true
+		}
+	}
+	return days, ip, ok
+}
+
+// ALPNSection holds the application protocols the domain's HTTPS endpoint
+// speaks and those its HTTPS records advertise
+type ALPNSection struct {
+	Status
+	*tlsprobe.ALPN
+	// Records are the domain's HTTPS records
+	Records []dns.HTTPSRecord `json:"records,omitempty"`
+	// Mismatches are where the records and the endpoint disagree
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// WebSection holds where the domain's websites lead
+type WebSection struct {
+	Status
+	Endpoints []WebEndpoint `json:"endpoints"`
+	// HSTSPreload is the preload status of a registrable domain
+	HSTSPreload *httpprobe.Preload `json:"hsts_preload,omitempty"`
+	// WellKnown is what the domain serves under /.well-known
+	WellKnown *httpprobe.WellKnown `json:"well_known,omitempty"`
+}
+
+// IPv6Section holds whether the domain's AAAA addresses accept connections
+type IPv6Section struct {
+	Status
+	Addresses []*reach.Result `json:"addresses"`
+}
+
+// Unreachable returns the addresses no port answered on, leaving out those
+// this host has no route to
+func (s *IPv6Section) Unreachable() []*reach.Result {
+	var down []*reach.Result
+	for _, r := range s.Addresses {
+		if !r.Reachable() && !r.NoRoute {
+			down = append(down, r)
+		}
+	}
+	return down
+}
+
+// WebEndpoint is the website of one host. A request failing partway keeps
+// the redirects followed before it.
+type WebEndpoint struct {
+	Host  string `json:"host"`
+	Error string `json:"error,omitempty"`
+	*httpprobe.Endpoint
+}
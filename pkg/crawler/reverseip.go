@@ -0,0 +1,41 @@
+package crawler
+
+// maxHostedDomains caps how many co-hosted domains are kept per IP
+const maxHostedDomains = 50
+
+// ReverseIPSection lists other domains hosted on the domain's addresses
+type ReverseIPSection struct {
+	Status
+	Source string         `json:"source"`
+	Hosts  []HostedOnAddr `json:"hosts"`
+}
+
+// HostedOnAddr holds the domains found on a single IP
+type HostedOnAddr struct {
+	IP      string   `json:"ip"`
+	Total   int      `json:"total"`
+	Domains []string `json:"domains,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlReverseIP(name string, records *RecordsSection) *ReverseIPSection {
+	if c.ReverseIP == nil {
+		return &ReverseIPSection{Status: Status{Error: "no reverse IP source configured"}}
+	}
+
+	section := &ReverseIPSection{Source: c.ReverseIP.Name()}
+	for _, rec := range records.Addresses() {
+		host := HostedOnAddr{IP: rec.Value}
+		domains, err := observe(c, name, "reverseip", rec.Value, func() ([]string, error) {
+			return c.ReverseIP.HostedDomains(rec.Value)
+		})
+		if err != nil {
+			host.Error = err.Error()
+		} else {
+			host.Total = len(domains)
+			host.Domains = domains[:min(len(domains), maxHostedDomains)]
+		}
+		section.Hosts = append(section.Hosts, host)
+	}
+	return section
+}
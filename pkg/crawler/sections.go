@@ -0,0 +1,216 @@
+package crawler
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+)
+
+// Sections of the crawl, for Options.Only and Options.Skip
+const (
+	SectionWhois       = "whois"
+	SectionNameservers = "nameservers"
+	SectionDNSSEC      = "dnssec"
+	SectionSOA         = "soa"
+	SectionRecursion   = "recursion"
+	SectionConsistency = "consistency"
+	SectionTrace       = "trace"
+	SectionRecords     = "records"
+	SectionPTR         = "ptr"
+	SectionASN         = "asn"
+	SectionReverseIP   = "reverse-ip"
+	SectionExposure    = "exposure"
+	SectionIntel       = "intel"
+	SectionReputation  = "reputation"
+	SectionBlocklists  = "blocklists"
+	SectionNXDomain    = "nxdomain"
+	SectionDeps        = "deps"
+	SectionEmail       = "email"
+	SectionTLS         = "tls"
+	SectionOCSP        = "ocsp"
+	SectionTLSScan     = "tls-scan"
+	SectionJARM        = "jarm"
+	SectionWeb         = "web"
+	SectionIPv6        = "ipv6"
+	SectionWWW         = "www"
+	SectionParking     = "parking"
+	SectionCAA         = "caa"
+	SectionOrg         = "org"
+)
+
+// Sections lists the sections in the order they are crawled
+var Sections = []string{
+	SectionWhois, SectionNameservers, SectionDNSSEC, SectionSOA, SectionRecursion,
+	SectionConsistency, SectionTrace, SectionRecords, SectionPTR, SectionASN,
+	SectionReverseIP, SectionExposure, SectionIntel, SectionReputation, SectionBlocklists,
+	SectionNXDomain, SectionDeps, SectionEmail, SectionTLS, SectionOCSP, SectionTLSScan,
+	SectionJARM, SectionWeb, SectionIPv6, SectionWWW, SectionParking, SectionCAA, SectionOrg,
+}
+
+// CheckSections fails on the first of names that isn't a section
+func CheckSections(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(Sections, name) {
+			return fmt.Errorf("unknown section %q (sections: %s)", name, strings.Join(Sections, ", "))
+		}
+	}
+	return nil
+}
+
+// Runs reports whether the crawl includes section. Skip excludes sections;
+// a non-empty Only includes exactly the sections it names, whatever the
+// other options say; otherwise the section's option decides, and the
+// sections without one run. TLS also runs for the checks built on it.
+func (o Options) Runs(section string) bool {
+	if slices.Contains(o.Skip, section) {
+		return false
+	}
+	if section == SectionTLS && (o.Runs(SectionOCSP) || o.Runs(SectionTLSScan) || o.Runs(SectionJARM)) {
+		return true
+	}
+	if len(o.Only) > 0 {
+		return slices.Contains(o.Only, section)
+	}
+	switch section {
+	case SectionWhois:
+		return !o.NoWhois
+	case SectionTrace:
+		return !o.NoTrace
+	case SectionASN:
+		return !o.NoASN
+	case SectionPTR:
+		return !o.NoPTR
+	case SectionDNSSEC:
+		return o.DNSSEC
+	case SectionSOA:
+		return o.SOA
+	case SectionRecursion:
+		return o.Recursion
+	case SectionConsistency:
+		return o.Consistency
+	case SectionReverseIP:
+		return o.ReverseIP
+	case SectionExposure:
+		return o.Exposure
+	case SectionIntel:
+		return o.ThreatIntel
+	case SectionReputation:
+		return o.Reputation
+	case SectionBlocklists:
+		return o.Blocklists
+	case SectionNXDomain:
+		return o.NXDomain
+	case SectionParking:
+		return o.Parking
+	case SectionDeps:
+		return o.Deps
+	case SectionTLS:
+		return o.TLS
+	case SectionOCSP:
+		return o.OCSP
+	case SectionTLSScan:
+		return o.TLSScan
+	case SectionJARM:
+		return o.JARM
+	case SectionWeb:
+		return o.Web
+	case SectionIPv6:
+		return o.IPv6
+	case SectionWWW:
+		return o.WWW
+	case SectionCAA:
+		return o.CAA
+	case SectionOrg:
+		return o.OrgLookup
+	}
+	return true
+}
+
+// Merge returns options running every section either o or other runs, and
+// the record types of both. Sort and NewDomainDays are o's unless o leaves
+// them unset.
+func (o Options) Merge(other Options) Options {
+	merged := Options{
+		NewDomainDays: cmp.Or(o.NewDomainDays, other.NewDomainDays),
+		Sort:          cmp.Or(o.Sort, other.Sort),
+	}
+	if len(o.RecordTypes) > 0 || len(other.RecordTypes) > 0 {
+		var types []string
+		for _, qtype := range slices.Concat(recordTypes(o), recordTypes(other)) {
+			types = append(types, strings.ToUpper(qtype))
+		}
+		slices.Sort(types)
+		merged.RecordTypes = slices.Compact(types)
+	}
+	for _, section := range Sections {
+		if o.Runs(section) || other.Runs(section) {
+			merged.Only = append(merged.Only, section)
+		}
+	}
+	return merged
+}
+
+// recordTypes returns the types of the records section
+func recordTypes(o Options) []string {
+	if len(o.RecordTypes) == 0 {
+		return dns.DefaultRecordTypes
+	}
+	return o.RecordTypes
+}
+
+// dependentTypes are the record types other sections are built on, by
+// the sections needing them
+var dependentTypes = []struct {
+	sections []string
+	types    []string
+}{
+	{[]string{SectionTLS, SectionIPv6, SectionWWW, SectionParking, SectionExposure, SectionReverseIP}, []string{"A", "AAAA"}},
+	{[]string{SectionWWW, SectionParking, SectionDeps}, []string{"CNAME"}},
+	{[]string{SectionDeps}, []string{"MX"}},
+	{[]string{SectionEmail}, []string{"TXT"}},
+}
+
+// lookupTypes returns the record types queried for the records section:
+// those of the section, and those of dependentTypes it leaves out that the
+// sections running need
+func lookupTypes(o Options) []string {
+	types := recordTypes(o)
+	for _, dep := range dependentTypes {
+		if !o.needs(dep.sections...) {
+			continue
+		}
+		for _, qtype := range dep.types {
+			if !hasType(types, qtype) {
+				types = append(slices.Clip(types), qtype)
+			}
+		}
+	}
+	return types
+}
+
+// hasType reports whether types, in any case, include qtype
+func hasType(types []string, qtype string) bool {
+	return slices.ContainsFunc(types, func(t string) bool { return strings.EqualFold(t, qtype) })
+}
+
+// needs reports whether any of sections runs, for the lookups that other
+// sections are built on
+func (o Options) needs(sections ...string) bool {
+	return slices.ContainsFunc(sections, o.Runs)
+}
+
+// needsNameservers reports whether the nameservers are looked up, to be
+// reported or as the input of other sections
+func (o Options) needsNameservers() bool {
+	return o.needs(SectionNameservers, SectionSOA, SectionRecursion, SectionConsistency, SectionDeps, SectionParking)
+}
+
+// needsRecords reports whether the records are looked up, to be reported
+// or as the input of other sections
+func (o Options) needsRecords() bool {
+	return o.needs(SectionRecords, SectionReverseIP, SectionExposure, SectionDeps, SectionEmail,
+		SectionTLS, SectionIPv6, SectionWWW, SectionParking)
+}
@@ -0,0 +1,115 @@
+package crawler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/reach"
+)
+
+// SOASection compares the SOA serial served by every authoritative address
+type SOASection struct {
+	Status
+	Primary    string      `json:"primary,omitempty"` // MNAME from the SOA record
+	Serial     uint32      `json:"serial"`            // serial of the primary, or the highest seen
+	Refresh    uint32      `json:"refresh"`
+	Consistent bool        `json:"consistent"`
+	Servers    []SOAServer `json:"servers"`
+}
+
+// SOAServer is the SOA answer of one authoritative address
+type SOAServer struct {
+	Name   string `json:"name"`
+	IP     string `json:"ip"`
+	Serial uint32 `json:"serial"`
+	Behind uint32 `json:"behind,omitempty"` // serials behind the reference serial
+	// Lag is how many seconds, at least, the server has been missing the
+	// reference serial, known only for date-based (YYYYMMDDnn) serials
+	Lag uint32 `json:"lag,omitempty"`
+	// Stale is set when Lag exceeds the zone's refresh interval, i.e. the
+	// server has missed at least one refresh
+	Stale bool   `json:"stale,omitempty"`
+	Error string `json:"error,omitempty"`
+	// NoRoute is set when this host has no route to the address, e.g. no
+	// IPv6 connectivity, so its serial is unknown rather than wrong
+	NoRoute bool `json:"no_route,omitempty"`
+}
+
+func (c *Crawler) crawlSOA(name string, nameservers *NameserverSection) *SOASection {
+	section := &SOASection{Consistent: true}
+	var primarySerial uint32
+	havePrimary := false
+
+	for _, addr := range c.nameserverAddrs(name, nameservers) {
+		server := SOAServer{Name: addr.Name, IP: addr.IP}
+		if addr.IP == "" {
+			server.Error = "no addresses"
+			section.Servers = append(section.Servers, server)
+			continue
+		}
+
+		soa, err := observe(c, name, "soa", fmt.Sprintf("%s@%s", name, addr.IP), func() (*dns.SOA, error) {
+			return c.Resolver.QuerySOA(name, addr.IP)
+		})
+		if err != nil {
+			server.Error = err.Error()
+			server.NoRoute = reach.IsNoRoute(err)
+		} else {
+			server.Serial = soa.Serial
+			section.Primary = soa.MName
+			section.Refresh = soa.Refresh
+			if soa.MName == addr.Name && !havePrimary {
+				primarySerial = soa.Serial
+				havePrimary = true
+			}
+			if section.Serial == 0 || serialNewer(soa.Serial, section.Serial) {
+				section.Serial = soa.Serial
+			}
+		}
+		section.Servers = append(section.Servers, server)
+	}
+
+	// Prefer the primary's serial as the reference; hidden primaries fall back to the newest
+	if havePrimary {
+		section.Serial = primarySerial
+	}
+
+	lag := serialAge(section.Serial, time.Now())
+	for i := range section.Servers {
+		s := &section.Servers[i]
+		if s.NoRoute {
+			continue
+		}
+		if s.Error != "" {
+			section.Consistent = false
+			continue
+		}
+		if s.Serial != section.Serial {
+			section.Consistent = false
+			if serialNewer(section.Serial, s.Serial) {
+				s.Behind = section.Serial - s.Serial
+				s.Lag = lag
+				s.Stale = lag > section.Refresh
+			}
+		}
+	}
+
+	return section
+}
+
+// serialNewer compares SOA serials using RFC 1982 serial number arithmetic
+func serialNewer(a, b uint32) bool {
+	return a != b && int32(a-b) > 0
+}
+
+// serialAge is how many seconds a date-based (YYYYMMDDnn) serial has existed
+// at least: it was published on its day at the latest, so it's been out
+// since that day ended. Other serials have no known age.
+func serialAge(serial uint32, now time.Time) uint32 {
+	day, err := time.Parse("20060102", fmt.Sprint(serial/100))
+	if err != nil || day.Year() < 1990 {
+		return 0
+	}
+	return uint32(max(now.Sub(day.Add(24*time.Hour)), 0) / time.Second)
+}
@@ -0,0 +1,95 @@
+package crawler
+
+import (
+	"cmp"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Orders of records and nameservers, for Options.Sort. Resolvers rotate
+// the answers they return, so every order is total: ties are broken by
+// value, keeping the output of successive runs comparable.
+const (
+	SortName     = "name"     // by value: addresses numerically, MX by preference
+	SortProvider = "provider" // grouped by provider, unknown providers last
+)
+
+// Sorts lists the supported orders
+var Sorts = []string{SortName, SortProvider}
+
+// CheckSort fails unless order is one of Sorts or empty
+func CheckSort(order string) error {
+	if order != "" && !slices.Contains(Sorts, order) {
+		return fmt.Errorf("unknown sort order %q (supported: %s)", order, strings.Join(Sorts, ", "))
+	}
+	return nil
+}
+
+func sortRecords(records []Record, order string) {
+	slices.SortStableFunc(records, func(a, b Record) int {
+		switch order {
+		case SortProvider:
+			if c := compareProviders(a.Provider, b.Provider); c != 0 {
+				return c
+			}
+		}
+		return compareValues(a.Value, b.Value)
+	})
+}
+
+func sortNameservers(servers []Nameserver, order string) {
+	slices.SortStableFunc(servers, func(a, b Nameserver) int {
+		switch order {
+		case SortProvider:
+			if c := compareProviders(a.Provider, b.Provider); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), compareValues(a.IP, b.IP))
+	})
+}
+
+// compareProviders orders providers alphabetically, with records of no
+// known provider last
+func compareProviders(a, b string) int {
+	if (a == "") != (b == "") {
+		if a == "" {
+			return 1
+		}
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// compareValues orders addresses numerically and values starting with a
+// number, such as MX and SRV records, by that number first; the rest
+// alphabetically
+func compareValues(a, b string) int {
+	addrA, errA := netip.ParseAddr(a)
+	addrB, errB := netip.ParseAddr(b)
+	if errA == nil && errB == nil {
+		return addrA.Compare(addrB)
+	}
+	numA, restA, okA := leadingNumber(a)
+	numB, restB, okB := leadingNumber(b)
+	if okA && okB {
+		return cmp.Or(cmp.Compare(numA, numB), compareValues(restA, restB))
+	}
+	return cmp.Or(strings.Compare(strings.ToLower(a), strings.ToLower(b)), strings.Compare(a, b))
+}
+
+// leadingNumber splits "10 mx.example.com" into 10 and "mx.example.com"
+func leadingNumber(s string) (uint64, string, bool) {
+	first, rest, found := strings.Cut(s, " ")
+	if !found {
+		return 0, "", false
+	}
+	n, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, rest, true
+}
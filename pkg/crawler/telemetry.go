@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records a span per crawled domain and per lookup. It uses the global
+// tracer provider, so nothing is recorded unless the program installs one.
+var tracer = otel.Tracer("github.com/auduny/dnscrawler/pkg/crawler")
+
+var (
+	attrDomain      = attribute.Key("dnscrawler.domain")
+	attrRootContext = attribute.Key("dnscrawler.root_context")
+	attrRegistered  = attribute.Key("dnscrawler.registered")
+	attrKind        = attribute.Key("dnscrawler.lookup.kind")
+	attrTarget      = attribute.Key("dnscrawler.lookup.target")
+	attrCached      = attribute.Key("dnscrawler.lookup.cached")
+)
+
+// withContext returns a copy of the crawler whose lookup spans are children
+// of ctx, and whose DNS queries stop waiting for a rate limit token once ctx
+// is done
+func (c *Crawler) withContext(ctx context.Context) *Crawler {
+	cp := *c
+	cp.ctx = ctx
+	if c.Resolver != nil {
+		cp.Resolver = c.Resolver.WithContext(ctx)
+	}
+	return &cp
+}
+
+func (c *Crawler) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// startLookup opens the span of a single lookup
+func startLookup(c *Crawler, ev Event) trace.Span {
+	_, span := tracer.Start(c.context(), ev.Kind, trace.WithAttributes(
+		attrDomain.String(ev.Domain),
+		attrKind.String(ev.Kind),
+		attrTarget.String(ev.Target),
+	))
+	return span
+}
+
+// endLookup closes a lookup span, marking it failed when the lookup returned an error
+func endLookup(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,37 @@
+package crawler
+
+import "github.com/auduny/dnscrawler/pkg/intel"
+
+// ThreatIntelSection holds reports from the configured threat-intelligence sources
+type ThreatIntelSection struct {
+	Status
+	Reports []ThreatReport `json:"reports"`
+}
+
+// ThreatReport is one source's report, or the error it returned
+type ThreatReport struct {
+	*intel.ThreatReport
+	Source string `json:"source"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (c *Crawler) crawlThreatIntel(name string) *ThreatIntelSection {
+	if len(c.Threat) == 0 {
+		return &ThreatIntelSection{Status: Status{Error: "no threat intel sources configured"}}
+	}
+
+	section := &ThreatIntelSection{}
+	for _, src := range c.Threat {
+		entry := ThreatReport{Source: src.Name()}
+		report, err := observe(c, name, "threat", src.Name(), func() (*intel.ThreatReport, error) {
+			return src.Report(name)
+		})
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.ThreatReport = report
+		}
+		section.Reports = append(section.Reports, entry)
+	}
+	return section
+}
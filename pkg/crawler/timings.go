@@ -0,0 +1,94 @@
+package crawler
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxSlowest is how many of the slowest lookups Timings keeps
+const maxSlowest = 10
+
+// KindTiming sums up the lookups of one kind
+type KindTiming struct {
+	Kind   string
+	Count  int           // lookups performed, errors included
+	Errors int           // lookups that failed
+	Cached int           // lookups answered from data collected earlier in the crawl
+	Total  time.Duration // time spent in the performed lookups
+	Max    time.Duration // slowest single lookup
+}
+
+// Timings records how long every lookup takes, so a run can report whether
+// WHOIS, the trace or the ASN lookups dominate its runtime. Register its
+// Hooks on the crawlers to measure; it is safe for concurrent crawls.
+type Timings struct {
+	mu      sync.Mutex
+	kinds   map[string]*KindTiming
+	slowest []Event // ordered slowest first
+}
+
+// Hooks returns the hooks feeding t
+func (t *Timings) Hooks() Hooks {
+	return Hooks{
+		OnResult: t.record,
+		OnError:  t.record,
+	}
+}
+
+func (t *Timings) record(ev Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.kinds == nil {
+		t.kinds = make(map[string]*KindTiming)
+	}
+	k, ok := t.kinds[ev.Kind]
+	if !ok {
+		k = &KindTiming{Kind: ev.Kind}
+		t.kinds[ev.Kind] = k
+	}
+	if ev.Cached {
+		k.Cached++
+		return
+	}
+	k.Count++
+	if ev.Err != nil {
+		k.Errors++
+	}
+	k.Total += ev.Duration
+	k.Max = max(k.Max, ev.Duration)
+
+	if len(t.slowest) == maxSlowest && ev.Duration <= t.slowest[maxSlowest-1].Duration {
+		return
+	}
+	ev.Data = nil // don't keep results alive
+	i, _ := slices.BinarySearchFunc(t.slowest, ev.Duration, func(e Event, d time.Duration) int {
+		return cmp.Compare(d, e.Duration)
+	})
+	t.slowest = slices.Insert(t.slowest, i, ev)
+	if len(t.slowest) > maxSlowest {
+		t.slowest = t.slowest[:maxSlowest]
+	}
+}
+
+// Kinds returns the totals per lookup kind, the most time-consuming first
+func (t *Timings) Kinds() []KindTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]KindTiming, 0, len(t.kinds))
+	for _, k := range t.kinds {
+		out = append(out, *k)
+	}
+	slices.SortFunc(out, func(a, b KindTiming) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Kind, b.Kind))
+	})
+	return out
+}
+
+// Slowest returns the slowest individual lookups, slowest first
+func (t *Timings) Slowest() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.slowest)
+}
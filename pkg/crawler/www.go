@@ -0,0 +1,185 @@
+package crawler
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/httpprobe"
+	"github.com/auduny/dnscrawler/pkg/tlsprobe"
+)
+
+// WWWSection compares a registrable domain with its www name, which
+// visitors expect to be the same site
+type WWWSection struct {
+	Status
+	Apex WWWHost `json:"apex"`
+	WWW  WWWHost `json:"www"`
+	// Canonical is the host both sites lead to, "" when they don't lead to
+	// the same one
+	Canonical string `json:"canonical,omitempty"`
+	// Issues are where the two differ in ways visitors notice
+	Issues []string `json:"issues,omitempty"`
+}
+
+// WWWHost is how one of the names resolves and answers
+type WWWHost struct {
+	Name      string   `json:"name"`
+	CNAME     []string `json:"cname,omitempty"`
+	Addresses []Record `json:"addresses,omitempty"`
+	// Hosting are the providers of the addresses, or their networks when
+	// the provider isn't known
+	Hosting  []string `json:"hosting,omitempty"`
+	FinalURL string   `json:"final_url,omitempty"`
+	// CertificateError is why the certificate served on the name doesn't
+	// cover it, or why none could be fetched
+	CertificateError string `json:"certificate_error,omitempty"`
+
+	httpError string
+}
+
+// crawlWWW resolves and fetches the www name next to the domain, reusing
+// what the TLS and web checks found for the domain itself
+func (c *Crawler) crawlWWW(name string, result *Result) *WWWSection {
+	www := "www." + name
+	section := &WWWSection{
+		Apex: WWWHost{Name: name},
+		WWW:  WWWHost{Name: www},
+	}
+	wwwRecords := c.crawlRecords(www, result.ASN)
+	if wwwRecords.Failed() {
+		section.Error = wwwRecords.Error
+		return section
+	}
+	section.Apex.resolution(result.Records.resolved())
+	section.WWW.resolution(wwwRecords.resolved())
+
+	// The sites, from the web check when it ran
+	endpoints := make(map[string]WebEndpoint)
+	if result.Web != nil {
+		for _, site := range result.Web.Endpoints {
+			endpoints[site.Host] = site
+		}
+	}
+	for _, h := range []*WWWHost{&section.Apex, &section.WWW} {
+		site, ok := endpoints[h.Name]
+		if !ok && len(h.Addresses) > 0 {
+			ep, err := observe(c, name, "http", h.Name, func() (*httpprobe.Endpoint, error) {
+				return c.HTTP.Probe(h.Name)
+			})
+			site = WebEndpoint{Host: h.Name, Endpoint: ep}
+			if err != nil {
+				site.Error = err.Error()
+			}
+		}
+		if site.Endpoint != nil && site.Error == "" {
+			h.FinalURL = site.FinalURL
+		} else {
+			h.httpError = site.Error
+		}
+	}
+
+	// The certificates, from the TLS check when it ran
+	for _, h := range []*WWWHost{&section.Apex, &section.WWW} {
+		if len(h.Addresses) == 0 {
+			continue
+		}
+		var info *tlsprobe.Info
+		if h.Name == name && result.TLS != nil && !result.TLS.Failed() {
+			info = result.TLS.Info
+		} else {
+			var err error
+			info, err = observe(c, name, "tls", h.Name, func() (*tlsprobe.Info, error) {
+				return c.TLS.Probe(h.Name)
+			})
+			if err != nil {
+				h.CertificateError = err.Error()
+				continue
+			}
+		}
+		if !info.Valid {
+			h.CertificateError = info.VerifyError
+		}
+	}
+
+	section.compare()
+	return section
+}
+
+func (h *WWWHost) resolution(records *RecordsSection) {
+	if records == nil {
+		return
+	}
+	for _, rec := range records.CNAME {
+		h.CNAME = append(h.CNAME, rec.Value)
+	}
+	h.Addresses = records.Addresses()
+	for _, rec := range h.Addresses {
+		// Without a known provider, Provider is the PTR name, which differs
+		// between addresses of the same network
+		hosting := rec.Provider
+		if hosting == "" || hosting == strings.TrimRight(rec.PTR, ".") {
+			hosting = cmp.Or(rec.ASN, hosting)
+		}
+		if hosting != "" && !slices.Contains(h.Hosting, hosting) {
+			h.Hosting = append(h.Hosting, hosting)
+		}
+	}
+}
+
+// compare fills in Canonical and Issues
+func (s *WWWSection) compare() {
+	apex, www := &s.Apex, &s.WWW
+	switch {
+	case len(apex.Addresses) == 0 && len(www.Addresses) == 0:
+		return
+	case len(www.Addresses) == 0:
+		s.Issues = append(s.Issues, fmt.Sprintf("%s doesn't resolve, visitors typing it get an error", www.Name))
+		return
+	case len(apex.Addresses) == 0:
+		s.Issues = append(s.Issues, fmt.Sprintf("%s doesn't resolve, visitors typing it get an error", apex.Name))
+		return
+	}
+
+	// Where they are hosted: only told apart when both are known
+	if len(apex.Hosting) > 0 && len(www.Hosting) > 0 && !overlaps(apex.Hosting, www.Hosting) {
+		s.Issues = append(s.Issues, fmt.Sprintf("www on %s, but the apex on %s", strings.Join(www.Hosting, ", "), strings.Join(apex.Hosting, ", ")))
+	}
+
+	// Which way they redirect
+	apexHost, wwwHost := hostOf(apex.FinalURL), hostOf(www.FinalURL)
+	switch {
+	case apex.FinalURL == "" && www.FinalURL == "":
+	case apex.FinalURL == "":
+		s.Issues = append(s.Issues, fmt.Sprintf("%s has no website: %s", apex.Name, cmp.Or(apex.httpError, "no answer")))
+	case www.FinalURL == "":
+		s.Issues = append(s.Issues, fmt.Sprintf("%s has no website: %s", www.Name, cmp.Or(www.httpError, "no answer")))
+	case apexHost == wwwHost:
+		s.Canonical = apexHost
+	case apexHost == apex.Name && wwwHost == www.Name:
+		s.Issues = append(s.Issues, "the apex and www serve the site without redirecting to one another, so it has two addresses")
+	default:
+		s.Issues = append(s.Issues, fmt.Sprintf("the apex leads to %s, but www to %s", apex.FinalURL, www.FinalURL))
+	}
+
+	// Whether the certificates cover both
+	for _, h := range []*WWWHost{apex, www} {
+		if h.CertificateError != "" && h.FinalURL != "" {
+			s.Issues = append(s.Issues, fmt.Sprintf("https://%s/ has no valid certificate: %s", h.Name, h.CertificateError))
+		}
+	}
+}
+
+func overlaps(a, b []string) bool {
+	return slices.ContainsFunc(a, func(s string) bool { return slices.Contains(b, s) })
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
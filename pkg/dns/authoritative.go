@@ -0,0 +1,297 @@
+package dns
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// SOA holds the fields of a zone's start-of-authority record
+type SOA struct {
+	MName   string `json:"mname"`
+	RName   string `json:"rname"`
+	Serial  uint32 `json:"serial"`
+	Refresh uint32 `json:"refresh"`
+	Retry   uint32 `json:"retry"`
+	Expire  uint32 `json:"expire"`
+	Minimum uint32 `json:"minimum"`
+}
+
+// WithAuthoritative sends the queries for the records of the crawled names
+// (GetRecords, LookupTXT and LookupRaw) to server without recursion,
+// instead of the recursive resolver, e.g. to check a pending zone change on
+// one secondary. The server is a host[:port], port 53 by default; a host
+// name is resolved through the recursive resolver on first use.
+func WithAuthoritative(server string) Option {
+	return func(r *Resolver) {
+		if server != "" {
+			r.authoritative = &authoritativeServer{name: server}
+		}
+	}
+}
+
+// Authoritative returns the server of WithAuthoritative as given, or ""
+// when records are asked of the recursive resolver
+func (r *Resolver) Authoritative() string {
+	if r.authoritative == nil {
+		return ""
+	}
+	return r.authoritative.name
+}
+
+// authoritativeServer is the server of WithAuthoritative, whose address is
+// looked up once
+type authoritativeServer struct {
+	name string
+
+	once sync.Once
+	addr string
+	err  error
+}
+
+// address returns the host:port the server's queries go to
+func (a *authoritativeServer) address(r *Resolver) (string, error) {
+	a.once.Do(func() {
+		host, port, err := net.SplitHostPort(a.name)
+		if err != nil {
+			host, port = strings.Trim(a.name, "[]"), "53"
+		}
+		if _, err := netip.ParseAddr(host); err != nil {
+			addrs := r.LookupAddrs(host)
+			if len(addrs) == 0 {
+				a.err = fmt.Errorf("server %s: no address found", host)
+				return
+			}
+			host = addrs[0]
+		}
+		a.addr = net.JoinHostPort(host, port)
+	})
+	return a.addr, a.err
+}
+
+// queryZone asks for the records of name. inZone marks the records of a
+// crawled name, which go to the server of WithAuthoritative when one is
+// set; it must then answer them authoritatively. Everything else is asked
+// of the recursive resolver.
+func (r *Resolver) queryZone(name string, qtype uint16, inZone bool) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+	m.RecursionDesired = !inZone || r.authoritative == nil
+	if m.RecursionDesired {
+		return r.exchange(m, defaultServer)
+	}
+
+	server, err := r.authoritative.address(r)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.exchange(m, server)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("%s answered %s", r.authoritative.name, dns.RcodeToString[resp.Rcode])
+	}
+	if !resp.Authoritative {
+		return nil, fmt.Errorf("%s isn't authoritative for %s", r.authoritative.name, strings.TrimSuffix(name, "."))
+	}
+	return resp, nil
+}
+
+// LookupAddrs returns all IPv4 and IPv6 addresses of a host
+func (r *Resolver) LookupAddrs(host string) []string {
+	host = dns.Fqdn(host)
+	addrs, _ := r.queryRecords(host, dns.TypeA, false)
+	addrs6, _ := r.queryRecords(host, dns.TypeAAAA, false)
+	return append(addrs, addrs6...)
+}
+
+// QuerySOA asks a specific server (without recursion) for the SOA of zone
+func (r *Resolver) QuerySOA(zone, serverIP string) (*SOA, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(zone), dns.TypeSOA)
+	m.RecursionDesired = false
+
+	resp, err := r.exchange(m, net.JoinHostPort(serverIP, "53"))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("server answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return &SOA{
+				MName:   strings.TrimSuffix(soa.Ns, "."),
+				RName:   strings.TrimSuffix(soa.Mbox, "."),
+				Serial:  soa.Serial,
+				Refresh: soa.Refresh,
+				Retry:   soa.Retry,
+				Expire:  soa.Expire,
+				Minimum: soa.Minttl,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no SOA in answer")
+}
+
+// recursionProbeName is an unrelated, always-resolvable name used to test for open recursion
+const recursionProbeName = "www.iana.org."
+
+// TestRecursion sends a recursive query for an unrelated name to serverIP and
+// reports whether the server resolved it, i.e. acts as an open resolver.
+func (r *Resolver) TestRecursion(serverIP string) (bool, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(recursionProbeName, dns.TypeA)
+	m.RecursionDesired = true
+
+	resp, err := r.exchange(m, net.JoinHostPort(serverIP, "53"))
+	if err != nil {
+		return false, err
+	}
+
+	return resp.RecursionAvailable && resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0, nil
+}
+
+// QueryRRset asks serverIP (without recursion) for the records of the given
+// type and returns their values sorted, so answers from different servers can
+// be compared directly.
+func (r *Resolver) QueryRRset(name, qtype, serverIP string) ([]string, error) {
+	t, ok := dns.StringToType[strings.ToUpper(qtype)]
+	if !ok {
+		return nil, fmt.Errorf("unknown record type %q", qtype)
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), t)
+	m.RecursionDesired = false
+
+	resp, err := r.exchange(m, net.JoinHostPort(serverIP, "53"))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("server answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	var values []string
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != t {
+			continue
+		}
+		values = append(values, rdata(rr))
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// rdata returns the presentation form of a record without its header
+func rdata(rr dns.RR) string {
+	hdr := rr.Header().String()
+	return strings.TrimPrefix(rr.String(), hdr)
+}
+
+// NXProbe is the answer to a query for a name that should not exist
+type NXProbe struct {
+	Name    string   `json:"name"`
+	Rcode   string   `json:"rcode"`
+	Answers []string `json:"answers,omitempty"`
+	// NegativeTTL is how long resolvers cache the negative answer:
+	// the lower of the SOA record's TTL and its MINIMUM field
+	NegativeTTL uint32 `json:"negative_ttl,omitempty"`
+	HasSOA      bool   `json:"has_soa"`
+}
+
+// ProbeNonexistent asks an authoritative server of zone, without recursion,
+// for a label below zone (see NonexistentName) and reports how the
+// nonexistent name is answered.
+func (r *Resolver) ProbeNonexistent(zone string) (*NXProbe, error) {
+	zone = dns.Fqdn(strings.ToLower(zone))
+	name := dns.Fqdn(r.NonexistentName(zone))
+
+	servers, err := r.zoneServers(zone)
+	if err != nil {
+		return nil, err
+	}
+	m := new(dns.Msg)
+	m.SetQuestion(name, dns.TypeA)
+	m.RecursionDesired = false
+
+	var resp *dns.Msg
+	for _, ip := range servers {
+		resp, err = r.exchange(m, net.JoinHostPort(ip, "53"))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	probe := &NXProbe{
+		Name:  strings.TrimSuffix(name, "."),
+		Rcode: dns.RcodeToString[resp.Rcode],
+	}
+	for _, rr := range resp.Answer {
+		probe.Answers = append(probe.Answers, rdata(rr))
+	}
+	for _, rr := range resp.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			probe.HasSOA = true
+			probe.NegativeTTL = min(soa.Hdr.Ttl, soa.Minttl)
+		}
+	}
+	return probe, nil
+}
+
+// NonexistentName returns a name below zone for ProbeNonexistent to ask
+// for. The label is random, so no server can answer that one name
+// specially, except when responses are recorded or replayed: it is then a
+// hash of the zone, the same on every run, so the recording replays.
+func (r *Resolver) NonexistentName(zone string) string {
+	zone = dns.Fqdn(strings.ToLower(zone))
+	var label []byte
+	if r.fixtures != nil {
+		sum := sha256.Sum256([]byte(zone))
+		label = sum[:8]
+	} else {
+		label = make([]byte, 8)
+		rand.Read(label)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("dnscrawler-%x.%s", label, zone), ".")
+}
+
+// zoneServers returns the addresses of the authoritative servers of the
+// zone name belongs to, walking up from name to the first label with NS
+// records
+func (r *Resolver) zoneServers(name string) ([]string, error) {
+	for zone := dns.Fqdn(name); ; {
+		nameservers, err := r.LookupNS(zone)
+		if err != nil {
+			return nil, err
+		}
+		var addrs []string
+		for _, ns := range nameservers {
+			addrs = append(addrs, r.LookupAddrs(ns)...)
+		}
+		if len(nameservers) > 0 {
+			if len(addrs) == 0 {
+				return nil, fmt.Errorf("no address found for the nameservers of %s", strings.TrimSuffix(zone, "."))
+			}
+			return addrs, nil
+		}
+		parent, end := dns.NextLabel(zone, 0)
+		if end || zone == "." {
+			return nil, fmt.Errorf("no nameservers found for %s", strings.TrimSuffix(name, "."))
+		}
+		zone = zone[parent:]
+	}
+}
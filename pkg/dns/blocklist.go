@@ -0,0 +1,134 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Blocklist is a DNS-based domain blocklist (RHSBL) queried as <domain>.<zone>
+type Blocklist struct {
+	Name string
+	Zone string
+	// decode maps a return code to its listing reasons, or an error for codes
+	// the list uses to signal refused or malformed queries
+	decode func(ip net.IP) ([]string, error)
+}
+
+// DomainBlocklists are the domain-name blocklists checked by CheckBlocklist
+var DomainBlocklists = []Blocklist{
+	{Name: "Spamhaus DBL", Zone: "dbl.spamhaus.org", decode: decodeDBL},
+	{Name: "SURBL", Zone: "multi.surbl.org", decode: decodeSURBL},
+	{Name: "URIBL", Zone: "multi.uribl.com", decode: decodeURIBL},
+}
+
+// Listing is a blocklist's answer for a domain
+type Listing struct {
+	Listed  bool     `json:"listed"`
+	Codes   []string `json:"codes,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// CheckBlocklist queries bl for domain. A domain that is not listed yields a
+// Listing with Listed false; refused queries (e.g. from public resolvers,
+// which most lists block) are returned as errors.
+func (r *Resolver) CheckBlocklist(domain string, bl Blocklist) (*Listing, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(strings.TrimSuffix(domain, ".")+"."+bl.Zone), dns.TypeA)
+	m.RecursionDesired = true
+
+	resp, err := r.exchange(m, defaultServer)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode == dns.RcodeNameError {
+		return &Listing{}, nil
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("server answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	listing := &Listing{}
+	for _, ans := range resp.Answer {
+		a, ok := ans.(*dns.A)
+		if !ok {
+			continue
+		}
+		reasons, err := bl.decode(a.A.To4())
+		if err != nil {
+			return nil, err
+		}
+		listing.Listed = true
+		listing.Codes = append(listing.Codes, a.A.String())
+		listing.Reasons = append(listing.Reasons, reasons...)
+	}
+	return listing, nil
+}
+
+var dblCodes = map[byte]string{
+	2:   "spam",
+	4:   "phishing",
+	5:   "malware",
+	6:   "botnet C&C",
+	102: "abused legit spam",
+	103: "abused legit redirector",
+	104: "abused legit phishing",
+	105: "abused legit malware",
+	106: "abused legit botnet C&C",
+}
+
+// decodeDBL interprets Spamhaus DBL codes (127.0.1.x); 127.255.255.x are errors
+func decodeDBL(ip net.IP) ([]string, error) {
+	if ip == nil || ip[0] != 127 {
+		return nil, fmt.Errorf("unexpected answer %v", ip)
+	}
+	if ip[1] == 255 && ip[2] == 255 {
+		switch ip[3] {
+		case 254:
+			return nil, fmt.Errorf("query refused: public resolvers are blocked")
+		case 255:
+			return nil, fmt.Errorf("query refused: excessive queries")
+		}
+		return nil, fmt.Errorf("query refused (%v)", ip)
+	}
+	if reason, ok := dblCodes[ip[3]]; ok {
+		return []string{reason}, nil
+	}
+	return []string{"listed"}, nil
+}
+
+// decodeSURBL interprets the SURBL multi bitmask; 127.0.0.1 means the query was blocked
+func decodeSURBL(ip net.IP) ([]string, error) {
+	return decodeBitmask(ip, []bitReason{{8, "phishing"}, {16, "malware"}, {64, "abuse"}, {128, "cracked site"}})
+}
+
+// decodeURIBL interprets the URIBL multi bitmask; 127.0.0.1 means the query was refused
+func decodeURIBL(ip net.IP) ([]string, error) {
+	return decodeBitmask(ip, []bitReason{{2, "black"}, {4, "grey"}, {8, "red"}})
+}
+
+type bitReason struct {
+	bit    byte
+	reason string
+}
+
+func decodeBitmask(ip net.IP, bits []bitReason) ([]string, error) {
+	if ip == nil || ip[0] != 127 {
+		return nil, fmt.Errorf("unexpected answer %v", ip)
+	}
+	if ip[3] == 1 {
+		return nil, fmt.Errorf("query refused: public resolvers are blocked")
+	}
+	var reasons []string
+	for _, b := range bits {
+		if ip[3]&b.bit != 0 {
+			reasons = append(reasons, b.reason)
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = []string{"listed"}
+	}
+	return reasons, nil
+}
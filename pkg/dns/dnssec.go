@@ -0,0 +1,108 @@
+package dns
+
+import (
+	"fmt"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+
+	"github.com/miekg/dns"
+)
+
+// DNSSEC describes the signing state of a zone as seen by a validating resolver
+type DNSSEC struct {
+	// DS records published in the parent zone
+	DS []string `json:"ds,omitempty"`
+	// DNSKEY algorithms published in the zone itself
+	Algorithms []string `json:"algorithms,omitempty"`
+	// Validated is set when the resolver authenticated the zone's DNSKEY set
+	Validated bool `json:"validated"`
+}
+
+// Signed reports whether the zone has a chain of trust from its parent
+func (d *DNSSEC) Signed() bool {
+	return len(d.DS) > 0
+}
+
+// LookupDNSSEC fetches the zone's DS and DNSKEY records and whether the
+// resolver could validate them.
+func (r *Resolver) LookupDNSSEC(zone string) (*DNSSEC, error) {
+	zone = dns.Fqdn(zone)
+	result := &DNSSEC{}
+
+	resp, err := r.exchange(dnssecQuery(zone, dns.TypeDS), defaultServer)
+	if err != nil {
+		return nil, err
+	}
+	for _, ans := range resp.Answer {
+		if ds, ok := ans.(*dns.DS); ok {
+			result.DS = append(result.DS, rdata(ds))
+		}
+	}
+
+	resp, err = r.exchange(dnssecQuery(zone, dns.TypeDNSKEY), defaultServer)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode == dns.RcodeServerFailure && result.Signed() {
+		// A validating resolver returns SERVFAIL for a broken chain of trust
+		return result, nil
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("server answered %s", dns.RcodeToString[resp.Rcode])
+	}
+	seen := make(map[string]bool)
+	for _, ans := range resp.Answer {
+		if key, ok := ans.(*dns.DNSKEY); ok {
+			alg := dns.AlgorithmToString[key.Algorithm]
+			if alg == "" {
+				alg = fmt.Sprint(key.Algorithm)
+			}
+			if !seen[alg] {
+				seen[alg] = true
+				result.Algorithms = append(result.Algorithms, alg)
+			}
+		}
+	}
+	result.Validated = resp.AuthenticatedData
+	return result, nil
+}
+
+func dnssecQuery(zone string, qtype uint16) *dns.Msg {
+	m := new(dns.Msg)
+	m.SetQuestion(zone, qtype)
+	m.RecursionDesired = true
+	m.AuthenticatedData = true
+	m.SetEdns0(4096, true)
+	return m
+}
+
+// LookupCAA returns the CAA records that apply to name. Like a certificate
+// authority, it climbs towards the root until a name with CAA records is
+// found, and returns that name alongside the records.
+func (r *Resolver) LookupCAA(name string) (string, []string, error) {
+	for zone := range domain.Hierarchy(name) {
+		// The TLD is not checked
+		if domain.LabelCount(zone) < 2 {
+			break
+		}
+		m := new(dns.Msg)
+		m.SetQuestion(dns.Fqdn(zone), dns.TypeCAA)
+		m.RecursionDesired = true
+
+		resp, err := r.exchange(m, defaultServer)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var records []string
+		for _, ans := range resp.Answer {
+			if caa, ok := ans.(*dns.CAA); ok {
+				records = append(records, fmt.Sprintf("%d %s %q", caa.Flag, caa.Tag, caa.Value))
+			}
+		}
+		if len(records) > 0 {
+			return zone, records, nil
+		}
+	}
+	return "", nil, nil
+}
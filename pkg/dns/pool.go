@@ -0,0 +1,162 @@
+package dns
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// maxIdleConns is how many idle connections are kept per server
+	maxIdleConns = 8
+	// idleConnTimeout is how long an idle connection is kept; servers close
+	// idle TCP connections after a few seconds anyway
+	idleConnTimeout = 10 * time.Second
+	// keepAlive is the TCP keep-alive period of stream connections
+	keepAlive = 30 * time.Second
+)
+
+// connPool keeps idle connections per client and server, so consecutive
+// queries to the same server don't each dial a new socket
+type connPool struct {
+	forward Dialer // opens stream connections when set
+	// source returns the local address of direct connections when set
+	source func(network, addr string) (net.Addr, error)
+
+	mu     sync.Mutex
+	idle   map[poolKey][]idleConn
+	closed bool
+}
+
+type poolKey struct {
+	client *dns.Client
+	server string
+}
+
+type idleConn struct {
+	conn  *dns.Conn
+	since time.Time
+}
+
+func newConnPool() *connPool {
+	return &connPool{idle: make(map[poolKey][]idleConn)}
+}
+
+// get returns an idle connection to server, or nil when there is none
+func (p *connPool) get(key poolKey) *dns.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := p.idle[key]
+	for len(conns) > 0 {
+		c := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		if time.Since(c.since) < idleConnTimeout {
+			p.idle[key] = conns
+			return c.conn
+		}
+		c.conn.Close()
+	}
+	delete(p.idle, key)
+	return nil
+}
+
+// put returns a healthy connection to the pool, closing it when the pool is full
+func (p *connPool) put(key poolKey, conn *dns.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || len(p.idle[key]) >= maxIdleConns {
+		conn.Close()
+		return
+	}
+	p.idle[key] = append(p.idle[key], idleConn{conn: conn, since: time.Now()})
+}
+
+// close closes every idle connection; later connections are not pooled
+func (p *connPool) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for _, conns := range p.idle {
+		for _, c := range conns {
+			errs = append(errs, c.conn.Close())
+		}
+	}
+	p.idle = nil
+	p.closed = true
+	return errors.Join(errs...)
+}
+
+// exchange sends m to server over a pooled connection of client. A pooled
+// connection the server has closed in the meantime is replaced once.
+func (p *connPool) exchange(client *dns.Client, m *dns.Msg, server string) (*dns.Msg, error) {
+	key := poolKey{client: client, server: server}
+	for {
+		conn := p.get(key)
+		reused := conn != nil
+		if !reused {
+			var err error
+			if conn, err = p.dial(client, server); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, _, err := client.ExchangeWithConn(m, conn)
+		if err != nil {
+			conn.Close()
+			var netErr net.Error
+			if reused && !(errors.As(err, &netErr) && netErr.Timeout()) {
+				continue
+			}
+			return nil, err
+		}
+		p.put(key, conn)
+		return resp, nil
+	}
+}
+
+// dial connects client to server, opening TCP and TLS connections with the
+// pool's forward dialer when it has one
+func (p *connPool) dial(client *dns.Client, server string) (*dns.Conn, error) {
+	if p.forward == nil || client.Net == "" || client.Net == "udp" {
+		if p.source != nil {
+			var err error
+			if client, err = p.bound(client, server); err != nil {
+				return nil, err
+			}
+		}
+		return client.Dial(server)
+	}
+	conn, err := p.forward.Dial("tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	if client.Net == "tcp-tls" {
+		conn = tls.Client(conn, client.TLSConfig)
+	}
+	return &dns.Conn{Conn: conn}, nil
+}
+
+// bound returns a copy of client dialing server from the pool's source
+func (p *connPool) bound(client *dns.Client, server string) (*dns.Client, error) {
+	d := net.Dialer{Timeout: client.Timeout}
+	if client.Dialer != nil {
+		d = *client.Dialer
+	}
+	network := "udp"
+	if strings.HasPrefix(client.Net, "tcp") {
+		network = "tcp"
+	}
+	local, err := p.source(network, server)
+	if err != nil {
+		return nil, err
+	}
+	d.LocalAddr = local
+	cp := *client
+	cp.Dialer = &d
+	return &cp, nil
+}
@@ -1,26 +1,133 @@
 package dns
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+
 	"github.com/miekg/dns"
 )
 
+// defaultServer is the recursive resolver used for all non-trace queries,
+// unless WithUpstreams sets others; queries to it go to the pool instead
+const defaultServer = "8.8.8.8:53"
+
+// defaultTLSServer is the DNS-over-TLS endpoint of defaultServer, and
+// defaultTLSName the name on its certificate
+const (
+	defaultTLSServer = "8.8.8.8:853"
+	defaultTLSName   = "dns.google"
+)
+
+// DefaultTimeout bounds a single DNS query, including connecting
+const DefaultTimeout = 5 * time.Second
+
+// DefaultTraceTimeout bounds a whole trace, from the root to the last zone
+const DefaultTraceTimeout = 30 * time.Second
+
 type Resolver struct {
-	client *dns.Client
+	client       *dns.Client // UDP queries sent directly to nameservers
+	recursive    *dns.Client // template of the clients of the recursive resolvers
+	upstreams    *upstreamPool
+	pool         *connPool
+	fixtures     *fixture.Store
+	limit        *ratelimit.Global
+	traceTimeout time.Duration
+
+	upstreamAddrs []string // of WithUpstreams
+	strategy      string
+
+	authoritative *authoritativeServer // of WithAuthoritative
+
+	ctx context.Context // of WithContext, bounds the wait for a token
+}
+
+// Option configures a Resolver
+type Option func(*Resolver)
+
+// WithFixtures routes every query through a fixture store for record/replay
+func WithFixtures(s *fixture.Store) Option {
+	return func(r *Resolver) {
+		r.fixtures = s
+	}
+}
+
+// WithLimit takes a token of g before every query sent to the network
+func WithLimit(g *ratelimit.Global) Option {
+	return func(r *Resolver) {
+		r.limit = g
+	}
+}
+
+// WithTimeout sets the timeout of every single query
+func WithTimeout(d time.Duration) Option {
+	return func(r *Resolver) {
+		r.client.Timeout = d
+		r.recursive.Timeout = d
+		r.recursive.Dialer.Timeout = d
+	}
+}
+
+// WithTraceTimeout bounds the total time of a trace; zero means no limit
+// beyond the timeouts of the individual queries
+func WithTraceTimeout(d time.Duration) Option {
+	return func(r *Resolver) {
+		r.traceTimeout = d
+	}
+}
+
+// Dialer opens the stream connections to the recursive resolver, e.g.
+// through a proxy
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// WithDialer opens every connection with d. UDP can't be tunnelled, so
+// with a dialer all queries go over TCP, including the trace queries to
+// nameservers, and the recursive resolver is queried over "tcp" unless
+// WithNetwork selects "tcp-tls".
+func WithDialer(d Dialer) Option {
+	return func(r *Resolver) {
+		r.pool.forward = d
+	}
+}
+
+// WithSource sends queries from the local address source returns for the
+// network ("udp" or "tcp") and address of the server, e.g.
+// bind.Source.LocalAddr. A query fails when source does. Stream
+// connections opened by the dialer of WithDialer are bound by that dialer
+// instead.
+func WithSource(source func(network, addr string) (net.Addr, error)) Option {
+	return func(r *Resolver) {
+		r.pool.source = source
+	}
+}
+
+// WithNetwork selects how the recursive resolver is queried: "udp" (the
+// default), "tcp" or "tcp-tls" (DNS over TLS). Queries sent directly to
+// nameservers use UDP, unless WithDialer is set.
+func WithNetwork(network string) Option {
+	return func(r *Resolver) {
+		r.recursive.Net = network
+	}
 }
 
 type TraceStep struct {
-	Zone   string
-	Server string
+	Zone   string `json:"zone"`
+	Server string `json:"server"`
 }
 
 type Nameserver struct {
-	Name string
-	IP   string
+	Name string `json:"name"`
+	IP   string `json:"ip,omitempty"`
 }
 
 type Records struct {
@@ -30,14 +137,95 @@ type Records struct {
 	TXT   []string
 	NS    []string
 	CNAME []string
+	// Other holds the records of the other types asked for, by type
+	Other map[string][]string
 }
 
-func NewResolver() *Resolver {
-	return &Resolver{
+// NewResolver creates a resolver. Connections are kept open and reused
+// across queries, so a resolver should be shared rather than created per crawl.
+func NewResolver(opts ...Option) *Resolver {
+	r := &Resolver{
 		client: &dns.Client{
-			Timeout: 5 * time.Second,
+			Timeout: DefaultTimeout,
+		},
+		recursive: &dns.Client{
+			Timeout: DefaultTimeout,
+			Dialer:  &net.Dialer{Timeout: DefaultTimeout, KeepAlive: keepAlive},
 		},
+		pool:         newConnPool(),
+		traceTimeout: DefaultTraceTimeout,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.pool.forward != nil {
+		r.client.Net = "tcp"
+		if r.recursive.Net == "" || r.recursive.Net == "udp" {
+			r.recursive.Net = "tcp"
+		}
+	}
+	r.upstreams = newUpstreamPool(r.upstreamAddrs, r.strategy, r.recursive)
+	return r
+}
+
+// Upstream describes the recursive resolver: its address, e.g. 8.8.8.8:53,
+// or the addresses and strategy of the pool
+func (r *Resolver) Upstream() string {
+	return r.upstreams.String()
+}
+
+// WithContext returns a copy of the resolver whose queries stop waiting for
+// a token of the WithLimit bucket once ctx is done, e.g. at a crawl's
+// deadline. The copy shares the connections and upstreams of r.
+func (r *Resolver) WithContext(ctx context.Context) *Resolver {
+	cp := *r
+	cp.ctx = ctx
+	return &cp
+}
+
+// Close closes the idle connections
+func (r *Resolver) Close() error {
+	return r.pool.close()
+}
+
+// exchange sends a query to server, going through the fixture store if one is set
+func (r *Resolver) exchange(m *dns.Msg, server string) (*dns.Msg, error) {
+	q := m.Question[0]
+	key := fmt.Sprintf("%s %s %s rd=%t", server, q.Name, dns.TypeToString[q.Qtype], m.RecursionDesired)
+
+	send := func(client *dns.Client, addr string) (*dns.Msg, error) {
+		ctx := r.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := r.limit.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return r.pool.exchange(client, m, addr)
+	}
+	packed, err := r.fixtures.Do("dns", key, func() ([]byte, error) {
+		var resp *dns.Msg
+		var err error
+		if server == defaultServer {
+			resp, err = r.upstreams.exchange(m, send)
+		} else {
+			resp, err = send(r.client, server)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.Pack()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(dns.Msg)
+	if err := resp.Unpack(packed); err != nil {
+		return nil, err
+	}
+	resp.Id = m.Id
+	return resp, nil
 }
 
 // Exists checks if a domain exists by querying DNS and checking for NXDOMAIN
@@ -47,7 +235,7 @@ func (r *Resolver) Exists(domain string) bool {
 	m.SetQuestion(domain, dns.TypeNS)
 	m.RecursionDesired = true
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	resp, err := r.exchange(m, defaultServer)
 	if err != nil {
 		return true // assume exists on network error
 	}
@@ -62,7 +250,7 @@ func (r *Resolver) GetNameservers(domain string) ([]Nameserver, error) {
 	m.SetQuestion(domain, dns.TypeNS)
 	m.RecursionDesired = true
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	resp, err := r.exchange(m, defaultServer)
 	if err != nil {
 		return nil, err
 	}
@@ -79,12 +267,32 @@ func (r *Resolver) GetNameservers(domain string) ([]Nameserver, error) {
 	return nameservers, nil
 }
 
+// LookupNS returns the NS hostnames for a zone without resolving their addresses
+func (r *Resolver) LookupNS(zone string) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(zone), dns.TypeNS)
+	m.RecursionDesired = true
+
+	resp, err := r.exchange(m, defaultServer)
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []string
+	for _, ans := range resp.Answer {
+		if ns, ok := ans.(*dns.NS); ok {
+			hosts = append(hosts, strings.TrimSuffix(ns.Ns, "."))
+		}
+	}
+	return hosts, nil
+}
+
 func (r *Resolver) resolveNS(nsName string) string {
 	m := new(dns.Msg)
 	m.SetQuestion(nsName, dns.TypeA)
 	m.RecursionDesired = true
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	resp, err := r.exchange(m, defaultServer)
 	if err != nil {
 		return ""
 	}
@@ -99,8 +307,32 @@ func (r *Resolver) resolveNS(nsName string) string {
 
 // Trace performs a DNS trace from root servers
 func (r *Resolver) Trace(domain string) ([]TraceStep, error) {
-	domain = dns.Fqdn(domain)
+	return r.TraceEach(domain, nil)
+}
+
+// ErrTraceTimeout is returned, along with the steps resolved so far, when a
+// trace takes longer than the resolver's trace timeout
+var ErrTraceTimeout = errors.New("trace timed out")
+
+func init() {
+	// Errors of recorded answers match the same errors when replayed
+	fixture.RegisterErrors(ErrTraceTimeout)
+}
+
+// TraceEach is Trace, calling fn (when not nil) with each step as soon as it
+// is resolved, so callers can show progress while slow TLD servers answer
+func (r *Resolver) TraceEach(name string, fn func(TraceStep)) ([]TraceStep, error) {
+	var deadline time.Time
+	if r.traceTimeout > 0 {
+		deadline = time.Now().Add(r.traceTimeout)
+	}
 	var steps []TraceStep
+	add := func(step TraceStep) {
+		steps = append(steps, step)
+		if fn != nil {
+			fn(step)
+		}
+	}
 
 	// Root servers
 	rootServers := []string{
@@ -111,28 +343,30 @@ func (r *Resolver) Trace(domain string) ([]TraceStep, error) {
 
 	currentServer := rootServers[0]
 
-	// Get domain parts for iteration
-	parts := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	// Zones from the root down to the name
 	zones := []string{"."}
-	for i := len(parts) - 1; i >= 0; i-- {
-		zone := strings.Join(parts[i:], ".") + "."
-		zones = append(zones, zone)
+	for _, zone := range slices.Backward(slices.Collect(domain.Hierarchy(name))) {
+		zones = append(zones, zone+".")
 	}
 
 	for i, zone := range zones {
 		if i == 0 {
 			// Root zone
 			serverName := r.getRootServerName(currentServer)
-			steps = append(steps, TraceStep{Zone: ".", Server: serverName})
+			add(TraceStep{Zone: ".", Server: serverName})
 			continue
 		}
 
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return steps, fmt.Errorf("%w after %s at %s", ErrTraceTimeout, r.traceTimeout, zone)
+		}
+
 		// Query for NS records of this zone
 		m := new(dns.Msg)
 		m.SetQuestion(zone, dns.TypeNS)
 		m.RecursionDesired = false
 
-		resp, _, err := r.client.Exchange(m, currentServer+":53")
+		resp, err := r.exchange(m, currentServer+":53")
 		if err != nil {
 			continue
 		}
@@ -160,10 +394,7 @@ func (r *Resolver) Trace(domain string) ([]TraceStep, error) {
 				}
 				if nextServer == "" {
 					// Resolve NS
-					ips, _ := net.LookupHost(serverName)
-					if len(ips) > 0 {
-						nextServer = ips[0]
-					}
+					nextServer = r.resolveNS(ns.Ns)
 				}
 				if nextServer != "" {
 					break
@@ -172,7 +403,7 @@ func (r *Resolver) Trace(domain string) ([]TraceStep, error) {
 		}
 
 		if serverName != "" {
-			steps = append(steps, TraceStep{Zone: zone, Server: serverName})
+			add(TraceStep{Zone: zone, Server: serverName})
 		}
 
 		if nextServer != "" {
@@ -197,8 +428,19 @@ func (r *Resolver) getRootServerName(ip string) string {
 
 // ASNInfo holds information about an IP's autonomous system
 type ASNInfo struct {
-	ASN  string
-	Org  string
+	ASN string `json:"asn"`
+	Org string `json:"org,omitempty"`
+}
+
+// Label returns the organization name if known, otherwise the AS number
+func (a *ASNInfo) Label() string {
+	if a == nil {
+		return ""
+	}
+	if a.Org != "" {
+		return a.Org
+	}
+	return a.ASN
 }
 
 // LookupASN returns ASN info for an IP address using Team Cymru's DNS service
@@ -235,7 +477,7 @@ func (r *Resolver) LookupASN(ip string) *ASNInfo {
 	m.SetQuestion(query, dns.TypeTXT)
 	m.RecursionDesired = true
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	resp, err := r.exchange(m, defaultServer)
 	if err != nil || len(resp.Answer) == 0 {
 		return nil
 	}
@@ -261,7 +503,7 @@ func (r *Resolver) lookupASName(asn string) *ASNInfo {
 	m.SetQuestion(nameQuery, dns.TypeTXT)
 	m.RecursionDesired = true
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	resp, err := r.exchange(m, defaultServer)
 	if err != nil || len(resp.Answer) == 0 {
 		return &ASNInfo{ASN: "AS" + asn}
 	}
@@ -283,44 +525,90 @@ func (r *Resolver) lookupASName(asn string) *ASNInfo {
 
 // ReverseLookup returns the PTR hostname for an IP address, or empty string on failure
 func (r *Resolver) ReverseLookup(ip string) string {
-	names, err := net.LookupAddr(ip)
-	if err != nil || len(names) == 0 {
+	arpa, err := dns.ReverseAddr(ip)
+	if err != nil {
 		return ""
 	}
-	return strings.TrimSuffix(names[0], ".")
-}
 
-// GetRecords fetches common DNS records for a domain
-func (r *Resolver) GetRecords(domain string) (*Records, error) {
-	domain = dns.Fqdn(domain)
-	records := &Records{}
-
-	// Fetch A records
-	records.A = r.queryRecords(domain, dns.TypeA)
+	m := new(dns.Msg)
+	m.SetQuestion(arpa, dns.TypePTR)
+	m.RecursionDesired = true
 
-	// Fetch AAAA records
-	records.AAAA = r.queryRecords(domain, dns.TypeAAAA)
+	resp, err := r.exchange(m, defaultServer)
+	if err != nil {
+		return ""
+	}
 
-	// Fetch MX records
-	records.MX = r.queryMX(domain)
+	for _, ans := range resp.Answer {
+		if ptr, ok := ans.(*dns.PTR); ok {
+			return strings.TrimSuffix(ptr.Ptr, ".")
+		}
+	}
+	return ""
+}
 
-	// Fetch TXT records (condensed)
-	records.TXT = r.queryTXT(domain)
+// DefaultRecordTypes are the record types GetRecords queries unless told
+// otherwise
+var DefaultRecordTypes = []string{"A", "AAAA", "MX", "TXT", "CNAME"}
 
-	// Fetch CNAME
-	records.CNAME = r.queryRecords(domain, dns.TypeCNAME)
+// CheckRecordTypes fails on the first of types that isn't a record type
+func CheckRecordTypes(types []string) error {
+	for _, name := range types {
+		if _, ok := dns.StringToType[strings.ToUpper(name)]; !ok {
+			return fmt.Errorf("unknown record type %q", name)
+		}
+	}
+	return nil
+}
 
+// GetRecords fetches the records of the given types for a domain, or of
+// DefaultRecordTypes when none are given. Types without a field of their own
+// go to Other.
+func (r *Resolver) GetRecords(domain string, types ...string) (*Records, error) {
+	domain = dns.Fqdn(domain)
+	if len(types) == 0 {
+		types = DefaultRecordTypes
+	}
+	records := &Records{}
+	for _, name := range types {
+		t, ok := dns.StringToType[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown record type %q", name)
+		}
+		var err error
+		switch t {
+		case dns.TypeA:
+			records.A, err = r.queryRecords(domain, t, true)
+		case dns.TypeAAAA:
+			records.AAAA, err = r.queryRecords(domain, t, true)
+		case dns.TypeMX:
+			records.MX, err = r.queryMX(domain, true)
+		case dns.TypeTXT:
+			records.TXT, err = r.queryTXT(domain, true)
+		case dns.TypeCNAME:
+			records.CNAME, err = r.queryRecords(domain, t, true)
+		default:
+			var values []string
+			if values, err = r.queryRdata(domain, t, true); len(values) > 0 {
+				if records.Other == nil {
+					records.Other = make(map[string][]string)
+				}
+				records.Other[dns.TypeToString[t]] = values
+			}
+		}
+		// Failed queries to the recursive resolver leave their type empty;
+		// an authoritative server must answer for all of them
+		if err != nil && r.authoritative != nil {
+			return nil, err
+		}
+	}
 	return records, nil
 }
 
-func (r *Resolver) queryRecords(domain string, qtype uint16) []string {
-	m := new(dns.Msg)
-	m.SetQuestion(domain, qtype)
-	m.RecursionDesired = true
-
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+func (r *Resolver) queryRecords(domain string, qtype uint16, inZone bool) ([]string, error) {
+	resp, err := r.queryZone(domain, qtype, inZone)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	var results []string
@@ -334,17 +622,30 @@ func (r *Resolver) queryRecords(domain string, qtype uint16) []string {
 			results = append(results, strings.TrimSuffix(rr.Target, "."))
 		}
 	}
-	return results
+	return results, nil
 }
 
-func (r *Resolver) queryMX(domain string) []string {
-	m := new(dns.Msg)
-	m.SetQuestion(domain, dns.TypeMX)
-	m.RecursionDesired = true
+// queryRdata returns the presentation form of the records of any type,
+// without their headers
+func (r *Resolver) queryRdata(domain string, qtype uint16, inZone bool) ([]string, error) {
+	resp, err := r.queryZone(domain, qtype, inZone)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+	var results []string
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == qtype {
+			results = append(results, rdata(rr))
+		}
+	}
+	return results, nil
+}
+
+func (r *Resolver) queryMX(domain string, inZone bool) ([]string, error) {
+	resp, err := r.queryZone(domain, dns.TypeMX, inZone)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	var results []string
@@ -353,29 +654,58 @@ func (r *Resolver) queryMX(domain string) []string {
 			results = append(results, fmt.Sprintf("%d %s", mx.Preference, strings.TrimSuffix(mx.Mx, ".")))
 		}
 	}
-	return results
+	return results, nil
 }
 
-func (r *Resolver) queryTXT(domain string) []string {
-	m := new(dns.Msg)
-	m.SetQuestion(domain, dns.TypeTXT)
-	m.RecursionDesired = true
-
-	resp, _, err := r.client.Exchange(m, "8.8.8.8:53")
+func (r *Resolver) queryTXT(domain string, inZone bool) ([]string, error) {
+	resp, err := r.queryZone(domain, dns.TypeTXT, inZone)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	var results []string
 	for _, ans := range resp.Answer {
 		if txt, ok := ans.(*dns.TXT); ok {
-			value := strings.Join(txt.Txt, "")
-			// Truncate long TXT records
-			if len(value) > 60 {
-				value = value[:57] + "..."
-			}
-			results = append(results, value)
+			results = append(results, strings.Join(txt.Txt, ""))
 		}
 	}
-	return results
+	return results, nil
+}
+
+// LookupTXT returns the TXT records for an arbitrary name, e.g.
+// _dmarc.example.com. Names in zone, the zone crawled, are asked of the
+// server of WithAuthoritative when one is set; names elsewhere, such as an
+// SPF redirect= target of another domain, of the recursive resolver.
+func (r *Resolver) LookupTXT(name, zone string) []string {
+	name, zone = dns.Fqdn(name), dns.Fqdn(zone)
+	inZone := strings.EqualFold(name, zone) || strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(zone))
+	txt, _ := r.queryTXT(name, inZone)
+	return txt
+}
+
+// LookupRaw asks the recursive resolver, or the server of
+// WithAuthoritative, for the records of the given type and returns the
+// answer section in zone-file presentation format, one record per entry
+// with its owner, TTL and class, including the CNAMEs leading to the
+// records. A name that doesn't exist has no records; other failure
+// responses are errors.
+func (r *Resolver) LookupRaw(name, qtype string) ([]string, error) {
+	t, ok := dns.StringToType[strings.ToUpper(qtype)]
+	if !ok {
+		return nil, fmt.Errorf("unknown record type %q", qtype)
+	}
+
+	resp, err := r.queryZone(dns.Fqdn(name), t, true)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("resolver answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	var rrs []string
+	for _, rr := range resp.Answer {
+		rrs = append(rrs, rr.String())
+	}
+	return rrs, nil
 }
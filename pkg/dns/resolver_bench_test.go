@@ -0,0 +1,27 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/auduny/dnscrawler/pkg/fixture"
+)
+
+// replayResolver answers from the fixtures in testdata/replay, recorded from
+// a mock delegation chain for www.example.com, so no query leaves the process
+func replayResolver(b *testing.B) *Resolver {
+	store, err := fixture.NewReplayer("testdata/replay")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return NewResolver(WithFixtures(store))
+}
+
+func BenchmarkTrace(b *testing.B) {
+	r := replayResolver(b)
+	for b.Loop() {
+		steps, err := r.Trace("www.example.com")
+		if err != nil || len(steps) != 3 {
+			b.Fatalf("trace: %v %v", steps, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package dns
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// HTTPSRecord is an HTTPS resource record (RFC 9460): where and how to
+// connect to a name's HTTPS service
+type HTTPSRecord struct {
+	// Priority 0 makes the record an alias for Target
+	Priority uint16 `json:"priority"`
+	Target   string `json:"target"` // "." is the owner name itself
+	// ALPN are the protocols the service speaks besides http/1.1, unless
+	// NoDefaultALPN leaves it out
+	ALPN          []string `json:"alpn,omitempty"`
+	NoDefaultALPN bool     `json:"no_default_alpn,omitempty"`
+}
+
+// Alias reports whether the record is in alias mode
+func (h HTTPSRecord) Alias() bool {
+	return h.Priority == 0
+}
+
+// LookupHTTPS returns the HTTPS records of name
+func (r *Resolver) LookupHTTPS(name string) ([]HTTPSRecord, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), dns.TypeHTTPS)
+	m.RecursionDesired = true
+
+	resp, err := r.exchange(m, defaultServer)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []HTTPSRecord
+	for _, ans := range resp.Answer {
+		rr, ok := ans.(*dns.HTTPS)
+		if !ok {
+			continue
+		}
+		record := HTTPSRecord{Priority: rr.Priority, Target: rr.Target}
+		if target := strings.TrimSuffix(rr.Target, "."); target != "" {
+			record.Target = target
+		}
+		for _, kv := range rr.Value {
+			switch kv := kv.(type) {
+			case *dns.SVCBAlpn:
+				record.ALPN = kv.Alpn
+			case *dns.SVCBNoDefaultAlpn:
+				record.NoDefaultALPN = true
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
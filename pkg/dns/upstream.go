@@ -0,0 +1,206 @@
+package dns
+
+import (
+	"cmp"
+	"crypto/tls"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Strategies of a pool of recursive resolvers, for WithUpstreams
+const (
+	// StrategyRoundRobin sends each query to the next resolver in turn
+	StrategyRoundRobin = "round-robin"
+	// StrategyFastest sends queries to the resolver answering fastest so far
+	StrategyFastest = "fastest"
+	// StrategyRandom sends every query of a run to one resolver picked at
+	// random when the resolver is created
+	StrategyRandom = "random"
+)
+
+// Strategies lists the pool strategies
+var Strategies = []string{StrategyRoundRobin, StrategyFastest, StrategyRandom}
+
+// CheckUpstreams fails on the first of the resolver addresses that can't be
+// parsed, or on an unknown strategy
+func CheckUpstreams(addrs []string, strategy string) error {
+	for _, addr := range addrs {
+		if _, err := parseUpstream(addr, "udp"); err != nil {
+			return err
+		}
+	}
+	if strategy != "" && !slices.Contains(Strategies, strategy) {
+		return fmt.Errorf("unknown resolver strategy %q (want %s)", strategy, strings.Join(Strategies, ", "))
+	}
+	return nil
+}
+
+// WithUpstreams replaces the recursive resolver with a pool of them, tried
+// in the order of strategy (StrategyRoundRobin when empty). A query moves
+// on to the next resolver when one fails or refuses it. Addresses are
+// host[:port], with port 53, or 853 over TLS, by default; over TLS,
+// host#name checks the certificate for name instead of host.
+func WithUpstreams(addrs []string, strategy string) Option {
+	return func(r *Resolver) {
+		r.upstreamAddrs = addrs
+		r.strategy = strategy
+	}
+}
+
+// upstream is a recursive resolver of the pool
+type upstream struct {
+	addr    string
+	tlsName string      // name on the certificate, over TLS
+	client  *dns.Client // the resolver's client, for its TLS config
+
+	rtt time.Duration // smoothed response time, zero until it answered
+}
+
+// parseUpstream reads a host[:port][#name] resolver address
+func parseUpstream(spec, network string) (*upstream, error) {
+	host, name, _ := strings.Cut(spec, "#")
+	port := "53"
+	if network == "tcp-tls" {
+		port = "853"
+	}
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host, port = h, p
+	} else {
+		host = strings.Trim(host, "[]")
+	}
+	if n, err := strconv.Atoi(port); host == "" || err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("invalid resolver address %q (want host[:port][#tls-name])", spec)
+	}
+	return &upstream{addr: net.JoinHostPort(host, port), tlsName: cmp.Or(name, host)}, nil
+}
+
+// upstreamPool picks the recursive resolver of each query
+type upstreamPool struct {
+	servers  []*upstream
+	strategy string
+	next     atomic.Uint64 // the round-robin turn
+	first    int           // the resolver of StrategyRandom
+
+	mu sync.Mutex // guards the response times
+}
+
+// newUpstreamPool creates the pool of the addresses, each with a copy of
+// template. Without valid addresses it holds the default resolver of
+// the template's network.
+func newUpstreamPool(addrs []string, strategy string, template *dns.Client) *upstreamPool {
+	p := &upstreamPool{strategy: cmp.Or(strategy, StrategyRoundRobin)}
+	var servers []*upstream
+	for _, addr := range addrs {
+		// Invalid addresses are rejected by CheckUpstreams
+		if u, err := parseUpstream(addr, template.Net); err == nil {
+			servers = append(servers, u)
+		}
+	}
+	if len(servers) == 0 {
+		servers = []*upstream{{addr: defaultServer}}
+		if template.Net == "tcp-tls" {
+			servers = []*upstream{{addr: defaultTLSServer, tlsName: defaultTLSName}}
+		}
+	}
+	for _, u := range servers {
+		client := *template
+		if template.Net == "tcp-tls" {
+			client.TLSConfig = &tls.Config{ServerName: u.tlsName}
+		}
+		u.client = &client
+		p.servers = append(p.servers, u)
+	}
+	p.first = rand.IntN(len(p.servers))
+	return p
+}
+
+// String returns the address of a single resolver, or the addresses and
+// the strategy of a pool
+func (p *upstreamPool) String() string {
+	if len(p.servers) == 1 {
+		return p.servers[0].addr
+	}
+	addrs := make([]string, len(p.servers))
+	for i, u := range p.servers {
+		addrs[i] = u.addr
+	}
+	return strings.Join(addrs, ", ") + " (" + p.strategy + ")"
+}
+
+// order returns the resolvers in the order a query tries them
+func (p *upstreamPool) order() []*upstream {
+	n := len(p.servers)
+	if n == 1 {
+		return p.servers
+	}
+	var start int
+	switch p.strategy {
+	case StrategyRandom:
+		start = p.first
+	case StrategyFastest:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		// Resolvers without a response time yet come first, so each is
+		// measured
+		return slices.SortedStableFunc(slices.Values(p.servers), func(a, b *upstream) int {
+			return cmp.Compare(a.rtt, b.rtt)
+		})
+	default:
+		start = int((p.next.Add(1) - 1) % uint64(n))
+	}
+	return append(slices.Clone(p.servers[start:]), p.servers[:start]...)
+}
+
+// measure records how long u took to answer, or to fail, for StrategyFastest
+func (p *upstreamPool) measure(u *upstream, took time.Duration, err error) {
+	if p.strategy != StrategyFastest {
+		return
+	}
+	if err != nil {
+		// A failing resolver goes to the back until it recovers
+		took = max(took, u.client.Timeout)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u.rtt == 0 {
+		u.rtt = took
+		return
+	}
+	u.rtt = (3*u.rtt + took) / 4
+}
+
+// exchange sends m with send to the resolvers in turn, until one answers
+// without refusing it. When all fail, the error of the last is returned.
+func (p *upstreamPool) exchange(m *dns.Msg, send func(client *dns.Client, addr string) (*dns.Msg, error)) (*dns.Msg, error) {
+	var refused *dns.Msg
+	var err error
+	for _, u := range p.order() {
+		start := time.Now()
+		var resp *dns.Msg
+		resp, err = send(u.client, u.addr)
+		p.measure(u, time.Since(start), err)
+		if err != nil {
+			if len(p.servers) > 1 {
+				err = fmt.Errorf("%s: %w", u.addr, err)
+			}
+			continue
+		}
+		if resp.Rcode != dns.RcodeRefused {
+			return resp, nil
+		}
+		refused = resp
+	}
+	if refused != nil {
+		return refused, nil
+	}
+	return nil, err
+}
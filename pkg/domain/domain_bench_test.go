@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+var benchDomains = []string{
+	"example.com",
+	"www.example.com",
+	"int.ytterdal.net",
+	"a.b.c.example.co.uk",
+	"kommune.no",
+	"Mail.Example.COM.",
+}
+
+func BenchmarkGetRootDomain(b *testing.B) {
+	for i := 0; b.Loop(); i++ {
+		GetRootDomain(benchDomains[i%len(benchDomains)])
+	}
+}
+
+func BenchmarkIsSubdomain(b *testing.B) {
+	for i := 0; b.Loop(); i++ {
+		IsSubdomain(benchDomains[i%len(benchDomains)])
+	}
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	for i := 0; b.Loop(); i++ {
+		Normalize(benchDomains[i%len(benchDomains)])
+	}
+}
+
+func BenchmarkAncestors(b *testing.B) {
+	for i := 0; b.Loop(); i++ {
+		Ancestors(benchDomains[i%len(benchDomains)])
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// Depth returns how many labels a name has below its registrable domain
+// e.g., "example.co.uk" -> 0, "www.example.co.uk" -> 1, "a.b.example.co.uk" -> 2
+//
+// A public suffix has depth 0 too.
+func Depth(name string) int {
+	name = Canonical(name)
+	return LabelCount(name) - LabelCount(GetRootDomain(name))
+}
+
+// Group is a set of names under a common parent
+type Group struct {
+	Parent string   `json:"parent"`
+	Names  []string `json:"names"`
+}
+
+// GroupByParent buckets names by their parent depth labels below the
+// registrable domain (see GetRegistrableWithDepth), so depth 0 groups by
+// registrable domain and depth 1 by the level below it
+// e.g., depth=1: "a.dev.example.com", "b.dev.example.com", "www.example.com"
+// -> dev.example.com: [a.dev.example.com, b.dev.example.com],
+// www.example.com: [www.example.com]
+//
+// Names not deep enough form a group of their own. Names are cleaned and
+// deduplicated, and groups and their names are in Compare order.
+func GroupByParent(names []string, depth int) []Group {
+	byParent := make(map[string][]string)
+	for _, name := range names {
+		name = Canonical(name)
+		if name == "" {
+			continue
+		}
+		parent := GetRegistrableWithDepth(name, depth)
+		byParent[parent] = append(byParent[parent], name)
+	}
+
+	groups := make([]Group, 0, len(byParent))
+	for parent, members := range byParent {
+		slices.SortFunc(members, Compare)
+		groups = append(groups, Group{Parent: parent, Names: slices.Compact(members)})
+	}
+	slices.SortFunc(groups, func(a, b Group) int { return Compare(a.Parent, b.Parent) })
+	return groups
+}
+
+// Compare orders names by hierarchy: label by label from the TLD, so a
+// name sorts right after its parent and siblings sort together
+// e.g., "example.com" < "www.example.com" < "example.net"
+func Compare(a, b string) int {
+	for a != "" && b != "" {
+		var la, lb string
+		if i := strings.LastIndexByte(a, '.'); i >= 0 {
+			la, a = a[i+1:], a[:i]
+		} else {
+			la, a = a, ""
+		}
+		if i := strings.LastIndexByte(b, '.'); i >= 0 {
+			lb, b = b[i+1:], b[:i]
+		} else {
+			lb, b = b, ""
+		}
+		if c := strings.Compare(la, lb); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ParseInput extracts the domain from what a user typed or pasted: a domain
+// name, a URL ("https://user@www.example.com:8443/path?q"), a host and port
+// ("www.example.com:8443") or an email address ("postmaster@example.com",
+// "mailto:postmaster@example.com"). The domain is normalized with Normalize.
+func ParseInput(input string) (string, error) {
+	s := strings.TrimSpace(input)
+	if len(s) >= len("mailto:") && strings.EqualFold(s[:len("mailto:")], "mailto:") {
+		s = s[len("mailto:"):]
+	}
+
+	host := s
+	if strings.ContainsAny(s, "/?#@:") {
+		// Without a scheme, parse as a network-path reference, so the input
+		// is read as [userinfo@]host[:port][/path]; that covers email
+		// addresses too
+		ref := s
+		if !strings.Contains(s, "://") {
+			ref = "//" + s
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return "", invalid(input, "%v", err)
+		}
+		if u.Host == "" {
+			return "", invalid(input, "no host name")
+		}
+		host = u.Hostname()
+	}
+	return Normalize(host)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"iter"
+	"strings"
+)
+
+// Canonical lowercases a name and strips surrounding whitespace and the trailing dot
+func Canonical(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// Labels splits a name into its labels, leftmost first
+// e.g., "www.Example.com." -> ["www", "example", "com"]
+func Labels(name string) []string {
+	name = Canonical(name)
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, ".")
+}
+
+// LabelCount returns the number of labels of a name
+func LabelCount(name string) int {
+	name = Canonical(name)
+	if name == "" {
+		return 0
+	}
+	return strings.Count(name, ".") + 1
+}
+
+// TLD returns the last label of a name
+// e.g., "www.example.co.uk" -> "uk"
+func TLD(name string) string {
+	name = Canonical(name)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// Hierarchy yields a name and then each of its parents up to the TLD
+// e.g., "www.example.com" -> "www.example.com", "example.com", "com"
+func Hierarchy(name string) iter.Seq[string] {
+	name = Canonical(name)
+	return func(yield func(string) bool) {
+		for off := 0; off >= 0 && name != ""; off = nextLabel(name, off) {
+			if !yield(name[off:]) {
+				return
+			}
+		}
+	}
+}
+
+// Ancestors returns the chain from a name up to its registrable domain,
+// the name first and the registrable domain last
+// e.g., "a.b.example.co.uk" -> ["a.b.example.co.uk", "b.example.co.uk", "example.co.uk"]
+//
+// A registrable domain, or a public suffix, is its own only ancestor.
+func Ancestors(name string) []string {
+	name = Canonical(name)
+	root := GetRootDomain(name)
+	var chain []string
+	for zone := range Hierarchy(name) {
+		chain = append(chain, zone)
+		if zone == root {
+			break
+		}
+	}
+	return chain
+}
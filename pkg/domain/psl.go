@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// embeddedSuffixList is the Public Suffix List snapshot shipped with the
+// binary; `dnscrawler update psl` caches a newer one
+//
+//go:embed public_suffix_list.dat
+var embeddedSuffixList []byte
+
+// SuffixList is a parsed Public Suffix List. By default only the ICANN
+// section is used: its suffixes are where registries delegate, so a
+// registrable domain below one has WHOIS data and its own zone. The private
+// section (github.io, ...) and organization-internal suffixes can be added
+// with With.
+type SuffixList struct {
+	// Version is the list's VERSION header, empty for lists without one
+	Version string
+	// Source is "embedded" or the path the list was loaded from
+	Source string
+
+	icann   ruleSet
+	private ruleSet // the PRIVATE section, used when enabled by With
+	active  ruleSet // rules PublicSuffix matches against
+}
+
+// ruleSet holds suffix rules by kind
+type ruleSet struct {
+	rules      map[string]bool // normal rules
+	wildcards  map[string]bool // parents of *.parent rules
+	exceptions map[string]bool // !name rules
+}
+
+func newRuleSet() ruleSet {
+	return ruleSet{
+		rules:      make(map[string]bool),
+		wildcards:  make(map[string]bool),
+		exceptions: make(map[string]bool),
+	}
+}
+
+// add adds a rule in list syntax: "name", "*.name" or "!name". Rules for
+// internationalized names are stored in xn-- form, as names are normalized
+// to it.
+func (s ruleSet) add(rule string) {
+	rule = strings.ToLower(rule)
+	toASCII := func(name string) string {
+		if ascii, err := ToASCII(name); err == nil {
+			return ascii
+		}
+		return name
+	}
+	switch {
+	case strings.HasPrefix(rule, "!"):
+		s.exceptions[toASCII(rule[1:])] = true
+	case strings.HasPrefix(rule, "*."):
+		s.wildcards[toASCII(rule[2:])] = true
+	default:
+		s.rules[toASCII(rule)] = true
+	}
+}
+
+func (s ruleSet) merge(other ruleSet) {
+	for _, pair := range [][2]map[string]bool{
+		{s.rules, other.rules}, {s.wildcards, other.wildcards}, {s.exceptions, other.exceptions},
+	} {
+		for k := range pair[1] {
+			pair[0][k] = true
+		}
+	}
+}
+
+func (s ruleSet) len() int {
+	return len(s.rules) + len(s.wildcards) + len(s.exceptions)
+}
+
+// ParseSuffixList parses a list in the publicsuffix.org format
+func ParseSuffixList(data []byte) (*SuffixList, error) {
+	l := &SuffixList{icann: newRuleSet(), private: newRuleSet()}
+	var section *ruleSet
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "// VERSION:"):
+			l.Version = strings.TrimSpace(strings.TrimPrefix(line, "// VERSION:"))
+		case strings.Contains(line, "===BEGIN ICANN DOMAINS==="):
+			section = &l.icann
+		case strings.Contains(line, "===BEGIN PRIVATE DOMAINS==="):
+			section = &l.private
+		case strings.Contains(line, "===END "):
+			section = nil
+		case line == "" || strings.HasPrefix(line, "//") || section == nil:
+		default:
+			// Rules end at the first whitespace
+			rule, _, _ := strings.Cut(line, " ")
+			section.add(rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if l.icann.len() == 0 {
+		return nil, errors.New("public suffix list: no ICANN rules found")
+	}
+	l.active = l.icann
+	return l, nil
+}
+
+// With returns a copy of the list that also uses the private section when
+// private is set, and the custom rules, such as "internal.corp.example" to
+// make each name directly below it a registrable domain. Custom rules use
+// the list's syntax, so "*.corp.example" and "!www.corp.example" work too.
+func (l *SuffixList) With(custom []string, private bool) (*SuffixList, error) {
+	cp := *l
+	cp.active = newRuleSet()
+	cp.active.merge(l.icann)
+	if private {
+		cp.active.merge(l.private)
+	}
+	for _, rule := range custom {
+		rule = strings.TrimSuffix(strings.TrimSpace(rule), ".")
+		if err := validateSyntax(strings.TrimPrefix(strings.TrimPrefix(rule, "!"), "*.")); err != nil {
+			return nil, err
+		}
+		cp.active.add(rule)
+	}
+	return &cp, nil
+}
+
+// Len returns the number of rules in use
+func (l *SuffixList) Len() int {
+	return l.active.len()
+}
+
+// PublicSuffix returns the public suffix of a lowercase name without a
+// trailing dot, e.g. "co.uk" for "www.example.co.uk". Names under no rule
+// have their last label as the suffix.
+func (l *SuffixList) PublicSuffix(name string) string {
+	s := l.active
+	// An exception rule prevails over every other rule; its suffix is the
+	// rule minus its first label
+	for off := 0; off >= 0; off = nextLabel(name, off) {
+		if s.exceptions[name[off:]] {
+			return name[nextLabel(name, off):]
+		}
+	}
+	// Otherwise the longest matching rule wins
+	for off := 0; off >= 0; off = nextLabel(name, off) {
+		if s.rules[name[off:]] {
+			return name[off:]
+		}
+		if next := nextLabel(name, off); next >= 0 && s.wildcards[name[next:]] {
+			return name[off:]
+		}
+	}
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// nextLabel returns the offset of the label after the one at off, or -1
+func nextLabel(name string, off int) int {
+	i := strings.IndexByte(name[off:], '.')
+	if i < 0 {
+		return -1
+	}
+	return off + i + 1
+}
+
+var (
+	suffixListOnce sync.Once
+	suffixList     atomic.Pointer[SuffixList]
+
+	// customSuffixes and privateSuffixes are set by UseSuffixes and
+	// applied to every list the package loads
+	suffixMu        sync.Mutex
+	customSuffixes  []string
+	privateSuffixes bool
+)
+
+// CurrentSuffixList returns the list used by GetRootDomain and IsSubdomain:
+// the one cached by `dnscrawler update psl` when it is present and valid,
+// otherwise the embedded snapshot, with the suffixes set by UseSuffixes
+func CurrentSuffixList() *SuffixList {
+	suffixListOnce.Do(func() {
+		l := loadSuffixList()
+		suffixMu.Lock()
+		defer suffixMu.Unlock()
+		if withSuffixes, err := l.With(customSuffixes, privateSuffixes); err == nil {
+			l = withSuffixes
+		}
+		suffixList.Store(l)
+	})
+	return suffixList.Load()
+}
+
+func loadSuffixList() *SuffixList {
+	if path := SuffixListCachePath(); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			if l, err := ParseSuffixList(data); err == nil {
+				l.Source = path
+				return l
+			}
+		}
+	}
+	l, err := ParseSuffixList(embeddedSuffixList)
+	if err != nil {
+		panic("embedded public suffix list: " + err.Error())
+	}
+	l.Source = "embedded"
+	return l
+}
+
+// SetSuffixList replaces the list used by the package, adding the suffixes
+// set by UseSuffixes
+func SetSuffixList(l *SuffixList) error {
+	suffixListOnce.Do(func() {})
+	suffixMu.Lock()
+	defer suffixMu.Unlock()
+	l, err := l.With(customSuffixes, privateSuffixes)
+	if err != nil {
+		return err
+	}
+	suffixList.Store(l)
+	return nil
+}
+
+// UseSuffixes makes GetRootDomain and IsSubdomain treat the custom suffixes
+// (see SuffixList.With) as registrable boundaries, and the private section
+// of the Public Suffix List as well when private is set. Crawling internal
+// zones needs this: with "internal.corp.example" as a suffix,
+// "app.internal.corp.example" is a domain of its own instead of a subdomain
+// of corp.example.
+func UseSuffixes(custom []string, private bool) error {
+	base := CurrentSuffixList()
+	if _, err := base.With(custom, private); err != nil {
+		return err
+	}
+	suffixMu.Lock()
+	customSuffixes, privateSuffixes = slices.Clone(custom), private
+	suffixMu.Unlock()
+	return SetSuffixList(base)
+}
+
+// SuffixListCachePath is where `dnscrawler update psl` stores the list, or ""
+// when there is no user cache directory
+func SuffixListCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dnscrawler", "public_suffix_list.dat")
+}
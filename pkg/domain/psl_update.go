@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SuffixListURL is where the Public Suffix List is published
+const SuffixListURL = "https://publicsuffix.org/list/public_suffix_list.dat"
+
+// SuffixListInfo describes the cached list; it is stored next to it as JSON
+type SuffixListInfo struct {
+	Version      string    `json:"version,omitempty"`       // the list's VERSION header
+	Rules        int       `json:"rules"`                   // number of ICANN rules
+	Fetched      time.Time `json:"fetched"`                 // when the list was downloaded
+	Checked      time.Time `json:"checked"`                 // when the server was last asked for a newer one
+	LastModified string    `json:"last_modified,omitempty"` // Last-Modified of the download
+	ETag         string    `json:"etag,omitempty"`          // ETag of the download
+}
+
+// LoadSuffixListInfo reads the information about the cached list. It
+// returns nil without error when no list has been cached.
+func LoadSuffixListInfo() (*SuffixListInfo, error) {
+	path := SuffixListCachePath()
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(infoPath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info SuffixListInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, fmt.Errorf("%s: %v", infoPath(path), err)
+	}
+	return &info, nil
+}
+
+// UpdateSuffixList downloads the list from SuffixListURL into the cache dir
+// and makes the package use it. The download is conditional, so it is cheap
+// when the cached list is current; updated reports whether a new list was
+// stored. A download with far fewer rules than the embedded snapshot is
+// rejected as truncated.
+func UpdateSuffixList(ctx context.Context, client *http.Client) (info *SuffixListInfo, updated bool, err error) {
+	path := SuffixListCachePath()
+	if path == "" {
+		return nil, false, fmt.Errorf("public suffix list: no user cache directory")
+	}
+	prev, _ := LoadSuffixListInfo()
+	if _, err := os.Stat(path); err != nil {
+		prev = nil // the list itself is gone; fetch it unconditionally
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, SuffixListURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("public suffix list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	now := time.Now().UTC()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && prev != nil:
+		prev.Checked = now
+		return prev, false, writeInfo(path, prev)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("public suffix list: %s answered %s", SuffixListURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, false, fmt.Errorf("public suffix list: %w", err)
+	}
+	l, err := ParseSuffixList(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if embedded, err := ParseSuffixList(embeddedSuffixList); err == nil && l.Len() < embedded.Len()/2 {
+		return nil, false, fmt.Errorf("public suffix list: download has only %d rules, the embedded snapshot %d", l.Len(), embedded.Len())
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return nil, false, err
+	}
+	info = &SuffixListInfo{
+		Version:      l.Version,
+		Rules:        l.Len(),
+		Fetched:      now,
+		Checked:      now,
+		LastModified: resp.Header.Get("Last-Modified"),
+		ETag:         resp.Header.Get("ETag"),
+	}
+	if err := writeInfo(path, info); err != nil {
+		return nil, false, err
+	}
+	l.Source = path
+	if err := SetSuffixList(l); err != nil {
+		return nil, false, err
+	}
+	return info, true, nil
+}
+
+func infoPath(listPath string) string {
+	return strings.TrimSuffix(listPath, filepath.Ext(listPath)) + ".json"
+}
+
+func writeInfo(listPath string, info *SuffixListInfo) error {
+	raw, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(infoPath(listPath), raw)
+}
+
+// writeFileAtomic replaces path, so concurrent readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToASCII converts an internationalized name to its xn-- (punycode) form.
+// The name is NFC-normalized and case-folded first, so differently typed
+// or pasted forms of a name map to the same ASCII name. ASCII names are
+// returned unchanged.
+func ToASCII(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if isASCII(name) {
+		return name, nil
+	}
+	folded := cases.Fold().String(norm.NFC.String(name))
+	ascii, err := idna.Lookup.ToASCII(folded)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %v", name, err)
+	}
+	return ascii, nil
+}
+
+// ToUnicode returns the display form of a name, with xn-- labels decoded.
+// Names that don't decode are returned unchanged.
+func ToUnicode(name string) string {
+	if !strings.Contains(name, "xn--") {
+		return name
+	}
+	display, err := idna.Lookup.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return display
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// Spoofing describes why an internationalized name may impersonate another
+type Spoofing struct {
+	// Unicode is the name as it is displayed
+	Unicode string `json:"unicode"`
+	// Skeleton is the name with each confusable character replaced by the
+	// ASCII letter it imitates, e.g. "apple.com" for "аррӏе.com"
+	Skeleton string `json:"skeleton"`
+	// Scripts lists the scripts of the name's letters, e.g. ["Cyrillic", "Latin"]
+	Scripts []string `json:"scripts"`
+	// MixedScript is set when a single label mixes scripts that aren't
+	// written together, such as Latin and Cyrillic
+	MixedScript bool `json:"mixed_script"`
+	// WholeScript is set when a non-ASCII label is made only of ASCII
+	// characters and letters imitating them, so it reads as an ASCII label
+	WholeScript bool `json:"whole_script"`
+	// Confusables lists the characters of those labels that imitate ASCII
+	// letters, e.g. "р (U+0440, Cyrillic) looks like p"
+	Confusables []string `json:"confusables,omitempty"`
+}
+
+// CheckSpoofing returns what makes a name a likely homograph of another, or
+// nil when no label mixes scripts or reads as an ASCII label. A name merely
+// written in another script, such as "пример.рф", isn't flagged. The name
+// may be given in Unicode or xn-- form.
+//
+// Confusables are a curated subset of Unicode's confusables.txt (UTS #39):
+// the Cyrillic, Greek, Armenian and Latin-extension letters that render
+// like ASCII letters in common fonts.
+func CheckSpoofing(name string) *Spoofing {
+	display := ToUnicode(Canonical(name))
+	if isASCII(display) {
+		return nil
+	}
+
+	s := &Spoofing{Unicode: display}
+	var skeleton strings.Builder
+	seen := make(map[rune]bool)
+	for _, label := range strings.Split(display, ".") {
+		if skeleton.Len() > 0 {
+			skeleton.WriteByte('.')
+		}
+		var labelScripts []string
+		var lookalikes []rune
+		// A non-ASCII label reads as an ASCII one until a character
+		// imitates none
+		readsASCII := !isASCII(label)
+		for _, r := range norm.NFD.String(label) {
+			script := scriptOf(r)
+			if script != "" && !slices.Contains(labelScripts, script) {
+				labelScripts = append(labelScripts, script)
+			}
+			if script != "" && !slices.Contains(s.Scripts, script) {
+				s.Scripts = append(s.Scripts, script)
+			}
+			if lookalike, ok := confusables[r]; ok {
+				skeleton.WriteRune(lookalike)
+				lookalikes = append(lookalikes, r)
+				continue
+			}
+			readsASCII = readsASCII && r <= unicode.MaxASCII
+			skeleton.WriteRune(r)
+		}
+		mixed := mixesScripts(labelScripts)
+		s.MixedScript = s.MixedScript || mixed
+		s.WholeScript = s.WholeScript || readsASCII
+		if !mixed && !readsASCII {
+			continue
+		}
+		for _, r := range lookalikes {
+			if !seen[r] {
+				seen[r] = true
+				s.Confusables = append(s.Confusables, fmt.Sprintf("%c (U+%04X, %s) looks like %c", r, r, scriptOf(r), confusables[r]))
+			}
+		}
+	}
+	s.Skeleton = norm.NFC.String(skeleton.String())
+	slices.Sort(s.Scripts)
+
+	if !s.MixedScript && !s.WholeScript {
+		return nil
+	}
+	return s
+}
+
+// scriptsToCheck are tried first; other scripts are looked up in unicode.Scripts
+var scriptsToCheck = []string{"Latin", "Cyrillic", "Greek", "Armenian", "Han", "Hiragana", "Katakana", "Hangul"}
+
+// scriptOf returns the Unicode script of a letter, or "" for characters
+// shared by scripts (digits, hyphen, combining marks)
+func scriptOf(r rune) string {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	for _, name := range scriptsToCheck {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// scriptSets are the combinations of scripts that are written together
+// (UTS #39 "highly restrictive"); a label using any other combination is
+// mixed-script
+var scriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+func mixesScripts(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return false
+	}
+	for _, set := range scriptSets {
+		if !slices.ContainsFunc(scripts, func(s string) bool { return !slices.Contains(set, s) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// confusables maps lowercase letters to the ASCII letter they imitate
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j',
+	'к': 'k', 'ӏ': 'l', 'м': 'm', 'п': 'n', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'г': 'r',
+	'ѕ': 's', 'т': 't', 'ѵ': 'v', 'ԝ': 'w', 'х': 'x', 'у': 'y',
+	// Greek
+	'α': 'a', 'ϲ': 'c', 'ε': 'e', 'η': 'n', 'ι': 'i', 'ϳ': 'j', 'κ': 'k',
+	'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+	// Armenian
+	'ց': 'g', 'հ': 'h', 'ո': 'n', 'օ': 'o', 'զ': 'q', 'ս': 'u',
+	// Latin extensions
+	'ɑ': 'a', 'ƅ': 'b', 'ɗ': 'd', 'ɡ': 'g', 'ı': 'i', 'ȷ': 'j', 'ɩ': 'i',
+	'ʀ': 'r', 'ʏ': 'y', 'ᴠ': 'v', 'ᴡ': 'w', 'ᴢ': 'z',
+}
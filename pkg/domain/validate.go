@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength  = 253 // RFC 1035 2.3.4, without the root's trailing dot
+	maxLabelLength = 63
+)
+
+// Validate reports why a name isn't a valid host name, or nil when it is.
+// Case and a single trailing dot are accepted, as Normalize removes them.
+//
+// Labels follow the RFC 1035 letter-digit-hyphen rule. Underscores are
+// allowed in labels below the registrable domain, where service names such
+// as _dmarc and _acme-challenge live, but not in the registrable domain or
+// the TLD, which registries never delegate with one.
+func Validate(name string) error {
+	if err := validateSyntax(name); err != nil {
+		return err
+	}
+	// The registrable domain and everything above it must be plain LDH
+	root := GetRootDomain(name)
+	if i := strings.IndexByte(root, '_'); i >= 0 {
+		label := root[strings.LastIndex(root[:i], ".")+1:]
+		if dot := strings.IndexByte(label, '.'); dot >= 0 {
+			label = label[:dot]
+		}
+		return invalid(name, "label %q of registrable domain %s has an underscore", label, root)
+	}
+	return nil
+}
+
+// validateSyntax checks the length and characters of a name and its labels.
+// Unlike Validate it doesn't consult the suffix list, so the list can use it
+// on its own rules.
+func validateSyntax(name string) error {
+	raw := name
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "." {
+		return invalid(raw, "empty name")
+	}
+	if strings.HasSuffix(name, "..") {
+		return invalid(raw, "more than one trailing dot")
+	}
+	name = strings.TrimSuffix(name, ".")
+	if net.ParseIP(strings.Trim(name, "[]")) != nil {
+		return invalid(raw, "is an IP address, not a domain name")
+	}
+	if !utf8.ValidString(name) {
+		return invalid(raw, "not valid UTF-8")
+	}
+	if len(name) > maxNameLength {
+		return invalid(raw, "%d characters long, the limit is %d", len(name), maxNameLength)
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		switch {
+		case label == "" && i == 0:
+			return invalid(raw, "starts with a dot")
+		case label == "":
+			return invalid(raw, "empty label (consecutive dots)")
+		case len(label) > maxLabelLength:
+			return invalid(raw, "label %q is %d characters long, the limit is %d", label, len(label), maxLabelLength)
+		case label[0] == '-' || label[len(label)-1] == '-':
+			return invalid(raw, "label %q starts or ends with a hyphen", label)
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			case r >= utf8.RuneSelf:
+				return invalid(raw, "label %q has non-ASCII character %q; use its xn-- (punycode) form", label, r)
+			default:
+				return invalid(raw, "label %q has invalid character %q", label, r)
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if strings.Trim(tld, "0123456789") == "" {
+		return invalid(raw, "top-level domain %q is all digits", tld)
+	}
+	return nil
+}
+
+func invalid(name, format string, args ...any) error {
+	return fmt.Errorf("invalid domain %q: %s", name, fmt.Sprintf(format, args...))
+}
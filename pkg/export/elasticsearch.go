@@ -0,0 +1,354 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+)
+
+// DefaultIndex receives documents when the config doesn't name an index
+const DefaultIndex = "dnscrawler-results"
+
+// elasticsearch indexes documents with the bulk API, which Elasticsearch and
+// OpenSearch share. An index template fixes the mapping, so fields don't
+// change type depending on which domain was indexed first.
+type elasticsearch struct {
+	cfg    config.Exporter
+	client *http.Client
+
+	mu          sync.Mutex // guards templateSet
+	templateSet bool
+}
+
+func newElasticsearch(cfg config.Exporter, client *http.Client) (*elasticsearch, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("exporter %s: url is required", cfg.Type)
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	if cfg.Index == "" {
+		cfg.Index = DefaultIndex
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+		if cfg.Insecure {
+			t := http.DefaultTransport.(*http.Transport).Clone()
+			t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			client.Transport = t
+		}
+	}
+	return &elasticsearch{cfg: cfg, client: client}, nil
+}
+
+func (e *elasticsearch) Name() string { return "elasticsearch " + e.cfg.Index }
+
+func (e *elasticsearch) Close() error { return nil }
+
+func (e *elasticsearch) Export(docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := e.ensureTemplate(); err != nil {
+		return fmt.Errorf("%s: index template: %v", e.Name(), err)
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, d := range docs {
+		// The ID makes retries of the same run idempotent
+		id := sha1.Sum([]byte(d.Result.Domain + "|" + d.Time.Format(time.RFC3339Nano)))
+		enc.Encode(map[string]any{"index": map[string]string{"_index": e.cfg.Index, "_id": hex.EncodeToString(id[:])}})
+		enc.Encode(esDocument(d))
+	}
+
+	resp, err := e.do(http.MethodPost, "/_bulk", "application/x-ndjson", &body)
+	if err != nil {
+		return fmt.Errorf("%s: %v", e.Name(), err)
+	}
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return fmt.Errorf("%s: bulk response: %v", e.Name(), err)
+	}
+	if result.Errors {
+		failed := 0
+		var first string
+		for _, item := range result.Items {
+			for _, op := range item {
+				if op.Status > 299 {
+					if failed == 0 {
+						first = op.Error.Type + ": " + op.Error.Reason
+					}
+					failed++
+				}
+			}
+		}
+		return fmt.Errorf("%s: %d of %d documents failed, first: %s", e.Name(), failed, len(docs), first)
+	}
+	return nil
+}
+
+// ensureTemplate installs the index template once per process
+func (e *elasticsearch) ensureTemplate() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.templateSet {
+		return nil
+	}
+	data, err := json.Marshal(indexTemplate(e.cfg.Index))
+	if err != nil {
+		return err
+	}
+	if _, err := e.do(http.MethodPut, "/_index_template/"+e.cfg.Index, "application/json", bytes.NewReader(data)); err != nil {
+		return err
+	}
+	e.templateSet = true
+	return nil
+}
+
+func (e *elasticsearch) do(method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, e.cfg.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	switch {
+	case e.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.cfg.APIKey)
+	case e.cfg.Username != "":
+		req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data[:min(len(data), 300)])))
+	}
+	return data, nil
+}
+
+// esDocument flattens a result into the fields of the index mapping. The full
+// result is kept in "result" but not indexed, since some sections have keys
+// that vary per domain (e.g. IP addresses) and would blow up the mapping.
+func esDocument(d Document) map[string]any {
+	r := d.Result
+	doc := map[string]any{
+		"@timestamp": d.Time.UTC().Format(time.RFC3339),
+		"domain":     r.Domain,
+		"registered": r.Registered,
+		"healthy":    len(d.Problems) == 0,
+		"problems":   d.Problems,
+		"result":     r,
+	}
+	if d.Group != "" {
+		doc["group"] = d.Group
+	}
+	if r.Root != nil {
+		doc["root_domain"] = r.Root.Domain
+	}
+
+	if s := r.Whois; s != nil {
+		w := map[string]any{"error": s.Error, "newly_registered": s.NewlyRegistered}
+		if s.Info != nil {
+			w["registrar"] = s.Registrar
+			w["registry"] = s.Registry
+			w["registrant"] = s.Registrant
+			w["status"] = s.Info.Status
+			w["days_to_expiry"] = s.DaysToExpiry
+			w["age_days"] = s.AgeDays
+			if t, ok := s.ExpiryTime(); ok {
+				w["expires"] = t.UTC().Format(time.RFC3339)
+			}
+			if t, ok := s.CreatedTime(); ok {
+				w["created"] = t.UTC().Format(time.RFC3339)
+			}
+		}
+		doc["whois"] = w
+	}
+
+	if s := r.Nameservers; s != nil {
+		var names, ips, providers []string
+		for _, ns := range s.Servers {
+			names = append(names, ns.Name)
+			if ns.IP != "" {
+				ips = append(ips, ns.IP)
+			}
+			if ns.Provider != "" {
+				providers = append(providers, ns.Provider)
+			}
+		}
+		doc["nameservers"] = map[string]any{"error": s.Error, "names": names, "ips": ips, "providers": providers, "count": len(s.Servers)}
+	}
+
+	if s := r.Records; s != nil {
+		doc["records"] = map[string]any{
+			"error": s.Error,
+			"a":     addresses(s.A),
+			"aaaa":  addresses(s.AAAA),
+			"cname": values(s.CNAME),
+			"mx":    values(s.MX),
+			"txt":   values(s.TXT),
+		}
+	}
+
+	if s := r.Email; s != nil {
+		doc["email"] = map[string]any{"error": s.Error, "spf": s.SPF, "dmarc": s.DMARC, "dmarc_policy": s.DMARCPolicy()}
+	}
+
+	if s := r.TLS; s != nil {
+		t := map[string]any{"error": s.Error}
+		if s.Info != nil {
+			t["subject"] = s.Subject
+			t["issuer"] = s.Issuer
+			t["not_after"] = s.NotAfter.UTC().Format(time.RFC3339)
+			t["days_to_expiry"] = s.DaysToExpiry
+			t["valid"] = s.Valid
+			t["verify_error"] = s.VerifyError
+		}
+		doc["tls"] = t
+	}
+
+	if s := r.DNSSEC; s != nil {
+		d := map[string]any{"error": s.Error, "signed": s.Signed}
+		if s.DNSSEC != nil {
+			d["validated"] = s.Validated
+		}
+		doc["dnssec"] = d
+	}
+	if s := r.CAA; s != nil {
+		doc["caa"] = map[string]any{"error": s.Error, "records": s.Records}
+	}
+	if s := r.Reputation; s != nil {
+		doc["reputation"] = map[string]any{"error": s.Error, "malicious": s.Malicious}
+	}
+	if s := r.Blocklists; s != nil {
+		doc["blocklists"] = map[string]any{"error": s.Error, "listed": s.Listed}
+	}
+	return doc
+}
+
+func values(records []crawler.Record) []string {
+	out := make([]string, len(records))
+	for i, r := range records {
+		out[i] = r.Value
+	}
+	return out
+}
+
+// addresses returns the values of A or AAAA records that are addresses,
+// leaving out the CNAME targets listed with them, which the ip mapping
+// would reject
+func addresses(records []crawler.Record) []string {
+	var out []string
+	for _, r := range records {
+		if _, err := netip.ParseAddr(r.Value); err == nil {
+			out = append(out, r.Value)
+		}
+	}
+	return out
+}
+
+// indexTemplate returns the composable index template for index. Unmapped
+// fields are kept in _source but not indexed.
+func indexTemplate(index string) map[string]any {
+	keyword := map[string]any{"type": "keyword"}
+	text := map[string]any{"type": "text", "fields": map[string]any{"keyword": map[string]any{"type": "keyword", "ignore_above": 1024}}}
+	boolean := map[string]any{"type": "boolean"}
+	integer := map[string]any{"type": "integer"}
+	date := map[string]any{"type": "date"}
+	object := func(props map[string]any) map[string]any {
+		return map[string]any{"properties": props}
+	}
+
+	return map[string]any{
+		"index_patterns": []string{index, index + "-*"},
+		"priority":       100,
+		"template": map[string]any{
+			"mappings": map[string]any{
+				"dynamic": false,
+				"properties": map[string]any{
+					"@timestamp":  date,
+					"domain":      keyword,
+					"root_domain": keyword,
+					"group":       keyword,
+					"registered":  boolean,
+					"healthy":     boolean,
+					"problems":    keyword,
+					"whois": object(map[string]any{
+						"error":            text,
+						"registrar":        keyword,
+						"registry":         keyword,
+						"registrant":       keyword,
+						"status":           keyword,
+						"created":          date,
+						"expires":          date,
+						"days_to_expiry":   integer,
+						"age_days":         integer,
+						"newly_registered": boolean,
+					}),
+					"nameservers": object(map[string]any{
+						"error":     text,
+						"names":     keyword,
+						"ips":       map[string]any{"type": "ip"},
+						"providers": keyword,
+						"count":     integer,
+					}),
+					"records": object(map[string]any{
+						"error": text,
+						"a":     map[string]any{"type": "ip"},
+						"aaaa":  map[string]any{"type": "ip"},
+						"cname": keyword,
+						"mx":    keyword,
+						"txt":   text,
+					}),
+					"email": object(map[string]any{
+						"error":        text,
+						"spf":          text,
+						"dmarc":        text,
+						"dmarc_policy": keyword,
+					}),
+					"tls": object(map[string]any{
+						"error":          text,
+						"subject":        keyword,
+						"issuer":         keyword,
+						"not_after":      date,
+						"days_to_expiry": integer,
+						"valid":          boolean,
+						"verify_error":   text,
+					}),
+					"dnssec":     object(map[string]any{"error": text, "signed": boolean, "validated": boolean}),
+					"caa":        object(map[string]any{"error": text, "records": keyword}),
+					"reputation": object(map[string]any{"error": text, "malicious": boolean}),
+					"blocklists": object(map[string]any{"error": text, "listed": boolean}),
+					"result":     map[string]any{"type": "object", "enabled": false},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+// Package export ships crawl results and alerts from monitoring runs to
+// external systems: search engines for dashboards over the whole domain
+// portfolio, and message queues for event-driven automation.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/notify"
+)
+
+// Default topics of the message queue exporters
+const (
+	DefaultTopic       = "dnscrawler.results"
+	DefaultEventsTopic = "dnscrawler.events"
+)
+
+// Document is one domain's result from one monitoring run
+type Document struct {
+	Time   time.Time
+	Group  string
+	Result *crawler.Result
+	// Problems are the failing health checks of the result
+	Problems []string
+}
+
+// Exporter sends the documents of a run to one destination
+type Exporter interface {
+	Name() string
+	Export(docs []Document) error
+	Close() error
+}
+
+// EventExporter is implemented by exporters that also publish alerts
+type EventExporter interface {
+	ExportAlerts(alerts []notify.Alert) error
+}
+
+// New creates the exporter described by a config entry. HTTP exporters
+// send through client; with cfg.Insecure, its transport must skip
+// certificate verification, and a nil client is replaced by one that does.
+func New(cfg config.Exporter, client *http.Client) (Exporter, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "elasticsearch", "opensearch":
+		return newElasticsearch(cfg, client)
+	case "kafka":
+		return newKafka(cfg)
+	case "nats":
+		return newNATS(cfg)
+	}
+	return nil, fmt.Errorf("exporter: unknown type %q", cfg.Type)
+}
+
+// message is the JSON published for a result by the message queue exporters
+type message struct {
+	Time     time.Time       `json:"time"`
+	Group    string          `json:"group,omitempty"`
+	Domain   string          `json:"domain"`
+	Healthy  bool            `json:"healthy"`
+	Problems []string        `json:"problems,omitempty"`
+	Result   *crawler.Result `json:"result"`
+}
+
+// JSON returns the message the message queue exporters publish for d
+func JSON(d Document) ([]byte, error) {
+	return json.Marshal(newMessage(d))
+}
+
+func newMessage(d Document) message {
+	return message{
+		Time:     d.Time,
+		Group:    d.Group,
+		Domain:   d.Result.Domain,
+		Healthy:  len(d.Problems) == 0,
+		Problems: d.Problems,
+		Result:   d.Result,
+	}
+}
+
+// topics returns the configured topics with defaults applied
+func topics(cfg config.Exporter) (string, string) {
+	topic, events := cfg.Topic, cfg.EventsTopic
+	if topic == "" {
+		topic = DefaultTopic
+	}
+	if events == "" {
+		events = DefaultEventsTopic
+	}
+	return topic, events
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/notify"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// kafkaTimeout bounds the publishing of one batch
+const kafkaTimeout = 30 * time.Second
+
+// kafkaExporter publishes results and alerts as JSON messages keyed by domain,
+// so all messages about a domain land in the same partition, in order
+type kafkaExporter struct {
+	writer *kafka.Writer
+	topic  string
+	events string
+}
+
+func newKafka(cfg config.Exporter) (*kafkaExporter, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("exporter kafka: brokers are required")
+	}
+	transport := &kafka.Transport{}
+	if cfg.TLS {
+		transport.TLS = &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	}
+	if cfg.SASL != "" {
+		mechanism, err := saslMechanism(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.SASL = mechanism
+	}
+
+	topic, events := topics(cfg)
+	return &kafkaExporter{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			Transport:    transport,
+		},
+		topic:  topic,
+		events: events,
+	}, nil
+}
+
+func saslMechanism(cfg config.Exporter) (sasl.Mechanism, error) {
+	switch strings.ToLower(cfg.SASL) {
+	case "plain":
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	}
+	return nil, fmt.Errorf("exporter kafka: unknown sasl mechanism %q", cfg.SASL)
+}
+
+func (k *kafkaExporter) Name() string { return "kafka " + k.topic }
+
+func (k *kafkaExporter) Export(docs []Document) error {
+	var msgs []kafka.Message
+	for _, d := range docs {
+		value, err := json.Marshal(newMessage(d))
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{Topic: k.topic, Key: []byte(d.Result.Domain), Value: value, Time: d.Time})
+	}
+	return k.write(msgs)
+}
+
+func (k *kafkaExporter) ExportAlerts(alerts []notify.Alert) error {
+	var msgs []kafka.Message
+	for _, a := range alerts {
+		value, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{Topic: k.events, Key: []byte(a.Domain), Value: value, Time: a.Time})
+	}
+	return k.write(msgs)
+}
+
+func (k *kafkaExporter) write(msgs []kafka.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+	defer cancel()
+	if err := k.writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("kafka: %v", err)
+	}
+	return nil
+}
+
+func (k *kafkaExporter) Close() error {
+	return k.writer.Close()
+}
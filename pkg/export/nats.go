@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/notify"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsFlushTimeout bounds how long a batch may take to reach the server
+const natsFlushTimeout = 10 * time.Second
+
+// natsExporter publishes results and alerts as JSON messages. The subjects
+// get the domain appended (dnscrawler.results.example.com), so subscribers can
+// filter with wildcards.
+type natsExporter struct {
+	conn   *nats.Conn
+	topic  string
+	events string
+}
+
+func newNATS(cfg config.Exporter) (*natsExporter, error) {
+	url := cfg.URL
+	if url == "" {
+		url = nats.DefaultURL
+	}
+	opts := []nats.Option{
+		nats.Name("dnscrawler"),
+		// Connect in the background, so a daemon can start before NATS
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("exporter nats: %v", err)
+	}
+	topic, events := topics(cfg)
+	return &natsExporter{conn: conn, topic: topic, events: events}, nil
+}
+
+func (n *natsExporter) Name() string { return "nats " + n.topic }
+
+func (n *natsExporter) Export(docs []Document) error {
+	for _, d := range docs {
+		data, err := json.Marshal(newMessage(d))
+		if err != nil {
+			return err
+		}
+		if err := n.conn.Publish(n.topic+"."+d.Result.Domain, data); err != nil {
+			return fmt.Errorf("nats: %v", err)
+		}
+	}
+	return n.flush()
+}
+
+func (n *natsExporter) ExportAlerts(alerts []notify.Alert) error {
+	for _, a := range alerts {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if err := n.conn.Publish(n.events+"."+a.Domain, data); err != nil {
+			return fmt.Errorf("nats: %v", err)
+		}
+	}
+	return n.flush()
+}
+
+// flush waits until the server has received everything published
+func (n *natsExporter) flush() error {
+	if err := n.conn.FlushTimeout(natsFlushTimeout); err != nil {
+		return fmt.Errorf("nats: %v", err)
+	}
+	return nil
+}
+
+func (n *natsExporter) Close() error {
+	return n.conn.Drain()
+}
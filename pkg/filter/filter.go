@@ -0,0 +1,105 @@
+// Package filter evaluates user-supplied boolean expressions against crawl results.
+//
+// Expressions use the expr language (https://expr-lang.org) and address
+// fields by their JSON names, e.g. `whois.days_to_expiry < 60 || email.dmarc == nil`.
+// Fields of sections that are missing or failed are nil, and nil compares
+// false, so such an expression doesn't match a domain whose WHOIS failed
+// instead of failing on it.
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+	"github.com/expr-lang/expr/vm/runtime"
+)
+
+// Filter is a compiled filter expression
+type Filter struct {
+	source  string
+	program *vm.Program
+}
+
+// Compile parses a filter expression
+func Compile(source string) (*Filter, error) {
+	options := []expr.Option{expr.AsBool(), expr.AllowUndefinedVariables(), expr.Patch(nilSafe{})}
+	for op, compare := range comparisons {
+		options = append(options, expr.Function(op, func(params ...any) (any, error) {
+			if params[0] == nil || params[1] == nil {
+				return false, nil
+			}
+			return compare(params[0], params[1]), nil
+		}, new(func(any, any) bool)))
+	}
+	program, err := expr.Compile(source, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %v", err)
+	}
+	return &Filter{source: source, program: program}, nil
+}
+
+// String returns the original expression
+func (f *Filter) String() string {
+	return f.source
+}
+
+// Match evaluates the filter against v, which is first converted to its JSON
+// representation so the expression sees the same field names as JSON output.
+func (f *Filter) Match(v any) (bool, error) {
+	env, err := toEnv(v)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := expr.Run(f.program, env)
+	if err != nil {
+		return false, err
+	}
+	return out.(bool), nil
+}
+
+// comparisons are the ordering operators, which fail on nil, by the name
+// of the function nilSafe calls instead
+var comparisons = map[string]func(a, b any) bool{
+	"<":  runtime.Less,
+	">":  runtime.More,
+	"<=": runtime.LessOrEqual,
+	">=": runtime.MoreOrEqual,
+}
+
+// nilSafe makes field access through nil give nil, like foo?.bar, and
+// ordering comparisons with nil false
+type nilSafe struct{}
+
+func (nilSafe) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.MemberNode:
+		// Methods are called through their member node
+		if !n.Optional && !n.Method {
+			n.Optional = true
+			ast.Patch(node, &ast.ChainNode{Node: n})
+		}
+	case *ast.BinaryNode:
+		if _, ok := comparisons[n.Operator]; ok {
+			ast.Patch(node, &ast.CallNode{
+				Callee:    &ast.IdentifierNode{Value: n.Operator},
+				Arguments: []ast.Node{n.Left, n.Right},
+			})
+		}
+	}
+}
+
+func toEnv(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	env := make(map[string]any)
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
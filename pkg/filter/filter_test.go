@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// TestMissingSection checks that fields of missing and failed sections
+// compare false instead of failing the filter
+func TestMissingSection(t *testing.T) {
+	days := 30
+	f, err := Compile(`whois.days_to_expiry < 60 || email.dmarc == "v=DMARC1; p=none"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		result *crawler.Result
+		want   bool
+	}{
+		{"unregistered", &crawler.Result{Domain: "example.com"}, false},
+		{"whois failed", &crawler.Result{Domain: "example.com", Registered: true,
+			Whois: &crawler.WhoisSection{Status: crawler.Status{Error: "timeout"}}}, false},
+		{"expiring", &crawler.Result{Domain: "example.com", Registered: true,
+			Whois: &crawler.WhoisSection{Info: &whois.Info{DaysToExpiry: &days}}}, true},
+		{"whois failed, dmarc", &crawler.Result{Domain: "example.com", Registered: true,
+			Whois: &crawler.WhoisSection{Status: crawler.Status{Error: "timeout"}},
+			Email: &crawler.EmailSection{DMARC: "v=DMARC1; p=reject"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := f.Match(tc.result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Package fixture records network responses to disk and replays them later,
+// so a crawl can be reproduced exactly without touching the network.
+package fixture
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// Mode selects whether a Store captures or serves responses
+type Mode int
+
+const (
+	// Record performs the real lookup and writes the response to disk
+	Record Mode = iota
+	// Replay serves responses from disk and never touches the network
+	Replay
+	// Offline serves no responses and never touches the network
+	Offline
+)
+
+// ErrNotRecorded is returned in replay mode when no fixture exists for a query
+var ErrNotRecorded = errors.New("no recorded fixture")
+
+// ErrOffline is returned in offline mode for every query
+var ErrOffline = errors.New("offline: no network access")
+
+// sentinels are the errors a replayed error still matches with errors.Is,
+// keyed by their message
+var (
+	sentinelsMu sync.RWMutex
+	sentinels   = map[string]error{}
+)
+
+func init() {
+	RegisterErrors(context.DeadlineExceeded, context.Canceled, os.ErrDeadlineExceeded, io.ErrUnexpectedEOF,
+		syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ENETUNREACH, syscall.EHOSTUNREACH)
+}
+
+// RegisterErrors makes errors matching one of errs when recorded match it
+// again when replayed, so callers testing them with errors.Is behave the
+// same under replay
+func RegisterErrors(errs ...error) {
+	sentinelsMu.Lock()
+	defer sentinelsMu.Unlock()
+	for _, err := range errs {
+		sentinels[err.Error()] = err
+	}
+}
+
+// replayedError is a recorded error, matching the registered errors the
+// original matched
+type replayedError struct {
+	msg string
+	is  []error
+}
+
+func (e *replayedError) Error() string   { return e.msg }
+func (e *replayedError) Unwrap() []error { return e.is }
+
+// Store captures or replays responses keyed by lookup kind and query.
+// A nil *Store is valid and simply performs every lookup live.
+type Store struct {
+	mode Mode
+	dir  string
+	mu   sync.Mutex
+	// onLookup is called with every lookup, see OnLookup
+	onLookup func(kind, key string)
+}
+
+// entry is the on-disk representation of a single recorded response
+type entry struct {
+	Kind  string `json:"kind"`
+	Key   string `json:"key"`
+	Data  []byte `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Is lists the registered errors the error matched
+	Is []string `json:"is,omitempty"`
+}
+
+// NewRecorder creates a store that records responses into dir
+func NewRecorder(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{mode: Record, dir: dir}, nil
+}
+
+// NewReplayer creates a store that replays responses previously recorded into dir
+func NewReplayer(dir string) (*Store, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &Store{mode: Replay, dir: dir}, nil
+}
+
+// NewOffline creates a store failing every lookup with ErrOffline, so
+// nothing reaches the network
+func NewOffline() *Store {
+	return &Store{mode: Offline}
+}
+
+// Replaying reports whether the store serves responses from disk
+func (s *Store) Replaying() bool {
+	return s != nil && s.mode == Replay
+}
+
+// OnLookup calls fn with the kind and key of every lookup made through the
+// store from now on, e.g. to list what a replayed crawl asked for. It must
+// be set before the store is used; fn may be called concurrently.
+func (s *Store) OnLookup(fn func(kind, key string)) {
+	s.onLookup = fn
+}
+
+// Do returns the response for the given kind and key. When recording, fetch is
+// called and its result (including any error) is written to disk; when
+// replaying, the recorded result is returned without calling fetch; offline,
+// Do fails.
+func (s *Store) Do(kind, key string, fetch func() ([]byte, error)) ([]byte, error) {
+	if s == nil {
+		return fetch()
+	}
+	if s.onLookup != nil {
+		s.onLookup(kind, key)
+	}
+
+	if s.mode == Offline {
+		return nil, fmt.Errorf("%w for %s %q", ErrOffline, kind, key)
+	}
+
+	path := s.path(kind, key)
+
+	if s.mode == Replay {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s %q", ErrNotRecorded, kind, key)
+		}
+		var e entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("corrupt fixture %s: %v", path, err)
+		}
+		if e.Error != "" {
+			return e.Data, replayed(e)
+		}
+		return e.Data, nil
+	}
+
+	data, fetchErr := fetch()
+	e := entry{Kind: kind, Key: key, Data: data}
+	if fetchErr != nil {
+		e.Error = fetchErr.Error()
+		e.Is = matching(fetchErr)
+	}
+	if err := s.write(path, e); err != nil {
+		// A fixture missing from the recording must not go unnoticed
+		return data, errors.Join(fetchErr, err)
+	}
+	return data, fetchErr
+}
+
+// write stores e at path
+func (s *Store) write(path string, e entry) error {
+	raw, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("recording %s %q: %w", e.Kind, e.Key, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("recording %s %q: %w", e.Kind, e.Key, err)
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return fmt.Errorf("recording %s %q: %w", e.Kind, e.Key, err)
+	}
+	return nil
+}
+
+// matching returns the messages of the registered errors err matches
+func matching(err error) []string {
+	sentinelsMu.RLock()
+	defer sentinelsMu.RUnlock()
+	var is []string
+	for msg, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			is = append(is, msg)
+		}
+	}
+	slices.Sort(is)
+	return is
+}
+
+// replayed rebuilds the recorded error of e
+func replayed(e entry) error {
+	sentinelsMu.RLock()
+	defer sentinelsMu.RUnlock()
+	err := &replayedError{msg: e.Error}
+	for _, msg := range e.Is {
+		if sentinel, ok := sentinels[msg]; ok {
+			err.is = append(err.is, sentinel)
+		}
+	}
+	return err
+}
+
+// path maps a kind/key pair to a stable file name inside the fixture directory
+func (s *Store) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + key))
+	return filepath.Join(s.dir, kind, hex.EncodeToString(sum[:12])+".json")
+}
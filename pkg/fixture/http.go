@@ -0,0 +1,105 @@
+package fixture
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// credentialParams are the query and form parameters services take API
+// keys in, left out of fixture keys so recordings hold no secrets and
+// replay whichever key is configured
+var credentialParams = []string{"apikey", "key", "app_key"}
+
+// Transport wraps base so HTTP responses are recorded or replayed by the store.
+// On a nil store it returns base unchanged (http.DefaultTransport if base is nil).
+func (s *Store) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if s == nil {
+		return base
+	}
+	return &transport{store: s, base: base}
+}
+
+type transport struct {
+	store *Store
+	base  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	key := req.Method + " " + withoutCredentials(req.URL)
+	if len(body) > 0 {
+		sum := sha256.Sum256(formWithoutCredentials(req.Header.Get("Content-Type"), body))
+		key += " body=" + hex.EncodeToString(sum[:8])
+	}
+
+	raw, err := t.store.Do("http", key, func() ([]byte, error) {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return httputil.DumpResponse(resp, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), req)
+}
+
+// withoutCredentials returns u with the credentialParams removed
+func withoutCredentials(u *url.URL) string {
+	query := u.Query()
+	if !stripCredentials(query) {
+		return u.String()
+	}
+	stripped := *u
+	stripped.RawQuery = query.Encode()
+	return stripped.String()
+}
+
+// formWithoutCredentials returns a request body with the credentialParams
+// removed when it is a form, such as PhishTank's
+func formWithoutCredentials(contentType string, body []byte) []byte {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "application/x-www-form-urlencoded" {
+		return body
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil || !stripCredentials(form) {
+		return body
+	}
+	return []byte(form.Encode())
+}
+
+// stripCredentials removes the credentialParams from values, reporting
+// whether there were any
+func stripCredentials(values url.Values) bool {
+	found := false
+	for _, param := range credentialParams {
+		if values.Has(param) {
+			values.Del(param)
+			found = true
+		}
+	}
+	return found
+}
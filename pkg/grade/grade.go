@@ -0,0 +1,281 @@
+// Package grade rolls the checks of a crawl into a letter grade and a
+// scorecard of what to fix first.
+package grade
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+)
+
+// Options returns the crawler options needed to grade a domain
+func Options() crawler.Options {
+	return crawler.Options{
+		NoTrace: true,
+		TLS:     true,
+		DNSSEC:  true,
+		CAA:     true,
+	}
+}
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+	// NA is a check that doesn't apply to the domain; it counts for
+	// nothing, neither points nor maximum
+	NA Status = "n/a"
+)
+
+// Check is one line of the scorecard
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Points int    `json:"points"`
+	Max    int    `json:"max"`
+	Detail string `json:"detail"`
+	// Fix describes the remediation when the check didn't pass
+	Fix string `json:"fix,omitempty"`
+}
+
+// Scorecard is the graded result for a domain
+type Scorecard struct {
+	Domain string  `json:"domain"`
+	Grade  string  `json:"grade"`
+	Score  int     `json:"score"` // 0-100
+	Checks []Check `json:"checks"`
+	// Remediation lists the fixes worth the most points, best first
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// maxRemediation is how many fixes are suggested
+const maxRemediation = 5
+
+// Grades are the letter grades with the minimum score for each
+var Grades = []struct {
+	Letter string
+	Min    int
+}{
+	{"A", 90},
+	{"B", 80},
+	{"C", 70},
+	{"D", 60},
+	{"F", 0},
+}
+
+// Evaluate grades a crawl result. Zone-level checks (WHOIS, nameservers,
+// DNSSEC) use the root domain's result when the domain is a subdomain.
+func Evaluate(r *crawler.Result) *Scorecard {
+	zone := r
+	if r.Root != nil {
+		zone = r.Root
+	}
+
+	checks := []Check{
+		checkDNSSEC(zone),
+		checkSPF(r),
+		checkDMARC(r),
+		checkNameservers(zone),
+		checkExpiry(zone),
+		checkTLS(r),
+		checkKey(r),
+		checkCAA(r),
+	}
+
+	card := &Scorecard{Domain: r.Domain, Checks: checks}
+	var points, max int
+	for _, c := range checks {
+		points += c.Points
+		max += c.Max
+	}
+	if max > 0 {
+		card.Score = points * 100 / max
+	}
+	card.Grade = Letter(card.Score)
+
+	failed := make([]Check, 0, len(checks))
+	for _, c := range checks {
+		if c.Status != Pass && c.Fix != "" {
+			failed = append(failed, c)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].Max-failed[i].Points > failed[j].Max-failed[j].Points
+	})
+	for _, c := range failed[:min(len(failed), maxRemediation)] {
+		card.Remediation = append(card.Remediation, c.Fix)
+	}
+	return card
+}
+
+// Letter returns the letter grade for a score
+func Letter(score int) string {
+	for _, g := range Grades {
+		if score >= g.Min {
+			return g.Letter
+		}
+	}
+	return "F"
+}
+
+// Rank orders letter grades, higher is better; unknown letters rank lowest
+func Rank(letter string) int {
+	for i, g := range Grades {
+		if strings.EqualFold(g.Letter, letter) {
+			return len(Grades) - i
+		}
+	}
+	return 0
+}
+
+// score builds a check, awarding full points on pass and half on warn; a
+// check that doesn't apply is left out of the maximum
+func score(name string, max int, status Status, detail, fix string) Check {
+	c := Check{Name: name, Status: status, Max: max, Detail: detail}
+	switch status {
+	case Pass:
+		c.Points = max
+	case Warn:
+		c.Points = max / 2
+	case NA:
+		c.Max = 0
+	}
+	if status != Pass && status != NA {
+		c.Fix = fix
+	}
+	return c
+}
+
+func checkDNSSEC(zone *crawler.Result) Check {
+	const name, max = "DNSSEC", 15
+	sec := zone.DNSSEC
+	switch {
+	case sec == nil || sec.Failed():
+		return score(name, max, Fail, "could not be checked", "Investigate why DNSSEC records could not be looked up")
+	case !sec.Signed:
+		return score(name, max, Fail, "zone is not signed", "Sign the zone and publish a DS record at the registrar")
+	case !sec.Validated:
+		return score(name, max, Fail, "signed but does not validate", "Fix the DNSSEC chain of trust: the DS record does not match the zone's keys")
+	}
+	return score(name, max, Pass, "signed and validated", "")
+}
+
+// checkSPF grades the domain's own SPF record, as SPF isn't inherited from
+// the registrable domain
+func checkSPF(r *crawler.Result) Check {
+	const name, max = "SPF", 10
+	email := r.Email
+	if email == nil || email.SPF == "" {
+		return score(name, max, Fail, "no SPF record", `Publish an SPF record, e.g. "v=spf1 -all" if the domain sends no mail`)
+	}
+	switch email.SPFPolicy() {
+	case "-":
+		return score(name, max, Pass, "hard fail (-all)", "")
+	case "~":
+		return score(name, max, Warn, "soft fail (~all)", "Tighten SPF from ~all to -all once all senders are listed")
+	}
+	return score(name, max, Fail, "permissive SPF policy", "End the SPF record with -all so unlisted senders are rejected")
+}
+
+// checkDMARC grades the policy receivers apply, which for a subdomain
+// without a record of its own is the registrable domain's
+func checkDMARC(r *crawler.Result) Check {
+	const name, max = "DMARC", 15
+	policy, tag := r.DMARCPolicy()
+	switch policy {
+	case "":
+		return score(name, max, Fail, "no DMARC record", "Publish a DMARC record at _dmarc with p=reject")
+	case "reject":
+		return score(name, max, Pass, tag+"=reject", "")
+	case "quarantine":
+		return score(name, max, Warn, tag+"=quarantine", "Move the DMARC policy from quarantine to reject")
+	}
+	return score(name, max, Fail, tag+"="+policy, "Enforce DMARC: p=none only monitors, move to quarantine and then reject")
+}
+
+func checkNameservers(zone *crawler.Result) Check {
+	const name, max = "Nameservers", 15
+	ns := zone.Nameservers
+	if ns == nil || ns.Failed() || len(ns.Servers) == 0 {
+		return score(name, max, Fail, "no nameservers found", "Investigate why the nameservers could not be looked up")
+	}
+	if len(ns.Servers) < 2 {
+		return score(name, max, Fail, "single nameserver", "Add at least one more nameserver")
+	}
+
+	networks := make(map[string]bool)
+	for _, s := range ns.Servers {
+		if s.ASN != "" {
+			networks[s.ASN] = true
+		}
+	}
+	detail := fmt.Sprintf("%d servers in %d networks", len(ns.Servers), len(networks))
+	if len(networks) < 2 {
+		return score(name, max, Warn, detail, "Spread nameservers across more than one network to survive a provider outage")
+	}
+	return score(name, max, Pass, detail, "")
+}
+
+func checkExpiry(zone *crawler.Result) Check {
+	const name, max = "Expiry", 15
+	w := zone.Whois
+	if w == nil || w.Failed() || w.DaysToExpiry == nil {
+		return score(name, max, Warn, "expiry date unknown", "Verify the registration expiry date with the registrar")
+	}
+	days := *w.DaysToExpiry
+	detail := fmt.Sprintf("expires in %d days", days)
+	switch {
+	case days < 14:
+		return score(name, max, Fail, detail, "Renew the domain now and enable auto-renewal")
+	case days < 60:
+		return score(name, max, Warn, detail, "Renew the domain or enable auto-renewal")
+	}
+	return score(name, max, Pass, detail, "")
+}
+
+func checkTLS(r *crawler.Result) Check {
+	const name, max = "TLS", 20
+	t := r.TLS
+	switch {
+	case t == nil || t.Failed():
+		return score(name, max, Fail, "no certificate", "Serve HTTPS with a valid certificate")
+	case !t.Valid:
+		return score(name, max, Fail, t.VerifyError, "Replace the certificate with one that validates for the domain")
+	case t.DaysToExpiry < 14:
+		return score(name, max, Warn, fmt.Sprintf("expires in %d days", t.DaysToExpiry), "Renew the certificate and automate renewal")
+	}
+	return score(name, max, Pass, fmt.Sprintf("valid, expires in %d days", t.DaysToExpiry), "")
+}
+
+func checkKey(r *crawler.Result) Check {
+	const name, max = "Key", 10
+	t := r.TLS
+	if t == nil || t.Failed() {
+		// Without a certificate there is no key to judge; the TLS check
+		// already asks for one
+		return score(name, max, NA, "no certificate", "")
+	}
+	if weak := t.Key.Weaknesses(); len(weak) > 0 {
+		return score(name, max, Fail, strings.Join(weak, ", "), "Reissue the certificate with an ECDSA P-256 or 2048-bit RSA key and a SHA-256 signature")
+	}
+	return score(name, max, Pass, t.Key.String(), "")
+}
+
+func checkCAA(r *crawler.Result) Check {
+	const name, max = "CAA", 10
+	caa := r.CAA
+	switch {
+	case caa == nil || caa.Failed():
+		return score(name, max, Fail, "could not be checked", "Investigate why CAA records could not be looked up")
+	case len(caa.Records) == 0:
+		return score(name, max, Fail, "no CAA records", "Publish CAA records naming the certificate authorities you use")
+	case caa.IssuerPermitted != nil && !*caa.IssuerPermitted:
+		return score(name, max, Warn, fmt.Sprintf("the certificate's issuer %s isn't permitted", caa.Issuer), "Add the CA in use to the CAA records, or replace the certificate")
+	}
+	return score(name, max, Pass, fmt.Sprintf("%d records at %s", len(caa.Records), caa.Name), "")
+}
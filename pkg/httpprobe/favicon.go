@@ -0,0 +1,103 @@
+package httpprobe
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxFavicon is the largest favicon hashed
+const maxFavicon = 1 << 20
+
+// Favicon is a site's /favicon.ico, hashed the ways search engines index it
+// so the hashes can be searched for other hosts serving the same icon
+type Favicon struct {
+	URL string `json:"url"`
+	// Hash is Shodan's http.favicon.hash: MurmurHash3 of the icon's base64
+	// with a line break every 76 characters
+	Hash int32 `json:"hash"`
+	// MD5 is Censys' favicon hash
+	MD5  string `json:"md5"`
+	Size int    `json:"size"`
+}
+
+// fetchFavicon gets /favicon.ico of a page's site, following redirects. It
+// returns nil when there is none.
+func fetchFavicon(ctx context.Context, transport http.RoundTripper, page string) *Favicon {
+	u, err := url.Parse(page)
+	if err != nil {
+		return nil
+	}
+	resp, data, err := get(ctx, transport, u.ResolveReference(&url.URL{Path: "/favicon.ico"}).String(), maxFavicon)
+	if err != nil || !found(resp) || len(data) == 0 {
+		return nil
+	}
+	sum := md5.Sum(data)
+	return &Favicon{
+		URL:  resp.Request.URL.String(),
+		Hash: murmur3(mimeBase64(data)),
+		MD5:  hex.EncodeToString(sum[:]),
+		Size: len(data),
+	}
+}
+
+// mimeBase64 encodes data as Python's base64.encodebytes does, which
+// Shodan's hash is computed on: lines of 76 characters, each ending in a
+// newline
+func mimeBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// murmur3 is the 32-bit MurmurHash3 with seed 0, as a signed integer the
+// way Python's mmh3.hash returns it
+func murmur3(data []byte) int32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	var h uint32
+	n := len(data)
+	for ; len(data) >= 4; data = data[4:] {
+		k := binary.LittleEndian.Uint32(data)
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return int32(h)
+}
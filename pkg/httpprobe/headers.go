@@ -0,0 +1,166 @@
+package httpprobe
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// minHSTSAge is the shortest HSTS max-age that passes, 180 days
+const minHSTSAge = 180 * 24 * 60 * 60
+
+// HeaderCheck is the verdict on one security header of the final response
+type HeaderCheck struct {
+	Name   string `json:"name"` // short name, e.g. HSTS
+	Header string `json:"header"`
+	Pass   bool   `json:"pass"`
+	Detail string `json:"detail"`
+}
+
+// checkHeaders grades the security headers of a response
+func checkHeaders(h http.Header, https bool) []HeaderCheck {
+	return []HeaderCheck{
+		checkHSTS(h, https),
+		checkCSP(h),
+		checkFraming(h),
+		checkReferrer(h),
+		checkNoSniff(h),
+	}
+}
+
+func checkHSTS(h http.Header, https bool) HeaderCheck {
+	c := HeaderCheck{Name: "HSTS", Header: "Strict-Transport-Security"}
+	value := h.Get(c.Header)
+	switch {
+	case !https:
+		c.Detail = "site is served over plain HTTP"
+	case value == "":
+		c.Detail = "missing"
+	default:
+		hsts := ParseHSTS(value)
+		c.Pass = hsts.MaxAge >= minHSTSAge
+		c.Detail = value
+		if !c.Pass {
+			c.Detail = "max-age below 180 days: " + value
+		}
+	}
+	return c
+}
+
+func checkCSP(h http.Header) HeaderCheck {
+	c := HeaderCheck{Name: "CSP", Header: "Content-Security-Policy"}
+	value := h.Get(c.Header)
+	switch {
+	case value == "" && h.Get("Content-Security-Policy-Report-Only") != "":
+		c.Detail = "report-only"
+	case value == "":
+		c.Detail = "missing"
+	default:
+		directives := parseCSP(value)
+		scripts, ok := directives["script-src"]
+		if !ok {
+			scripts, ok = directives["default-src"]
+		}
+		switch {
+		case !ok:
+			c.Detail = "doesn't restrict scripts (no script-src or default-src)"
+		case strings.Contains(scripts, "'unsafe-inline'") && !strings.Contains(scripts, "'nonce-") && !strings.Contains(scripts, "'sha"):
+			c.Detail = "scripts allow 'unsafe-inline'"
+		default:
+			c.Pass = true
+			c.Detail = "present"
+		}
+	}
+	return c
+}
+
+func checkFraming(h http.Header) HeaderCheck {
+	c := HeaderCheck{Name: "X-FRAME", Header: "X-Frame-Options"}
+	value := strings.ToUpper(strings.TrimSpace(h.Get(c.Header)))
+	_, frameAncestors := parseCSP(h.Get("Content-Security-Policy"))["frame-ancestors"]
+	switch {
+	case frameAncestors:
+		c.Pass = true
+		c.Detail = "CSP frame-ancestors"
+	case value == "DENY" || value == "SAMEORIGIN":
+		c.Pass = true
+		c.Detail = value
+	case value == "":
+		c.Detail = "missing"
+	default:
+		c.Detail = "unsupported value: " + value
+	}
+	return c
+}
+
+// weakReferrers are Referrer-Policy values that send full URLs to other sites
+var weakReferrers = map[string]bool{"unsafe-url": true, "no-referrer-when-downgrade": true}
+
+func checkReferrer(h http.Header) HeaderCheck {
+	c := HeaderCheck{Name: "REFERRER", Header: "Referrer-Policy"}
+	value := strings.TrimSpace(h.Get(c.Header))
+	// The last policy a browser understands applies
+	policies := strings.Split(value, ",")
+	policy := strings.ToLower(strings.TrimSpace(policies[len(policies)-1]))
+	switch {
+	case value == "":
+		c.Detail = "missing"
+	case weakReferrers[policy]:
+		c.Detail = policy + " leaks full URLs to other sites"
+	default:
+		c.Pass = true
+		c.Detail = policy
+	}
+	return c
+}
+
+func checkNoSniff(h http.Header) HeaderCheck {
+	c := HeaderCheck{Name: "NOSNIFF", Header: "X-Content-Type-Options"}
+	value := strings.TrimSpace(h.Get(c.Header))
+	c.Pass = strings.EqualFold(value, "nosniff")
+	c.Detail = value
+	if value == "" {
+		c.Detail = "missing"
+	}
+	return c
+}
+
+// HSTS is a parsed Strict-Transport-Security header
+type HSTS struct {
+	MaxAge            int  `json:"max_age"`
+	IncludeSubDomains bool `json:"include_subdomains"`
+	Preload           bool `json:"preload"`
+}
+
+// ParseHSTS parses a Strict-Transport-Security header value
+func ParseHSTS(value string) HSTS {
+	var hsts HSTS
+	for _, directive := range strings.Split(value, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			hsts.MaxAge, _ = strconv.Atoi(strings.Trim(strings.TrimSpace(arg), `"`))
+		case "includesubdomains":
+			hsts.IncludeSubDomains = true
+		case "preload":
+			hsts.Preload = true
+		}
+	}
+	return hsts
+}
+
+// parseCSP maps the directives of a Content-Security-Policy to their sources
+func parseCSP(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, directive := range strings.Split(value, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, seen := directives[name]; !seen {
+			directives[name] = strings.Join(fields[1:], " ")
+		}
+	}
+	return directives
+}
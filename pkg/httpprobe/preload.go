@@ -0,0 +1,231 @@
+package httpprobe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PreloadListURL is Chromium's HSTS preload list, which the other browsers
+// build theirs from. Gitiles serves it base64 encoded with ?format=TEXT.
+const PreloadListURL = "https://chromium.googlesource.com/chromium/src/+/main/net/http/transport_security_state_static.json?format=TEXT"
+
+// minPreloadAge is the HSTS max-age the preload list requires, one year
+const minPreloadAge = 365 * 24 * 60 * 60
+
+// PreloadList holds the names on the HSTS preload list and whether their
+// subdomains are included
+type PreloadList struct {
+	names map[string]bool
+}
+
+// Len returns the number of names on the list
+func (l *PreloadList) Len() int {
+	return len(l.names)
+}
+
+// Preloaded reports whether browsers force HTTPS for a name, as it or a
+// parent that includes its subdomains is on the list
+func (l *PreloadList) Preloaded(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if _, ok := l.names[name]; ok {
+		return true
+	}
+	for i := strings.IndexByte(name, '.'); i >= 0; i = strings.IndexByte(name, '.') {
+		name = name[i+1:]
+		if l.names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// PreloadListCachePath is where `dnscrawler update hsts` stores the list, or
+// "" when there is no user cache directory
+func PreloadListCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dnscrawler", "hsts_preload.txt")
+}
+
+// LoadPreloadList reads the cached list. It returns nil without error when
+// no list has been cached.
+func LoadPreloadList() (*PreloadList, error) {
+	path := PreloadListCachePath()
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// One name per line, followed by " +" when its subdomains are included
+	l := &PreloadList{names: make(map[string]bool)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, subdomains := strings.CutSuffix(scanner.Text(), " +")
+		l.names[name] = subdomains
+	}
+	return l, scanner.Err()
+}
+
+// UpdatePreloadList downloads the list from PreloadListURL into the cache
+// dir, keeping only the names whose HTTPS is forced
+func UpdatePreloadList(ctx context.Context, client *http.Client) (*PreloadList, error) {
+	path := PreloadListCachePath()
+	if path == "" {
+		return nil, fmt.Errorf("HSTS preload list: no user cache directory")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, PreloadListURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HSTS preload list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HSTS preload list: %s answered %s", req.URL.Host, resp.Status)
+	}
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, io.LimitReader(resp.Body, 64<<20)))
+	if err != nil {
+		return nil, fmt.Errorf("HSTS preload list: %w", err)
+	}
+
+	// The JSON has comment lines
+	var stripped bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("//")) {
+			stripped.Write(line)
+			stripped.WriteByte('\n')
+		}
+	}
+	var list struct {
+		Entries []struct {
+			Name              string `json:"name"`
+			Mode              string `json:"mode"`
+			IncludeSubdomains bool   `json:"include_subdomains"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(stripped.Bytes(), &list); err != nil {
+		return nil, fmt.Errorf("HSTS preload list: %w", err)
+	}
+
+	l := &PreloadList{names: make(map[string]bool)}
+	var out bytes.Buffer
+	for _, e := range list.Entries {
+		// Entries without the mode only pin keys
+		if e.Mode != "force-https" {
+			continue
+		}
+		l.names[e.Name] = e.IncludeSubdomains
+		out.WriteString(e.Name)
+		if e.IncludeSubdomains {
+			out.WriteString(" +")
+		}
+		out.WriteByte('\n')
+	}
+	if len(l.names) == 0 {
+		return nil, fmt.Errorf("HSTS preload list: no entries in the download")
+	}
+	if err := writeFileAtomic(path, out.Bytes()); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Preload is a domain's HSTS preload status
+type Preload struct {
+	// Status is preloaded when the domain is on the list, eligible when
+	// its site meets the list's requirements, and missing otherwise
+	Status string `json:"status"`
+	// Problems are the requirements the site doesn't meet; a preloaded
+	// domain with problems may be removed from the list
+	Problems []string `json:"problems,omitempty"`
+	// Unchecked is set when no list has been downloaded, so a domain that
+	// is eligible may be preloaded already
+	Unchecked bool `json:"unchecked,omitempty"`
+}
+
+// CheckPreload works out the preload status of a registrable domain from
+// the probe of its website. The list requires the domain to redirect from
+// HTTP to HTTPS on the same host first, and to send an HSTS header with a
+// max-age of a year, includeSubDomains and preload over HTTPS, on a
+// redirect to elsewhere as well.
+func (p *Prober) CheckPreload(domain string, ep *Endpoint) *Preload {
+	pl := &Preload{Status: "missing", Unchecked: p.Preload == nil}
+	if p.Preload != nil && p.Preload.Preloaded(domain) {
+		pl.Status = "preloaded"
+	}
+
+	// The response of https://domain/: the first one when the site doesn't
+	// answer HTTP, the one after the upgrade otherwise
+	responses := append(slices.Clone(ep.Redirects), Hop{URL: ep.FinalURL, Status: ep.Status, header: ep.header})
+	https := responses[0]
+	if u, err := url.Parse(https.URL); err == nil && u.Scheme == "http" {
+		if len(responses) < 2 || !strings.HasPrefix(responses[1].URL, "https://"+domain+"/") {
+			pl.Problems = append(pl.Problems, "HTTP doesn't redirect to HTTPS on the same host first")
+		}
+		if len(responses) > 1 {
+			https = responses[1]
+		}
+	}
+	switch value := https.header.Get("Strict-Transport-Security"); {
+	case !strings.HasPrefix(https.URL, "https://"):
+		pl.Problems = append(pl.Problems, "not served over HTTPS")
+	case value == "":
+		pl.Problems = append(pl.Problems, "no HSTS header on "+https.URL)
+	default:
+		hsts := ParseHSTS(value)
+		if hsts.MaxAge < minPreloadAge {
+			pl.Problems = append(pl.Problems, "max-age below one year")
+		}
+		if !hsts.IncludeSubDomains {
+			pl.Problems = append(pl.Problems, "no includeSubDomains")
+		}
+		if !hsts.Preload {
+			pl.Problems = append(pl.Problems, "no preload directive")
+		}
+	}
+	if pl.Status == "missing" && len(pl.Problems) == 0 {
+		pl.Status = "eligible"
+	}
+	return pl
+}
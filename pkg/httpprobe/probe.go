@@ -0,0 +1,179 @@
+// Package httpprobe fetches a domain's website and reports where it leads.
+package httpprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultMaxRedirects is the number of redirects followed before giving up
+const DefaultMaxRedirects = 10
+
+// maxBody is how much of the final response's body is kept
+const maxBody = 256 << 10
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// Hop is a redirect on the way to the final URL
+type Hop struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Server string `json:"server,omitempty"`
+
+	header http.Header
+}
+
+// Endpoint is where a host's website leads
+type Endpoint struct {
+	// URL is the first URL fetched: http:// when the host answers on port
+	// 80, so the redirect to HTTPS shows up in Redirects
+	URL       string `json:"url"`
+	FinalURL  string `json:"final_url"`
+	Status    int    `json:"status"`
+	Server    string `json:"server,omitempty"`
+	Redirects []Hop  `json:"redirects,omitempty"`
+	// Technologies are what the final page is built with and hosted on
+	Technologies []Technology `json:"technologies,omitempty"`
+	// SecurityHeaders grades the security headers of the final response
+	SecurityHeaders []HeaderCheck `json:"security_headers,omitempty"`
+	// Favicon is the final site's /favicon.ico, nil when it has none
+	Favicon *Favicon `json:"favicon,omitempty"`
+
+	header http.Header
+	body   []byte
+}
+
+// HTTPS reports whether the final URL is served over HTTPS
+func (e *Endpoint) HTTPS() bool {
+	u, err := url.Parse(e.FinalURL)
+	return err == nil && u.Scheme == "https"
+}
+
+// Body returns the start of the final response's body
+func (e *Endpoint) Body() []byte {
+	return e.body
+}
+
+// Prober fetches websites, following redirects
+type Prober struct {
+	Timeout      time.Duration // for each redirect chain and the favicon
+	MaxRedirects int
+	// Transport makes the requests, e.g. through a proxy or from fixtures;
+	// nil uses http.DefaultTransport
+	Transport http.RoundTripper
+	// Preload is the HSTS preload list for CheckPreload; nil when it
+	// hasn't been downloaded
+	Preload *PreloadList
+	// WellKnownPaths are the /.well-known paths DiscoverWellKnown looks for
+	WellKnownPaths []string
+}
+
+func NewProber() *Prober {
+	return &Prober{Timeout: 10 * time.Second, MaxRedirects: DefaultMaxRedirects, WellKnownPaths: DefaultWellKnown}
+}
+
+// Probe fetches http://host/ and follows its redirects, falling back to
+// https://host/ when nothing answers on port 80, then fetches the favicon of
+// the site it ends up on. Each of them has Timeout of its own, so a port 80
+// that never answers leaves the fallback its full time. Error statuses are
+// reported in the result rather than as an error.
+func (p *Prober) Probe(host string) (*Endpoint, error) {
+	client := &http.Client{
+		Transport: p.Transport,
+		// Redirects are followed here, to record them
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	ep, err := p.follow(client, "http://"+host+"/")
+	if err != nil && ep == nil {
+		var httpsErr error
+		if ep, httpsErr = p.follow(client, "https://"+host+"/"); httpsErr == nil {
+			err = nil
+		}
+	}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+		defer cancel()
+		ep.Favicon = fetchFavicon(ctx, p.Transport, ep.FinalURL)
+	}
+	return ep, err
+}
+
+// follow fetches a URL and the ones it redirects to within Timeout. It
+// returns a nil Endpoint when the first request fails, and the redirects so
+// far when a later one does.
+func (p *Prober) follow(client *http.Client, start string) (*Endpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	ep := &Endpoint{URL: start}
+	next := start
+	for range p.MaxRedirects + 1 {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "dnscrawler")
+		resp, err := client.Do(req)
+		if err != nil {
+			if len(ep.Redirects) == 0 {
+				return nil, err
+			}
+			return ep, err
+		}
+		server := resp.Header.Get("Server")
+		location, err := resp.Location()
+		if !isRedirect(resp.StatusCode) || err != nil {
+			// A redirect without a usable Location ends the chain too
+			ep.FinalURL, ep.Status, ep.Server, ep.header = next, resp.StatusCode, server, resp.Header
+			ep.SecurityHeaders = checkHeaders(resp.Header, ep.HTTPS())
+			// A body cut short doesn't change where the site leads
+			ep.body, _ = io.ReadAll(io.LimitReader(resp.Body, maxBody))
+			resp.Body.Close()
+			ep.Technologies = fingerprint(resp.Header, ep.body)
+			return ep, nil
+		}
+		resp.Body.Close()
+		ep.Redirects = append(ep.Redirects, Hop{URL: next, Status: resp.StatusCode, Server: server, header: resp.Header})
+		next = location.String()
+	}
+	return ep, fmt.Errorf("%w after %s", errTooManyRedirects, next)
+}
+
+// get fetches a URL, following redirects, and reads up to limit bytes of
+// the body
+func get(ctx context.Context, transport http.RoundTripper, url string, limit int64) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", "dnscrawler")
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	return resp, body, err
+}
+
+// found reports whether a response is the file asked for: sites answering
+// missing paths with a page rather than a 404 serve HTML
+func found(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return resp.StatusCode == http.StatusOK && mediaType != "text/html"
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package httpprobe
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Technology is software or a platform a website runs on
+type Technology struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // cms, shop, framework, language, hosting or cdn
+}
+
+// Platform reports whether the technology is where the site is hosted
+// rather than what it is built with
+func (t Technology) Platform() bool {
+	return t.Category == "hosting" || t.Category == "cdn"
+}
+
+// signature tells a technology by any of its traces in a response. Header
+// patterns are "Name: regexp" and match the header's value; an empty
+// regexp matches any value. Cookies are name prefixes.
+type signature struct {
+	name, category string
+	headers        []string
+	cookies        []string
+	generator      string // regexp on the meta generator tag
+	html           string // regexp on the page
+}
+
+// signatures are the technologies told apart, in the manner of Wappalyzer
+// but much smaller
+var signatures = []signature{
+	// CMSs and site builders
+	{name: "WordPress", category: "cms", headers: []string{"Link: wp-json"}, generator: `^WordPress`, html: `/wp-(content|includes)/`},
+	{name: "Drupal", category: "cms", headers: []string{"X-Generator: ^Drupal", "X-Drupal-Cache: "}, generator: `^Drupal`},
+	{name: "Joomla", category: "cms", generator: `^Joomla`},
+	{name: "Ghost", category: "cms", headers: []string{"X-Ghost-Cache-Status: "}, generator: `^Ghost`},
+	{name: "Wix", category: "cms", headers: []string{"X-Wix-Request-Id: "}, generator: `^Wix\.com`},
+	{name: "Squarespace", category: "cms", headers: []string{"Server: ^Squarespace"}, html: `static1\.squarespace\.com`},
+	{name: "Webflow", category: "cms", generator: `^Webflow`, html: `data-wf-site=`},
+	{name: "Hugo", category: "cms", generator: `^Hugo`},
+	{name: "Jekyll", category: "cms", generator: `^Jekyll`},
+
+	// Shops
+	{name: "Shopify", category: "shop", headers: []string{"X-ShopId: ", "X-Shopify-Stage: "}, cookies: []string{"_shopify_"}, html: `cdn\.shopify\.com`},
+	{name: "WooCommerce", category: "shop", html: `/wp-content/plugins/woocommerce/`},
+	{name: "Magento", category: "shop", headers: []string{"X-Magento-Cache-Debug: "}, cookies: []string{"X-Magento-Vary"}, html: `Mage\.Cookies`},
+
+	// Frameworks and languages
+	{name: "Next.js", category: "framework", headers: []string{"X-Powered-By: Next\\.js"}, html: `__NEXT_DATA__|/_next/static/`},
+	{name: "Nuxt", category: "framework", html: `__NUXT__|/_nuxt/`},
+	{name: "Gatsby", category: "framework", generator: `^Gatsby`, html: `id="___gatsby"`},
+	{name: "Angular", category: "framework", html: `ng-version="`},
+	{name: "Laravel", category: "framework", cookies: []string{"laravel_session"}},
+	{name: "Express", category: "framework", headers: []string{"X-Powered-By: ^Express"}},
+	{name: "ASP.NET", category: "framework", headers: []string{"X-AspNet-Version: ", "X-Powered-By: ASP\\.NET"}, cookies: []string{"ASP.NET_SessionId"}},
+	{name: "PHP", category: "language", headers: []string{"X-Powered-By: ^PHP"}, cookies: []string{"PHPSESSID"}},
+
+	// Hosting platforms and CDNs
+	{name: "Vercel", category: "hosting", headers: []string{"Server: ^Vercel", "X-Vercel-Id: "}},
+	{name: "Netlify", category: "hosting", headers: []string{"Server: ^Netlify", "X-NF-Request-Id: "}},
+	{name: "GitHub Pages", category: "hosting", headers: []string{"Server: ^GitHub\\.com"}},
+	{name: "Heroku", category: "hosting", headers: []string{"Via: vegur"}},
+	{name: "Google Cloud", category: "hosting", headers: []string{"Server: ^Google Frontend"}},
+	{name: "Amazon S3", category: "hosting", headers: []string{"Server: ^AmazonS3"}},
+	{name: "Cloudflare", category: "cdn", headers: []string{"Server: ^cloudflare", "CF-RAY: "}},
+	{name: "Amazon CloudFront", category: "cdn", headers: []string{"X-Amz-Cf-Id: ", "Via: CloudFront"}},
+	{name: "Fastly", category: "cdn", headers: []string{"X-Served-By: ^cache-", "Fastly-Debug-Digest: "}},
+	{name: "Akamai", category: "cdn", headers: []string{"Server: ^AkamaiGHost"}},
+	{name: "Azure Front Door", category: "cdn", headers: []string{"X-Azure-Ref: "}},
+}
+
+var generatorTag = regexp.MustCompile(`(?i)<meta\s[^>]*name=["']generator["'][^>]*content=["']([^"']+)|<meta\s[^>]*content=["']([^"']+)["'][^>]*name=["']generator["']`)
+
+// matcher is a compiled signature
+type matcher struct {
+	tech      Technology
+	headers   map[string]*regexp.Regexp // nil matches any value
+	cookies   []string
+	generator *regexp.Regexp
+	html      *regexp.Regexp
+}
+
+var matchers = compileSignatures(signatures)
+
+func compileSignatures(signatures []signature) []matcher {
+	// Header values and generators match case-insensitively
+	compile := func(pattern, flags string) *regexp.Regexp {
+		if pattern == "" {
+			return nil
+		}
+		return regexp.MustCompile(flags + pattern)
+	}
+	var compiled []matcher
+	for _, s := range signatures {
+		m := matcher{
+			tech:      Technology{Name: s.name, Category: s.category},
+			headers:   make(map[string]*regexp.Regexp),
+			cookies:   s.cookies,
+			generator: compile(s.generator, "(?i)"),
+			html:      compile(s.html, ""),
+		}
+		for _, hp := range s.headers {
+			name, pattern, _ := strings.Cut(hp, ": ")
+			m.headers[name] = compile(pattern, "(?i)")
+		}
+		compiled = append(compiled, m)
+	}
+	return compiled
+}
+
+// fingerprint lists the technologies a response shows traces of
+func fingerprint(h http.Header, body []byte) []Technology {
+	var generator string
+	if m := generatorTag.FindSubmatch(body); m != nil {
+		generator = string(m[1]) + string(m[2])
+	}
+	var cookies []string
+	for _, c := range h.Values("Set-Cookie") {
+		name, _, _ := strings.Cut(c, "=")
+		cookies = append(cookies, strings.TrimSpace(name))
+	}
+
+	var found []Technology
+	for _, m := range matchers {
+		if m.matches(h, cookies, generator, body) {
+			found = append(found, m.tech)
+		}
+	}
+	return found
+}
+
+func (m matcher) matches(h http.Header, cookies []string, generator string, body []byte) bool {
+	for name, pattern := range m.headers {
+		values := h.Values(name)
+		if len(values) > 0 && (pattern == nil || slices.ContainsFunc(values, pattern.MatchString)) {
+			return true
+		}
+	}
+	for _, prefix := range m.cookies {
+		if slices.ContainsFunc(cookies, func(c string) bool { return strings.HasPrefix(c, prefix) }) {
+			return true
+		}
+	}
+	if m.generator != nil && m.generator.MatchString(generator) {
+		return true
+	}
+	return m.html != nil && m.html.Match(body)
+}
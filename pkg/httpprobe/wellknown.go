@@ -0,0 +1,157 @@
+package httpprobe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWellKnown are the /.well-known paths looked for unless configured
+// otherwise
+var DefaultWellKnown = []string{
+	"security.txt",
+	"mta-sts.txt",
+	"matrix/server",
+	"apple-app-site-association",
+	"openid-configuration",
+}
+
+// maxWellKnown is how much of a well-known file is read
+const maxWellKnown = 64 << 10
+
+// wellKnownHosts are the prefixes of the hosts serving paths that don't live
+// on the domain itself
+var wellKnownHosts = map[string]string{"mta-sts.txt": "mta-sts."}
+
+// WellKnown is what a domain serves under /.well-known
+type WellKnown struct {
+	Files []WellKnownFile `json:"files"`
+	// SecurityTxt is the parsed security.txt, nil when there is none
+	SecurityTxt *SecurityTxt `json:"security_txt,omitempty"`
+}
+
+// Found returns the paths the domain serves
+func (w *WellKnown) Found() []string {
+	var found []string
+	for _, f := range w.Files {
+		if f.Found {
+			found = append(found, f.Path)
+		}
+	}
+	return found
+}
+
+// WellKnownFile is one /.well-known path. It counts as found when it
+// answers 200 with something other than HTML, which sites answering
+// missing paths with a page serve.
+type WellKnownFile struct {
+	Path   string `json:"path"`
+	URL    string `json:"url"` // after redirects
+	Status int    `json:"status,omitempty"`
+	Found  bool   `json:"found"`
+	Error  string `json:"error,omitempty"`
+
+	body []byte
+}
+
+// DiscoverWellKnown fetches the Prober's WellKnownPaths of a domain over
+// HTTPS, concurrently, and parses its security.txt
+func (p *Prober) DiscoverWellKnown(domain string) *WellKnown {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	w := &WellKnown{Files: make([]WellKnownFile, len(p.WellKnownPaths))}
+	var wg sync.WaitGroup
+	for i, path := range p.WellKnownPaths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Files[i] = p.fetchWellKnown(ctx, domain, path)
+		}()
+	}
+	wg.Wait()
+
+	for _, f := range w.Files {
+		if f.Path == "security.txt" && f.Found {
+			w.SecurityTxt = ParseSecurityTxt(f.body)
+		}
+	}
+	return w
+}
+
+// WellKnownURL is where DiscoverWellKnown looks for a path of a domain
+func WellKnownURL(domain, path string) string {
+	return "https://" + wellKnownHosts[path] + domain + "/.well-known/" + path
+}
+
+func (p *Prober) fetchWellKnown(ctx context.Context, domain, path string) WellKnownFile {
+	f := WellKnownFile{Path: path, URL: WellKnownURL(domain, path)}
+	resp, body, err := get(ctx, p.Transport, f.URL, maxWellKnown)
+	if err != nil {
+		f.Error = err.Error()
+		return f
+	}
+	f.URL, f.Status = resp.Request.URL.String(), resp.StatusCode
+	f.Found = found(resp)
+	if f.Found {
+		f.body = body
+	}
+	return f
+}
+
+// SecurityTxt is a security.txt (RFC 9116): how to report vulnerabilities
+// to the domain's owner
+type SecurityTxt struct {
+	Contact    []string  `json:"contact"`
+	Expires    time.Time `json:"expires,omitempty"`
+	Encryption []string  `json:"encryption,omitempty"`
+	Policy     []string  `json:"policy,omitempty"`
+	Canonical  []string  `json:"canonical,omitempty"`
+	// Signed is set when the file is OpenPGP cleartext signed; the
+	// signature isn't verified
+	Signed bool `json:"signed,omitempty"`
+}
+
+// Expired reports whether the file is past its Expires date, after which
+// RFC 9116 says it is stale and shouldn't be used
+func (s *SecurityTxt) Expired(now time.Time) bool {
+	return !s.Expires.IsZero() && now.After(s.Expires)
+}
+
+// ParseSecurityTxt parses the fields of a security.txt, skipping comments
+// and the armor of a signed one
+func ParseSecurityTxt(data []byte) *SecurityTxt {
+	s := &SecurityTxt{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "-----BEGIN PGP SIGNED MESSAGE-----" {
+			s.Signed = true
+			continue
+		}
+		if line == "-----BEGIN PGP SIGNATURE-----" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, "#") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(name) {
+		case "contact":
+			s.Contact = append(s.Contact, value)
+		case "expires":
+			s.Expires, _ = time.Parse(time.RFC3339, value)
+		case "encryption":
+			s.Encryption = append(s.Encryption, value)
+		case "policy":
+			s.Policy = append(s.Policy, value)
+		case "canonical":
+			s.Canonical = append(s.Canonical, value)
+		}
+	}
+	return s
+}
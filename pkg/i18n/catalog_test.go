@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// untranslated are the terms printed as written in every language
+var untranslated = map[string]bool{
+	"ALPN": true, "APEX / WWW": true, "CAA": true, "DMARC": true, "DNSSEC": true, "HSTS": true,
+	"HSTS PRELOAD": true, "HTTPS RR": true, "IDN": true, "IPV6": true, "JARM": true, "NXDOMAIN": true,
+	"OCSP": true, "PSL": true, "RDAP": true, "SOA": true, "SPF": true, "TLS": true,
+	"WELL-KNOWN": true, "WHOIS": true,
+}
+
+// translated are the methods of output.Formatter and Language translating
+// their first argument
+var translated = map[string]bool{
+	"PrintSection": true, "PrintKeyValue": true, "PrintError": true, "PrintWarning": true,
+	"PrintDim": true, "Sprintf": true, "T": true,
+}
+
+// verbs are the formatting directives of a message
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogs checks that every message the commands print in the text
+// output has an entry in each catalog, and that no message is built with
+// fmt.Sprintf or + where the formatter can't look it up
+func TestCatalogs(t *testing.T) {
+	files, err := filepath.Glob("../../cmd/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !translated[sel.Sel.Name] || isPackage(sel.X, "fmt") {
+				return true
+			}
+			pos := fset.Position(call.Args[0].Pos())
+			switch arg := call.Args[0].(type) {
+			case *ast.BasicLit:
+				msg, err := strconv.Unquote(arg.Value)
+				if err != nil || !translatable(msg) {
+					return true
+				}
+				for code, messages := range catalogs {
+					if _, ok := messages[msg]; !ok {
+						t.Errorf("%s: %q has no %s translation", pos, msg, code)
+					}
+				}
+			case *ast.CallExpr:
+				if fn, ok := arg.Fun.(*ast.SelectorExpr); ok && isPackage(fn.X, "fmt") {
+					t.Errorf("%s: message built with fmt.%s, use the formatter's Sprintf", pos, fn.Sel.Name)
+				}
+			case *ast.BinaryExpr:
+				if hasLiteral(arg) {
+					t.Errorf("%s: message built with +, use the formatter's Sprintf", pos)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// translatable reports whether msg has words to translate
+func translatable(msg string) bool {
+	if untranslated[msg] {
+		return false
+	}
+	return strings.ContainsFunc(verbs.ReplaceAllString(msg, ""), func(r rune) bool {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	})
+}
+
+func isPackage(x ast.Expr, name string) bool {
+	id, ok := x.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+// hasLiteral reports whether a concatenation includes a string literal
+func hasLiteral(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.STRING
+	case *ast.BinaryExpr:
+		return hasLiteral(e.X) || hasLiteral(e.Y)
+	case *ast.ParenExpr:
+		return hasLiteral(e.X)
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package i18n
+
+// german is the German catalog
+var german = map[string]string{
+	// Sections
+	"BLOCKLISTS":     "SPERRLISTEN",
+	"CHANGES":        "ÄNDERUNGEN",
+	"CONSISTENCY":    "KONSISTENZ",
+	"CURRENT":        "AKTUELL",
+	"DEPENDENCIES":   "ABHÄNGIGKEITEN",
+	"DNS TRACE":      "DNS-VERFOLGUNG",
+	"EMAIL":          "E-MAIL",
+	"EXPOSURE":       "OFFENE DIENSTE",
+	"NAMESERVERS":    "NAMESERVER",
+	"RAW":            "ROHDATEN",
+	"RECORDS":        "EINTRÄGE",
+	"RECURSION":      "REKURSION",
+	"REGISTRY":       "VERGABESTELLE",
+	"REPUTATION":     "REPUTATION",
+	"SCORECARD":      "BEWERTUNG",
+	"SHARED HOSTING": "GETEILTES HOSTING",
+	"THREAT INTEL":   "BEDROHUNGSDATEN",
+	"TOP FIXES":      "WICHTIGSTE MASSNAHMEN",
+	"WEB":            "WEBSITE",
+
+	// Keys
+	"ADDRESS":      "ADRESSE",
+	"ALGORITHMS":   "ALGORITHMEN",
+	"ANSWER":       "ANTWORT",
+	"CANONICAL":    "KANONISCH",
+	"CATEGORIES":   "KATEGORIEN",
+	"CHANGED":      "GEÄNDERT",
+	"CREATED":      "ERSTELLT",
+	"DETECTED":     "ERKANNT",
+	"EXPIRES":      "LÄUFT AB",
+	"FETCHED":      "ABGERUFEN",
+	"GRADE":        "NOTE",
+	"ISSUER":       "AUSSTELLER",
+	"KEY":          "SCHLÜSSEL",
+	"LEGAL ENTITY": "RECHTSTRÄGER",
+	"LIFECYCLE":    "LEBENSZYKLUS",
+	"LOCKS":        "SPERREN",
+	"NAMES":        "NAMEN",
+	"NEGATIVE TTL": "NEGATIVE TTL",
+	"OPERATOR":     "BETREIBER",
+	"ORG NUMBER":   "REGISTERNR.",
+	"POLICY":       "RICHTLINIE",
+	"PREVIOUS":     "VORHER",
+	"PRIMARY":      "PRIMÄR",
+	"PROBE":        "TEST",
+	"REGISTRANT":   "INHABER",
+	"REGISTRAR":    "REGISTRAR",
+	"RELATED":      "VERWANDT",
+	"SECURITY":     "SICHERHEIT",
+	"SEEN":         "GESEHEN",
+	"SERIAL":       "SERIENNR.",
+	"STATUS":       "STATUS",
+	"SUBJECT":      "SUBJEKT",
+	"TECH":         "TECHNIK",
+	"UPDATED":      "AKTUALISIERT",
+
+	// Messages
+	"All servers agree":                                   "Alle Server stimmen überein",
+	"All servers return identical answers":                "Alle Server liefern identische Antworten",
+	"No CAA records: any certificate authority may issue": "Keine CAA-Einträge: jede Zertifizierungsstelle darf ausstellen",
+	"No IDN tables published; ASCII names only":           "Keine IDN-Tabellen veröffentlicht; nur ASCII-Namen",
+	"No WHOIS history recorded":                           "Kein WHOIS-Verlauf gespeichert",
+	"No abuse contacts found":                             "Keine Abuse-Kontakte gefunden",
+	"No alerts":                                           "Keine Warnungen",
+	"No external dependencies":                            "Keine externen Abhängigkeiten",
+	"No historical records found":                         "Keine historischen Einträge gefunden",
+	"No nameservers found":                                "Keine Nameserver gefunden",
+	"No records found":                                    "Keine Einträge gefunden",
+	"No trace data":                                       "Keine Verfolgungsdaten",
+	"Shutting down":                                       "Wird beendet",
+	"chain":                                               "Kette",
+	"endpoints":                                           "Endpunkte",
+	"versions":                                            "Versionen",
+	"Domain not registered":                               "Domain nicht registriert",
+	"Domain not registered (%s: %s)":                      "Domain nicht registriert (%s: %s)",
+	"Domain not in DNS, but not available (%s: %s)":       "Domain nicht im DNS, aber nicht verfügbar (%s: %s)",
+	"%s (root domain)":                                    "%s (Hauptdomain)",
+	"POSSIBLE SPOOFING: %s looks like %s":                 "MÖGLICHE FÄLSCHUNG: %s sieht aus wie %s",
+	"MALICIOUS: listed by %s (%s)":                        "BÖSARTIG: gelistet bei %s (%s)",
+	"NEWLY REGISTERED: created %s (%d days ago)":          "NEU REGISTRIERT: erstellt %s (vor %d Tagen)",
+	"asked %s directly":                                   "direkt bei %s abgefragt",
+	"lookup failed: %s":                                   "Abfrage fehlgeschlagen: %s",
+	"check failed: %s":                                    "Prüfung fehlgeschlagen: %s",
+	"trace failed: %s":                                    "Verfolgung fehlgeschlagen: %s",
+	"probe failed: %s":                                    "Test fehlgeschlagen: %s",
+	"plugin failed: %s":                                   "Plugin fehlgeschlagen: %s",
+	"analysis failed: %s":                                 "Analyse fehlgeschlagen: %s",
+	"ALPN check failed: %s":                               "ALPN-Prüfung fehlgeschlagen: %s",
+	"%s: unreachable (%s)":                                "%s: nicht erreichbar (%s)",
+	"%s differs: %s":                                      "%s weicht ab: %s",
+	"%d of %d AAAA addresses unreachable":                 "%d von %d AAAA-Adressen nicht erreichbar",
+	"%s (%d days)":                                        "%s (%d Tage)",
+	"Zone is not signed":                                  "Zone ist nicht signiert",
+	"Zone is signed but does not validate":                "Zone ist signiert, validiert aber nicht",
+	"signed and validated":                                "signiert und validiert",
+	"mixes scripts: %s":                                   "mischt Schriftsysteme: %s",
+	"%s is an open resolver":                              "%s ist ein offener Resolver",
+	"refuses recursion":                                   "verweigert Rekursion",
+	"not listed":                                          "nicht gelistet",
+	"%s: listed (%s)":                                     "%s: gelistet (%s)",
+	"%s serial %d is %d behind":                           "%s Seriennummer %d liegt %d zurück",
+	"%s serial %d is %d behind for at least %s, longer than the %s refresh interval":   "%s Seriennummer %d liegt %d zurück, seit mindestens %s, länger als das Aktualisierungsintervall von %s",
+	"%s: no route from this host, not checked":                                         "%s: keine Route von diesem Host, nicht geprüft",
+	"%s: not checked, no IPv6 route from here":                                         "%s: nicht geprüft, keine IPv6-Route von hier",
+	"AAAA published but unreachable: clients preferring IPv6 fail or fall back slowly": "AAAA veröffentlicht, aber nicht erreichbar: Clients, die IPv6 bevorzugen, scheitern oder weichen langsam aus",
+	"%s has %d known vulnerabilities: %s":                                              "%s hat %d bekannte Schwachstellen: %s",
+	"nameservers of %s unknown, so are the zones it depends on: %s":                    "Nameserver von %s unbekannt, ebenso die Zonen, von denen sie abhängt: %s",
+	"security.txt has no Contact":                                                      "security.txt hat keinen Contact",
+	"security.txt expired %s":                                                          "security.txt abgelaufen am %s",
+	"the certificate's issuer, %s, is permitted":                                       "der Aussteller des Zertifikats, %s, ist erlaubt",
+	"%s related domains: %s":                                                           "%s verwandte Domains: %s",
+	"shared with other domains: %s":                                                    "geteilt mit anderen Domains: %s",
+	"and %d more":                                                                      "und %d weitere",
+	"  runs %s":                                                                        "  läuft mit %s",
+	"  favicon mmh3 %d, md5 %s":                                                        "  Favicon mmh3 %d, md5 %s",
+	"  via %d %s":                                                                      "  über %d %s",
+	"%s lists the registrant as dissolved, bankrupt or being wound up":                 "%s führt den Inhaber als aufgelöst, insolvent oder in Liquidation",
+	"invalid pattern: %v":                                                              "ungültiges Muster: %v",
+	"HSTS preload list: %v":                                                            "HSTS-Preload-Liste: %v",
+	"%d/%d rules passed":                                                               "%d/%d Regeln erfüllt",
+	"none since %s":                                                                    "keine seit %s",
+	"HTTP listening on %s":                                                             "HTTP lauscht auf %s",
+	"gRPC listening on %s":                                                             "gRPC lauscht auf %s",
+	"No API keys configured (serve.keys): anyone who can connect may crawl":            "Keine API-Schlüssel konfiguriert (serve.keys): jeder, der sich verbinden kann, darf crawlen",
+}
@@ -0,0 +1,95 @@
+// Package i18n translates the text output into the operator's language.
+//
+// Messages are looked up by their English text, so untranslated messages and
+// the English language need no catalog: they print as written.
+package i18n
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Language is a set of translations. The nil Language is English.
+type Language struct {
+	Code     string
+	messages map[string]string
+}
+
+// catalogs are the translations by language code
+var catalogs = map[string]map[string]string{
+	"no": norwegian,
+	"de": german,
+}
+
+// aliases map other codes to the catalog they use
+var aliases = map[string]string{"nb": "no", "nn": "no"}
+
+// Codes lists the supported language codes, English first
+func Codes() []string {
+	codes := []string{"en"}
+	for code := range catalogs {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes[1:])
+	return codes
+}
+
+// Lookup returns the language with the given code, e.g. "de" or "nb_NO.UTF-8".
+// ok is false for languages without translations, for which English is
+// returned.
+func Lookup(code string) (lang *Language, ok bool) {
+	// Locales are language[_territory][.codeset][@modifier]
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	code = cmp.Or(aliases[code], code)
+	if code == "en" {
+		return nil, true
+	}
+	messages, ok := catalogs[code]
+	if !ok {
+		return nil, false
+	}
+	return &Language{Code: code, messages: messages}, true
+}
+
+// Select returns the language named by code, or by the locale environment
+// (LC_ALL, LC_MESSAGES, LANG) when code is empty. Unsupported languages are
+// an error when asked for by name; an unsupported locale falls back to
+// English.
+func Select(code string) (*Language, error) {
+	if code != "" {
+		lang, ok := Lookup(code)
+		if !ok {
+			return nil, fmt.Errorf("unsupported language %q (supported: %s)", code, strings.Join(Codes(), ", "))
+		}
+		return lang, nil
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			lang, _ := Lookup(locale)
+			return lang, nil
+		}
+	}
+	return nil, nil
+}
+
+// T returns the translation of msg, or msg when there is none
+func (l *Language) T(msg string) string {
+	if l == nil {
+		return msg
+	}
+	if t, ok := l.messages[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format
+func (l *Language) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(l.T(format), args...)
+}
@@ -0,0 +1,130 @@
+package i18n
+
+// norwegian is the Norwegian (bokmål) catalog
+var norwegian = map[string]string{
+	// Sections
+	"BLOCKLISTS":     "BLOKKERINGSLISTER",
+	"CHANGES":        "ENDRINGER",
+	"CONSISTENCY":    "KONSISTENS",
+	"CURRENT":        "NÅVÆRENDE",
+	"DEPENDENCIES":   "AVHENGIGHETER",
+	"DNS TRACE":      "DNS-SPORING",
+	"EMAIL":          "E-POST",
+	"EXPOSURE":       "EKSPONERING",
+	"NAMESERVERS":    "NAVNETJENERE",
+	"RAW":            "RÅDATA",
+	"RECORDS":        "POSTER",
+	"RECURSION":      "REKURSJON",
+	"REGISTRY":       "REGISTER",
+	"REPUTATION":     "OMDØMME",
+	"SCORECARD":      "VURDERING",
+	"SHARED HOSTING": "DELT HOSTING",
+	"THREAT INTEL":   "TRUSSELDATA",
+	"TOP FIXES":      "VIKTIGSTE TILTAK",
+	"WEB":            "NETTSTED",
+
+	// Keys
+	"ADDRESS":      "ADRESSE",
+	"ALGORITHMS":   "ALGORITMER",
+	"ANSWER":       "SVAR",
+	"CANONICAL":    "KANONISK",
+	"CATEGORIES":   "KATEGORIER",
+	"CHANGED":      "ENDRET",
+	"CREATED":      "OPPRETTET",
+	"DETECTED":     "OPPDAGET",
+	"EXPIRES":      "UTLØPER",
+	"FETCHED":      "HENTET",
+	"GRADE":        "KARAKTER",
+	"ISSUER":       "UTSTEDER",
+	"KEY":          "NØKKEL",
+	"LEGAL ENTITY": "RETTSSUBJEKT",
+	"LIFECYCLE":    "LIVSSYKLUS",
+	"LOCKS":        "LÅSER",
+	"NAMES":        "NAVN",
+	"NEGATIVE TTL": "NEGATIV TTL",
+	"OPERATOR":     "OPERATØR",
+	"ORG NUMBER":   "ORG.NR.",
+	"POLICY":       "REGLER",
+	"PREVIOUS":     "FORRIGE",
+	"PRIMARY":      "PRIMÆR",
+	"PROBE":        "TEST",
+	"REGISTRANT":   "INNEHAVER",
+	"REGISTRAR":    "REGISTRATOR",
+	"RELATED":      "RELATERT",
+	"SECURITY":     "SIKKERHET",
+	"SEEN":         "SETT",
+	"SERIAL":       "SERIENR.",
+	"STATUS":       "TILSTAND",
+	"SUBJECT":      "SUBJEKT",
+	"TECH":         "TEKNISK",
+	"UPDATED":      "OPPDATERT",
+
+	// Messages
+	"All servers agree":                                   "Alle servere er enige",
+	"All servers return identical answers":                "Alle servere gir identiske svar",
+	"No CAA records: any certificate authority may issue": "Ingen CAA-poster: enhver sertifikatutsteder kan utstede",
+	"No IDN tables published; ASCII names only":           "Ingen IDN-tabeller publisert; kun ASCII-navn",
+	"No WHOIS history recorded":                           "Ingen WHOIS-historikk lagret",
+	"No abuse contacts found":                             "Ingen misbrukskontakter funnet",
+	"No alerts":                                           "Ingen varsler",
+	"No external dependencies":                            "Ingen eksterne avhengigheter",
+	"No historical records found":                         "Ingen historiske poster funnet",
+	"No nameservers found":                                "Ingen navnetjenere funnet",
+	"No records found":                                    "Ingen poster funnet",
+	"No trace data":                                       "Ingen sporingsdata",
+	"Shutting down":                                       "Avslutter",
+	"chain":                                               "kjede",
+	"endpoints":                                           "endepunkter",
+	"versions":                                            "versjoner",
+	"Domain not registered":                               "Domenet er ikke registrert",
+	"Domain not registered (%s: %s)":                      "Domenet er ikke registrert (%s: %s)",
+	"Domain not in DNS, but not available (%s: %s)":       "Domenet er ikke i DNS, men heller ikke ledig (%s: %s)",
+	"%s (root domain)":                                    "%s (hoveddomene)",
+	"POSSIBLE SPOOFING: %s looks like %s":                 "MULIG FORFALSKNING: %s ligner %s",
+	"MALICIOUS: listed by %s (%s)":                        "ONDSINNET: oppført hos %s (%s)",
+	"NEWLY REGISTERED: created %s (%d days ago)":          "NYLIG REGISTRERT: opprettet %s (for %d dager siden)",
+	"asked %s directly":                                   "spurt %s direkte",
+	"lookup failed: %s":                                   "oppslag feilet: %s",
+	"check failed: %s":                                    "sjekk feilet: %s",
+	"trace failed: %s":                                    "sporing feilet: %s",
+	"probe failed: %s":                                    "test feilet: %s",
+	"plugin failed: %s":                                   "tillegg feilet: %s",
+	"analysis failed: %s":                                 "analyse feilet: %s",
+	"ALPN check failed: %s":                               "ALPN-sjekk feilet: %s",
+	"%s: unreachable (%s)":                                "%s: utilgjengelig (%s)",
+	"%s differs: %s":                                      "%s avviker: %s",
+	"%d of %d AAAA addresses unreachable":                 "%d av %d AAAA-adresser er utilgjengelige",
+	"%s (%d days)":                                        "%s (%d dager)",
+	"Zone is not signed":                                  "Sonen er ikke signert",
+	"Zone is signed but does not validate":                "Sonen er signert, men validerer ikke",
+	"signed and validated":                                "signert og validert",
+	"mixes scripts: %s":                                   "blander skriftsystemer: %s",
+	"%s is an open resolver":                              "%s er en åpen resolver",
+	"refuses recursion":                                   "avviser rekursjon",
+	"not listed":                                          "ikke oppført",
+	"%s: listed (%s)":                                     "%s: oppført (%s)",
+	"%s serial %d is %d behind":                           "%s serienummer %d ligger %d bak",
+	"%s serial %d is %d behind for at least %s, longer than the %s refresh interval":   "%s serienummer %d ligger %d bak i minst %s, lenger enn oppfriskingsintervallet på %s",
+	"%s: no route from this host, not checked":                                         "%s: ingen rute fra denne maskinen, ikke sjekket",
+	"%s: not checked, no IPv6 route from here":                                         "%s: ikke sjekket, ingen IPv6-rute herfra",
+	"AAAA published but unreachable: clients preferring IPv6 fail or fall back slowly": "AAAA publisert, men utilgjengelig: klienter som foretrekker IPv6 feiler eller faller sakte tilbake",
+	"%s has %d known vulnerabilities: %s":                                              "%s har %d kjente sårbarheter: %s",
+	"nameservers of %s unknown, so are the zones it depends on: %s":                    "navnetjenerne til %s er ukjente, og dermed sonene den avhenger av: %s",
+	"security.txt has no Contact":                                                      "security.txt har ingen Contact",
+	"security.txt expired %s":                                                          "security.txt utløp %s",
+	"the certificate's issuer, %s, is permitted":                                       "sertifikatets utsteder, %s, er tillatt",
+	"%s related domains: %s":                                                           "%s relaterte domener: %s",
+	"shared with other domains: %s":                                                    "delt med andre domener: %s",
+	"and %d more":                                                                      "og %d til",
+	"  runs %s":                                                                        "  kjører %s",
+	"  favicon mmh3 %d, md5 %s":                                                        "  favikon mmh3 %d, md5 %s",
+	"  via %d %s":                                                                      "  via %d %s",
+	"%s lists the registrant as dissolved, bankrupt or being wound up":                 "%s oppgir innehaveren som oppløst, konkurs eller under avvikling",
+	"invalid pattern: %v":                                                              "ugyldig mønster: %v",
+	"HSTS preload list: %v":                                                            "HSTS-preloadliste: %v",
+	"%d/%d rules passed":                                                               "%d/%d regler bestått",
+	"none since %s":                                                                    "ingen siden %s",
+	"HTTP listening on %s":                                                             "HTTP lytter på %s",
+	"gRPC listening on %s":                                                             "gRPC lytter på %s",
+	"No API keys configured (serve.keys): anyone who can connect may crawl":            "Ingen API-nøkler konfigurert (serve.keys): alle som kan koble til, kan crawle",
+}
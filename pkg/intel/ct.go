@@ -0,0 +1,140 @@
+package intel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CTCertificate is a certificate found in certificate transparency logs
+type CTCertificate struct {
+	// Fingerprint identifies the certificate across its precertificate and
+	// final entries
+	Fingerprint string    `json:"fingerprint"`
+	Issuer      string    `json:"issuer"`
+	Names       []string  `json:"names"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+}
+
+// CTSource lists the certificates logged for a domain
+type CTSource interface {
+	Name() string
+	// Certificates returns the unexpired certificates of a domain and its
+	// subdomains logged after the cursor, and the cursor to pass next time.
+	// An empty cursor lists them from the start; sources without cursors
+	// always list every certificate and return "".
+	Certificates(domain, after string) (certs []CTCertificate, next string, err error)
+}
+
+// NewCTSource returns the named certificate transparency source:
+// "certspotter" (default; keyless with a low rate, or with an API key) or
+// "crtsh"
+func (c *Client) NewCTSource(name string) (CTSource, error) {
+	switch strings.ToLower(name) {
+	case "", "certspotter":
+		return &certSpotter{c: c}, nil
+	case "crtsh", "crt.sh":
+		return &crtSh{c: c}, nil
+	}
+	return nil, fmt.Errorf("unknown certificate transparency source %q", name)
+}
+
+// certSpotterMaxPages bounds the pages of issuances fetched per domain and
+// call; the next call resumes where it stopped
+const certSpotterMaxPages = 20
+
+type certSpotter struct {
+	c *Client
+}
+
+func (s *certSpotter) Name() string { return "Cert Spotter" }
+
+// Certificates pages through the issuances in the order Cert Spotter saw
+// them; the cursor is the id of the last issuance fetched
+func (s *certSpotter) Certificates(domain, after string) ([]CTCertificate, string, error) {
+	var headers map[string]string
+	if key := s.c.key("certspotter"); key != "" {
+		headers = map[string]string{"Authorization": "Bearer " + key}
+	}
+	var certs []CTCertificate
+	for range certSpotterMaxPages {
+		u := "https://api.certspotter.com/v1/issuances?include_subdomains=true&expand=dns_names&expand=issuer&domain=" + url.QueryEscape(domain)
+		if after != "" {
+			u += "&after=" + url.QueryEscape(after)
+		}
+		var page []struct {
+			ID        string   `json:"id"`
+			TBSSHA256 string   `json:"tbs_sha256"`
+			DNSNames  []string `json:"dns_names"`
+			NotBefore string   `json:"not_before"`
+			NotAfter  string   `json:"not_after"`
+			Issuer    struct {
+				FriendlyName string `json:"friendly_name"`
+				Name         string `json:"name"`
+			} `json:"issuer"`
+		}
+		if err := s.c.getJSON(u, headers, &page); err != nil {
+			return nil, "", err
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, iss := range page {
+			cert := CTCertificate{Fingerprint: iss.TBSSHA256, Names: iss.DNSNames, Issuer: iss.Issuer.FriendlyName}
+			if cert.Issuer == "" {
+				cert.Issuer = iss.Issuer.Name
+			}
+			cert.NotBefore, _ = time.Parse(time.RFC3339, iss.NotBefore)
+			cert.NotAfter, _ = time.Parse(time.RFC3339, iss.NotAfter)
+			if cert.NotAfter.Before(time.Now()) {
+				continue
+			}
+			certs = append(certs, cert)
+		}
+		after = page[len(page)-1].ID
+	}
+	return certs, after, nil
+}
+
+type crtSh struct {
+	c *Client
+}
+
+func (s *crtSh) Name() string { return "crt.sh" }
+
+func (s *crtSh) Certificates(domain, _ string) ([]CTCertificate, string, error) {
+	var certs []CTCertificate
+	// The domain itself, then its subdomains
+	for _, q := range []string{domain, "%." + domain} {
+		var entries []struct {
+			IssuerName   string `json:"issuer_name"`
+			NameValue    string `json:"name_value"`
+			SerialNumber string `json:"serial_number"`
+			NotBefore    string `json:"not_before"`
+			NotAfter     string `json:"not_after"`
+		}
+		u := "https://crt.sh/?output=json&exclude=expired&q=" + url.QueryEscape(q)
+		if err := s.c.getJSON(u, nil, &entries); err != nil {
+			return nil, "", err
+		}
+		for _, e := range entries {
+			// crt.sh has no certificate hash in its JSON; the issuer and
+			// serial are shared by the precertificate and the certificate
+			sum := sha256.Sum256([]byte(e.IssuerName + "/" + e.SerialNumber))
+			fingerprint := hex.EncodeToString(sum[:])
+			if slices.ContainsFunc(certs, func(c CTCertificate) bool { return c.Fingerprint == fingerprint }) {
+				continue
+			}
+			cert := CTCertificate{Fingerprint: fingerprint, Issuer: e.IssuerName, Names: strings.Fields(e.NameValue)}
+			cert.NotBefore, _ = time.Parse("2006-01-02T15:04:05", e.NotBefore)
+			cert.NotAfter, _ = time.Parse("2006-01-02T15:04:05", e.NotAfter)
+			certs = append(certs, cert)
+		}
+	}
+	return certs, "", nil
+}
@@ -0,0 +1,92 @@
+// Package intel queries external threat-intelligence and enrichment services.
+//
+// Every source is optional: sources that need an API key are only available
+// when the key is configured.
+package intel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client holds the shared HTTP client and API keys for all sources
+type Client struct {
+	HTTP *http.Client
+	Keys map[string]string
+}
+
+// NewClient creates a client using the given API keys (keyed by service name)
+func NewClient(keys map[string]string) *Client {
+	return &Client{
+		HTTP: &http.Client{Timeout: 15 * time.Second},
+		Keys: keys,
+	}
+}
+
+// key returns the configured API key for a service
+func (c *Client) key(service string) string {
+	if c.Keys == nil {
+		return ""
+	}
+	return c.Keys[service]
+}
+
+// get performs a GET request and returns the body, treating non-2xx statuses as errors
+func (c *Client) get(url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req, headers)
+}
+
+func (c *Client) do(req *http.Request, headers map[string]string) ([]byte, error) {
+	req.Header.Set("User-Agent", "dnscrawler")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// The error quotes the URL, whose query may carry an API key
+		if urlErr, ok := err.(*url.Error); ok {
+			stripped := *req.URL
+			stripped.RawQuery = ""
+			urlErr.URL = stripped.String()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{Host: req.URL.Host, Code: resp.StatusCode}
+	}
+	return body, nil
+}
+
+// StatusError is returned for answers with a non-2xx status
+type StatusError struct {
+	Host string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: HTTP %d", e.Host, e.Code)
+}
+
+// getJSON performs a GET request and decodes the JSON response into out
+func (c *Client) getJSON(url string, headers map[string]string, out any) error {
+	body, err := c.get(url, headers)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,227 @@
+package intel
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Organization is a legal entity in a national business registry
+type Organization struct {
+	Number  string `json:"number"`
+	Name    string `json:"name"`
+	Form    string `json:"form,omitempty"` // legal form, e.g. ASA, ApS, Oyj
+	Address string `json:"address,omitempty"`
+	// Dissolved is set when the registry lists the entity as deleted,
+	// bankrupt or being wound up
+	Dissolved bool `json:"dissolved,omitempty"`
+}
+
+// OrganizationRegistry looks up organization numbers in a country's
+// business registry
+type OrganizationRegistry interface {
+	Name() string
+	Organization(number string) (*Organization, error)
+}
+
+// ErrOrganizationNotFound is returned when the registry has no entity with
+// the number
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// OrganizationRegistry returns the business registry of the country of a
+// ccTLD, for the TLDs whose registries publish the registrant's
+// organization number: the Brønnøysund Register Centre for .no, CVR for .dk
+// (through cvrapi.dk) and PRH for .fi. None of them needs an API key.
+func (c *Client) OrganizationRegistry(tld string) (OrganizationRegistry, bool) {
+	switch strings.ToLower(strings.TrimPrefix(tld, ".")) {
+	case "no":
+		return &brreg{c: c}, true
+	case "dk":
+		return &cvr{c: c}, true
+	case "fi":
+		return &prh{c: c}, true
+	}
+	return nil, false
+}
+
+// statusCode returns the HTTP status of a StatusError, zero for other errors
+func statusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	return 0
+}
+
+// notFound turns the registries' 404 into ErrOrganizationNotFound
+func notFound(err error, number string) error {
+	if statusCode(err) == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrOrganizationNotFound, number)
+	}
+	return err
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+type brreg struct {
+	c *Client
+}
+
+func (b *brreg) Name() string { return "Brønnøysund Register Centre" }
+
+// Organization looks up a number in Enhetsregisteret. Deleted entities are
+// answered with 410 Gone.
+func (b *brreg) Organization(number string) (*Organization, error) {
+	number = strings.ReplaceAll(number, " ", "")
+	var resp struct {
+		Number string `json:"organisasjonsnummer"`
+		Name   string `json:"navn"`
+		Form   struct {
+			Code string `json:"kode"`
+		} `json:"organisasjonsform"`
+		Address struct {
+			Lines    []string `json:"adresse"`
+			PostCode string   `json:"postnummer"`
+			City     string   `json:"poststed"`
+		} `json:"forretningsadresse"`
+		Bankrupt    bool   `json:"konkurs"`
+		WindingUp   bool   `json:"underAvvikling"`
+		ForcedUp    bool   `json:"underTvangsavviklingEllerTvangsopplosning"`
+		DeletedDate string `json:"slettedato"`
+	}
+	err := b.c.getJSON("https://data.brreg.no/enhetsregisteret/api/enheter/"+url.PathEscape(number), nil, &resp)
+	if statusCode(err) == http.StatusGone {
+		return &Organization{Number: number, Dissolved: true}, nil
+	}
+	if err != nil {
+		return nil, notFound(err, number)
+	}
+	return &Organization{
+		Number:    resp.Number,
+		Name:      resp.Name,
+		Form:      resp.Form.Code,
+		Address:   joinNonEmpty(", ", strings.Join(resp.Address.Lines, ", "), joinNonEmpty(" ", resp.Address.PostCode, resp.Address.City)),
+		Dissolved: resp.Bankrupt || resp.WindingUp || resp.ForcedUp || resp.DeletedDate != "",
+	}, nil
+}
+
+type cvr struct {
+	c *Client
+}
+
+func (v *cvr) Name() string { return "CVR" }
+
+// Organization looks up a CVR number through cvrapi.dk, which answers
+// unknown numbers with an error field
+func (v *cvr) Organization(number string) (*Organization, error) {
+	number = strings.TrimPrefix(strings.ReplaceAll(number, " ", ""), "DK")
+	var resp struct {
+		VAT      int    `json:"vat"`
+		Name     string `json:"name"`
+		Address  string `json:"address"`
+		Zipcode  string `json:"zipcode"`
+		City     string `json:"city"`
+		Form     string `json:"companydesc"`
+		EndDate  string `json:"enddate"`
+		Error    string `json:"error"`
+		Bankrupt bool   `json:"creditbankrupt"`
+	}
+	u := "https://cvrapi.dk/api?country=dk&vat=" + url.QueryEscape(number)
+	if err := v.c.getJSON(u, nil, &resp); err != nil {
+		return nil, notFound(err, number)
+	}
+	switch {
+	case resp.Error == "NOT_FOUND" || resp.Error == "" && resp.VAT == 0:
+		return nil, fmt.Errorf("%w: %s", ErrOrganizationNotFound, number)
+	case resp.Error != "":
+		return nil, fmt.Errorf("cvrapi.dk: %s", resp.Error)
+	}
+	return &Organization{
+		Number:    fmt.Sprint(resp.VAT),
+		Name:      resp.Name,
+		Form:      resp.Form,
+		Address:   joinNonEmpty(", ", resp.Address, joinNonEmpty(" ", resp.Zipcode, resp.City)),
+		Dissolved: resp.EndDate != "" || resp.Bankrupt,
+	}, nil
+}
+
+type prh struct {
+	c *Client
+}
+
+func (p *prh) Name() string { return "PRH" }
+
+// Organization looks up a business ID in the Finnish Trade Register's open
+// data API. An entity has a name per period; the current one has no end date.
+func (p *prh) Organization(number string) (*Organization, error) {
+	number = strings.TrimSpace(number)
+	var resp struct {
+		Companies []struct {
+			BusinessID struct {
+				Value string `json:"value"`
+			} `json:"businessId"`
+			Names []struct {
+				Name    string `json:"name"`
+				Type    string `json:"type"`
+				EndDate string `json:"endDate"`
+			} `json:"names"`
+			Forms []struct {
+				Descriptions []struct {
+					Language    string `json:"languageCode"`
+					Description string `json:"description"`
+				} `json:"descriptions"`
+			} `json:"companyForms"`
+			Addresses []struct {
+				Street      string `json:"street"`
+				Building    string `json:"buildingNumber"`
+				PostCode    string `json:"postCode"`
+				PostOffices []struct {
+					City string `json:"city"`
+				} `json:"postOffices"`
+			} `json:"addresses"`
+			EndDate string `json:"endDate"`
+		} `json:"companies"`
+	}
+	u := "https://avoindata.prh.fi/opendata-ytj-api/v3/companies?businessId=" + url.QueryEscape(number)
+	if err := p.c.getJSON(u, nil, &resp); err != nil {
+		return nil, notFound(err, number)
+	}
+	if len(resp.Companies) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrOrganizationNotFound, number)
+	}
+	company := resp.Companies[0]
+	org := &Organization{Number: company.BusinessID.Value, Dissolved: company.EndDate != ""}
+	for _, n := range company.Names {
+		if n.EndDate == "" && (org.Name == "" || n.Type == "1") {
+			org.Name = n.Name
+		}
+	}
+	if len(company.Forms) > 0 {
+		// Language code 3 is English, 1 Finnish
+		for _, d := range company.Forms[0].Descriptions {
+			if org.Form == "" || d.Language == "3" {
+				org.Form = d.Description
+			}
+		}
+	}
+	if len(company.Addresses) > 0 {
+		a := company.Addresses[0]
+		var city string
+		if len(a.PostOffices) > 0 {
+			city = a.PostOffices[0].City
+		}
+		org.Address = joinNonEmpty(", ", joinNonEmpty(" ", a.Street, a.Building), joinNonEmpty(" ", a.PostCode, city))
+	}
+	return org, nil
+}
@@ -0,0 +1,156 @@
+package intel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PassiveRecord is one historical observation of a DNS answer
+type PassiveRecord struct {
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Source    string    `json:"source"`
+}
+
+// PassiveDNS returns what a domain has historically resolved to
+type PassiveDNS interface {
+	Name() string
+	// History returns observations for the given record type (A, AAAA, NS, MX)
+	History(domain, rrtype string) ([]PassiveRecord, error)
+}
+
+// PassiveDNSSourceNames lists the supported passive DNS providers
+var PassiveDNSSourceNames = []string{"securitytrails", "circl"}
+
+// NewPassiveDNS returns the named passive DNS source, which must have an API key configured.
+// For CIRCL the key is "user:password".
+func (c *Client) NewPassiveDNS(name string) (PassiveDNS, error) {
+	name = strings.ToLower(name)
+	if c.key(name) == "" {
+		return nil, fmt.Errorf("%s requires an API key", name)
+	}
+	switch name {
+	case "securitytrails":
+		return &securityTrails{c: c}, nil
+	case "circl":
+		return &circlPDNS{c: c}, nil
+	}
+	return nil, fmt.Errorf("unknown passive DNS source %q", name)
+}
+
+// PassiveDNSSources returns every passive DNS source that has an API key configured
+func (c *Client) PassiveDNSSources() []PassiveDNS {
+	var sources []PassiveDNS
+	for _, name := range PassiveDNSSourceNames {
+		if src, err := c.NewPassiveDNS(name); err == nil {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
+
+// SortPassiveRecords orders records by type, then by when they were first seen
+func SortPassiveRecords(records []PassiveRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].FirstSeen.Before(records[j].FirstSeen)
+	})
+}
+
+func (s *securityTrails) History(domain, rrtype string) ([]PassiveRecord, error) {
+	u := fmt.Sprintf("https://api.securitytrails.com/v1/history/%s/dns/%s",
+		url.PathEscape(domain), strings.ToLower(rrtype))
+
+	var resp struct {
+		Records []struct {
+			Values    []map[string]any `json:"values"`
+			FirstSeen string           `json:"first_seen"`
+			LastSeen  string           `json:"last_seen"`
+		} `json:"records"`
+	}
+	if err := s.c.getJSON(u, map[string]string{"APIKEY": s.c.key("securitytrails")}, &resp); err != nil {
+		return nil, err
+	}
+
+	var records []PassiveRecord
+	for _, r := range resp.Records {
+		first, _ := time.Parse("2006-01-02", r.FirstSeen)
+		last, _ := time.Parse("2006-01-02", r.LastSeen)
+		for _, v := range r.Values {
+			records = append(records, PassiveRecord{
+				Type:      strings.ToUpper(rrtype),
+				Value:     securityTrailsValue(v),
+				FirstSeen: first,
+				LastSeen:  last,
+				Source:    s.Name(),
+			})
+		}
+	}
+	return records, nil
+}
+
+// securityTrailsValue extracts the record value; the key differs per record type
+func securityTrailsValue(v map[string]any) string {
+	for _, key := range []string{"ip", "ipv6", "nameserver", "host", "value"} {
+		if s, ok := v[key].(string); ok {
+			if p, ok := v["priority"].(float64); ok && key == "host" {
+				return fmt.Sprintf("%d %s", int(p), s)
+			}
+			return s
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+type circlPDNS struct {
+	c *Client
+}
+
+func (p *circlPDNS) Name() string { return "CIRCL" }
+
+func (p *circlPDNS) History(domain, rrtype string) ([]PassiveRecord, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(p.c.key("circl")))
+	body, err := p.c.get("https://www.circl.lu/pdns/query/"+url.PathEscape(domain), map[string]string{
+		"Authorization": "Basic " + auth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Response is newline-delimited JSON covering all record types
+	var records []PassiveRecord
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var line struct {
+			RRType    string `json:"rrtype"`
+			RData     string `json:"rdata"`
+			TimeFirst int64  `json:"time_first"`
+			TimeLast  int64  `json:"time_last"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &line) != nil {
+			continue
+		}
+		if !strings.EqualFold(line.RRType, rrtype) {
+			continue
+		}
+		records = append(records, PassiveRecord{
+			Type:      strings.ToUpper(line.RRType),
+			Value:     strings.TrimSuffix(line.RData, "."),
+			FirstSeen: time.Unix(line.TimeFirst, 0).UTC(),
+			LastSeen:  time.Unix(line.TimeLast, 0).UTC(),
+			Source:    p.Name(),
+		})
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,134 @@
+package intel
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Listing is a reputation source's verdict on a domain
+type Listing struct {
+	Source  string   `json:"source"`
+	Listed  bool     `json:"listed"`
+	Threats []string `json:"threats,omitempty"`
+	Detail  string   `json:"detail,omitempty"`
+}
+
+// ReputationSource checks whether a domain is on a known-malicious list
+type ReputationSource interface {
+	Name() string
+	Check(domain string) (*Listing, error)
+}
+
+// ReputationSources returns the available reputation sources. Google Safe
+// Browsing needs an API key; PhishTank works without one at a lower rate limit.
+func (c *Client) ReputationSources() []ReputationSource {
+	var sources []ReputationSource
+	if c.key("safebrowsing") != "" {
+		sources = append(sources, &safeBrowsing{c: c})
+	}
+	sources = append(sources, &phishTank{c: c})
+	return sources
+}
+
+type safeBrowsing struct {
+	c *Client
+}
+
+func (s *safeBrowsing) Name() string { return "Safe Browsing" }
+
+// Check looks up the domain's http and https root URLs in the Safe Browsing lists
+func (s *safeBrowsing) Check(domain string) (*Listing, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"client": map[string]string{"clientId": "dnscrawler", "clientVersion": "1.0"},
+		"threatInfo": map[string]any{
+			"threatTypes":      []string{"MALWARE", "SOCIAL_ENGINEERING", "UNWANTED_SOFTWARE", "POTENTIALLY_HARMFUL_APPLICATION"},
+			"platformTypes":    []string{"ANY_PLATFORM"},
+			"threatEntryTypes": []string{"URL"},
+			"threatEntries": []map[string]string{
+				{"url": "http://" + domain + "/"},
+				{"url": "https://" + domain + "/"},
+			},
+		},
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "https://safebrowsing.googleapis.com/v4/threatMatches:find", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.c.do(req, map[string]string{
+		"X-Goog-Api-Key": s.c.key("safebrowsing"),
+		"Content-Type":   "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Matches []struct {
+			ThreatType string `json:"threatType"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	listing := &Listing{Source: s.Name(), Listed: len(resp.Matches) > 0}
+	seen := make(map[string]bool)
+	for _, m := range resp.Matches {
+		threat := strings.ToLower(strings.ReplaceAll(m.ThreatType, "_", " "))
+		if !seen[threat] {
+			seen[threat] = true
+			listing.Threats = append(listing.Threats, threat)
+		}
+	}
+	sort.Strings(listing.Threats)
+	return listing, nil
+}
+
+type phishTank struct {
+	c *Client
+}
+
+func (p *phishTank) Name() string { return "PhishTank" }
+
+// Check asks PhishTank whether the domain's root URL is a verified phish
+func (p *phishTank) Check(domain string) (*Listing, error) {
+	form := url.Values{"url": {"http://" + domain + "/"}, "format": {"json"}}
+	if key := p.c.key("phishtank"); key != "" {
+		form.Set("app_key", key)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://checkurl.phishtank.com/checkurl/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.c.do(req, map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Results struct {
+			InDatabase      bool   `json:"in_database"`
+			Verified        bool   `json:"verified"`
+			Valid           bool   `json:"valid"`
+			PhishDetailPage string `json:"phish_detail_page"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	r := resp.Results
+	// Only verified, still-valid entries count; unverified submissions are noise
+	listing := &Listing{Source: p.Name(), Listed: r.InDatabase && r.Verified && r.Valid}
+	if listing.Listed {
+		listing.Threats = []string{"phishing"}
+		listing.Detail = r.PhishDetailPage
+	}
+	return listing, nil
+}
@@ -0,0 +1,107 @@
+package intel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ReverseIPSource lists domains hosted on an IP address
+type ReverseIPSource interface {
+	Name() string
+	HostedDomains(ip string) ([]string, error)
+}
+
+// NewReverseIPSource returns the named reverse-IP source. Supported sources
+// are "hackertarget" (keyless, rate limited) and "securitytrails" (API key).
+func (c *Client) NewReverseIPSource(name string) (ReverseIPSource, error) {
+	switch strings.ToLower(name) {
+	case "", "hackertarget":
+		return &hackerTarget{c: c}, nil
+	case "securitytrails":
+		if c.key("securitytrails") == "" {
+			return nil, fmt.Errorf("securitytrails requires an API key")
+		}
+		return &securityTrails{c: c}, nil
+	}
+	return nil, fmt.Errorf("unknown reverse IP source %q", name)
+}
+
+type hackerTarget struct {
+	c *Client
+}
+
+func (h *hackerTarget) Name() string { return "HackerTarget" }
+
+func (h *hackerTarget) HostedDomains(ip string) ([]string, error) {
+	u := "https://api.hackertarget.com/reverseiplookup/?q=" + url.QueryEscape(ip)
+	if key := h.c.key("hackertarget"); key != "" {
+		u += "&apikey=" + url.QueryEscape(key)
+	}
+
+	body, err := h.c.get(u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(string(body))
+	// Errors are returned as plain text with a 200 status
+	if strings.HasPrefix(text, "error") || strings.HasPrefix(text, "API count exceeded") {
+		return nil, fmt.Errorf("hackertarget: %s", text)
+	}
+	if strings.HasPrefix(text, "No DNS A records found") {
+		return nil, nil
+	}
+
+	var domains []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			domains = append(domains, line)
+		}
+	}
+	return domains, nil
+}
+
+type securityTrails struct {
+	c *Client
+}
+
+func (s *securityTrails) Name() string { return "SecurityTrails" }
+
+func (s *securityTrails) HostedDomains(ip string) ([]string, error) {
+	filter := map[string]string{"ipv4": ip}
+	if strings.Contains(ip, ":") {
+		filter = map[string]string{"ipv6": ip}
+	}
+	payload, _ := json.Marshal(map[string]any{"filter": filter})
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.securitytrails.com/v1/domains/list", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.c.do(req, map[string]string{
+		"APIKEY":       s.c.key("securitytrails"),
+		"Content-Type": "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Records []struct {
+			Hostname string `json:"hostname"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(resp.Records))
+	for _, r := range resp.Records {
+		domains = append(domains, r.Hostname)
+	}
+	return domains, nil
+}
@@ -0,0 +1,179 @@
+package intel
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ThreatReport is what a threat-intelligence source knows about a domain
+type ThreatReport struct {
+	Source       string              `json:"source"`
+	Verdict      *Verdict            `json:"verdict,omitempty"`
+	WhoisHistory []WhoisHistoryEntry `json:"whois_history,omitempty"`
+	Related      []string            `json:"related,omitempty"`
+	// RelatedError is why the related domains couldn't be fetched, when the
+	// rest of the report could
+	RelatedError string `json:"related_error,omitempty"`
+}
+
+// Verdict summarizes scanner detections for a domain
+type Verdict struct {
+	Malicious  int      `json:"malicious"`
+	Suspicious int      `json:"suspicious"`
+	Harmless   int      `json:"harmless"`
+	Undetected int      `json:"undetected"`
+	Reputation int      `json:"reputation"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Flagged reports whether any scanner considers the domain malicious or suspicious
+func (v *Verdict) Flagged() bool {
+	return v != nil && (v.Malicious > 0 || v.Suspicious > 0)
+}
+
+// WhoisHistoryEntry is a historical WHOIS registration state
+type WhoisHistoryEntry struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Registrar  string    `json:"registrar,omitempty"`
+	Registrant string    `json:"registrant,omitempty"`
+	Created    time.Time `json:"created,omitempty"`
+	Expires    time.Time `json:"expires,omitempty"`
+}
+
+// ThreatSource produces threat-intelligence reports for domains
+type ThreatSource interface {
+	Name() string
+	Report(domain string) (*ThreatReport, error)
+}
+
+// ThreatSources returns every threat-intelligence source with an API key configured
+func (c *Client) ThreatSources() []ThreatSource {
+	var sources []ThreatSource
+	if c.key("securitytrails") != "" {
+		sources = append(sources, &securityTrails{c: c})
+	}
+	if c.key("virustotal") != "" {
+		sources = append(sources, &virusTotal{c: c})
+	}
+	return sources
+}
+
+// Report fetches historical WHOIS and associated domains from SecurityTrails
+func (s *securityTrails) Report(domain string) (*ThreatReport, error) {
+	headers := map[string]string{"APIKEY": s.c.key("securitytrails")}
+	report := &ThreatReport{Source: s.Name()}
+
+	var whois struct {
+		Result struct {
+			Items []struct {
+				Started       int64  `json:"started"`
+				Ended         int64  `json:"ended"`
+				RegistrarName string `json:"registrarName"`
+				CreatedDate   int64  `json:"createdDate"`
+				ExpiresDate   int64  `json:"expiresDate"`
+				Contact       []struct {
+					Type         string `json:"type"`
+					Name         string `json:"name"`
+					Organization string `json:"organization"`
+				} `json:"contact"`
+			} `json:"items"`
+		} `json:"result"`
+	}
+	u := fmt.Sprintf("https://api.securitytrails.com/v1/history/%s/whois", url.PathEscape(domain))
+	if err := s.c.getJSON(u, headers, &whois); err != nil {
+		return nil, err
+	}
+	for _, item := range whois.Result.Items {
+		entry := WhoisHistoryEntry{
+			From:      unixMilli(item.Started),
+			To:        unixMilli(item.Ended),
+			Registrar: item.RegistrarName,
+			Created:   unixMilli(item.CreatedDate),
+			Expires:   unixMilli(item.ExpiresDate),
+		}
+		for _, contact := range item.Contact {
+			if contact.Type == "registrant" {
+				entry.Registrant = contact.Organization
+				if entry.Registrant == "" {
+					entry.Registrant = contact.Name
+				}
+			}
+		}
+		report.WhoisHistory = append(report.WhoisHistory, entry)
+	}
+	sort.Slice(report.WhoisHistory, func(i, j int) bool {
+		return report.WhoisHistory[i].From.After(report.WhoisHistory[j].From)
+	})
+
+	var associated struct {
+		Records []struct {
+			Hostname string `json:"hostname"`
+		} `json:"records"`
+	}
+	u = fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s/associated", url.PathEscape(domain))
+	if err := s.c.getJSON(u, headers, &associated); err != nil {
+		report.RelatedError = err.Error()
+	}
+	for _, r := range associated.Records {
+		report.Related = append(report.Related, r.Hostname)
+	}
+
+	return report, nil
+}
+
+type virusTotal struct {
+	c *Client
+}
+
+func (v *virusTotal) Name() string { return "VirusTotal" }
+
+// Report fetches the latest scanner verdicts and categories from VirusTotal
+func (v *virusTotal) Report(domain string) (*ThreatReport, error) {
+	var resp struct {
+		Data struct {
+			Attributes struct {
+				LastAnalysisStats struct {
+					Harmless   int `json:"harmless"`
+					Malicious  int `json:"malicious"`
+					Suspicious int `json:"suspicious"`
+					Undetected int `json:"undetected"`
+				} `json:"last_analysis_stats"`
+				Reputation int               `json:"reputation"`
+				Categories map[string]string `json:"categories"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	u := "https://www.virustotal.com/api/v3/domains/" + url.PathEscape(domain)
+	if err := v.c.getJSON(u, map[string]string{"x-apikey": v.c.key("virustotal")}, &resp); err != nil {
+		return nil, err
+	}
+
+	attrs := resp.Data.Attributes
+	verdict := &Verdict{
+		Malicious:  attrs.LastAnalysisStats.Malicious,
+		Suspicious: attrs.LastAnalysisStats.Suspicious,
+		Harmless:   attrs.LastAnalysisStats.Harmless,
+		Undetected: attrs.LastAnalysisStats.Undetected,
+		Reputation: attrs.Reputation,
+	}
+	seen := make(map[string]bool)
+	for _, category := range attrs.Categories {
+		if !seen[category] {
+			seen[category] = true
+			verdict.Categories = append(verdict.Categories, category)
+		}
+	}
+	sort.Strings(verdict.Categories)
+
+	return &ThreatReport{Source: v.Name(), Verdict: verdict}, nil
+}
+
+func unixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/intel"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+// ctSeenLimit is the number of certificate fingerprints kept per domain
+const ctSeenLimit = 5000
+
+func ctSeenKey(name string) string {
+	return state.Key("ct-seen", domain.Canonical(name))
+}
+
+// ctCursorKey holds the source's cursor, so a run only pages through the
+// certificates logged since the previous one
+func ctCursorKey(name string) string {
+	return state.Key("ct-cursor", domain.Canonical(name))
+}
+
+// CheckCT looks up the certificates logged for a domain and its subdomains
+// and alerts on those not seen before, which may be phishing or shadow IT.
+// The first lookup of a domain only records what is already logged. With
+// CTIssuers set, certificates from the expected CAs are recorded silently.
+func (m *Monitor) CheckCT(name string, settings config.Alerts) ([]notify.Alert, error) {
+	name = domain.Canonical(name)
+	var seen []string
+	found, err := state.GetJSON(m.Store, ctSeenKey(name), &seen)
+	if err != nil {
+		return nil, err
+	}
+	baseline := !found
+	var cursor string
+	if _, err := state.GetJSON(m.Store, ctCursorKey(name), &cursor); err != nil {
+		return nil, err
+	}
+	certs, next, err := m.CT.Certificates(name, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", m.CT.Name(), err)
+	}
+
+	var fresh []intel.CTCertificate
+	for _, cert := range certs {
+		if cert.Fingerprint == "" || slices.Contains(seen, cert.Fingerprint) {
+			continue
+		}
+		seen = append(seen, cert.Fingerprint)
+		if !baseline && !m.expectedIssuer(cert.Issuer) {
+			fresh = append(fresh, cert)
+		}
+	}
+	if len(seen) > ctSeenLimit {
+		seen = seen[len(seen)-ctSeenLimit:]
+	}
+	if err := state.PutJSON(m.Store, ctSeenKey(name), seen); err != nil {
+		return nil, err
+	}
+	if next != "" && next != cursor {
+		if err := state.PutJSON(m.Store, ctCursorKey(name), next); err != nil {
+			return nil, err
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	title := fmt.Sprintf("%d new certificates in CT logs", len(fresh))
+	level := severity(settings, "ct", notify.Info)
+	if len(m.CTIssuers) > 0 {
+		title = fmt.Sprintf("%d certificates from unexpected CAs in CT logs", len(fresh))
+		level = severity(settings, "ct", notify.Warning)
+	}
+	if level == "" {
+		return nil, nil
+	}
+	var details []string
+	for _, cert := range fresh {
+		details = append(details, fmt.Sprintf("%s, issued by %s on %s", strings.Join(cert.Names, " "), cert.Issuer, cert.NotBefore.Format(time.DateOnly)))
+	}
+	return []notify.Alert{{
+		Domain:   name,
+		Kind:     "ct",
+		Severity: level,
+		Title:    title,
+		Details:  details,
+		Time:     time.Now().UTC(),
+	}}, nil
+}
+
+// expectedIssuer reports whether issuer matches one of CTIssuers. Without
+// CTIssuers no issuer is expected.
+func (m *Monitor) expectedIssuer(issuer string) bool {
+	return slices.ContainsFunc(m.CTIssuers, func(expected string) bool {
+		return strings.Contains(strings.ToLower(issuer), strings.ToLower(expected))
+	})
+}
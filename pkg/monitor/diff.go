@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+)
+
+// Diff describes what changed between two crawls of the same domain. Sections
+// that are missing or failed in either crawl are skipped, so a transient
+// lookup failure isn't reported as a change.
+func Diff(old, cur *crawler.Result) []string {
+	var changes []string
+	if old.Registered != cur.Registered {
+		if cur.Registered {
+			return []string{"domain is registered again"}
+		}
+		return []string{"domain no longer resolves"}
+	}
+
+	if ok(old.Whois) && ok(cur.Whois) {
+		changes = appendField(changes, "registrar", old.Whois.Registrar, cur.Whois.Registrar)
+		changes = appendField(changes, "registrant", old.Whois.Registrant, cur.Whois.Registrant)
+		changes = appendField(changes, "expiry", old.Whois.Expires, cur.Whois.Expires)
+		changes = appendSet(changes, "status", old.Whois.Info.Status, cur.Whois.Info.Status)
+	}
+
+	if ok(old.Nameservers) && ok(cur.Nameservers) {
+		changes = appendSet(changes, "NS", nsNames(old.Nameservers), nsNames(cur.Nameservers))
+	}
+
+	if ok(old.Records) && ok(cur.Records) {
+		changes = appendSet(changes, "A", values(old.Records.A), values(cur.Records.A))
+		changes = appendSet(changes, "AAAA", values(old.Records.AAAA), values(cur.Records.AAAA))
+		changes = appendSet(changes, "CNAME", values(old.Records.CNAME), values(cur.Records.CNAME))
+		changes = appendSet(changes, "MX", values(old.Records.MX), values(cur.Records.MX))
+		changes = appendSet(changes, "TXT", values(old.Records.TXT), values(cur.Records.TXT))
+	}
+
+	if old.Email != nil && cur.Email != nil {
+		changes = appendField(changes, "SPF", old.Email.SPF, cur.Email.SPF)
+		changes = appendField(changes, "DMARC", old.Email.DMARC, cur.Email.DMARC)
+	}
+
+	if ok(old.TLS) && ok(cur.TLS) {
+		changes = appendField(changes, "certificate issuer", old.TLS.Issuer, cur.TLS.Issuer)
+	}
+	return changes
+}
+
+// ok reports whether a section was collected successfully
+func ok(section interface{ Failed() bool }) bool {
+	switch s := section.(type) {
+	case *crawler.WhoisSection:
+		return s != nil && !s.Failed() && s.Info != nil && s.QueryError == ""
+	case *crawler.NameserverSection:
+		return s != nil && !s.Failed()
+	case *crawler.RecordsSection:
+		return s != nil && !s.Failed()
+	case *crawler.TLSSection:
+		return s != nil && !s.Failed() && s.Info != nil
+	}
+	return false
+}
+
+func appendField(changes []string, name, old, cur string) []string {
+	if old == cur {
+		return changes
+	}
+	return append(changes, fmt.Sprintf("%s changed from %s to %s", name, orNone(old), orNone(cur)))
+}
+
+func appendSet(changes []string, name string, old, cur []string) []string {
+	before := make(map[string]bool)
+	for _, v := range old {
+		before[v] = true
+	}
+	after := make(map[string]bool)
+	for _, v := range cur {
+		after[v] = true
+	}
+
+	var added, removed []string
+	for _, v := range cur {
+		if !before[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !after[v] {
+			removed = append(removed, v)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("%s added: %s", name, strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("%s removed: %s", name, strings.Join(removed, ", ")))
+	}
+	return changes
+}
+
+func nsNames(s *crawler.NameserverSection) []string {
+	names := make([]string, len(s.Servers))
+	for i, ns := range s.Servers {
+		names[i] = ns.Name
+	}
+	return names
+}
+
+func values(records []crawler.Record) []string {
+	out := make([]string, len(records))
+	for i, r := range records {
+		out[i] = r.Value
+	}
+	return out
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
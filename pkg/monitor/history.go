@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+// historyLimit is the number of alerts kept per domain
+const historyLimit = 200
+
+func historyKey(name string) string {
+	return state.Key("history", domain.Canonical(name))
+}
+
+// LoadHistory returns the stored alerts of a domain, oldest first
+func LoadHistory(s state.Store, domain string) ([]notify.Alert, error) {
+	var alerts []notify.Alert
+	_, err := state.GetJSON(s, historyKey(domain), &alerts)
+	return alerts, err
+}
+
+// AppendHistory adds alerts to a domain's history, dropping the oldest
+// entries beyond historyLimit
+func AppendHistory(s state.Store, domain string, alerts []notify.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	history, err := LoadHistory(s, domain)
+	if err != nil {
+		return err
+	}
+	history = append(history, alerts...)
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+	return state.PutJSON(s, historyKey(domain), history)
+}
@@ -0,0 +1,428 @@
+// Package monitor crawls groups of domains, compares them against the
+// previous snapshot and raises alerts for changes, upcoming expiry and failing
+// health checks.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/export"
+	"github.com/auduny/dnscrawler/pkg/intel"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/policy"
+	"github.com/auduny/dnscrawler/pkg/redact"
+	"github.com/auduny/dnscrawler/pkg/state"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records a span per group check, parenting the crawl spans
+var tracer = otel.Tracer("github.com/auduny/dnscrawler/pkg/monitor")
+
+// DefaultAlerts are the thresholds used unless the config overrides them
+var DefaultAlerts = config.Alerts{
+	DomainExpiry: config.Threshold{Warning: 30, Critical: 14},
+	CertExpiry:   config.Threshold{Warning: 21, Critical: 7},
+}
+
+// Monitor checks groups of domains and dispatches their alerts. It is safe
+// for concurrent use by groups with distinct domains.
+type Monitor struct {
+	Crawler *crawler.Crawler
+	Store   state.Store
+	// Notifiers are the configured sinks by name, referenced by groups
+	Notifiers map[string]notify.Notifier
+	// Alerts are the global alert settings, applied on top of DefaultAlerts
+	Alerts config.Alerts
+	// Exporters receive the result of every domain checked, and the alerts
+	// when they implement export.EventExporter
+	Exporters []export.Exporter
+	// CT lists the certificates logged for the domains of groups with ct,
+	// and CTIssuers are the CAs expected to issue them
+	CT        intel.CTSource
+	CTIssuers []string
+	// Redact masks the sensitive data of the results in the alerts returned,
+	// exported and notified and in the exported results, as --redact does.
+	// Snapshots and history keep it.
+	Redact bool
+}
+
+// Close releases the exporters and the state store
+func (m *Monitor) Close() error {
+	for _, e := range m.Exporters {
+		e.Close()
+	}
+	return m.Store.Close()
+}
+
+// CheckGroup crawls every domain in the group, saves the new snapshots and
+// sends the resulting alerts to the group's notifiers. Alerts are returned
+// even when delivery fails.
+func (m *Monitor) CheckGroup(g config.Group) (alerts []notify.Alert, errs []error) {
+	ctx, span := tracer.Start(context.Background(), "monitor.group", trace.WithAttributes(
+		attribute.String("dnscrawler.group", g.Name),
+		attribute.Int("dnscrawler.group.domains", len(g.Domains)),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("dnscrawler.group.alerts", len(alerts)))
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d errors, first: %v", len(errs), errs[0]))
+		}
+		span.End()
+	}()
+
+	c := *m.Crawler
+	// High-value domains should also restrict who may issue certificates
+	c.Options = crawler.Options{NoTrace: true, TLS: g.TLS, CAA: g.HighValue}
+
+	var pol *policy.Policy
+	if g.Policy != "" {
+		p, err := policy.Load(g.Policy)
+		if err != nil {
+			return nil, []error{fmt.Errorf("group %s: %v", g.Name, err)}
+		}
+		pol = p
+		c.Options = c.Options.Merge(pol.Options())
+	}
+
+	settings := DefaultAlerts.Merge(m.Alerts).Merge(g.Alerts)
+	for kind, s := range settings.Severities {
+		if s != "none" && !notify.Valid(s) {
+			return nil, []error{fmt.Errorf("group %s: invalid severity %q for %s alerts", g.Name, s, kind)}
+		}
+	}
+	routes, err := routing(g)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var docs []export.Document
+	for _, domain := range g.Domains {
+		cur := c.CrawlContext(ctx, domain)
+		checked := time.Now().UTC()
+		docs = append(docs, export.Document{Time: checked, Group: g.Name, Result: cur, Problems: Health(cur)})
+		prev, err := LoadSnapshot(m.Store, domain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: loading snapshot: %v", domain, err))
+		}
+
+		found := Check(prev, cur, settings)
+		if g.HighValue {
+			found = append(found, CheckLocks(prev, cur, settings)...)
+			found = append(found, CheckCAA(prev, cur, settings)...)
+		}
+		if pol != nil {
+			found = append(found, CheckPolicy(pol, prev, cur, settings)...)
+		}
+		if g.CT && m.CT != nil {
+			ct, err := m.CheckCT(domain, settings)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: certificate transparency: %v", domain, err))
+			}
+			found = append(found, ct...)
+		}
+		for i := range found {
+			found[i].Group = g.Name
+		}
+		if err := AppendHistory(m.Store, domain, found); err != nil {
+			errs = append(errs, fmt.Errorf("%s: saving history: %v", domain, err))
+		}
+		if m.Redact {
+			for i := range found {
+				found[i].Details = slices.Clone(found[i].Details)
+			}
+			redact.Value(found, cur)
+		}
+		alerts = append(alerts, found...)
+
+		if ok(cur.Whois) {
+			if _, err := RecordWhois(m.Store, domain, cur.Whois.Info, time.Now().UTC()); err != nil {
+				errs = append(errs, fmt.Errorf("%s: saving WHOIS history: %v", domain, err))
+			}
+		}
+
+		if err := SaveSnapshot(m.Store, domain, cur, checked); err != nil {
+			errs = append(errs, fmt.Errorf("%s: saving snapshot: %v", domain, err))
+		}
+		if m.Redact {
+			redact.Value(&docs[len(docs)-1].Problems, cur)
+			redact.Result(cur)
+		}
+	}
+
+	for _, e := range m.Exporters {
+		if err := e.Export(docs); err != nil {
+			errs = append(errs, err)
+		}
+		if ee, ok := e.(export.EventExporter); ok {
+			if err := ee.ExportAlerts(alerts); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(routes) {
+		n, ok := m.Notifiers[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("group %s: unknown notifier %q", g.Name, name))
+			continue
+		}
+		var routed []notify.Alert
+		for _, a := range alerts {
+			if notify.AtLeast(a.Severity, routes[name]) {
+				routed = append(routed, a)
+			}
+		}
+		if err := n.Notify(routed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return alerts, errs
+}
+
+// routing returns the minimum severity each notifier of the group receives.
+// Notifiers listed in notify receive everything.
+func routing(g config.Group) (map[string]string, error) {
+	routes := make(map[string]string)
+	add := func(name, severity string) {
+		if cur, ok := routes[name]; !ok || !notify.AtLeast(severity, cur) {
+			routes[name] = severity
+		}
+	}
+	for _, name := range g.Notify {
+		add(name, notify.Info)
+	}
+	for _, r := range g.Routes {
+		if !notify.Valid(r.Severity) {
+			return nil, fmt.Errorf("group %s: invalid route severity %q", g.Name, r.Severity)
+		}
+		for _, name := range r.Notify {
+			add(name, r.Severity)
+		}
+	}
+	return routes, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// severity returns the configured severity for an alert kind, or fallback.
+// An empty result means the kind is muted.
+func severity(settings config.Alerts, kind, fallback string) string {
+	s, ok := settings.Severities[kind]
+	if !ok {
+		return fallback
+	}
+	if s == "none" {
+		return ""
+	}
+	return s
+}
+
+// Check compares a crawl with the previous snapshot (nil on the first run).
+// Expiry and health alerts are only raised when their state differs from the
+// snapshot, so a domain doesn't alert on every run until it's fixed.
+func Check(prev, cur *crawler.Result, settings config.Alerts) []notify.Alert {
+	now := time.Now().UTC()
+	var alerts []notify.Alert
+	alert := func(kind, severity, title string, details []string) {
+		if severity == "" {
+			return
+		}
+		alerts = append(alerts, notify.Alert{Domain: cur.Domain, Kind: kind, Severity: severity, Title: title, Details: details, Time: now})
+	}
+
+	if prev != nil {
+		if changes := Diff(prev, cur); len(changes) > 0 {
+			alert("change", severity(settings, "change", notify.Warning), "DNS or registration changed", changes)
+		}
+	}
+
+	if level, days, ok := domainExpiry(cur, settings.DomainExpiry); ok && level != "" {
+		prevLevel, _, _ := domainExpiry(prev, settings.DomainExpiry)
+		if level != prevLevel {
+			alert("expiry", level, fmt.Sprintf("domain expires in %d days", days), nil)
+		}
+	}
+
+	if level, days, ok := CertExpiry(cur, settings.CertExpiry); ok && level != "" {
+		prevLevel, _, _ := CertExpiry(prev, settings.CertExpiry)
+		if level != prevLevel {
+			alert("expiry", level, fmt.Sprintf("certificate expires in %d days", days), nil)
+		}
+	}
+
+	problems := Health(cur)
+	if len(problems) > 0 && (prev == nil || !slices.Equal(problems, Health(prev))) {
+		fallback := notify.Warning
+		if !cur.Registered {
+			fallback = notify.Critical
+		}
+		alert("health", severity(settings, "health", fallback), "health checks failing", problems)
+	}
+	return alerts
+}
+
+// CheckLocks raises an alert when a domain lacks a transfer or registry
+// lock, for groups of high-value domains. Like health alerts, it is only
+// raised when the missing locks differ from the previous snapshot's.
+func CheckLocks(prev, cur *crawler.Result, settings config.Alerts) []notify.Alert {
+	missing := missingLocks(cur)
+	if len(missing) == 0 || (prev != nil && slices.Equal(missing, missingLocks(prev))) {
+		return nil
+	}
+	level := severity(settings, "lock", notify.Warning)
+	if level == "" {
+		return nil
+	}
+	return []notify.Alert{{
+		Domain:   cur.Domain,
+		Kind:     "lock",
+		Severity: level,
+		Title:    "high-value domain is not fully locked",
+		Details:  missing,
+		Time:     time.Now().UTC(),
+	}}
+}
+
+// missingLocks lists the locks a registered domain lacks, nothing when its
+// registry doesn't publish EPP statuses
+func missingLocks(r *crawler.Result) []string {
+	if r == nil || !r.Registered || !ok(r.Whois) || r.Whois.Locks == nil {
+		return nil
+	}
+	return r.Whois.Locks.Missing()
+}
+
+// CheckCAA raises an alert when a high-value domain has no CAA records, so
+// any certificate authority may issue for it. It is raised when the records
+// disappear, not on every run without them.
+func CheckCAA(prev, cur *crawler.Result, settings config.Alerts) []notify.Alert {
+	if !missingCAA(cur) || (prev != nil && missingCAA(prev)) {
+		return nil
+	}
+	level := severity(settings, "caa", notify.Warning)
+	if level == "" {
+		return nil
+	}
+	return []notify.Alert{{
+		Domain:   cur.Domain,
+		Kind:     "caa",
+		Severity: level,
+		Title:    "high-value domain has no CAA records",
+		Details:  []string{"any certificate authority may issue for it"},
+		Time:     time.Now().UTC(),
+	}}
+}
+
+// missingCAA reports whether a registered domain's CAA lookup found no records
+func missingCAA(r *crawler.Result) bool {
+	return r != nil && r.Registered && r.CAA != nil && !r.CAA.Failed() && len(r.CAA.Records) == 0
+}
+
+// CheckPolicy raises an alert when the set of failing policy rules differs
+// from the previous snapshot's and is not empty
+func CheckPolicy(pol *policy.Policy, prev, cur *crawler.Result, settings config.Alerts) []notify.Alert {
+	failures := policyFailures(pol, cur)
+	if len(failures) == 0 || (prev != nil && slices.Equal(failures, policyFailures(pol, prev))) {
+		return nil
+	}
+	level := severity(settings, "policy", notify.Warning)
+	if level == "" {
+		return nil
+	}
+	return []notify.Alert{{
+		Domain:   cur.Domain,
+		Kind:     "policy",
+		Severity: level,
+		Title:    fmt.Sprintf("%d policy rules failing", len(failures)),
+		Details:  failures,
+		Time:     time.Now().UTC(),
+	}}
+}
+
+func policyFailures(pol *policy.Policy, r *crawler.Result) []string {
+	var failures []string
+	for _, o := range pol.Evaluate(r).Outcomes {
+		if !o.Pass {
+			failures = append(failures, fmt.Sprintf("%s: %s", o.Rule, o.Detail))
+		}
+	}
+	return failures
+}
+
+// Health lists the failed checks of a crawl
+func Health(r *crawler.Result) []string {
+	if !r.Registered {
+		return []string{"domain does not resolve"}
+	}
+	var problems []string
+	if r.Whois != nil && r.Whois.Failed() {
+		problems = append(problems, "WHOIS lookup failed")
+	}
+	if r.Nameservers == nil || r.Nameservers.Failed() || len(r.Nameservers.Servers) == 0 {
+		problems = append(problems, "no nameservers found")
+	}
+	if r.Records != nil && r.Records.Failed() {
+		problems = append(problems, "record lookup failed")
+	}
+	if r.TLS != nil {
+		if r.TLS.Failed() {
+			problems = append(problems, "TLS probe failed")
+		} else if !r.TLS.Valid {
+			problems = append(problems, "certificate invalid: "+r.TLS.VerifyError)
+		} else if r.TLS.OCSP != nil && r.TLS.OCSP.Revoked() {
+			problems = append(problems, "certificate revoked")
+		}
+		for _, ep := range r.TLS.Endpoints {
+			if ep.Info != nil && !ep.Valid && r.TLS.Valid {
+				problems = append(problems, fmt.Sprintf("certificate invalid on %s: %s", ep.IP, ep.VerifyError))
+			}
+		}
+	}
+	return problems
+}
+
+// domainExpiry returns the alert severity for the registration expiry, if known
+func domainExpiry(r *crawler.Result, t config.Threshold) (string, int, bool) {
+	if r == nil || !ok(r.Whois) || r.Whois.DaysToExpiry == nil {
+		return "", 0, false
+	}
+	days := *r.Whois.DaysToExpiry
+	return level(days, t), days, true
+}
+
+// CertExpiry returns the alert severity for the expiry of the first
+// certificate to expire, if probed
+func CertExpiry(r *crawler.Result, t config.Threshold) (string, int, bool) {
+	if r == nil || !ok(r.TLS) {
+		return "", 0, false
+	}
+	days, _, _ := r.TLS.SoonestExpiry()
+	return level(days, t), days, true
+}
+
+func level(days int, t config.Threshold) string {
+	switch {
+	case days < t.Critical:
+		return notify.Critical
+	case days < t.Warning:
+		return notify.Warning
+	}
+	return ""
+}
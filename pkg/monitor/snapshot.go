@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+func snapshotKey(name string) string {
+	return state.Key("snapshot", domain.Canonical(name))
+}
+
+// LoadSnapshot returns the stored snapshot of a domain, or nil if there is none
+func LoadSnapshot(s state.Store, domain string) (*crawler.Result, error) {
+	result := &crawler.Result{}
+	found, err := state.GetJSON(s, snapshotKey(domain), result)
+	if err != nil || !found {
+		return nil, err
+	}
+	return result, nil
+}
+
+func snapshotTimeKey(name string) string {
+	return state.Key("snapshot-time", domain.Canonical(name))
+}
+
+// SaveSnapshot replaces the stored snapshot of a domain, taken at t
+func SaveSnapshot(s state.Store, domain string, result *crawler.Result, t time.Time) error {
+	if err := state.PutJSON(s, snapshotKey(domain), result); err != nil {
+		return err
+	}
+	return state.PutJSON(s, snapshotTimeKey(domain), t)
+}
+
+// SnapshotTime returns when the stored snapshot of a domain was taken, or
+// the zero time for snapshots saved before it was recorded
+func SnapshotTime(s state.Store, domain string) (time.Time, error) {
+	var t time.Time
+	_, err := state.GetJSON(s, snapshotTimeKey(domain), &t)
+	return t, err
+}
@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"slices"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/state"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// whoisHistoryLimit is the number of WHOIS snapshots kept per domain
+const whoisHistoryLimit = 500
+
+func whoisHistoryKey(name string) string {
+	return state.Key("whois-history", domain.Canonical(name))
+}
+
+// WhoisSnapshot is the parsed WHOIS data of a domain over the period it was
+// seen unchanged
+type WhoisSnapshot struct {
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Registrar   string    `json:"registrar,omitempty"`
+	Registrant  string    `json:"registrant,omitempty"`
+	NameServers []string  `json:"name_servers,omitempty"`
+	Status      []string  `json:"status,omitempty"`
+	Created     string    `json:"created,omitempty"`
+	Expires     string    `json:"expires,omitempty"`
+}
+
+// NewWhoisSnapshot takes the fields worth tracking from a lookup made at a
+// time. Name servers and statuses are sorted, so a registry listing them in
+// another order isn't a change.
+func NewWhoisSnapshot(info *whois.Info, at time.Time) WhoisSnapshot {
+	s := WhoisSnapshot{
+		FirstSeen:  at,
+		LastSeen:   at,
+		Registrar:  info.Registrar,
+		Registrant: info.Registrant,
+		Status:     slices.Sorted(slices.Values(info.Status)),
+		Created:    info.Created,
+		Expires:    info.Expires,
+	}
+	for _, ns := range info.NameServers {
+		s.NameServers = append(s.NameServers, domain.Canonical(ns))
+	}
+	slices.Sort(s.NameServers)
+	s.NameServers = slices.Compact(s.NameServers)
+	return s
+}
+
+// Changes describes what changed from prev to s, like Diff
+func (s WhoisSnapshot) Changes(prev WhoisSnapshot) []string {
+	var changes []string
+	changes = appendField(changes, "registrar", prev.Registrar, s.Registrar)
+	changes = appendField(changes, "registrant", prev.Registrant, s.Registrant)
+	changes = appendSet(changes, "NS", prev.NameServers, s.NameServers)
+	changes = appendSet(changes, "status", prev.Status, s.Status)
+	changes = appendField(changes, "creation date", prev.Created, s.Created)
+	changes = appendField(changes, "expiry", prev.Expires, s.Expires)
+	return changes
+}
+
+// LoadWhoisHistory returns the stored WHOIS snapshots of a domain, oldest first
+func LoadWhoisHistory(s state.Store, name string) ([]WhoisSnapshot, error) {
+	var history []WhoisSnapshot
+	_, err := state.GetJSON(s, whoisHistoryKey(name), &history)
+	return history, err
+}
+
+// RecordWhois adds the WHOIS data of a lookup to a domain's history. When it
+// is unchanged, only the last snapshot's LastSeen is moved forward, so the
+// history holds one snapshot per state rather than one per lookup. It
+// reports whether the data changed; the first snapshot of a domain isn't a
+// change.
+func RecordWhois(s state.Store, name string, info *whois.Info, at time.Time) (changed bool, err error) {
+	history, err := LoadWhoisHistory(s, name)
+	if err != nil {
+		return false, err
+	}
+	cur := NewWhoisSnapshot(info, at)
+	if n := len(history); n > 0 {
+		last := &history[n-1]
+		if at.Before(last.LastSeen) {
+			return false, nil // an older lookup, e.g. from a replayed run
+		}
+		if len(cur.Changes(*last)) == 0 {
+			last.LastSeen = at
+			return false, state.PutJSON(s, whoisHistoryKey(name), history)
+		}
+		changed = true
+	}
+	history = append(history, cur)
+	if len(history) > whoisHistoryLimit {
+		history = history[len(history)-whoisHistoryLimit:]
+	}
+	return changed, state.PutJSON(s, whoisHistoryKey(name), history)
+}
+
+// WhoisChange is a change between two consecutive snapshots. It happened
+// after Since, when the old data was last seen, and by Detected.
+type WhoisChange struct {
+	Since    time.Time `json:"since"`
+	Detected time.Time `json:"detected"`
+	Changes  []string  `json:"changes"`
+}
+
+// WhoisChanges lists the changes in a history, oldest first
+func WhoisChanges(history []WhoisSnapshot) []WhoisChange {
+	var changes []WhoisChange
+	for i := 1; i < len(history); i++ {
+		prev, cur := history[i-1], history[i]
+		if c := cur.Changes(prev); len(c) > 0 {
+			changes = append(changes, WhoisChange{Since: prev.LastSeen, Detected: cur.FirstSeen, Changes: c})
+		}
+	}
+	return changes
+}
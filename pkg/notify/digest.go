@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+// digest queues alerts in the state store and passes them on at most once per interval
+type digest struct {
+	Notifier
+	interval time.Duration
+	store    state.Store
+	key      string
+
+	mu sync.Mutex // serializes queue updates from concurrent groups
+}
+
+// digestQueue is the stored state of a digest
+type digestQueue struct {
+	Since  time.Time `json:"since"` // when the oldest queued alert arrived
+	Alerts []Alert   `json:"alerts"`
+}
+
+// Notify queues the alerts and flushes the queue once the oldest entry has
+// waited a full interval. It is also called without alerts to flush.
+func (d *digest) Notify(alerts []Alert) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	q := &digestQueue{}
+	if _, err := state.GetJSON(d.store, d.key, q); err != nil {
+		return err
+	}
+	if len(q.Alerts) == 0 && len(alerts) > 0 {
+		q.Since = time.Now()
+	}
+	q.Alerts = append(q.Alerts, alerts...)
+
+	if len(q.Alerts) > 0 && time.Since(q.Since) >= d.interval {
+		if err := d.Notifier.Notify(q.Alerts); err != nil {
+			// Keep the queue so the digest is retried on the next run
+			return errors.Join(err, state.PutJSON(d.store, d.key, q))
+		}
+		q = &digestQueue{}
+	}
+	return state.PutJSON(d.store, d.key, q)
+}
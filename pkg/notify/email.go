@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+)
+
+// email sends alerts as a plain-text message over SMTP
+type email struct {
+	name string
+	to   []string
+	smtp config.SMTP
+}
+
+func newEmail(name string, cfg config.Notifier, env Env) (*email, error) {
+	if env.SMTP.Host == "" {
+		return nil, fmt.Errorf("notifier %s: smtp.host is not configured", name)
+	}
+	if env.SMTP.From == "" {
+		return nil, fmt.Errorf("notifier %s: smtp.from is not configured", name)
+	}
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("notifier %s: no recipients (to)", name)
+	}
+	return &email{name: name, to: cfg.To, smtp: env.SMTP}, nil
+}
+
+func (e *email) Name() string { return e.name }
+
+func (e *email) Notify(alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	if err := e.send(e.message(alerts)); err != nil {
+		return fmt.Errorf("%s: %v", e.name, err)
+	}
+	return nil
+}
+
+// message renders the alerts as an RFC 5322 message, grouped by domain
+func (e *email) message(alerts []Alert) []byte {
+	var body strings.Builder
+	var order []string
+	byDomain := make(map[string][]Alert)
+	for _, a := range alerts {
+		if _, seen := byDomain[a.Domain]; !seen {
+			order = append(order, a.Domain)
+		}
+		byDomain[a.Domain] = append(byDomain[a.Domain], a)
+	}
+	for _, domain := range order {
+		domainAlerts := byDomain[domain]
+		header := domain
+		if g := domainAlerts[0].Group; g != "" {
+			header += " (" + g + ")"
+		}
+		fmt.Fprintf(&body, "%s\n%s\n", header, strings.Repeat("=", len(header)))
+		for _, a := range domainAlerts {
+			fmt.Fprintf(&body, "[%s] %s\n", strings.ToUpper(a.Severity), a.Title)
+			for _, d := range a.Details {
+				fmt.Fprintf(&body, "  - %s\n", d)
+			}
+		}
+		body.WriteString("\n")
+	}
+
+	subject := fmt.Sprintf("[dnscrawler] %s: %s", strings.ToUpper(Highest(alerts)), summary(alerts))
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes()
+}
+
+// smtpTimeout bounds a whole delivery, from connecting to QUIT, so a
+// stalled server can't hold up the run
+const smtpTimeout = 30 * time.Second
+
+func (e *email) send(msg []byte) error {
+	port := e.smtp.Port
+	if port == 0 {
+		port = 587
+		if e.smtp.TLS {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(e.smtp.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if e.smtp.Username != "" {
+		auth = smtp.PlainAuth("", e.smtp.Username, e.smtp.Password, e.smtp.Host)
+	}
+
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+	var conn net.Conn
+	var err error
+	if e.smtp.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: e.smtp.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+	c, err := smtp.NewClient(conn, e.smtp.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	// Upgrade with STARTTLS when the server offers it
+	if !e.smtp.TLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: e.smtp.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.smtp.From); err != nil {
+		return err
+	}
+	for _, rcpt := range e.to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,190 @@
+// Package notify delivers monitoring alerts to chat webhooks.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+// Severity levels, from least to most urgent
+const (
+	Info     = "info"
+	Warning  = "warning"
+	Critical = "critical"
+)
+
+// Alert is a single event about a monitored domain
+type Alert struct {
+	Domain   string    `json:"domain"`
+	Group    string    `json:"group,omitempty"`
+	Kind     string    `json:"kind"` // change, expiry or health
+	Severity string    `json:"severity"`
+	Title    string    `json:"title"`
+	Details  []string  `json:"details,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Notifier sends a batch of alerts to one destination
+type Notifier interface {
+	Name() string
+	Notify(alerts []Alert) error
+}
+
+// Env holds what notifiers need besides their own config entry
+type Env struct {
+	HTTP *http.Client
+	SMTP config.SMTP
+	// State holds the queues of digest notifiers
+	State state.Store
+}
+
+// New creates the notifier described by a config entry. With a digest
+// interval, alerts are queued in env.State and sent in batches.
+func New(name string, cfg config.Notifier, env Env) (Notifier, error) {
+	n, err := newNotifier(name, cfg, env)
+	if err != nil || cfg.Digest <= 0 {
+		return n, err
+	}
+	if env.State == nil {
+		return nil, fmt.Errorf("notifier %s: digest needs a state store", name)
+	}
+	return &digest{
+		Notifier: n,
+		interval: cfg.Digest,
+		store:    env.State,
+		key:      state.Key("digest", name),
+	}, nil
+}
+
+func newNotifier(name string, cfg config.Notifier, env Env) (Notifier, error) {
+	if strings.EqualFold(cfg.Type, "email") {
+		return newEmail(name, cfg, env)
+	}
+
+	client := env.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("notifier %s: url is required", name)
+	}
+	w := webhook{name: name, url: cfg.URL, client: client}
+	switch strings.ToLower(cfg.Type) {
+	case "slack":
+		w.payload = slackPayload
+	case "teams":
+		w.payload = teamsPayload
+	case "discord":
+		w.payload = discordPayload
+	default:
+		return nil, fmt.Errorf("notifier %s: unknown type %q", name, cfg.Type)
+	}
+	return &w, nil
+}
+
+// webhook posts alerts as JSON; payload renders them in the service's format
+type webhook struct {
+	name    string
+	url     string
+	client  *http.Client
+	payload func(alerts []Alert) any
+}
+
+func (w *webhook) Name() string { return w.name }
+
+func (w *webhook) Notify(alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(w.payload(alerts))
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %v", w.name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: HTTP %d", w.name, resp.StatusCode)
+	}
+	return nil
+}
+
+// Valid reports whether severity is a known severity level
+func Valid(severity string) bool {
+	switch severity {
+	case Info, Warning, Critical:
+		return true
+	}
+	return false
+}
+
+// AtLeast reports whether severity is at least as urgent as min
+func AtLeast(severity, min string) bool {
+	return rank(severity) >= rank(min)
+}
+
+// Highest returns the most urgent severity among the alerts
+func Highest(alerts []Alert) string {
+	highest := Info
+	for _, a := range alerts {
+		if rank(a.Severity) > rank(highest) {
+			highest = a.Severity
+		}
+	}
+	return highest
+}
+
+func rank(severity string) int {
+	switch severity {
+	case Critical:
+		return 2
+	case Warning:
+		return 1
+	}
+	return 0
+}
+
+// summary is the one-line description of a batch, used as fallback text
+func summary(alerts []Alert) string {
+	if len(alerts) == 1 {
+		return fmt.Sprintf("%s: %s", alerts[0].Domain, alerts[0].Title)
+	}
+	domains := make(map[string]bool)
+	for _, a := range alerts {
+		domains[a.Domain] = true
+	}
+	return fmt.Sprintf("%d alerts for %d domains", len(alerts), len(domains))
+}
+
+func emoji(severity string) string {
+	switch severity {
+	case Critical:
+		return "🔴"
+	case Warning:
+		return "🟠"
+	}
+	return "🔵"
+}
+
+// color returns the hex color for a severity, without the leading #
+func color(severity string) string {
+	switch severity {
+	case Critical:
+		return "D13438"
+	case Warning:
+		return "F2A33A"
+	}
+	return "3A87F2"
+}
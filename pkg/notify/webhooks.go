@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// slackPayload renders alerts as Block Kit sections for an incoming webhook
+func slackPayload(alerts []Alert) any {
+	blocks := []map[string]any{{
+		"type": "header",
+		"text": map[string]string{"type": "plain_text", "text": summary(alerts)},
+	}}
+	for _, a := range alerts {
+		text := fmt.Sprintf("%s *%s* — %s", emoji(a.Severity), a.Domain, a.Title)
+		for _, d := range a.Details {
+			text += "\n• " + d
+		}
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		})
+	}
+	return map[string]any{"text": summary(alerts), "blocks": blocks}
+}
+
+// teamsPayload renders alerts as a legacy MessageCard, which Teams incoming
+// webhooks and workflow connectors both accept
+func teamsPayload(alerts []Alert) any {
+	var sections []map[string]any
+	for _, a := range alerts {
+		section := map[string]any{
+			"activityTitle":    fmt.Sprintf("%s %s", emoji(a.Severity), a.Domain),
+			"activitySubtitle": a.Title,
+		}
+		if len(a.Details) > 0 {
+			section["text"] = strings.Join(a.Details, "<br>")
+		}
+		sections = append(sections, section)
+	}
+	return map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "http://schema.org/extensions",
+		"summary":    summary(alerts),
+		"title":      summary(alerts),
+		"themeColor": color(Highest(alerts)),
+		"sections":   sections,
+	}
+}
+
+// discordMaxEmbeds is the number of embeds Discord accepts per message
+const discordMaxEmbeds = 10
+
+// discordPayload renders alerts as embeds, one per alert
+func discordPayload(alerts []Alert) any {
+	var embeds []map[string]any
+	for _, a := range alerts[:min(len(alerts), discordMaxEmbeds)] {
+		c, _ := strconv.ParseInt(color(a.Severity), 16, 32)
+		embed := map[string]any{
+			"title": fmt.Sprintf("%s: %s", a.Domain, a.Title),
+			"color": c,
+		}
+		if len(a.Details) > 0 {
+			embed["description"] = "• " + strings.Join(a.Details, "\n• ")
+		}
+		if !a.Time.IsZero() {
+			embed["timestamp"] = a.Time.Format("2006-01-02T15:04:05Z07:00")
+		}
+		embeds = append(embeds, embed)
+	}
+	content := summary(alerts)
+	if len(alerts) > discordMaxEmbeds {
+		content += fmt.Sprintf(" (showing %d)", discordMaxEmbeds)
+	}
+	return map[string]any{"content": content, "embeds": embeds}
+}
@@ -2,9 +2,13 @@ package output
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 
+	"github.com/auduny/dnscrawler/pkg/i18n"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
 var (
@@ -16,12 +20,49 @@ var (
 	errorColor    = color.New(color.FgRed)
 	dimColor      = color.New(color.FgHiBlack)
 	providerColor = color.New(color.FgGreen)
+	bannerColor   = color.New(color.FgWhite, color.BgRed, color.Bold)
 )
 
-type Formatter struct{}
+type Formatter struct {
+	// mu serializes the status line with output printed through Exclusive
+	mu     sync.Mutex
+	tty    bool // stderr is a terminal
+	status bool // a status line is shown
+	// Lang translates section titles, keys and messages; nil prints them
+	// in English
+	Lang *i18n.Language
+}
 
 func New() *Formatter {
-	return &Formatter{}
+	return &Formatter{tty: isatty.IsTerminal(os.Stderr.Fd())}
+}
+
+// PrintStatus shows a transient progress line on stderr, replacing the
+// previous one. It does nothing unless stderr is a terminal.
+func (f *Formatter) PrintStatus(msg string) {
+	if !f.tty {
+		return
+	}
+	// Longer lines would wrap, and only the last row would be replaced
+	if r := []rune(msg); len(r) > 76 {
+		msg = string(r[:75]) + "…"
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dimColor.Fprintf(os.Stderr, "\r\033[K%s", msg)
+	f.status = true
+}
+
+// Exclusive removes the status line and runs print without status updates
+// interleaving with its output
+func (f *Formatter) Exclusive(print func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.status {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		f.status = false
+	}
+	print()
 }
 
 func (f *Formatter) PrintTitle(domain string) {
@@ -32,11 +73,11 @@ func (f *Formatter) PrintTitle(domain string) {
 
 func (f *Formatter) PrintSection(name string) {
 	fmt.Println()
-	sectionColor.Println(name)
+	sectionColor.Println(f.Lang.T(name))
 }
 
 func (f *Formatter) PrintKeyValue(key, value string) {
-	labelColor.Printf("%-12s ", key)
+	labelColor.Printf("%-12s ", f.Lang.T(key))
 	valueColor.Println(value)
 }
 
@@ -70,13 +111,6 @@ func (f *Formatter) PrintArrowItemWithProviderAndASN(value, provider, asn string
 	fmt.Println()
 }
 
-func (f *Formatter) PrintTraceStep(zone, server string) {
-	dimColor.Print("  ")
-	valueColor.Print(zone)
-	dimColor.Print(" → ")
-	valueColor.Println(server)
-}
-
 func (f *Formatter) PrintRecord(recordType, value string) {
 	labelColor.Printf("  %-6s ", recordType)
 	valueColor.Println(value)
@@ -108,11 +142,57 @@ func (f *Formatter) PrintRecordWithProviderAndASN(recordType, value, providerNam
 }
 
 func (f *Formatter) PrintError(msg string) {
-	errorColor.Printf("  ✗ %s\n", msg)
+	errorColor.Printf("  ✗ %s\n", f.Lang.T(msg))
+}
+
+func (f *Formatter) PrintWarning(msg string) {
+	arrowColor.Printf("  ! %s\n", f.Lang.T(msg))
+}
+
+// PrintBanner prints a highlighted line for findings that must not be missed
+func (f *Formatter) PrintBanner(msg string) {
+	bannerColor.Printf(" %s ", msg)
+	fmt.Println()
+}
+
+// PrintCheck prints a scorecard line: a pass/warn/fail marker, the check
+// name, its points (if any) and a detail
+func (f *Formatter) PrintCheck(status, name, points, detail string) {
+	switch status {
+	case "pass":
+		providerColor.Print("  ✓ ")
+	case "warn":
+		arrowColor.Print("  ! ")
+	case "n/a":
+		dimColor.Print("  - ")
+	default:
+		errorColor.Print("  ✗ ")
+	}
+	labelColor.Printf("%-12s ", name)
+	if points != "" {
+		valueColor.Printf("%-7s ", points)
+	}
+	dimColor.Println(detail)
+}
+
+// PrintSummary prints a domain and its fields on one line
+func (f *Formatter) PrintSummary(domain string, fields ...string) {
+	titleColor.Print(domain)
+	for _, field := range fields {
+		dimColor.Print("  ")
+		valueColor.Print(field)
+	}
+	fmt.Println()
 }
 
 func (f *Formatter) PrintDim(msg string) {
-	dimColor.Printf("  %s\n", msg)
+	dimColor.Printf("  %s\n", f.Lang.T(msg))
+}
+
+// Sprintf formats a message in the formatter's language, for messages with
+// values such as errors or counts
+func (f *Formatter) Sprintf(format string, args ...any) string {
+	return f.Lang.Sprintf(format, args...)
 }
 
 func (f *Formatter) Finish() {
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// JUnitSuites is the root of a JUnit XML report, as rendered by Jenkins and GitLab
+type JUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr,omitempty"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite groups the test cases of one domain
+type JUnitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a single check
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure marks a failed check
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// AddCase appends a test case to the suite; a non-empty failure message marks
+// it failed and the detail becomes the failure text instead of its output
+func (s *JUnitSuite) AddCase(name, detail, failure string) {
+	c := JUnitCase{Name: name, Classname: s.Name}
+	if failure != "" {
+		c.Failure = &JUnitFailure{Message: failure, Text: detail}
+		s.Failures++
+	} else {
+		c.SystemOut = detail
+	}
+	s.Tests++
+	s.Cases = append(s.Cases, c)
+}
+
+// Add appends a suite and updates the totals
+func (r *JUnitSuites) Add(s JUnitSuite) {
+	r.Tests += s.Tests
+	r.Failures += s.Failures
+	r.Suites = append(r.Suites, s)
+}
+
+// Write renders the report as indented XML
+func (r *JUnitSuites) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
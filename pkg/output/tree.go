@@ -0,0 +1,56 @@
+package output
+
+import "fmt"
+
+// TreeNode is an entry of a tree printed by PrintTree
+type TreeNode struct {
+	Label string
+	// Provider is shown in brackets after the label, as by
+	// PrintArrowItemWithProvider
+	Provider string
+	// Annotation is dimmed after the label and provider
+	Annotation string
+	Children   []*TreeNode
+}
+
+// Add appends a child to the node and returns it
+func (n *TreeNode) Add(label, provider, annotation string) *TreeNode {
+	child := &TreeNode{Label: label, Provider: provider, Annotation: annotation}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// PrintTree prints each root and its descendants, drawing the branches
+// with box-drawing characters so every level is indented below its parent
+func (f *Formatter) PrintTree(roots ...*TreeNode) {
+	for _, root := range roots {
+		dimColor.Print("  ")
+		printTreeLabel(root)
+		printTreeChildren(root.Children, "  ")
+	}
+}
+
+func printTreeChildren(children []*TreeNode, prefix string) {
+	for i, child := range children {
+		branch, indent := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		dimColor.Print(prefix + branch)
+		printTreeLabel(child)
+		printTreeChildren(child.Children, prefix+indent)
+	}
+}
+
+func printTreeLabel(n *TreeNode) {
+	valueColor.Print(n.Label)
+	if n.Provider != "" {
+		dimColor.Print(" [")
+		providerColor.Print(n.Provider)
+		dimColor.Print("]")
+	}
+	if n.Annotation != "" {
+		dimColor.Printf(" (%s)", n.Annotation)
+	}
+	fmt.Println()
+}
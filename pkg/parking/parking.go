@@ -0,0 +1,188 @@
+// Package parking tells parked and for-sale domains apart from their
+// nameservers, addresses and landing pages.
+package parking
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// Signal is one trace of parking
+type Signal struct {
+	Kind     string `json:"kind"` // nameserver, address, cname, wildcard or page
+	Value    string `json:"value"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// Verdict is why a domain is taken for parked
+type Verdict struct {
+	// Provider is the parking service, "" when only generic markers were seen
+	Provider string `json:"provider,omitempty"`
+	// ForSale is set when the domain is offered on a marketplace
+	ForSale bool     `json:"for_sale"`
+	Signals []Signal `json:"signals"`
+}
+
+// Label describes the verdict in a few words, e.g. "parked (Sedo)"
+func (v *Verdict) Label() string {
+	label := "parked"
+	if v.ForSale {
+		label += ", for sale"
+	}
+	if v.Provider != "" {
+		label += " (" + v.Provider + ")"
+	}
+	return label
+}
+
+// Input is what is known about a domain
+type Input struct {
+	Nameservers []string
+	Addresses   []string // of the domain's A and AAAA records
+	CNAMEs      []string
+	// Wildcard are the answers for a nonexistent name below the domain
+	Wildcard []string
+	// Page is the body of the domain's website, nil when not fetched
+	Page []byte
+}
+
+// provider is a parking service and its traces
+type provider struct {
+	name        string
+	nameservers string // regexp on nameserver hostnames
+	networks    []string
+	cnames      string // regexp on CNAME targets
+	page        string // regexp on the landing page
+	marketplace bool   // domains parked here are for sale
+}
+
+var providers = []provider{
+	{name: "Sedo", nameservers: `\.sedoparking\.com$`, networks: []string{"64.190.62.0/23", "91.195.240.0/23"}, page: `sedoparking\.com|sedo\.com/search/details`},
+	{name: "Bodis", nameservers: `\.bodis\.com$`, networks: []string{"199.59.240.0/22"}, page: `bodis\.com`},
+	{name: "ParkingCrew", nameservers: `\.parkingcrew\.net$`, networks: []string{"185.53.176.0/22"}, page: `parkingcrew\.net`},
+	{name: "Above.com", nameservers: `\.above\.com$`, networks: []string{"103.224.182.0/23", "103.224.212.0/23"}, page: `above\.com/marketplace`},
+	{name: "GoDaddy", networks: []string{"34.102.136.180/32"}, page: `img1\.wsimg\.com/parking-lander|parked free, courtesy of godaddy`},
+	{name: "Namecheap", cnames: `^parkingpage\.namecheap\.com$`, page: `parkingpage\.namecheap\.com`},
+	{name: "Dan.com", nameservers: `\.dan\.com$`, page: `dan\.com/buy-domain`, marketplace: true},
+	{name: "Afternic", nameservers: `\.afternic\.com$`, page: `afternic\.com`, marketplace: true},
+	{name: "HugeDomains", nameservers: `\.namebrightdns\.com$`, page: `hugedomains\.com`, marketplace: true},
+	{name: "Undeveloped", nameservers: `\.undeveloped\.com$`, page: `undeveloped\.com`, marketplace: true},
+}
+
+// saleMarkers are phrases of landing pages offering the domain
+var saleMarkers = regexp.MustCompile(`(?i)(this|the) domain( name)? (is|may be|might be) for sale|buy this domain|make an offer on this domain|domain is available for purchase`)
+
+// parkedMarkers are phrases of landing pages without content of their own
+var parkedMarkers = regexp.MustCompile(`(?i)this domain( name)? (is|has been) parked|parked free`)
+
+type matcher struct {
+	provider
+	nameservers, cnames, page *regexp.Regexp
+	networks                  []netip.Prefix
+}
+
+var matchers = compile(providers)
+
+func compile(providers []provider) []matcher {
+	compile := func(pattern string) *regexp.Regexp {
+		if pattern == "" {
+			return nil
+		}
+		return regexp.MustCompile("(?i)" + pattern)
+	}
+	var compiled []matcher
+	for _, p := range providers {
+		m := matcher{provider: p, nameservers: compile(p.nameservers), cnames: compile(p.cnames), page: compile(p.page)}
+		for _, network := range p.networks {
+			m.networks = append(m.networks, netip.MustParsePrefix(network))
+		}
+		compiled = append(compiled, m)
+	}
+	return compiled
+}
+
+func (m matcher) contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, network := range m.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinSources is how many independent sources must show traces of parking
+// for a domain to be taken for parked: its nameservers, its addresses (or
+// those of a nonexistent name below it), its CNAME targets and its page.
+// A single one is as often a shared host or a stray phrase.
+const MinSources = 2
+
+// Detect weighs the traces of parking, returning nil when fewer than
+// MinSources show any. The provider is the one seen first, as nameservers
+// and addresses are surer signs than a page's markers.
+func Detect(in Input) *Verdict {
+	v := &Verdict{}
+	add := func(kind, value string, m *matcher) {
+		s := Signal{Kind: kind, Value: value}
+		if m != nil {
+			s.Provider = m.name
+			if v.Provider == "" {
+				v.Provider = m.name
+			}
+			v.ForSale = v.ForSale || m.marketplace
+		}
+		v.Signals = append(v.Signals, s)
+	}
+
+	for _, m := range matchers {
+		for _, ns := range in.Nameservers {
+			if m.nameservers != nil && m.nameservers.MatchString(strings.TrimSuffix(ns, ".")) {
+				add("nameserver", ns, &m)
+			}
+		}
+	}
+	for _, m := range matchers {
+		for _, ip := range in.Addresses {
+			if m.contains(ip) {
+				add("address", ip, &m)
+			}
+		}
+		for _, ip := range in.Wildcard {
+			if m.contains(ip) {
+				add("wildcard", ip, &m)
+			}
+		}
+		for _, target := range in.CNAMEs {
+			if m.cnames != nil && m.cnames.MatchString(strings.TrimSuffix(target, ".")) {
+				add("cname", target, &m)
+			}
+		}
+	}
+	if len(in.Page) > 0 {
+		for _, m := range matchers {
+			if m.page != nil && m.page.Match(in.Page) {
+				add("page", "links to "+m.name, &m)
+			}
+		}
+		if marker := saleMarkers.Find(in.Page); marker != nil {
+			v.ForSale = true
+			add("page", string(marker), nil)
+		} else if marker := parkedMarkers.Find(in.Page); marker != nil {
+			add("page", string(marker), nil)
+		}
+	}
+
+	sources := map[string]bool{}
+	for _, s := range v.Signals {
+		// A wildcard answer is an address of the same zone
+		sources[strings.Replace(s.Kind, "wildcard", "address", 1)] = true
+	}
+	if len(sources) < MinSources {
+		return nil
+	}
+	return v
+}
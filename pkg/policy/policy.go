@@ -0,0 +1,288 @@
+// Package policy evaluates crawl results against user-defined compliance rules.
+//
+// A policy is a YAML file with a list of rules. Each rule sets exactly one
+// condition:
+//
+//	rules:
+//	  - name: dns-provider
+//	    nameserver_providers: [Amazon Route 53, Cloudflare]
+//	  - name: dmarc
+//	    dmarc_policy: reject
+//	  - name: registrar
+//	    registrar: MarkMonitor
+//	  - name: ns-redundancy
+//	    min_ns_providers: 2
+//	  - name: expiry
+//	    expr: whois.days_to_expiry > 30
+//	  - name: registry-lock
+//	    registry_lock: true
+package policy
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/filter"
+	"github.com/auduny/dnscrawler/pkg/provider"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is a named set of rules
+type Policy struct {
+	Name  string  `yaml:"name"`
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Rule is a single compliance requirement
+type Rule struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	// NameserverProviders lists the allowed DNS providers; every nameserver must match one
+	NameserverProviders []string `yaml:"nameserver_providers"`
+	// MinNSProviders is the minimum number of distinct DNS providers
+	MinNSProviders int `yaml:"min_ns_providers"`
+	// Registrar must appear (case-insensitively) in the WHOIS registrar
+	Registrar string `yaml:"registrar"`
+	// DMARCPolicy is the required DMARC p= value, or sp= of the zone's record
+	// for a subdomain without one
+	DMARCPolicy string `yaml:"dmarc_policy"`
+	// DNSSEC requires the zone to be signed and validate
+	DNSSEC bool `yaml:"dnssec"`
+	// MinDaysToExpiry is the minimum remaining registration period
+	MinDaysToExpiry int `yaml:"min_days_to_expiry"`
+	// TransferLock requires clientTransferProhibited (see whois.Locks)
+	TransferLock bool `yaml:"transfer_lock"`
+	// RegistryLock requires serverTransferProhibited and serverUpdateProhibited
+	RegistryLock bool `yaml:"registry_lock"`
+	// Expr is an expression evaluated against the JSON result, as with --filter
+	Expr string `yaml:"expr"`
+
+	expr *filter.Filter
+}
+
+// Outcome is the result of evaluating one rule
+type Outcome struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description,omitempty"`
+	Pass        bool   `json:"pass"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// Report holds all rule outcomes for a domain
+type Report struct {
+	Domain   string    `json:"domain"`
+	Pass     bool      `json:"pass"`
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Load reads and validates a policy file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("%s: no rules", path)
+	}
+	for i, r := range p.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("%s: rule %s: %v", path, r.Name, err)
+		}
+	}
+	return p, nil
+}
+
+func (r *Rule) compile() error {
+	conditions := 0
+	for _, set := range []bool{
+		len(r.NameserverProviders) > 0,
+		r.MinNSProviders > 0,
+		r.Registrar != "",
+		r.DMARCPolicy != "",
+		r.DNSSEC,
+		r.MinDaysToExpiry > 0,
+		r.TransferLock,
+		r.RegistryLock,
+		r.Expr != "",
+	} {
+		if set {
+			conditions++
+		}
+	}
+	if conditions != 1 {
+		return fmt.Errorf("must set exactly one condition, has %d", conditions)
+	}
+	if r.Expr != "" {
+		f, err := filter.Compile(r.Expr)
+		if err != nil {
+			return err
+		}
+		r.expr = f
+	}
+	return nil
+}
+
+// Options returns the crawler options needed to evaluate the policy
+func (p *Policy) Options() crawler.Options {
+	opts := crawler.Options{NoTrace: true}
+	for _, r := range p.Rules {
+		if r.DNSSEC {
+			opts.DNSSEC = true
+		}
+		if r.expr != nil {
+			// Expressions may reference any section
+			opts.TLS, opts.DNSSEC, opts.CAA = true, true, true
+		}
+	}
+	return opts
+}
+
+// Evaluate checks every rule against a crawl result. Zone-level rules use the
+// root domain's result when the domain is a subdomain.
+func (p *Policy) Evaluate(result *crawler.Result) *Report {
+	report := &Report{Domain: result.Domain, Pass: true}
+	for _, r := range p.Rules {
+		pass, detail := r.evaluate(result)
+		report.Outcomes = append(report.Outcomes, Outcome{
+			Rule:        r.Name,
+			Description: r.Description,
+			Pass:        pass,
+			Detail:      detail,
+		})
+		report.Pass = report.Pass && pass
+	}
+	return report
+}
+
+func (r *Rule) evaluate(result *crawler.Result) (bool, string) {
+	zone := result
+	if result.Root != nil {
+		zone = result.Root
+	}
+	if !zone.Registered {
+		return false, "domain not registered"
+	}
+
+	switch {
+	case len(r.NameserverProviders) > 0:
+		ns, err := nameservers(zone)
+		if err != "" {
+			return false, err
+		}
+		for _, s := range ns {
+			if !provider.Listed(r.NameserverProviders, s.Provider) {
+				return false, fmt.Sprintf("%s is hosted by %s", s.Name, cmp.Or(s.Provider, "unknown provider"))
+			}
+		}
+		return true, strings.Join(providers(ns), ", ")
+
+	case r.MinNSProviders > 0:
+		ns, err := nameservers(zone)
+		if err != "" {
+			return false, err
+		}
+		found := providers(ns)
+		detail := fmt.Sprintf("%d providers: %s", len(found), strings.Join(found, ", "))
+		return len(found) >= r.MinNSProviders, detail
+
+	case r.Registrar != "":
+		if zone.Whois == nil || zone.Whois.Failed() {
+			return false, "WHOIS unavailable"
+		}
+		registrar := zone.Whois.Registrar
+		return strings.Contains(strings.ToLower(registrar), strings.ToLower(r.Registrar)), "registrar is " + registrar
+
+	case r.DMARCPolicy != "":
+		// A subdomain without a record gets the zone's sp= policy
+		got, tag := result.DMARCPolicy()
+		if tag == "" {
+			return false, "no DMARC record"
+		}
+		return strings.EqualFold(got, r.DMARCPolicy), tag + "=" + got
+
+	case r.DNSSEC:
+		sec := zone.DNSSEC
+		switch {
+		case sec == nil || sec.Failed():
+			return false, "DNSSEC could not be checked"
+		case !sec.Signed:
+			return false, "zone is not signed"
+		case !sec.Validated:
+			return false, "zone does not validate"
+		}
+		return true, "signed and validated"
+
+	case r.MinDaysToExpiry > 0:
+		if zone.Whois == nil || zone.Whois.Failed() || zone.Whois.DaysToExpiry == nil {
+			return false, "expiry date unknown"
+		}
+		days := *zone.Whois.DaysToExpiry
+		return days >= r.MinDaysToExpiry, fmt.Sprintf("expires in %d days", days)
+
+	case r.TransferLock, r.RegistryLock:
+		if zone.Whois == nil || zone.Whois.Failed() || zone.Whois.Info == nil {
+			return false, "WHOIS unavailable"
+		}
+		locks := zone.Whois.Locks
+		switch {
+		case locks == nil:
+			return false, "registry publishes no EPP statuses"
+		case r.TransferLock && !locks.TransferLock:
+			return false, "no clientTransferProhibited status"
+		case r.RegistryLock && locks.RegistryLockPartial:
+			return false, "only one of serverTransferProhibited and serverUpdateProhibited"
+		case r.RegistryLock && !locks.RegistryLock:
+			return false, "no serverTransferProhibited and serverUpdateProhibited statuses"
+		}
+		return true, "locked"
+
+	case r.expr != nil:
+		ok, err := r.expr.Match(result)
+		if err != nil {
+			return false, err.Error()
+		}
+		if !ok {
+			return false, "expression is false: " + r.expr.String()
+		}
+		return true, ""
+	}
+	return false, "rule has no condition"
+}
+
+func nameservers(zone *crawler.Result) ([]crawler.Nameserver, string) {
+	ns := zone.Nameservers
+	if ns == nil || ns.Failed() {
+		return nil, "nameserver lookup failed"
+	}
+	if len(ns.Servers) == 0 {
+		return nil, "no nameservers"
+	}
+	return ns.Servers, ""
+}
+
+// providers returns the distinct providers of the nameservers, sorted
+func providers(ns []crawler.Nameserver) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range ns {
+		if s.Provider != "" && !seen[s.Provider] {
+			seen[s.Provider] = true
+			out = append(out, s.Provider)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -2,7 +2,10 @@ package provider
 
 import (
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
 )
 
 // Pattern represents a regex pattern and its associated provider name
@@ -14,6 +17,7 @@ type Pattern struct {
 // Matcher identifies DNS providers from nameserver hostnames
 type Matcher struct {
 	patterns []Pattern
+	index    *suffixIndex
 }
 
 // Built-in provider patterns for common DNS providers
@@ -210,6 +214,7 @@ func newMatcherFrom(patterns []struct {
 			Provider: bp.provider,
 		})
 	}
+	m.index = buildSuffixIndex(m.patterns)
 
 	return m
 }
@@ -236,6 +241,7 @@ func (m *Matcher) AddPattern(patternSpec string) error {
 
 	// Add custom patterns at the beginning so they take precedence
 	m.patterns = append([]Pattern{{Regex: re, Provider: provider}}, m.patterns...)
+	m.index = buildSuffixIndex(m.patterns)
 	return nil
 }
 
@@ -250,20 +256,27 @@ func (m *Matcher) AddPatterns(patternSpecs []string) []error {
 	return errors
 }
 
-// Match returns the provider name for a nameserver hostname
-// Returns empty string if no match found
+// Match returns the provider name for a nameserver hostname, which may be in
+// FQDN form. Returns empty string if no match found
 func (m *Matcher) Match(nameserver string) string {
-	ns := strings.ToLower(nameserver)
+	ns := domain.Canonical(nameserver)
 
-	for _, p := range m.patterns {
-		if p.Regex.MatchString(ns) {
-			return p.Provider
-		}
+	// A Matcher has an index once it has patterns
+	if m.index == nil {
+		return ""
+	}
+	if i := m.index.match(m.patterns, ns); i >= 0 {
+		return m.patterns[i].Provider
 	}
-
 	return ""
 }
 
+// Listed reports whether provider is one of names, ignoring case as
+// provider names are typed by hand in flags and policies
+func Listed(names []string, provider string) bool {
+	return slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, provider) })
+}
+
 // PatternError represents an error with a pattern specification
 type PatternError struct {
 	Pattern string
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchMatcher has the built-in patterns plus n custom suffix patterns, as
+// in deployments mapping many in-house nameserver domains
+func benchMatcher(n int) *Matcher {
+	m := NewMatcher()
+	specs := make([]string, n)
+	for i := range specs {
+		specs[i] = fmt.Sprintf(`\.dns%d\.example\.(com|net)$:Customer %d`, i, i)
+	}
+	m.AddPatterns(specs)
+	return m
+}
+
+var benchNames = []string{
+	"ns-1234.awsdns-56.org",
+	"ns1.google.com",
+	"dana.ns.cloudflare.com",
+	"ns1.dns250.example.net",
+	"a.ns.unknown-provider.example",
+	"ns2.self-hosted.no",
+}
+
+// scan is the regex-per-pattern matching the suffix index replaces
+func scan(m *Matcher, name string) string {
+	for _, p := range m.patterns {
+		if p.Regex.MatchString(name) {
+			return p.Provider
+		}
+	}
+	return ""
+}
+
+func BenchmarkMatch(b *testing.B) {
+	for _, n := range []int{0, 100, 500} {
+		m := benchMatcher(n)
+		b.Run(fmt.Sprintf("trie/custom=%d", n), func(b *testing.B) {
+			for i := 0; b.Loop(); i++ {
+				m.Match(benchNames[i%len(benchNames)])
+			}
+		})
+		b.Run(fmt.Sprintf("regex/custom=%d", n), func(b *testing.B) {
+			for i := 0; b.Loop(); i++ {
+				scan(m, benchNames[i%len(benchNames)])
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"regexp/syntax"
+	"strings"
+)
+
+// maxSuffixes bounds how many literal suffixes one pattern may expand to
+// before it is left to the regex fallback
+const maxSuffixes = 64
+
+// suffixIndex answers suffix-anchored literal patterns (e.g. `\.ovh\.(net|com)$`)
+// with a trie over the reversed hostname, so a match costs one walk over the
+// name instead of one regex per pattern. Other patterns are kept for a regex
+// fallback. Pattern order decides between several matches, as before.
+type suffixIndex struct {
+	root     *suffixNode
+	fallback []int // indexes of patterns that must be evaluated as regexes
+}
+
+type suffixNode struct {
+	children map[byte]*suffixNode
+	pattern  int // lowest index of a pattern ending here, or -1
+}
+
+func newSuffixNode() *suffixNode {
+	return &suffixNode{pattern: -1}
+}
+
+func buildSuffixIndex(patterns []Pattern) *suffixIndex {
+	idx := &suffixIndex{root: newSuffixNode()}
+	for i, p := range patterns {
+		suffixes, ok := literalSuffixes(p.Regex.String())
+		if !ok {
+			idx.fallback = append(idx.fallback, i)
+			continue
+		}
+		for _, s := range suffixes {
+			idx.insert(s, i)
+		}
+	}
+	return idx
+}
+
+func (idx *suffixIndex) insert(suffix string, pattern int) {
+	n := idx.root
+	for i := len(suffix) - 1; i >= 0; i-- {
+		c := suffix[i]
+		next, ok := n.children[c]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[byte]*suffixNode)
+			}
+			next = newSuffixNode()
+			n.children[c] = next
+		}
+		n = next
+	}
+	if n.pattern < 0 || pattern < n.pattern {
+		n.pattern = pattern
+	}
+}
+
+// match returns the index of the first pattern matching name (lowercased), or -1
+func (idx *suffixIndex) match(patterns []Pattern, name string) int {
+	best := idx.root.pattern
+	n := idx.root
+	for i := len(name) - 1; i >= 0 && n != nil; i-- {
+		n = n.children[name[i]]
+		if n != nil && n.pattern >= 0 && (best < 0 || n.pattern < best) {
+			best = n.pattern
+		}
+	}
+
+	// Only regexes ordered before the trie's match can take precedence
+	for _, i := range idx.fallback {
+		if best >= 0 && i > best {
+			break
+		}
+		if patterns[i].Regex.MatchString(name) {
+			return i
+		}
+	}
+	return best
+}
+
+// literalSuffixes returns the lowercase strings a pattern matches at the end
+// of a name when it is a finite set of literals anchored with $, such as
+// `\.ovh\.(net|com)$`. It reports false for anything else.
+func literalSuffixes(pattern string) ([]string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	re = re.Simplify()
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 || re.Sub[len(re.Sub)-1].Op != syntax.OpEndText {
+		return nil, false
+	}
+	body := &syntax.Regexp{Op: syntax.OpConcat, Sub: re.Sub[:len(re.Sub)-1]}
+	return expand(body)
+}
+
+// expand lists every string re matches when that set is small and finite
+func expand(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		return []string{strings.ToLower(string(re.Rune))}, true
+	case syntax.OpCapture:
+		return expand(re.Sub[0])
+	case syntax.OpCharClass:
+		var out []string
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if len(out) >= maxSuffixes {
+					return nil, false
+				}
+				out = append(out, strings.ToLower(string(r)))
+			}
+		}
+		return dedupe(out), true
+	case syntax.OpAlternate:
+		var out []string
+		for _, sub := range re.Sub {
+			s, ok := expand(sub)
+			if !ok || len(out)+len(s) > maxSuffixes {
+				return nil, false
+			}
+			out = append(out, s...)
+		}
+		return dedupe(out), true
+	case syntax.OpConcat:
+		out := []string{""}
+		for _, sub := range re.Sub {
+			s, ok := expand(sub)
+			if !ok || len(out)*len(s) > maxSuffixes {
+				return nil, false
+			}
+			product := make([]string, 0, len(out)*len(s))
+			for _, prefix := range out {
+				for _, suffix := range s {
+					product = append(product, prefix+suffix)
+				}
+			}
+			out = product
+		}
+		return dedupe(out), true
+	}
+	return nil, false
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
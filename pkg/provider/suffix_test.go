@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+)
+
+// TestSuffixIndex checks that the trie picks the same provider as trying
+// every pattern's regex in order, for a nameserver below every public
+// suffix and below every literal suffix of the patterns
+func TestSuffixIndex(t *testing.T) {
+	m := benchMatcher(100)
+
+	f, err := os.Open("../domain/public_suffix_list.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rule := strings.TrimSpace(scanner.Text())
+		if rule == "" || strings.HasPrefix(rule, "//") {
+			continue
+		}
+		rule = strings.TrimPrefix(strings.TrimPrefix(rule, "!"), "*.")
+		names = append(names, "ns1.example."+rule, "ns1."+rule)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range m.patterns {
+		suffixes, _ := literalSuffixes(p.Regex.String())
+		for _, s := range suffixes {
+			names = append(names, "ns1"+s, "NS1"+strings.ToUpper(s)+".", "ns1"+s+".example", strings.TrimPrefix(s, "."))
+		}
+	}
+	names = append(names, benchNames...)
+
+	for _, name := range names {
+		if got, want := m.Match(name), scan(m, domain.Canonical(name)); got != want {
+			t.Errorf("Match(%q) = %q, regexes say %q", name, got, want)
+		}
+	}
+}
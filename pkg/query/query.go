@@ -0,0 +1,76 @@
+// Package query extracts values from crawl results with jq selectors.
+//
+// Queries use the jq language as implemented by gojq
+// (https://github.com/itchyny/gojq) and address fields by their JSON names,
+// e.g. `.records.mx[].value` or `.whois.registrar`.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+)
+
+// Query is a compiled jq query
+type Query struct {
+	source string
+	code   *gojq.Code
+}
+
+// Compile parses a jq query
+func Compile(source string) (*Query, error) {
+	parsed, err := gojq.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	return &Query{source: source, code: code}, nil
+}
+
+// String returns the original query
+func (q *Query) String() string {
+	return q.source
+}
+
+// Run evaluates the query against v, which is first converted to its JSON
+// representation so the query sees the same field names as JSON output.
+// It returns every value the query yields, stopping at the first error.
+func (q *Query) Run(v any) ([]any, error) {
+	input, err := toInput(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []any
+	iter := q.code.Run(input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			if err, ok := err.(*gojq.HaltError); ok && err.Value() == nil {
+				break
+			}
+			return values, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func toInput(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var input any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
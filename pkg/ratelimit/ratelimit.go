@@ -0,0 +1,230 @@
+// Package ratelimit limits requests per client with token buckets, so a public
+// server can't be used to flood WHOIS servers and resolvers. Batch crawls use
+// the same buckets per lookup target.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long an unused client bucket is kept
+const idleTimeout = 10 * time.Minute
+
+// Limiter holds one token bucket per client key
+type Limiter struct {
+	qps   rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	clients map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// New creates a limiter refilling qps tokens per second up to burst
+func New(qps float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		qps:     rate.Limit(qps),
+		burst:   burst,
+		clients: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+// Burst is the most tokens a single request may take
+func (l *Limiter) Burst() int {
+	return l.burst
+}
+
+// Allow takes n tokens from the client's bucket. When there aren't enough, it
+// takes none and returns how long the client should wait before retrying.
+func (l *Limiter) Allow(key string, n int) (bool, time.Duration) {
+	if n > l.burst {
+		return false, 0
+	}
+	now := time.Now()
+
+	l.mu.Lock()
+	l.sweep(now)
+	b, ok := l.clients[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.qps, l.burst)}
+		l.clients[key] = b
+	}
+	b.seen = now
+	l.mu.Unlock()
+
+	r := b.limiter.ReserveN(now, n)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// Wait blocks until a token is available in the client's bucket or ctx is done
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	for {
+		ok, delay := l.Allow(key, 1)
+		if ok {
+			return nil
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// sweep drops idle buckets once a minute; l.mu must be held
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < time.Minute {
+		return
+	}
+	for key, b := range l.clients {
+		if now.Sub(b.seen) > idleTimeout {
+			delete(l.clients, key)
+		}
+	}
+	l.swept = now
+}
+
+// Global is a single token bucket shared by all outbound queries of a
+// process, whatever their target. A nil Global doesn't limit.
+type Global struct {
+	limiter *rate.Limiter
+}
+
+// NewGlobal creates a bucket refilling qps tokens per second, with a
+// second's worth of burst. It returns nil when qps isn't positive.
+func NewGlobal(qps float64) *Global {
+	if qps <= 0 {
+		return nil
+	}
+	return &Global{limiter: rate.NewLimiter(rate.Limit(qps), max(1, int(qps)))}
+}
+
+// Wait blocks until a token is available or ctx is done
+func (g *Global) Wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+	return g.limiter.Wait(ctx)
+}
+
+// Transport waits for a token before every request sent with base
+func (g *Global) Transport(base http.RoundTripper) http.RoundTripper {
+	if g == nil {
+		return base
+	}
+	return &globalTransport{global: g, base: base}
+}
+
+// DialContext returns dial waiting for a token before every connection it
+// opens. A nil dial dials directly; a nil Global returns dial as is.
+func (g *Global) DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if g == nil {
+		return dial
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := g.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+type globalTransport struct {
+	global *Global
+	base   http.RoundTripper
+}
+
+func (t *globalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.global.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// RetryAfter formats a delay for the Retry-After header, in whole seconds
+func RetryAfter(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// ParseProxies parses the addresses and CIDR prefixes of trusted proxies
+func ParseProxies(specs []string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, spec := range specs {
+		prefix, err := netip.ParsePrefix(spec)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(spec)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid proxy %q: not an address or prefix", spec)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// ClientIP returns the address of the client. When the request comes from
+// one of the trusted proxies, X-Forwarded-For is read from the right,
+// skipping the trusted proxies: the nearest untrusted hop is the client,
+// as whatever is further left was sent by the client itself.
+func ClientIP(r *http.Request, proxies []netip.Prefix) string {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+	if !trusted(client, proxies) {
+		return client
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		client = hop
+		if !trusted(hop, proxies) {
+			break
+		}
+	}
+	return client
+}
+
+// trusted reports whether addr is one of the proxies
+func trusted(addr string, proxies []netip.Prefix) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	return slices.ContainsFunc(proxies, func(p netip.Prefix) bool { return p.Contains(ip) })
+}
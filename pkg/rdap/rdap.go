@@ -0,0 +1,233 @@
+// Package rdap is a minimal client for the Registration Data Access Protocol.
+//
+// Queries go through the rdap.org bootstrap redirector, which forwards each
+// request to the authoritative registry or RIR.
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+)
+
+const bootstrapURL = "https://rdap.org"
+
+// ianaBootstrapURL is IANA's RDAP bootstrap registry for domains (RFC 9224)
+const ianaBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// Object is an RDAP domain or IP network response
+type Object struct {
+	ObjectClass string   `json:"objectClassName"`
+	Handle      string   `json:"handle"`
+	Name        string   `json:"name"`
+	LDHName     string   `json:"ldhName"`
+	Status      []string `json:"status"`
+	Port43      string   `json:"port43"`
+	Entities    []Entity `json:"entities"`
+	Events      []Event  `json:"events"`
+}
+
+// Entity is a contact or organization attached to an object
+type Entity struct {
+	Handle    string          `json:"handle"`
+	Roles     []string        `json:"roles"`
+	VCard     json.RawMessage `json:"vcardArray"`
+	PublicIDs []PublicID      `json:"publicIds"`
+	Entities  []Entity        `json:"entities"`
+}
+
+// PublicID is an identifier of an entity issued by someone else than the
+// registry, such as an organization number
+type PublicID struct {
+	Type       string `json:"type"`
+	Identifier string `json:"identifier"`
+}
+
+// Event is a lifecycle event such as registration or expiration
+type Event struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+// Client performs RDAP queries
+type Client struct {
+	HTTP *http.Client
+
+	bootstrapOnce sync.Once
+	bootstrap     map[string][]string // TLD -> RDAP base URLs
+	bootstrapErr  error
+}
+
+func NewClient() *Client {
+	return &Client{HTTP: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Domain looks up a domain name
+func (c *Client) Domain(name string) (*Object, error) {
+	return c.get(bootstrapURL + "/domain/" + url.PathEscape(domain.Canonical(name)))
+}
+
+// IP looks up the network containing an IP address
+func (c *Client) IP(ip string) (*Object, error) {
+	return c.get(bootstrapURL + "/ip/" + url.PathEscape(ip))
+}
+
+// Servers returns the RDAP base URLs of a TLD's registry from IANA's
+// bootstrap registry, or nil when the registry runs no RDAP service. The
+// registry is fetched once per client.
+func (c *Client) Servers(tld string) ([]string, error) {
+	c.bootstrapOnce.Do(func() {
+		c.bootstrap, c.bootstrapErr = c.fetchBootstrap()
+	})
+	if c.bootstrapErr != nil {
+		return nil, c.bootstrapErr
+	}
+	return c.bootstrap[domain.Canonical(strings.TrimPrefix(tld, "."))], nil
+}
+
+func (c *Client) fetchBootstrap() (map[string][]string, error) {
+	resp, err := c.HTTP.Get(ianaBootstrapURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDAP bootstrap: HTTP %d", resp.StatusCode)
+	}
+
+	// Each service is a pair of a TLD list and a URL list
+	var registry struct {
+		Services [][2][]string `json:"services"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 5<<20)).Decode(&registry); err != nil {
+		return nil, fmt.Errorf("RDAP bootstrap: %v", err)
+	}
+	servers := make(map[string][]string)
+	for _, service := range registry.Services {
+		for _, tld := range service[0] {
+			servers[strings.ToLower(tld)] = service[1]
+		}
+	}
+	return servers, nil
+}
+
+func (c *Client) get(u string) (*Object, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("not found in RDAP")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDAP: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return nil, err
+	}
+	obj := &Object{}
+	if err := json.Unmarshal(body, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// FindRole returns all entities (searched recursively) that have the given role
+func (o *Object) FindRole(role string) []Entity {
+	return findRole(o.Entities, role)
+}
+
+func findRole(entities []Entity, role string) []Entity {
+	var found []Entity
+	for _, e := range entities {
+		for _, r := range e.Roles {
+			if strings.EqualFold(r, role) {
+				found = append(found, e)
+				break
+			}
+		}
+		found = append(found, findRole(e.Entities, role)...)
+	}
+	return found
+}
+
+// RegistrantOrgNumber returns the organization number among the public
+// IDs of the registrants, which some registries publish over RDAP only
+// (Norid); "" when there is none
+func (o *Object) RegistrantOrgNumber() string {
+	for _, e := range o.FindRole("registrant") {
+		for _, id := range e.PublicIDs {
+			if strings.Contains(strings.ToLower(id.Type), "organi") && id.Identifier != "" {
+				return id.Identifier
+			}
+		}
+	}
+	return ""
+}
+
+// EventDate returns the date of the first event with the given action
+func (o *Object) EventDate(action string) string {
+	for _, e := range o.Events {
+		if e.Action == action {
+			return e.Date
+		}
+	}
+	return ""
+}
+
+// Contact holds the commonly used vCard properties of an entity
+type Contact struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+// Contact decodes the entity's jCard (RFC 7095)
+func (e *Entity) Contact() Contact {
+	var c Contact
+	var card []json.RawMessage
+	if json.Unmarshal(e.VCard, &card) != nil || len(card) < 2 {
+		return c
+	}
+	var props [][]json.RawMessage
+	if json.Unmarshal(card[1], &props) != nil {
+		return c
+	}
+	for _, prop := range props {
+		if len(prop) < 4 {
+			continue
+		}
+		var name string
+		json.Unmarshal(prop[0], &name)
+		var value string
+		if json.Unmarshal(prop[3], &value) != nil {
+			continue
+		}
+		switch name {
+		case "fn":
+			c.Name = value
+		case "email":
+			c.Email = value
+		case "tel":
+			c.Phone = strings.TrimPrefix(value, "tel:")
+		}
+	}
+	return c
+}
@@ -0,0 +1,114 @@
+// Package reach checks that the addresses a domain publishes accept
+// connections.
+package reach
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultPorts are the ports tried, HTTPS and HTTP
+var DefaultPorts = []int{443, 80}
+
+// Result is whether one address accepts connections
+type Result struct {
+	IP string `json:"ip"`
+	// Open are the ports that accepted a connection
+	Open []int `json:"open,omitempty"`
+	// Error is why the first port failed when none accepted
+	Error string `json:"error,omitempty"`
+	// NoRoute is set when this host has no route to the address, e.g. no
+	// IPv6 connectivity, so it says nothing about the address itself
+	NoRoute bool `json:"no_route,omitempty"`
+}
+
+// Reachable reports whether any port accepted a connection
+func (r *Result) Reachable() bool {
+	return len(r.Open) > 0
+}
+
+// Checker connects to addresses over TCP
+type Checker struct {
+	Timeout time.Duration
+	Ports   []int
+	// DialContext connects to addresses, e.g. through a proxy; nil dials
+	// directly
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func NewChecker() *Checker {
+	return &Checker{Timeout: 5 * time.Second, Ports: DefaultPorts}
+}
+
+// Check connects to every port of an IP concurrently. Dialing directly, an
+// IP this host has no route to isn't tried.
+func (c *Checker) Check(ip string) *Result {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	dial := c.DialContext
+	if dial == nil {
+		if !hasRoute(ip) {
+			return &Result{IP: ip, Error: "no route from this host", NoRoute: true}
+		}
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	errs := make([]error, len(c.Ports))
+	var wg sync.WaitGroup
+	for i, port := range c.Ports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := dial(ctx, network(ip), net.JoinHostPort(ip, strconv.Itoa(port)))
+			if err == nil {
+				conn.Close()
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	r := &Result{IP: ip}
+	for i, err := range errs {
+		if err == nil {
+			r.Open = append(r.Open, c.Ports[i])
+		}
+	}
+	if !r.Reachable() && len(errs) > 0 {
+		r.Error = errs[0].Error()
+		r.NoRoute = slices.ContainsFunc(errs, IsNoRoute)
+	}
+	return r
+}
+
+// network is "tcp6" for IPv6 addresses and "tcp4" for the others, so a
+// check of an IPv6 address tells whether it answers over IPv6
+func network(ip string) string {
+	if a, err := netip.ParseAddr(ip); err == nil && a.Is6() && !a.Is4In6() {
+		return "tcp6"
+	}
+	return "tcp4"
+}
+
+// hasRoute reports whether this host has a route to an IP. Connecting a
+// UDP socket only looks the route up, sending nothing.
+func hasRoute(ip string) bool {
+	conn, err := net.Dial("udp", net.JoinHostPort(ip, "443"))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// IsNoRoute reports whether a connection failed before leaving this host
+func IsNoRoute(err error) bool {
+	return errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EADDRNOTAVAIL)
+}
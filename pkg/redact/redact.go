@@ -0,0 +1,260 @@
+// Package redact masks sensitive data in crawl results, so reports can be
+// shared outside the organization: the registrant and contact details, the
+// addresses of internal networks and the tokens of verification records.
+package redact
+
+import (
+	"cmp"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/intel"
+)
+
+// Mask replaces redacted names, contacts and tokens
+const Mask = "[redacted]"
+
+// InternalMask replaces addresses of internal networks
+const InternalMask = "[internal]"
+
+// verificationTXT matches TXT records proving control of the domain to a
+// service, e.g. google-site-verification=..., MS=ms12345 or
+// atlassian-domain-verification=..., up to the token
+var verificationTXT = regexp.MustCompile(`(?i)^([a-z0-9_.-]*(verification|verify|validation)[a-z0-9_.-]*|ms|docusign)[=:] ?`)
+
+// cgnat is the shared address space of carrier-grade NAT, RFC 6598
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// Result masks the sensitive data of a result and of its root domain's, in
+// place
+func Result(r *crawler.Result) {
+	if r == nil {
+		return
+	}
+	if w := r.Whois; w != nil && w.Info != nil {
+		for _, field := range []*string{&w.Registrant, &w.RegistrantOrgNumber} {
+			if *field != "" {
+				*field = Mask
+			}
+		}
+		for i := range w.TechContacts {
+			c := &w.TechContacts[i]
+			for _, field := range []*string{&c.Handle, &c.Name, &c.Email, &c.Phone} {
+				if *field != "" {
+					*field = Mask
+				}
+			}
+		}
+	}
+	if o := organization(r); o != nil {
+		for _, field := range []*string{&o.Number, &o.Name, &o.Address} {
+			if *field != "" {
+				*field = Mask
+			}
+		}
+	}
+	if records := r.Records; records != nil {
+		for i := range records.TXT {
+			records.TXT[i].Value = TXT(records.TXT[i].Value)
+		}
+		for i := range records.Other["TXT"] {
+			records.Other["TXT"][i].Value = TXT(records.Other["TXT"][i].Value)
+		}
+	}
+	walkStrings(reflect.ValueOf(r).Elem(), internalAddresses)
+	Result(r.Root)
+}
+
+// Value masks, in place, the sensitive data of r quoted in output derived
+// from it, such as a scorecard, an audit report or alerts, and the internal
+// addresses. v is a pointer or a slice; r itself is left as is.
+func Value(v any, r *crawler.Result) {
+	found := secrets(r)
+	walkStrings(reflect.ValueOf(v), func(s string) string {
+		for _, secret := range found {
+			s = strings.ReplaceAll(s, secret, Mask)
+		}
+		return internalAddresses(s)
+	})
+}
+
+// secrets returns the values Result masks in r and its root domain's,
+// longest first so a value containing another is masked whole
+func secrets(r *crawler.Result) []string {
+	var found []string
+	add := func(values ...string) {
+		for _, v := range values {
+			// Too short to mask without hitting unrelated text
+			if len(v) >= 4 {
+				found = append(found, v)
+			}
+		}
+	}
+	for ; r != nil; r = r.Root {
+		if w := r.Whois; w != nil && w.Info != nil {
+			add(w.Registrant, w.RegistrantOrgNumber)
+			for _, c := range w.TechContacts {
+				add(c.Handle, c.Name, c.Email, c.Phone)
+			}
+		}
+		if o := organization(r); o != nil {
+			add(o.Number, o.Name, o.Address)
+		}
+		if records := r.Records; records != nil {
+			for _, txt := range append(slices.Clone(records.TXT), records.Other["TXT"]...) {
+				unquoted := strings.Trim(txt.Value, `"`)
+				if m := verificationTXT.FindStringIndex(unquoted); m != nil {
+					add(unquoted[m[1]:])
+				}
+			}
+		}
+	}
+	slices.SortFunc(found, func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+	return slices.Compact(found)
+}
+
+// organization returns the registrant's entry in the business registry,
+// if the crawl found it
+func organization(r *crawler.Result) *intel.Organization {
+	if r.Whois == nil || r.Whois.Organization == nil {
+		return nil
+	}
+	return r.Whois.Organization.Organization
+}
+
+// TXT masks the token of a verification record, keeping the service it
+// is for. Values in presentation format keep their quotes.
+func TXT(value string) string {
+	quote := ""
+	if strings.HasPrefix(value, `"`) {
+		quote = `"`
+	}
+	unquoted := strings.Trim(value, `"`)
+	m := verificationTXT.FindStringIndex(unquoted)
+	if m == nil || m[1] == len(unquoted) {
+		return value
+	}
+	return quote + unquoted[:m[1]] + Mask + quote
+}
+
+// Line masks the internal addresses and verification tokens of a record
+// in zone-file format, as printed by --raw
+func Line(line string) string {
+	fields := strings.Split(line, "\t")
+	if len(fields) == 5 && fields[3] == "TXT" {
+		fields[4] = TXT(fields[4])
+	}
+	return internalAddresses(strings.Join(fields, "\t"))
+}
+
+// internalAddresses masks the private, loopback, link-local and CGNAT
+// addresses in s, alone or within a message such as an error
+func internalAddresses(s string) string {
+	if !strings.ContainsAny(s, ".:") {
+		return s
+	}
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		// A trailing period or colon ends a sentence or precedes a message
+		token := strings.TrimRight(s[start:end], ".:")
+		switch {
+		case internal(token):
+			b.WriteString(InternalMask)
+		case strings.Contains(token, ":") && !strings.Contains(token, "::"):
+			// An IPv4 address behind a prefix such as SPF's ip4:
+			prefix, addr, _ := strings.Cut(token, ":")
+			if internal(addr) {
+				addr = InternalMask
+			}
+			b.WriteString(prefix + ":" + addr)
+		default:
+			b.WriteString(token)
+		}
+		b.WriteString(s[start+len(token) : end])
+		start = -1
+	}
+	for i, c := range s {
+		if isAddressChar(c) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		b.WriteRune(c)
+	}
+	flush(len(s))
+	return b.String()
+}
+
+func isAddressChar(c rune) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' || c == '.' || c == ':' || c == '/'
+}
+
+// internal reports whether token is an internal address, an address with
+// a port or a network
+func internal(token string) bool {
+	var addr netip.Addr
+	if a, err := netip.ParseAddr(token); err == nil {
+		addr = a
+	} else if ap, err := netip.ParseAddrPort(token); err == nil {
+		addr = ap.Addr()
+	} else if p, err := netip.ParsePrefix(token); err == nil {
+		addr = p.Masked().Addr()
+	} else {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() || cgnat.Contains(addr)
+}
+
+// walkStrings applies fix to every exported string reachable from v,
+// including map keys
+func walkStrings(v reflect.Value, fix func(string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(fix(v.String()))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			walkStrings(v.Elem(), fix)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				walkStrings(v.Field(i), fix)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			walkStrings(v.Index(i), fix)
+		}
+	case reflect.Map:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		fixed := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := reflect.New(v.Type().Key()).Elem()
+			key.Set(iter.Key())
+			walkStrings(key, fix)
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			walkStrings(value, fix)
+			fixed.SetMapIndex(key, value)
+		}
+		v.Set(fixed)
+	}
+}
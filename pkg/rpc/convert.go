@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/rpc/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto converts a crawl result to its protobuf message. The complete
+// result is also included as JSON, for sections without a typed message.
+func ToProto(r *crawler.Result) (*pb.Result, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	out := toProto(r)
+	out.Json = string(data)
+	return out, nil
+}
+
+func toProto(r *crawler.Result) *pb.Result {
+	out := &pb.Result{
+		Domain:     r.Domain,
+		Registered: r.Registered,
+	}
+	if r.Root != nil {
+		out.Root = toProto(r.Root)
+	}
+
+	if s := r.Whois; s != nil {
+		w := &pb.Whois{Error: s.Error, NewlyRegistered: s.NewlyRegistered}
+		if s.Info != nil {
+			w.Registrar = s.Registrar
+			w.Registry = s.Registry
+			w.Created = s.Created
+			w.Updated = s.Updated
+			w.Expires = s.Expires
+			w.Status = s.Info.Status
+			w.Registrant = s.Registrant
+			w.NameServers = s.NameServers
+			if s.DaysToExpiry != nil {
+				w.DaysToExpiry = proto.Int32(int32(*s.DaysToExpiry))
+			}
+			if s.AgeDays != nil {
+				w.AgeDays = proto.Int32(int32(*s.AgeDays))
+			}
+		}
+		out.Whois = w
+	}
+
+	if s := r.Nameservers; s != nil {
+		ns := &pb.Nameservers{Error: s.Error}
+		for _, server := range s.Servers {
+			ns.Servers = append(ns.Servers, &pb.Nameserver{
+				Name:     server.Name,
+				Ip:       server.IP,
+				Provider: server.Provider,
+				Asn:      server.ASN,
+			})
+		}
+		out.Nameservers = ns
+	}
+
+	if s := r.Records; s != nil {
+		out.Records = &pb.Records{
+			Error: s.Error,
+			A:     records(s.A),
+			Aaaa:  records(s.AAAA),
+			Cname: records(s.CNAME),
+			Mx:    records(s.MX),
+			Txt:   records(s.TXT),
+		}
+	}
+
+	if s := r.Email; s != nil {
+		out.Email = &pb.Email{Error: s.Error, Spf: s.SPF, Dmarc: s.DMARC}
+	}
+
+	if s := r.TLS; s != nil {
+		t := &pb.TLS{Error: s.Error}
+		if s.Info != nil {
+			t.Subject = s.Subject
+			t.Issuer = s.Issuer
+			t.NotBefore = timestamppb.New(s.NotBefore)
+			t.NotAfter = timestamppb.New(s.NotAfter)
+			t.DaysToExpiry = int32(s.DaysToExpiry)
+			t.DnsNames = s.DNSNames
+			t.Version = s.Version
+			t.Valid = s.Valid
+			t.VerifyError = s.VerifyError
+		}
+		out.Tls = t
+	}
+
+	if s := r.DNSSEC; s != nil {
+		d := &pb.DNSSEC{Error: s.Error, Signed: s.Signed}
+		if s.DNSSEC != nil {
+			d.Validated = s.Validated
+			d.Ds = s.DS
+			d.Algorithms = s.Algorithms
+		}
+		out.Dnssec = d
+	}
+
+	if s := r.CAA; s != nil {
+		out.Caa = &pb.CAA{Error: s.Error, Name: s.Name, Records: s.Records}
+	}
+	if s := r.Reputation; s != nil {
+		out.Reputation = &pb.Reputation{Error: s.Error, Malicious: s.Malicious}
+	}
+	if s := r.Blocklists; s != nil {
+		out.Blocklists = &pb.Blocklists{Error: s.Error, Listed: s.Listed}
+	}
+	return out
+}
+
+func records(in []crawler.Record) []*pb.Record {
+	var out []*pb.Record
+	for _, r := range in {
+		out = append(out, &pb.Record{Value: r.Value, Provider: r.Provider, Ptr: r.PTR, Asn: r.ASN})
+	}
+	return out
+}
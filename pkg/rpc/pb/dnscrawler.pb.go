@@ -0,0 +1,1580 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: dnscrawler/v1/dnscrawler.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Options selects the optional checks, mirroring the command line flags.
+// Unlike on the command line, the slow DNS trace is opt-in.
+type Options struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	NoWhois     bool                   `protobuf:"varint,1,opt,name=no_whois,json=noWhois,proto3" json:"no_whois,omitempty"`
+	Trace       bool                   `protobuf:"varint,2,opt,name=trace,proto3" json:"trace,omitempty"`
+	Tls         bool                   `protobuf:"varint,3,opt,name=tls,proto3" json:"tls,omitempty"`
+	Deps        bool                   `protobuf:"varint,4,opt,name=deps,proto3" json:"deps,omitempty"`
+	Soa         bool                   `protobuf:"varint,5,opt,name=soa,proto3" json:"soa,omitempty"`
+	Recursion   bool                   `protobuf:"varint,6,opt,name=recursion,proto3" json:"recursion,omitempty"`
+	Consistency bool                   `protobuf:"varint,7,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	Nxdomain    bool                   `protobuf:"varint,8,opt,name=nxdomain,proto3" json:"nxdomain,omitempty"`
+	Reputation  bool                   `protobuf:"varint,9,opt,name=reputation,proto3" json:"reputation,omitempty"`
+	Blocklists  bool                   `protobuf:"varint,10,opt,name=blocklists,proto3" json:"blocklists,omitempty"`
+	Dnssec      bool                   `protobuf:"varint,11,opt,name=dnssec,proto3" json:"dnssec,omitempty"`
+	Caa         bool                   `protobuf:"varint,12,opt,name=caa,proto3" json:"caa,omitempty"`
+	// Age in days below which a domain is flagged as newly registered; 0 uses the default.
+	NewDomainDays int32 `protobuf:"varint,13,opt,name=new_domain_days,json=newDomainDays,proto3" json:"new_domain_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Options) GetNoWhois() bool {
+	if x != nil {
+		return x.NoWhois
+	}
+	return false
+}
+
+func (x *Options) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+func (x *Options) GetTls() bool {
+	if x != nil {
+		return x.Tls
+	}
+	return false
+}
+
+func (x *Options) GetDeps() bool {
+	if x != nil {
+		return x.Deps
+	}
+	return false
+}
+
+func (x *Options) GetSoa() bool {
+	if x != nil {
+		return x.Soa
+	}
+	return false
+}
+
+func (x *Options) GetRecursion() bool {
+	if x != nil {
+		return x.Recursion
+	}
+	return false
+}
+
+func (x *Options) GetConsistency() bool {
+	if x != nil {
+		return x.Consistency
+	}
+	return false
+}
+
+func (x *Options) GetNxdomain() bool {
+	if x != nil {
+		return x.Nxdomain
+	}
+	return false
+}
+
+func (x *Options) GetReputation() bool {
+	if x != nil {
+		return x.Reputation
+	}
+	return false
+}
+
+func (x *Options) GetBlocklists() bool {
+	if x != nil {
+		return x.Blocklists
+	}
+	return false
+}
+
+func (x *Options) GetDnssec() bool {
+	if x != nil {
+		return x.Dnssec
+	}
+	return false
+}
+
+func (x *Options) GetCaa() bool {
+	if x != nil {
+		return x.Caa
+	}
+	return false
+}
+
+func (x *Options) GetNewDomainDays() int32 {
+	if x != nil {
+		return x.NewDomainDays
+	}
+	return 0
+}
+
+type CrawlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	Options       *Options               `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlRequest) Reset() {
+	*x = CrawlRequest{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlRequest) ProtoMessage() {}
+
+func (x *CrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlRequest.ProtoReflect.Descriptor instead.
+func (*CrawlRequest) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CrawlRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *CrawlRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type CrawlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlResponse) Reset() {
+	*x = CrawlResponse{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlResponse) ProtoMessage() {}
+
+func (x *CrawlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlResponse.ProtoReflect.Descriptor instead.
+func (*CrawlResponse) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CrawlResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Domains []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	Options *Options               `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// Seconds between crawls; the server enforces a minimum.
+	IntervalSeconds int32 `protobuf:"varint,3,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// What changed since the previous crawl; empty for the first crawl.
+	Changes       []string               `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetChanges() []string {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Result mirrors the JSON output. Sections without a typed message are only
+// available in json, the complete result as printed by -o json.
+type Result struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Domain     string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Registered bool                   `protobuf:"varint,2,opt,name=registered,proto3" json:"registered,omitempty"`
+	// The registrable domain's result when domain is a subdomain.
+	Root          *Result      `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	Whois         *Whois       `protobuf:"bytes,4,opt,name=whois,proto3" json:"whois,omitempty"`
+	Nameservers   *Nameservers `protobuf:"bytes,5,opt,name=nameservers,proto3" json:"nameservers,omitempty"`
+	Records       *Records     `protobuf:"bytes,6,opt,name=records,proto3" json:"records,omitempty"`
+	Email         *Email       `protobuf:"bytes,7,opt,name=email,proto3" json:"email,omitempty"`
+	Tls           *TLS         `protobuf:"bytes,8,opt,name=tls,proto3" json:"tls,omitempty"`
+	Dnssec        *DNSSEC      `protobuf:"bytes,9,opt,name=dnssec,proto3" json:"dnssec,omitempty"`
+	Caa           *CAA         `protobuf:"bytes,10,opt,name=caa,proto3" json:"caa,omitempty"`
+	Reputation    *Reputation  `protobuf:"bytes,11,opt,name=reputation,proto3" json:"reputation,omitempty"`
+	Blocklists    *Blocklists  `protobuf:"bytes,12,opt,name=blocklists,proto3" json:"blocklists,omitempty"`
+	Json          string       `protobuf:"bytes,100,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Result) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Result) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+func (x *Result) GetRoot() *Result {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *Result) GetWhois() *Whois {
+	if x != nil {
+		return x.Whois
+	}
+	return nil
+}
+
+func (x *Result) GetNameservers() *Nameservers {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *Result) GetRecords() *Records {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *Result) GetEmail() *Email {
+	if x != nil {
+		return x.Email
+	}
+	return nil
+}
+
+func (x *Result) GetTls() *TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Result) GetDnssec() *DNSSEC {
+	if x != nil {
+		return x.Dnssec
+	}
+	return nil
+}
+
+func (x *Result) GetCaa() *CAA {
+	if x != nil {
+		return x.Caa
+	}
+	return nil
+}
+
+func (x *Result) GetReputation() *Reputation {
+	if x != nil {
+		return x.Reputation
+	}
+	return nil
+}
+
+func (x *Result) GetBlocklists() *Blocklists {
+	if x != nil {
+		return x.Blocklists
+	}
+	return nil
+}
+
+func (x *Result) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+type Whois struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when the lookup failed; the other fields are then empty.
+	Error           string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Registrar       string   `protobuf:"bytes,2,opt,name=registrar,proto3" json:"registrar,omitempty"`
+	Registry        string   `protobuf:"bytes,3,opt,name=registry,proto3" json:"registry,omitempty"`
+	Created         string   `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	Updated         string   `protobuf:"bytes,5,opt,name=updated,proto3" json:"updated,omitempty"`
+	Expires         string   `protobuf:"bytes,6,opt,name=expires,proto3" json:"expires,omitempty"`
+	Status          []string `protobuf:"bytes,7,rep,name=status,proto3" json:"status,omitempty"`
+	Registrant      string   `protobuf:"bytes,8,opt,name=registrant,proto3" json:"registrant,omitempty"`
+	NameServers     []string `protobuf:"bytes,9,rep,name=name_servers,json=nameServers,proto3" json:"name_servers,omitempty"`
+	DaysToExpiry    *int32   `protobuf:"varint,10,opt,name=days_to_expiry,json=daysToExpiry,proto3,oneof" json:"days_to_expiry,omitempty"`
+	AgeDays         *int32   `protobuf:"varint,11,opt,name=age_days,json=ageDays,proto3,oneof" json:"age_days,omitempty"`
+	NewlyRegistered bool     `protobuf:"varint,12,opt,name=newly_registered,json=newlyRegistered,proto3" json:"newly_registered,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Whois) Reset() {
+	*x = Whois{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Whois) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Whois) ProtoMessage() {}
+
+func (x *Whois) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Whois.ProtoReflect.Descriptor instead.
+func (*Whois) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Whois) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Whois) GetRegistrar() string {
+	if x != nil {
+		return x.Registrar
+	}
+	return ""
+}
+
+func (x *Whois) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *Whois) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *Whois) GetUpdated() string {
+	if x != nil {
+		return x.Updated
+	}
+	return ""
+}
+
+func (x *Whois) GetExpires() string {
+	if x != nil {
+		return x.Expires
+	}
+	return ""
+}
+
+func (x *Whois) GetStatus() []string {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *Whois) GetRegistrant() string {
+	if x != nil {
+		return x.Registrant
+	}
+	return ""
+}
+
+func (x *Whois) GetNameServers() []string {
+	if x != nil {
+		return x.NameServers
+	}
+	return nil
+}
+
+func (x *Whois) GetDaysToExpiry() int32 {
+	if x != nil && x.DaysToExpiry != nil {
+		return *x.DaysToExpiry
+	}
+	return 0
+}
+
+func (x *Whois) GetAgeDays() int32 {
+	if x != nil && x.AgeDays != nil {
+		return *x.AgeDays
+	}
+	return 0
+}
+
+func (x *Whois) GetNewlyRegistered() bool {
+	if x != nil {
+		return x.NewlyRegistered
+	}
+	return false
+}
+
+type Nameservers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Servers       []*Nameserver          `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nameservers) Reset() {
+	*x = Nameservers{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nameservers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nameservers) ProtoMessage() {}
+
+func (x *Nameservers) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nameservers.ProtoReflect.Descriptor instead.
+func (*Nameservers) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Nameservers) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Nameservers) GetServers() []*Nameserver {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type Nameserver struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Asn           string                 `protobuf:"bytes,4,opt,name=asn,proto3" json:"asn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nameserver) Reset() {
+	*x = Nameserver{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nameserver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nameserver) ProtoMessage() {}
+
+func (x *Nameserver) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nameserver.ProtoReflect.Descriptor instead.
+func (*Nameserver) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Nameserver) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Nameserver) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Nameserver) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Nameserver) GetAsn() string {
+	if x != nil {
+		return x.Asn
+	}
+	return ""
+}
+
+type Records struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	A             []*Record              `protobuf:"bytes,2,rep,name=a,proto3" json:"a,omitempty"`
+	Aaaa          []*Record              `protobuf:"bytes,3,rep,name=aaaa,proto3" json:"aaaa,omitempty"`
+	Cname         []*Record              `protobuf:"bytes,4,rep,name=cname,proto3" json:"cname,omitempty"`
+	Mx            []*Record              `protobuf:"bytes,5,rep,name=mx,proto3" json:"mx,omitempty"`
+	Txt           []*Record              `protobuf:"bytes,6,rep,name=txt,proto3" json:"txt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Records) Reset() {
+	*x = Records{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Records) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Records) ProtoMessage() {}
+
+func (x *Records) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Records.ProtoReflect.Descriptor instead.
+func (*Records) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Records) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Records) GetA() []*Record {
+	if x != nil {
+		return x.A
+	}
+	return nil
+}
+
+func (x *Records) GetAaaa() []*Record {
+	if x != nil {
+		return x.Aaaa
+	}
+	return nil
+}
+
+func (x *Records) GetCname() []*Record {
+	if x != nil {
+		return x.Cname
+	}
+	return nil
+}
+
+func (x *Records) GetMx() []*Record {
+	if x != nil {
+		return x.Mx
+	}
+	return nil
+}
+
+func (x *Records) GetTxt() []*Record {
+	if x != nil {
+		return x.Txt
+	}
+	return nil
+}
+
+type Record struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Ptr           string                 `protobuf:"bytes,3,opt,name=ptr,proto3" json:"ptr,omitempty"`
+	Asn           string                 `protobuf:"bytes,4,opt,name=asn,proto3" json:"asn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Record) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Record) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Record) GetPtr() string {
+	if x != nil {
+		return x.Ptr
+	}
+	return ""
+}
+
+func (x *Record) GetAsn() string {
+	if x != nil {
+		return x.Asn
+	}
+	return ""
+}
+
+type Email struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Spf           string                 `protobuf:"bytes,2,opt,name=spf,proto3" json:"spf,omitempty"`
+	Dmarc         string                 `protobuf:"bytes,3,opt,name=dmarc,proto3" json:"dmarc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Email) Reset() {
+	*x = Email{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Email) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Email) ProtoMessage() {}
+
+func (x *Email) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Email.ProtoReflect.Descriptor instead.
+func (*Email) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Email) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Email) GetSpf() string {
+	if x != nil {
+		return x.Spf
+	}
+	return ""
+}
+
+func (x *Email) GetDmarc() string {
+	if x != nil {
+		return x.Dmarc
+	}
+	return ""
+}
+
+type TLS struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer        string                 `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	NotBefore     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	DaysToExpiry  int32                  `protobuf:"varint,6,opt,name=days_to_expiry,json=daysToExpiry,proto3" json:"days_to_expiry,omitempty"`
+	DnsNames      []string               `protobuf:"bytes,7,rep,name=dns_names,json=dnsNames,proto3" json:"dns_names,omitempty"`
+	Version       string                 `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+	Valid         bool                   `protobuf:"varint,9,opt,name=valid,proto3" json:"valid,omitempty"`
+	VerifyError   string                 `protobuf:"bytes,10,opt,name=verify_error,json=verifyError,proto3" json:"verify_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TLS) Reset() {
+	*x = TLS{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLS) ProtoMessage() {}
+
+func (x *TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLS.ProtoReflect.Descriptor instead.
+func (*TLS) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TLS) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TLS) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *TLS) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *TLS) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *TLS) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *TLS) GetDaysToExpiry() int32 {
+	if x != nil {
+		return x.DaysToExpiry
+	}
+	return 0
+}
+
+func (x *TLS) GetDnsNames() []string {
+	if x != nil {
+		return x.DnsNames
+	}
+	return nil
+}
+
+func (x *TLS) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *TLS) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *TLS) GetVerifyError() string {
+	if x != nil {
+		return x.VerifyError
+	}
+	return ""
+}
+
+type DNSSEC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Signed        bool                   `protobuf:"varint,2,opt,name=signed,proto3" json:"signed,omitempty"`
+	Validated     bool                   `protobuf:"varint,3,opt,name=validated,proto3" json:"validated,omitempty"`
+	Ds            []string               `protobuf:"bytes,4,rep,name=ds,proto3" json:"ds,omitempty"`
+	Algorithms    []string               `protobuf:"bytes,5,rep,name=algorithms,proto3" json:"algorithms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DNSSEC) Reset() {
+	*x = DNSSEC{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSSEC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSSEC) ProtoMessage() {}
+
+func (x *DNSSEC) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSSEC.ProtoReflect.Descriptor instead.
+func (*DNSSEC) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DNSSEC) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DNSSEC) GetSigned() bool {
+	if x != nil {
+		return x.Signed
+	}
+	return false
+}
+
+func (x *DNSSEC) GetValidated() bool {
+	if x != nil {
+		return x.Validated
+	}
+	return false
+}
+
+func (x *DNSSEC) GetDs() []string {
+	if x != nil {
+		return x.Ds
+	}
+	return nil
+}
+
+func (x *DNSSEC) GetAlgorithms() []string {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+type CAA struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Error string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// Where the records were found, which may be a parent of the domain.
+	Name          string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Records       []string `protobuf:"bytes,3,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CAA) Reset() {
+	*x = CAA{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CAA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CAA) ProtoMessage() {}
+
+func (x *CAA) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CAA.ProtoReflect.Descriptor instead.
+func (*CAA) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CAA) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CAA) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CAA) GetRecords() []string {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type Reputation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Malicious     bool                   `protobuf:"varint,2,opt,name=malicious,proto3" json:"malicious,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reputation) Reset() {
+	*x = Reputation{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reputation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reputation) ProtoMessage() {}
+
+func (x *Reputation) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reputation.ProtoReflect.Descriptor instead.
+func (*Reputation) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Reputation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Reputation) GetMalicious() bool {
+	if x != nil {
+		return x.Malicious
+	}
+	return false
+}
+
+type Blocklists struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Listed        bool                   `protobuf:"varint,2,opt,name=listed,proto3" json:"listed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Blocklists) Reset() {
+	*x = Blocklists{}
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Blocklists) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Blocklists) ProtoMessage() {}
+
+func (x *Blocklists) ProtoReflect() protoreflect.Message {
+	mi := &file_dnscrawler_v1_dnscrawler_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Blocklists.ProtoReflect.Descriptor instead.
+func (*Blocklists) Descriptor() ([]byte, []int) {
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Blocklists) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Blocklists) GetListed() bool {
+	if x != nil {
+		return x.Listed
+	}
+	return false
+}
+
+var File_dnscrawler_v1_dnscrawler_proto protoreflect.FileDescriptor
+
+const file_dnscrawler_v1_dnscrawler_proto_rawDesc = "" +
+	"\n" +
+	"\x1ednscrawler/v1/dnscrawler.proto\x12\rdnscrawler.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe0\x02\n" +
+	"\aOptions\x12\x19\n" +
+	"\bno_whois\x18\x01 \x01(\bR\anoWhois\x12\x14\n" +
+	"\x05trace\x18\x02 \x01(\bR\x05trace\x12\x10\n" +
+	"\x03tls\x18\x03 \x01(\bR\x03tls\x12\x12\n" +
+	"\x04deps\x18\x04 \x01(\bR\x04deps\x12\x10\n" +
+	"\x03soa\x18\x05 \x01(\bR\x03soa\x12\x1c\n" +
+	"\trecursion\x18\x06 \x01(\bR\trecursion\x12 \n" +
+	"\vconsistency\x18\a \x01(\bR\vconsistency\x12\x1a\n" +
+	"\bnxdomain\x18\b \x01(\bR\bnxdomain\x12\x1e\n" +
+	"\n" +
+	"reputation\x18\t \x01(\bR\n" +
+	"reputation\x12\x1e\n" +
+	"\n" +
+	"blocklists\x18\n" +
+	" \x01(\bR\n" +
+	"blocklists\x12\x16\n" +
+	"\x06dnssec\x18\v \x01(\bR\x06dnssec\x12\x10\n" +
+	"\x03caa\x18\f \x01(\bR\x03caa\x12&\n" +
+	"\x0fnew_domain_days\x18\r \x01(\x05R\rnewDomainDays\"Z\n" +
+	"\fCrawlRequest\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x120\n" +
+	"\aoptions\x18\x02 \x01(\v2\x16.dnscrawler.v1.OptionsR\aoptions\">\n" +
+	"\rCrawlResponse\x12-\n" +
+	"\x06result\x18\x01 \x01(\v2\x15.dnscrawler.v1.ResultR\x06result\"\x85\x01\n" +
+	"\fWatchRequest\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x120\n" +
+	"\aoptions\x18\x02 \x01(\v2\x16.dnscrawler.v1.OptionsR\aoptions\x12)\n" +
+	"\x10interval_seconds\x18\x03 \x01(\x05R\x0fintervalSeconds\"\x88\x01\n" +
+	"\rWatchResponse\x12-\n" +
+	"\x06result\x18\x01 \x01(\v2\x15.dnscrawler.v1.ResultR\x06result\x12\x18\n" +
+	"\achanges\x18\x02 \x03(\tR\achanges\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\xb8\x04\n" +
+	"\x06Result\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x02 \x01(\bR\n" +
+	"registered\x12)\n" +
+	"\x04root\x18\x03 \x01(\v2\x15.dnscrawler.v1.ResultR\x04root\x12*\n" +
+	"\x05whois\x18\x04 \x01(\v2\x14.dnscrawler.v1.WhoisR\x05whois\x12<\n" +
+	"\vnameservers\x18\x05 \x01(\v2\x1a.dnscrawler.v1.NameserversR\vnameservers\x120\n" +
+	"\arecords\x18\x06 \x01(\v2\x16.dnscrawler.v1.RecordsR\arecords\x12*\n" +
+	"\x05email\x18\a \x01(\v2\x14.dnscrawler.v1.EmailR\x05email\x12$\n" +
+	"\x03tls\x18\b \x01(\v2\x12.dnscrawler.v1.TLSR\x03tls\x12-\n" +
+	"\x06dnssec\x18\t \x01(\v2\x15.dnscrawler.v1.DNSSECR\x06dnssec\x12$\n" +
+	"\x03caa\x18\n" +
+	" \x01(\v2\x12.dnscrawler.v1.CAAR\x03caa\x129\n" +
+	"\n" +
+	"reputation\x18\v \x01(\v2\x19.dnscrawler.v1.ReputationR\n" +
+	"reputation\x129\n" +
+	"\n" +
+	"blocklists\x18\f \x01(\v2\x19.dnscrawler.v1.BlocklistsR\n" +
+	"blocklists\x12\x12\n" +
+	"\x04json\x18d \x01(\tR\x04json\"\x96\x03\n" +
+	"\x05Whois\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x1c\n" +
+	"\tregistrar\x18\x02 \x01(\tR\tregistrar\x12\x1a\n" +
+	"\bregistry\x18\x03 \x01(\tR\bregistry\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\tR\acreated\x12\x18\n" +
+	"\aupdated\x18\x05 \x01(\tR\aupdated\x12\x18\n" +
+	"\aexpires\x18\x06 \x01(\tR\aexpires\x12\x16\n" +
+	"\x06status\x18\a \x03(\tR\x06status\x12\x1e\n" +
+	"\n" +
+	"registrant\x18\b \x01(\tR\n" +
+	"registrant\x12!\n" +
+	"\fname_servers\x18\t \x03(\tR\vnameServers\x12)\n" +
+	"\x0edays_to_expiry\x18\n" +
+	" \x01(\x05H\x00R\fdaysToExpiry\x88\x01\x01\x12\x1e\n" +
+	"\bage_days\x18\v \x01(\x05H\x01R\aageDays\x88\x01\x01\x12)\n" +
+	"\x10newly_registered\x18\f \x01(\bR\x0fnewlyRegisteredB\x11\n" +
+	"\x0f_days_to_expiryB\v\n" +
+	"\t_age_days\"X\n" +
+	"\vNameservers\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x123\n" +
+	"\aservers\x18\x02 \x03(\v2\x19.dnscrawler.v1.NameserverR\aservers\"^\n" +
+	"\n" +
+	"Nameserver\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x10\n" +
+	"\x03asn\x18\x04 \x01(\tR\x03asn\"\xec\x01\n" +
+	"\aRecords\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12#\n" +
+	"\x01a\x18\x02 \x03(\v2\x15.dnscrawler.v1.RecordR\x01a\x12)\n" +
+	"\x04aaaa\x18\x03 \x03(\v2\x15.dnscrawler.v1.RecordR\x04aaaa\x12+\n" +
+	"\x05cname\x18\x04 \x03(\v2\x15.dnscrawler.v1.RecordR\x05cname\x12%\n" +
+	"\x02mx\x18\x05 \x03(\v2\x15.dnscrawler.v1.RecordR\x02mx\x12'\n" +
+	"\x03txt\x18\x06 \x03(\v2\x15.dnscrawler.v1.RecordR\x03txt\"^\n" +
+	"\x06Record\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x10\n" +
+	"\x03ptr\x18\x03 \x01(\tR\x03ptr\x12\x10\n" +
+	"\x03asn\x18\x04 \x01(\tR\x03asn\"E\n" +
+	"\x05Email\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x10\n" +
+	"\x03spf\x18\x02 \x01(\tR\x03spf\x12\x14\n" +
+	"\x05dmarc\x18\x03 \x01(\tR\x05dmarc\"\xd7\x02\n" +
+	"\x03TLS\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x129\n" +
+	"\n" +
+	"not_before\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tnotBefore\x127\n" +
+	"\tnot_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\x12$\n" +
+	"\x0edays_to_expiry\x18\x06 \x01(\x05R\fdaysToExpiry\x12\x1b\n" +
+	"\tdns_names\x18\a \x03(\tR\bdnsNames\x12\x18\n" +
+	"\aversion\x18\b \x01(\tR\aversion\x12\x14\n" +
+	"\x05valid\x18\t \x01(\bR\x05valid\x12!\n" +
+	"\fverify_error\x18\n" +
+	" \x01(\tR\vverifyError\"\x84\x01\n" +
+	"\x06DNSSEC\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x16\n" +
+	"\x06signed\x18\x02 \x01(\bR\x06signed\x12\x1c\n" +
+	"\tvalidated\x18\x03 \x01(\bR\tvalidated\x12\x0e\n" +
+	"\x02ds\x18\x04 \x03(\tR\x02ds\x12\x1e\n" +
+	"\n" +
+	"algorithms\x18\x05 \x03(\tR\n" +
+	"algorithms\"I\n" +
+	"\x03CAA\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\arecords\x18\x03 \x03(\tR\arecords\"@\n" +
+	"\n" +
+	"Reputation\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x1c\n" +
+	"\tmalicious\x18\x02 \x01(\bR\tmalicious\":\n" +
+	"\n" +
+	"Blocklists\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x16\n" +
+	"\x06listed\x18\x02 \x01(\bR\x06listed2\x9c\x01\n" +
+	"\x0eCrawlerService\x12D\n" +
+	"\x05Crawl\x12\x1b.dnscrawler.v1.CrawlRequest\x1a\x1c.dnscrawler.v1.CrawlResponse0\x01\x12D\n" +
+	"\x05Watch\x12\x1b.dnscrawler.v1.WatchRequest\x1a\x1c.dnscrawler.v1.WatchResponse0\x01B,Z*github.com/auduny/dnscrawler/pkg/rpc/pb;pbb\x06proto3"
+
+var (
+	file_dnscrawler_v1_dnscrawler_proto_rawDescOnce sync.Once
+	file_dnscrawler_v1_dnscrawler_proto_rawDescData []byte
+)
+
+func file_dnscrawler_v1_dnscrawler_proto_rawDescGZIP() []byte {
+	file_dnscrawler_v1_dnscrawler_proto_rawDescOnce.Do(func() {
+		file_dnscrawler_v1_dnscrawler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dnscrawler_v1_dnscrawler_proto_rawDesc), len(file_dnscrawler_v1_dnscrawler_proto_rawDesc)))
+	})
+	return file_dnscrawler_v1_dnscrawler_proto_rawDescData
+}
+
+var file_dnscrawler_v1_dnscrawler_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_dnscrawler_v1_dnscrawler_proto_goTypes = []any{
+	(*Options)(nil),               // 0: dnscrawler.v1.Options
+	(*CrawlRequest)(nil),          // 1: dnscrawler.v1.CrawlRequest
+	(*CrawlResponse)(nil),         // 2: dnscrawler.v1.CrawlResponse
+	(*WatchRequest)(nil),          // 3: dnscrawler.v1.WatchRequest
+	(*WatchResponse)(nil),         // 4: dnscrawler.v1.WatchResponse
+	(*Result)(nil),                // 5: dnscrawler.v1.Result
+	(*Whois)(nil),                 // 6: dnscrawler.v1.Whois
+	(*Nameservers)(nil),           // 7: dnscrawler.v1.Nameservers
+	(*Nameserver)(nil),            // 8: dnscrawler.v1.Nameserver
+	(*Records)(nil),               // 9: dnscrawler.v1.Records
+	(*Record)(nil),                // 10: dnscrawler.v1.Record
+	(*Email)(nil),                 // 11: dnscrawler.v1.Email
+	(*TLS)(nil),                   // 12: dnscrawler.v1.TLS
+	(*DNSSEC)(nil),                // 13: dnscrawler.v1.DNSSEC
+	(*CAA)(nil),                   // 14: dnscrawler.v1.CAA
+	(*Reputation)(nil),            // 15: dnscrawler.v1.Reputation
+	(*Blocklists)(nil),            // 16: dnscrawler.v1.Blocklists
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_dnscrawler_v1_dnscrawler_proto_depIdxs = []int32{
+	0,  // 0: dnscrawler.v1.CrawlRequest.options:type_name -> dnscrawler.v1.Options
+	5,  // 1: dnscrawler.v1.CrawlResponse.result:type_name -> dnscrawler.v1.Result
+	0,  // 2: dnscrawler.v1.WatchRequest.options:type_name -> dnscrawler.v1.Options
+	5,  // 3: dnscrawler.v1.WatchResponse.result:type_name -> dnscrawler.v1.Result
+	17, // 4: dnscrawler.v1.WatchResponse.time:type_name -> google.protobuf.Timestamp
+	5,  // 5: dnscrawler.v1.Result.root:type_name -> dnscrawler.v1.Result
+	6,  // 6: dnscrawler.v1.Result.whois:type_name -> dnscrawler.v1.Whois
+	7,  // 7: dnscrawler.v1.Result.nameservers:type_name -> dnscrawler.v1.Nameservers
+	9,  // 8: dnscrawler.v1.Result.records:type_name -> dnscrawler.v1.Records
+	11, // 9: dnscrawler.v1.Result.email:type_name -> dnscrawler.v1.Email
+	12, // 10: dnscrawler.v1.Result.tls:type_name -> dnscrawler.v1.TLS
+	13, // 11: dnscrawler.v1.Result.dnssec:type_name -> dnscrawler.v1.DNSSEC
+	14, // 12: dnscrawler.v1.Result.caa:type_name -> dnscrawler.v1.CAA
+	15, // 13: dnscrawler.v1.Result.reputation:type_name -> dnscrawler.v1.Reputation
+	16, // 14: dnscrawler.v1.Result.blocklists:type_name -> dnscrawler.v1.Blocklists
+	8,  // 15: dnscrawler.v1.Nameservers.servers:type_name -> dnscrawler.v1.Nameserver
+	10, // 16: dnscrawler.v1.Records.a:type_name -> dnscrawler.v1.Record
+	10, // 17: dnscrawler.v1.Records.aaaa:type_name -> dnscrawler.v1.Record
+	10, // 18: dnscrawler.v1.Records.cname:type_name -> dnscrawler.v1.Record
+	10, // 19: dnscrawler.v1.Records.mx:type_name -> dnscrawler.v1.Record
+	10, // 20: dnscrawler.v1.Records.txt:type_name -> dnscrawler.v1.Record
+	17, // 21: dnscrawler.v1.TLS.not_before:type_name -> google.protobuf.Timestamp
+	17, // 22: dnscrawler.v1.TLS.not_after:type_name -> google.protobuf.Timestamp
+	1,  // 23: dnscrawler.v1.CrawlerService.Crawl:input_type -> dnscrawler.v1.CrawlRequest
+	3,  // 24: dnscrawler.v1.CrawlerService.Watch:input_type -> dnscrawler.v1.WatchRequest
+	2,  // 25: dnscrawler.v1.CrawlerService.Crawl:output_type -> dnscrawler.v1.CrawlResponse
+	4,  // 26: dnscrawler.v1.CrawlerService.Watch:output_type -> dnscrawler.v1.WatchResponse
+	25, // [25:27] is the sub-list for method output_type
+	23, // [23:25] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_dnscrawler_v1_dnscrawler_proto_init() }
+func file_dnscrawler_v1_dnscrawler_proto_init() {
+	if File_dnscrawler_v1_dnscrawler_proto != nil {
+		return
+	}
+	file_dnscrawler_v1_dnscrawler_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dnscrawler_v1_dnscrawler_proto_rawDesc), len(file_dnscrawler_v1_dnscrawler_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dnscrawler_v1_dnscrawler_proto_goTypes,
+		DependencyIndexes: file_dnscrawler_v1_dnscrawler_proto_depIdxs,
+		MessageInfos:      file_dnscrawler_v1_dnscrawler_proto_msgTypes,
+	}.Build()
+	File_dnscrawler_v1_dnscrawler_proto = out.File
+	file_dnscrawler_v1_dnscrawler_proto_goTypes = nil
+	file_dnscrawler_v1_dnscrawler_proto_depIdxs = nil
+}
@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: dnscrawler/v1/dnscrawler.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CrawlerService_Crawl_FullMethodName = "/dnscrawler.v1.CrawlerService/Crawl"
+	CrawlerService_Watch_FullMethodName = "/dnscrawler.v1.CrawlerService/Watch"
+)
+
+// CrawlerServiceClient is the client API for CrawlerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Crawler runs dnscrawler lookups for other services.
+type CrawlerServiceClient interface {
+	// Crawl crawls the requested domains and streams one result per domain as
+	// soon as it is complete, in request order.
+	Crawl(ctx context.Context, in *CrawlRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlResponse], error)
+	// Watch re-crawls the requested domains every interval and streams a
+	// result whenever a domain changed since the previous crawl. The first
+	// crawl of every domain is always sent.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
+}
+
+type crawlerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCrawlerServiceClient(cc grpc.ClientConnInterface) CrawlerServiceClient {
+	return &crawlerServiceClient{cc}
+}
+
+func (c *crawlerServiceClient) Crawl(ctx context.Context, in *CrawlRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CrawlerService_ServiceDesc.Streams[0], CrawlerService_Crawl_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CrawlRequest, CrawlResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_CrawlClient = grpc.ServerStreamingClient[CrawlResponse]
+
+func (c *crawlerServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CrawlerService_ServiceDesc.Streams[1], CrawlerService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
+// CrawlerServiceServer is the server API for CrawlerService service.
+// All implementations must embed UnimplementedCrawlerServiceServer
+// for forward compatibility.
+//
+// Crawler runs dnscrawler lookups for other services.
+type CrawlerServiceServer interface {
+	// Crawl crawls the requested domains and streams one result per domain as
+	// soon as it is complete, in request order.
+	Crawl(*CrawlRequest, grpc.ServerStreamingServer[CrawlResponse]) error
+	// Watch re-crawls the requested domains every interval and streams a
+	// result whenever a domain changed since the previous crawl. The first
+	// crawl of every domain is always sent.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+// UnimplementedCrawlerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCrawlerServiceServer struct{}
+
+func (UnimplementedCrawlerServiceServer) Crawl(*CrawlRequest, grpc.ServerStreamingServer[CrawlResponse]) error {
+	return status.Error(codes.Unimplemented, "method Crawl not implemented")
+}
+func (UnimplementedCrawlerServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedCrawlerServiceServer) mustEmbedUnimplementedCrawlerServiceServer() {}
+func (UnimplementedCrawlerServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCrawlerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CrawlerServiceServer will
+// result in compilation errors.
+type UnsafeCrawlerServiceServer interface {
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+func RegisterCrawlerServiceServer(s grpc.ServiceRegistrar, srv CrawlerServiceServer) {
+	// If the following call panics, it indicates UnimplementedCrawlerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CrawlerService_ServiceDesc, srv)
+}
+
+func _CrawlerService_Crawl_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CrawlRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CrawlerServiceServer).Crawl(m, &grpc.GenericServerStream[CrawlRequest, CrawlResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_CrawlServer = grpc.ServerStreamingServer[CrawlResponse]
+
+func _CrawlerService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CrawlerServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+// CrawlerService_ServiceDesc is the grpc.ServiceDesc for CrawlerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CrawlerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dnscrawler.v1.CrawlerService",
+	HandlerType: (*CrawlerServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Crawl",
+			Handler:       _CrawlerService_Crawl_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _CrawlerService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dnscrawler/v1/dnscrawler.proto",
+}
@@ -0,0 +1,196 @@
+// Package rpc serves the crawler over gRPC. The service is defined in
+// proto/dnscrawler/v1/dnscrawler.proto; run `buf generate` after changing it.
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/auth"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+	"github.com/auduny/dnscrawler/pkg/rpc/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultMinInterval is the shortest Watch interval accepted unless
+// Server.MinInterval says otherwise
+const DefaultMinInterval = time.Minute
+
+// Server implements the CrawlerService
+type Server struct {
+	pb.UnimplementedCrawlerServiceServer
+
+	// Crawler is copied for every request and given the request's options
+	Crawler *crawler.Crawler
+	// MinInterval is the shortest Watch interval accepted; zero means DefaultMinInterval
+	MinInterval time.Duration
+	// MaxDomains limits the domains of a single request; zero means no limit
+	MaxDomains int
+	// Limiter rate limits crawls per API key or address, one token per domain;
+	// nil disables it. Watch is charged once, when it starts.
+	Limiter *ratelimit.Limiter
+}
+
+// Register adds the service to a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterCrawlerServiceServer(g, s)
+}
+
+func (s *Server) Crawl(req *pb.CrawlRequest, stream grpc.ServerStreamingServer[pb.CrawlResponse]) error {
+	domains, err := s.domains(req.GetDomains())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	if err := s.allow(ctx, len(domains)); err != nil {
+		return err
+	}
+	c := s.crawler(req.GetOptions())
+
+	for _, name := range domains {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		result, err := ToProto(c.CrawlContext(ctx, name))
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(&pb.CrawlResponse{Result: result}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	domains, err := s.domains(req.GetDomains())
+	if err != nil {
+		return err
+	}
+	minInterval := s.MinInterval
+	if minInterval == 0 {
+		minInterval = DefaultMinInterval
+	}
+	interval := time.Duration(req.GetIntervalSeconds()) * time.Second
+	if interval < minInterval {
+		return status.Errorf(codes.InvalidArgument, "interval must be at least %s", minInterval)
+	}
+	ctx := stream.Context()
+	c := s.crawler(req.GetOptions())
+
+	prev := make(map[string]*crawler.Result)
+	for {
+		// Every round crawls each domain again, so it is charged like a
+		// new Crawl
+		if err := s.allow(ctx, len(domains)); err != nil {
+			return err
+		}
+		for _, name := range domains {
+			if err := ctx.Err(); err != nil {
+				return status.FromContextError(err).Err()
+			}
+			cur := c.CrawlContext(ctx, name)
+			var changes []string
+			if old, seen := prev[name]; seen {
+				changes = monitor.Diff(old, cur)
+				if len(changes) == 0 {
+					continue
+				}
+			}
+			prev[name] = cur
+
+			result, err := ToProto(cur)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			resp := &pb.WatchResponse{Result: result, Changes: changes, Time: timestamppb.Now()}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// allow takes n tokens from the rate limit of the calling API key or address
+func (s *Server) allow(ctx context.Context, n int) error {
+	if s.Limiter == nil {
+		return nil
+	}
+	key := ""
+	if name, ok := auth.KeyName(ctx); ok {
+		key = "key:" + name
+	} else if p, ok := peer.FromContext(ctx); ok {
+		key = p.Addr.String()
+		if host, _, err := net.SplitHostPort(key); err == nil {
+			key = host
+		}
+	}
+	ok, wait := s.Limiter.Allow(key, n)
+	if ok {
+		return nil
+	}
+	if wait == 0 {
+		return status.Errorf(codes.InvalidArgument, "%d domains exceed the rate limit burst of %d", n, s.Limiter.Burst())
+	}
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded, retry in %s seconds", ratelimit.RetryAfter(wait))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// domains validates and normalizes the domains of a request
+func (s *Server) domains(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no domains given")
+	}
+	if s.MaxDomains > 0 && len(names) > s.MaxDomains {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d domains per request", s.MaxDomains)
+	}
+	out := make([]string, len(names))
+	for i, name := range names {
+		normalized, err := domain.Normalize(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		out[i] = normalized
+	}
+	return out, nil
+}
+
+// crawler returns a copy of the server's crawler with the requested options
+func (s *Server) crawler(opts *pb.Options) *crawler.Crawler {
+	return s.Crawler.WithOptions(crawler.Options{
+		NoWhois:     opts.GetNoWhois(),
+		NoTrace:     !opts.GetTrace(),
+		TLS:         opts.GetTls(),
+		Deps:        opts.GetDeps(),
+		SOA:         opts.GetSoa(),
+		Recursion:   opts.GetRecursion(),
+		Consistency: opts.GetConsistency(),
+		NXDomain:    opts.GetNxdomain(),
+		Reputation:  opts.GetReputation(),
+		Blocklists:  opts.GetBlocklists(),
+		DNSSEC:      opts.GetDnssec(),
+		CAA:         opts.GetCaa(),
+
+		NewDomainDays: int(opts.GetNewDomainDays()),
+	})
+}
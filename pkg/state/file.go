@@ -0,0 +1,40 @@
+package state
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps every key as a JSON file below Dir
+type FileStore struct {
+	Dir string
+}
+
+func (s *FileStore) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (s *FileStore) Put(key string, value []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash never leaves a truncated value
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, value, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *FileStore) Close() error { return nil }
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(key)+".json")
+}
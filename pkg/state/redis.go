@@ -0,0 +1,56 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds every Redis command
+const redisTimeout = 10 * time.Second
+
+// redisStore keeps every key as a Redis string below a key prefix
+type redisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func openRedis(cfg config.State) (*redisStore, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("state: redis backend needs a url")
+	}
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("state: %v", err)
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = "dnscrawler:"
+	}
+	return &redisStore{client: redis.NewClient(opts), prefix: prefix}, nil
+}
+
+func (s *redisStore) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (s *redisStore) Put(key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return s.client.Set(ctx, s.prefix+key, value, 0).Err()
+}
+
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
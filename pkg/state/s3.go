@@ -0,0 +1,90 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Timeout bounds every S3 request
+const s3Timeout = 30 * time.Second
+
+// s3Store keeps every key as a JSON object in a bucket, below a prefix
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func openS3(cfg config.State) (*s3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("state: s3 backend needs a bucket")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "s3.amazonaws.com"
+	}
+
+	// Without static keys, fall back to the usual AWS environment variables,
+	// shared credentials file and instance/pod roles
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{},
+	})
+	if cfg.AccessKey != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("state: %v", err)
+	}
+	return &s3Store{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *s3Store) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	obj, err := s.client.GetObject(ctx, s.bucket, s.object(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *s3Store) Put(key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, s.bucket, s.object(key), bytes.NewReader(value), int64(len(value)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
+
+func (s *s3Store) Close() error { return nil }
+
+func (s *s3Store) object(key string) string {
+	return s.prefix + key + ".json"
+}
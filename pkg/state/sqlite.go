@@ -0,0 +1,57 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteStore keeps all keys in a single table of a SQLite database file
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func openSQLite(cfg config.State) (*sqliteStore, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("state: sqlite backend needs a path")
+	}
+	db, err := sql.Open("sqlite", cfg.Path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS state (
+		key     TEXT PRIMARY KEY,
+		value   BLOB NOT NULL,
+		updated INTEGER NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("state: %v", err)
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRow(`SELECT value FROM state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (s *sqliteStore) Put(key string, value []byte) error {
+	_, err := s.db.Exec(`INSERT INTO state (key, value, updated) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated = excluded.updated`,
+		key, value, time.Now().Unix())
+	return err
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
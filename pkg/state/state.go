@@ -0,0 +1,68 @@
+// Package state stores monitoring snapshots and notifier queues in a
+// pluggable key-value backend: local files, SQLite, Redis or S3.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+)
+
+// Store is a key-value store. Keys are slash-separated paths built with Key.
+type Store interface {
+	// Get returns the value of key, or nil without error if it doesn't exist
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	Close() error
+}
+
+// Open creates the store selected by the config
+func Open(cfg config.State) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", "file":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("state: file backend needs a path")
+		}
+		return &FileStore{Dir: cfg.Path}, nil
+	case "sqlite":
+		return openSQLite(cfg)
+	case "redis":
+		return openRedis(cfg)
+	case "s3":
+		return openS3(cfg)
+	}
+	return nil, fmt.Errorf("state: unknown backend %q", cfg.Backend)
+}
+
+// Key joins parts into a store key, escaping separators inside the parts
+func Key(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, p := range parts {
+		p = strings.NewReplacer("/", "_", `\`, "_").Replace(p)
+		if p == "." || p == ".." {
+			p = "_"
+		}
+		escaped[i] = p
+	}
+	return strings.Join(escaped, "/")
+}
+
+// GetJSON decodes the value of key into v, reporting whether it existed
+func GetJSON(s Store, key string, v any) (bool, error) {
+	data, err := s.Get(key)
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// PutJSON stores v encoded as JSON
+func PutJSON(s Store, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Put(key, data)
+}
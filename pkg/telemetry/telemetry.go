@@ -0,0 +1,272 @@
+// Package telemetry exports crawl traces and metrics over OTLP so long-running
+// modes (serve, daemon) can be observed in an APM or OpenTelemetry collector.
+//
+// The crawler records its spans through the global tracer provider; Setup
+// installs providers backed by OTLP exporters, and Hooks records per-lookup
+// metrics.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// DefaultServiceName identifies dnscrawler when neither the config nor
+// OTEL_SERVICE_NAME sets a service name
+const DefaultServiceName = "dnscrawler"
+
+// Telemetry owns the installed tracer and meter providers
+type Telemetry struct {
+	traces  *sdktrace.TracerProvider
+	metrics *sdkmetric.MeterProvider
+}
+
+// Enabled reports whether export is configured, either in the config file or
+// through the standard OTEL_EXPORTER_OTLP_* environment variables
+func Enabled(cfg config.Telemetry) bool {
+	return cfg.Endpoint != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT") != ""
+}
+
+// Setup installs OTLP-exporting tracer and meter providers as the global
+// providers. It returns nil when export isn't enabled.
+func Setup(ctx context.Context, cfg config.Telemetry) (*Telemetry, error) {
+	if !Enabled(cfg) {
+		return nil, nil
+	}
+
+	name := cfg.ServiceName
+	if name == "" {
+		name = os.Getenv("OTEL_SERVICE_NAME")
+	}
+	if name == "" {
+		name = DefaultServiceName
+	}
+	// resource.Default also picks up OTEL_RESOURCE_ATTRIBUTES
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(name)))
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+
+	spans, err := traceExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+	points, err := metricExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if cfg.Interval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.Interval))
+	}
+	t := &Telemetry{
+		traces: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(spans),
+			sdktrace.WithResource(res),
+		),
+		metrics: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(points, readerOpts...)),
+			sdkmetric.WithResource(res),
+		),
+	}
+	otel.SetTracerProvider(t.traces)
+	otel.SetMeterProvider(t.metrics)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return t, nil
+}
+
+// shutdownTimeout bounds the final flush in Close
+const shutdownTimeout = 5 * time.Second
+
+// Close flushes pending spans and metrics and stops the exporters. It is a
+// no-op on a nil Telemetry.
+func (t *Telemetry) Close() error {
+	if t == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return errors.Join(t.traces.Shutdown(ctx), t.metrics.Shutdown(ctx))
+}
+
+// protocol returns the configured OTLP protocol, grpc or http
+func protocol(cfg config.Telemetry) (string, error) {
+	p := cfg.Protocol
+	if p == "" {
+		p = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch strings.ToLower(p) {
+	case "", "grpc":
+		return "grpc", nil
+	case "http", "http/protobuf":
+		return "http", nil
+	}
+	return "", fmt.Errorf("unknown protocol %q (want grpc or http)", p)
+}
+
+// signalURL appends the signal path to a base endpoint URL, as is done for
+// OTEL_EXPORTER_OTLP_ENDPOINT: http://collector:4318 becomes
+// http://collector:4318/v1/traces
+func signalURL(endpoint, signal string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	return u.JoinPath("v1", signal).String(), nil
+}
+
+// traceExporter creates the span exporter. Options not set in the config are
+// left to the exporter, which reads the OTEL_EXPORTER_OTLP_* variables itself.
+func traceExporter(ctx context.Context, cfg config.Telemetry) (sdktrace.SpanExporter, error) {
+	p, err := protocol(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p == "http" {
+		var opts []otlptracehttp.Option
+		if strings.Contains(cfg.Endpoint, "://") {
+			u, err := signalURL(cfg.Endpoint, "traces")
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracehttp.WithEndpointURL(u))
+		} else if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	var opts []otlptracegrpc.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+	} else if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// metricExporter creates the metric exporter, like traceExporter
+func metricExporter(ctx context.Context, cfg config.Telemetry) (sdkmetric.Exporter, error) {
+	p, err := protocol(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p == "http" {
+		var opts []otlpmetrichttp.Option
+		if strings.Contains(cfg.Endpoint, "://") {
+			u, err := signalURL(cfg.Endpoint, "metrics")
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(u))
+		} else if cfg.Endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	var opts []otlpmetricgrpc.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+	} else if cfg.Endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// Hooks returns crawler hooks recording lookup metrics with the global meter
+// provider:
+//
+//	dnscrawler.lookup.duration   histogram of lookup latency in seconds
+//	dnscrawler.lookups           lookups performed, by outcome (ok or error)
+//	dnscrawler.lookup.cache_hits lookups answered from data already collected
+//
+// Every instrument carries the lookup kind (whois, records, asn, ...).
+func Hooks() (crawler.Hooks, error) {
+	meter := otel.Meter("github.com/auduny/dnscrawler/pkg/telemetry")
+
+	duration, err := meter.Float64Histogram("dnscrawler.lookup.duration",
+		metric.WithDescription("Duration of crawl lookups"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return crawler.Hooks{}, err
+	}
+	lookups, err := meter.Int64Counter("dnscrawler.lookups",
+		metric.WithDescription("Crawl lookups performed"),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return crawler.Hooks{}, err
+	}
+	hits, err := meter.Int64Counter("dnscrawler.lookup.cache_hits",
+		metric.WithDescription("Crawl lookups answered from data already collected"),
+		metric.WithUnit("{lookup}"))
+	if err != nil {
+		return crawler.Hooks{}, err
+	}
+
+	record := func(ev crawler.Event, outcome string) {
+		// Hooks don't carry the crawl's context, so there's none to pass on
+		ctx := context.Background()
+		kind := attribute.String("dnscrawler.lookup.kind", ev.Kind)
+		if ev.Cached {
+			hits.Add(ctx, 1, metric.WithAttributes(kind))
+			return
+		}
+		attrs := metric.WithAttributes(kind, attribute.String("dnscrawler.lookup.outcome", outcome))
+		duration.Record(ctx, ev.Duration.Seconds(), attrs)
+		lookups.Add(ctx, 1, attrs)
+	}
+	return crawler.Hooks{
+		OnResult: func(ev crawler.Event) { record(ev, "ok") },
+		OnError:  func(ev crawler.Event) { record(ev, "error") },
+	}, nil
+}
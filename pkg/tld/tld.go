@@ -0,0 +1,251 @@
+// Package tld reports on a top-level domain: its registry operator and
+// endpoints from IANA, its nameservers, its DNSSEC state and which
+// languages its registry accepts internationalized names for.
+package tld
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/dns"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/rdap"
+	"github.com/auduny/dnscrawler/pkg/whois"
+)
+
+// idnTablesURL lists the IDN tables registries have deposited with IANA
+const idnTablesURL = "https://www.iana.org/domains/idn-tables"
+
+// rootZoneURL lists every TLD of the root zone database with its type
+const rootZoneURL = "https://www.iana.org/domains/root/db"
+
+// Report describes a TLD
+type Report struct {
+	TLD string `json:"tld"`
+	// Unicode is the display form of an internationalized TLD
+	Unicode string `json:"unicode,omitempty"`
+	// Type is "country-code", "generic" or "infrastructure" (arpa)
+	Type string `json:"type"`
+
+	Operator        string `json:"operator,omitempty"`
+	Status          string `json:"status,omitempty"`
+	Created         string `json:"created,omitempty"`
+	Changed         string `json:"changed,omitempty"`
+	RegistrationURL string `json:"registration_url,omitempty"`
+
+	WhoisServer string   `json:"whois_server,omitempty"`
+	RDAP        []string `json:"rdap,omitempty"`
+
+	Nameservers []whois.TLDNameserver `json:"nameservers,omitempty"`
+	DNSSEC      *dns.DNSSEC           `json:"dnssec,omitempty"`
+	IDN         *IDN                  `json:"idn,omitempty"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// IDN describes a TLD's support for internationalized names
+type IDN struct {
+	// Tables are the IDN tables the registry has published with IANA; a TLD
+	// without any accepts ASCII names only, as far as IANA knows
+	Tables []IDNTable `json:"tables"`
+}
+
+// IDNTable is a set of characters a registry accepts for one language or script
+type IDNTable struct {
+	Language string `json:"language"` // language or script tag, e.g. "no" or "cyrl"
+	Version  string `json:"version"`
+	URL      string `json:"url"`
+}
+
+// Inspector gathers TLD reports
+type Inspector struct {
+	Resolver *dns.Resolver
+	Whois    *whois.Client
+	RDAP     *rdap.Client
+	HTTP     *http.Client
+}
+
+func NewInspector(resolver *dns.Resolver, whoisClient *whois.Client, rdapClient *rdap.Client, httpClient *http.Client) *Inspector {
+	return &Inspector{
+		Resolver: resolver,
+		Whois:    whoisClient,
+		RDAP:     rdapClient,
+		HTTP:     httpClient,
+	}
+}
+
+// Inspect reports on a TLD given as "no", ".no" or in Unicode. Sources that
+// fail are listed in Errors; the rest of the report is still filled in.
+func (i *Inspector) Inspect(name string) *Report {
+	name = strings.TrimPrefix(name, ".")
+	r := &Report{TLD: name}
+	if typ, err := i.rootZoneType(name); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("root zone database: %v", err))
+		r.Type = tldType(name)
+	} else {
+		r.Type = typ
+	}
+	if display := domain.ToUnicode(name); display != name {
+		r.Unicode = display
+	}
+
+	if rec, err := i.Whois.LookupTLD(name); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("IANA: %v", err))
+	} else {
+		r.Operator = rec.Organisation
+		r.Status = rec.Status
+		r.Created = rec.Created
+		r.Changed = rec.Changed
+		r.RegistrationURL = rec.RegistrationURL
+		r.WhoisServer = rec.WhoisServer
+		r.Nameservers = rec.Nameservers
+	}
+
+	if servers, err := i.RDAP.Servers(name); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("RDAP bootstrap: %v", err))
+	} else {
+		r.RDAP = servers
+	}
+
+	// The root zone database is authoritative for the delegation; DNS is
+	// the fallback when IANA can't be reached
+	if len(r.Nameservers) == 0 {
+		if servers, err := i.Resolver.GetNameservers(name); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("nameservers: %v", err))
+		} else {
+			for _, ns := range servers {
+				r.Nameservers = append(r.Nameservers, whois.TLDNameserver{Name: ns.Name, Addrs: nonEmpty(ns.IP)})
+			}
+		}
+	}
+
+	if sec, err := i.Resolver.LookupDNSSEC(name); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("DNSSEC: %v", err))
+	} else {
+		r.DNSSEC = sec
+	}
+
+	if tables, err := i.idnTables(name); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("IDN tables: %v", err))
+	} else {
+		r.IDN = &IDN{Tables: tables}
+	}
+	return r
+}
+
+// tldType guesses the type from the name when the root zone database can't
+// be read. Internationalized ccTLDs look like any other IDN TLD and are
+// reported as generic.
+func tldType(name string) string {
+	switch {
+	case name == "arpa":
+		return "infrastructure"
+	case len(name) == 2:
+		return "country-code"
+	}
+	return "generic"
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// idnTableLink matches the table links of the IDN tables page, which are
+// named <tld>_<language>_<version>.txt
+var idnTableLink = regexp.MustCompile(`href="(/domains/idn-tables/tables/([a-z0-9-]+)_([a-z0-9-]+)_([0-9.]+)\.(?:txt|html))"`)
+
+// idnTables returns the newest IDN table of each language the TLD's
+// registry has published with IANA
+func (i *Inspector) idnTables(name string) ([]IDNTable, error) {
+	resp, err := i.HTTP.Get(idnTablesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	page, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	newest := make(map[string]IDNTable)
+	for _, m := range idnTableLink.FindAllStringSubmatch(string(page), -1) {
+		if m[2] != name {
+			continue
+		}
+		table := IDNTable{Language: m[3], Version: m[4], URL: "https://www.iana.org" + m[1]}
+		if prev, ok := newest[table.Language]; !ok || compareVersions(prev.Version, table.Version) < 0 {
+			newest[table.Language] = table
+		}
+	}
+	tables := make([]IDNTable, 0, len(newest))
+	for _, table := range newest {
+		tables = append(tables, table)
+	}
+	slices.SortFunc(tables, func(a, b IDNTable) int { return strings.Compare(a.Language, b.Language) })
+	return tables, nil
+}
+
+// compareVersions orders dotted version numbers such as "1.9" and "1.10"
+// by their numeric parts
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for k := 0; k < len(as) || k < len(bs); k++ {
+		var x, y int
+		if k < len(as) {
+			x, _ = strconv.Atoi(as[k])
+		}
+		if k < len(bs) {
+			y, _ = strconv.Atoi(bs[k])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// rootZoneRow matches a TLD's row of the root zone database page: the link
+// to the TLD's page, then its type
+var rootZoneRow = regexp.MustCompile(`href="/domains/root/db/([a-z0-9-]+)\.html">[^<]*</a></span></td>\s*<td>([a-z-]+)</td>`)
+
+// rootZoneType returns the TLD's type from the root zone database, which
+// also knows the internationalized ccTLDs such as xn--p1ai. IANA's
+// sponsored, generic-restricted and test TLDs are reported as generic.
+func (i *Inspector) rootZoneType(name string) (string, error) {
+	resp, err := i.HTTP.Get(rootZoneURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	page, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return "", err
+	}
+
+	for _, m := range rootZoneRow.FindAllStringSubmatch(string(page), -1) {
+		if m[1] != name {
+			continue
+		}
+		switch m[2] {
+		case "country-code", "infrastructure":
+			return m[2], nil
+		}
+		return "generic", nil
+	}
+	return "", fmt.Errorf("%s is not in the root zone", name)
+}
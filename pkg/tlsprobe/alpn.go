@@ -0,0 +1,112 @@
+package tlsprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"slices"
+
+	"github.com/quic-go/quic-go"
+)
+
+// ALPN is what application protocols an HTTPS endpoint speaks
+type ALPN struct {
+	// Protocols are those the endpoint negotiates, of h3, h2 and http/1.1
+	Protocols []string `json:"protocols"`
+	// H3Unchecked is set when QUIC couldn't be tried, as connections go
+	// through a proxy
+	H3Unchecked bool `json:"h3_unchecked,omitempty"`
+}
+
+// Speaks reports whether the endpoint negotiates a protocol
+func (a *ALPN) Speaks(protocol string) bool {
+	return slices.Contains(a.Protocols, protocol)
+}
+
+// ProbeALPN offers host:443 each protocol on its own: h3 in a QUIC
+// handshake, alongside h2 and http/1.1 in TLS ones. It fails only when no
+// handshake completes.
+func (p *Prober) ProbeALPN(host string) (*ALPN, error) {
+	a := &ALPN{H3Unchecked: p.NoQUIC}
+	h3 := make(chan bool, 1)
+	if a.H3Unchecked {
+		h3 <- false
+	} else {
+		go func() { h3 <- p.negotiatesQUIC(host) }()
+	}
+
+	var tcp []string
+	var err error
+	for _, protocol := range []string{"h2", "http/1.1"} {
+		var negotiated bool
+		if negotiated, err = p.negotiatesTLS(host, protocol); negotiated {
+			tcp = append(tcp, protocol)
+		}
+	}
+	if <-h3 {
+		a.Protocols = append(a.Protocols, "h3")
+	}
+	a.Protocols = append(a.Protocols, tcp...)
+	// A server refusing h2 fails that handshake, so only the last one
+	// tells whether TLS works at all
+	if err != nil && len(a.Protocols) == 0 {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (p *Prober) negotiatesTLS(host, protocol string) (bool, error) {
+	state, err := p.handshake(host, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{protocol},
+	})
+	if err != nil {
+		return false, err
+	}
+	// A server without ALPN speaks http/1.1
+	negotiated := state.NegotiatedProtocol
+	return negotiated == protocol || negotiated == "" && protocol == "http/1.1", nil
+}
+
+// negotiatesQUIC reports whether host:443 completes a QUIC handshake for
+// h3, recorded and replayed through the prober's fixtures
+func (p *Prober) negotiatesQUIC(host string) bool {
+	negotiated, _ := p.Fixtures.Do("tls", host+"@"+host+" quic h3", func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+		defer cancel()
+		if p.Wait != nil {
+			if err := p.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		remote, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, "443"))
+		if err != nil {
+			return nil, err
+		}
+		var local *net.UDPAddr
+		if p.Source != nil {
+			addr, err := p.Source("udp", remote.String())
+			if err != nil {
+				return nil, err
+			}
+			local, _ = addr.(*net.UDPAddr)
+		}
+		udp, err := net.ListenUDP("udp", local)
+		if err != nil {
+			return nil, err
+		}
+		defer udp.Close()
+		conn, err := quic.Dial(ctx, udp, remote, &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h3"},
+		}, &quic.Config{HandshakeIdleTimeout: p.Timeout})
+		if err != nil {
+			return nil, err
+		}
+		defer conn.CloseWithError(0, "")
+		return []byte(conn.ConnectionState().TLS.NegotiatedProtocol), nil
+	})
+	return string(negotiated) == "h3"
+}
@@ -0,0 +1,135 @@
+package tlsprobe
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// maxIssuerFetches bounds how many missing intermediates are fetched
+const maxIssuerFetches = 3
+
+// ChainCert is one certificate of the chain, from the leaf towards the root
+type ChainCert struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+	// Source is where the certificate came from: served by the endpoint,
+	// the system's root store, or fetched from the issuer URL of the
+	// certificate below it because the endpoint left it out
+	Source string `json:"source"` // served, root store or fetched
+}
+
+// verifyChain checks the served certificates against the system roots and
+// fills in Valid, VerifyError, Chain and MissingIntermediates, returning the
+// chain. A chain that only verifies with intermediates fetched from the
+// issuer URLs is missing them: browsers may fill the gap, but most other
+// clients fail.
+func (p *Prober) verifyChain(ctx context.Context, info *Info, host string, served []*x509.Certificate) []*x509.Certificate {
+	leaf := served[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range served[1:] {
+		intermediates.AddCert(cert)
+	}
+	opts := x509.VerifyOptions{DNSName: host, Intermediates: intermediates}
+	chains, err := leaf.Verify(opts)
+
+	var unknown x509.UnknownAuthorityError
+	var fetched []*x509.Certificate
+	if errors.As(err, &unknown) {
+		fetched = p.fetchIssuers(ctx, served[len(served)-1])
+		for _, cert := range fetched {
+			opts.Intermediates.AddCert(cert)
+		}
+		if len(fetched) > 0 {
+			if completed, retryErr := leaf.Verify(opts); retryErr == nil {
+				chains = completed
+				info.MissingIntermediates = true
+			}
+		}
+	}
+
+	switch {
+	case info.MissingIntermediates:
+		info.VerifyError = "the server doesn't send its intermediate certificates"
+	case err != nil:
+		info.VerifyError = err.Error()
+	default:
+		info.Valid = true
+	}
+
+	chain := served
+	if len(chains) > 0 {
+		chain = chains[0]
+	}
+	for _, cert := range chain {
+		source := "root store"
+		switch {
+		case slices.ContainsFunc(served, cert.Equal):
+			source = "served"
+		case slices.ContainsFunc(fetched, cert.Equal):
+			source = "fetched"
+		}
+		info.Chain = append(info.Chain, ChainCert{
+			Subject:  subjectName(cert),
+			Issuer:   issuerName(cert),
+			NotAfter: cert.NotAfter,
+			Source:   source,
+		})
+	}
+	return chain
+}
+
+// fetchIssuers follows the issuer URLs (Authority Information Access) up
+// from a certificate, returning the certificates found
+func (p *Prober) fetchIssuers(ctx context.Context, cert *x509.Certificate) []*x509.Certificate {
+	client := &http.Client{Transport: p.Transport}
+	var fetched []*x509.Certificate
+	for range maxIssuerFetches {
+		if len(cert.IssuingCertificateURL) == 0 || cert.CheckSignatureFrom(cert) == nil {
+			break
+		}
+		issuer, err := fetchCertificate(ctx, client, cert.IssuingCertificateURL[0])
+		if err != nil {
+			break
+		}
+		fetched = append(fetched, issuer)
+		cert = issuer
+	}
+	return fetched
+}
+
+func fetchCertificate(ctx context.Context, client *http.Client, url string) (*x509.Certificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	der, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	// Issuer URLs serve DER; a PEM or PKCS#7 answer isn't parsed
+	return x509.ParseCertificate(der)
+}
+
+func subjectName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.Subject.Organization) > 0 {
+		return cert.Subject.Organization[0]
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,105 @@
+package tlsprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/auduny/dnscrawler/pkg/fixture"
+)
+
+// errDial marks the errors of connecting to an endpoint, as opposed to
+// those of the handshake, so they survive a replay
+var errDial = errors.New("dial failed")
+
+func init() {
+	fixture.RegisterErrors(errDial)
+}
+
+// dialError is an error connecting to an endpoint
+type dialError struct{ error }
+
+func (e dialError) Unwrap() []error { return []error{e.error, errDial} }
+
+// handshakeRecord is what a fixture keeps of a completed handshake
+type handshakeRecord struct {
+	Version            uint16   `json:"version"`
+	CipherSuite        uint16   `json:"cipher_suite"`
+	NegotiatedProtocol string   `json:"negotiated_protocol,omitempty"`
+	Certificates       [][]byte `json:"certificates"`
+	OCSPResponse       []byte   `json:"ocsp_response,omitempty"`
+}
+
+// dial connects to addr:443 within ctx
+func (p *Prober) dial(ctx context.Context, addr string) (net.Conn, error) {
+	dial := p.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(addr, "443"))
+	if err != nil {
+		return nil, dialError{err}
+	}
+	return conn, nil
+}
+
+// handshake completes a TLS handshake with config on addr:443, recorded
+// and replayed through the prober's fixtures. The state holds the fields
+// the prober reads: the version, cipher, protocol, certificates and OCSP
+// response.
+func (p *Prober) handshake(addr string, config *tls.Config) (tls.ConnectionState, error) {
+	key := fmt.Sprintf("%s@%s %s-%s %s", config.ServerName, addr,
+		tls.VersionName(config.MinVersion), tls.VersionName(config.MaxVersion), strings.Join(config.NextProtos, ","))
+	raw, err := p.Fixtures.Do("tls", key, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+		defer cancel()
+		conn, err := p.dial(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, config)
+		defer tlsConn.Close()
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, err
+		}
+		state := tlsConn.ConnectionState()
+		record := handshakeRecord{
+			Version:            state.Version,
+			CipherSuite:        state.CipherSuite,
+			NegotiatedProtocol: state.NegotiatedProtocol,
+			OCSPResponse:       state.OCSPResponse,
+		}
+		for _, cert := range state.PeerCertificates {
+			record.Certificates = append(record.Certificates, cert.Raw)
+		}
+		return json.Marshal(record)
+	})
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	var record handshakeRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return tls.ConnectionState{}, err
+	}
+	state := tls.ConnectionState{
+		HandshakeComplete:  true,
+		Version:            record.Version,
+		CipherSuite:        record.CipherSuite,
+		NegotiatedProtocol: record.NegotiatedProtocol,
+		ServerName:         config.ServerName,
+		OCSPResponse:       record.OCSPResponse,
+	}
+	for _, der := range record.Certificates {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return tls.ConnectionState{}, err
+		}
+		state.PeerCertificates = append(state.PeerCertificates, cert)
+	}
+	return state, nil
+}
@@ -0,0 +1,364 @@
+package tlsprobe
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JARM fingerprints how a TLS server answers ten crafted client hellos, as
+// https://github.com/salesforce/jarm does, so servers set up the same way
+// share a fingerprint. An endpoint answering none of them gets 62 zeros.
+func (p *Prober) JARM(host, addr string) (string, error) {
+	var answers []string
+	answered := false
+	for i, probe := range jarmProbes {
+		raw, err := p.Fixtures.Do("tls", fmt.Sprintf("%s@%s jarm %d", host, addr, i+1), func() ([]byte, error) {
+			answer, err := p.jarmProbe(host, addr, probe)
+			return []byte(answer), err
+		})
+		answer := string(raw)
+		if errors.Is(err, errDial) {
+			return "", err
+		}
+		answered = answered || answer != "|||"
+		answers = append(answers, answer)
+	}
+	if !answered {
+		return strings.Repeat("0", 62), nil
+	}
+	return jarmHash(answers), nil
+}
+
+// jarmProbeSpec is one of the client hellos
+type jarmProbeSpec struct {
+	version     uint16 // the record and hello version, TLS 1.3 sent as 1.2
+	tls13       bool
+	noTLS13     bool // offer no TLS 1.3 cipher suites
+	cipherOrder string
+	grease      bool
+	rareALPN    bool
+	// supportedVersions is "1.2", "1.3", or "" to leave the extension out
+	supportedVersions string
+	extensionOrder    string
+}
+
+var jarmProbes = []jarmProbeSpec{
+	{version: tls12, cipherOrder: "forward", supportedVersions: "1.2", extensionOrder: "reverse"},
+	{version: tls12, cipherOrder: "reverse", supportedVersions: "1.2", extensionOrder: "forward"},
+	{version: tls12, cipherOrder: "top half", extensionOrder: "forward"},
+	{version: tls12, cipherOrder: "bottom half", rareALPN: true, extensionOrder: "forward"},
+	{version: tls12, cipherOrder: "middle out", grease: true, rareALPN: true, extensionOrder: "reverse"},
+	{version: tls11, cipherOrder: "forward", extensionOrder: "forward"},
+	{version: tls12, tls13: true, cipherOrder: "forward", supportedVersions: "1.3", extensionOrder: "reverse"},
+	{version: tls12, tls13: true, cipherOrder: "reverse", supportedVersions: "1.3", extensionOrder: "forward"},
+	{version: tls12, tls13: true, noTLS13: true, cipherOrder: "forward", supportedVersions: "1.3", extensionOrder: "forward"},
+	{version: tls12, tls13: true, cipherOrder: "middle out", grease: true, supportedVersions: "1.3", extensionOrder: "reverse"},
+}
+
+const (
+	tls11 = 0x0302
+	tls12 = 0x0303
+)
+
+// jarmCiphers are the suites offered, in order; jarmHashCiphers numbers
+// them in the fingerprint
+var (
+	jarmCiphers = []uint16{
+		0x0016, 0x0033, 0x0067, 0xc09e, 0xc0a2, 0x009e, 0x0039, 0x006b, 0xc09f, 0xc0a3, 0x009f, 0x0045, 0x00be, 0x0088,
+		0x00c4, 0x009a, 0xc008, 0xc009, 0xc023, 0xc0ac, 0xc0ae, 0xc02b, 0xc00a, 0xc024, 0xc0ad, 0xc0af, 0xc02c, 0xc072,
+		0xc073, 0xcca9, 0x1302, 0x1301, 0xcc14, 0xc007, 0xc012, 0xc013, 0xc027, 0xc02f, 0xc014, 0xc028, 0xc030, 0xc060,
+		0xc061, 0xc076, 0xc077, 0xcca8, 0x1305, 0x1304, 0x1303, 0xcc13, 0xc011, 0x000a, 0x002f, 0x003c, 0xc09c, 0xc0a0,
+		0x009c, 0x0035, 0x003d, 0xc09d, 0xc0a1, 0x009d, 0x0041, 0x00ba, 0x0084, 0x00c0, 0x0007, 0x0004, 0x0005,
+	}
+	jarmHashCiphers = []uint16{
+		0x0004, 0x0005, 0x0007, 0x000a, 0x0016, 0x002f, 0x0033, 0x0035, 0x0039, 0x003c, 0x003d, 0x0041, 0x0045, 0x0067,
+		0x006b, 0x0084, 0x0088, 0x009a, 0x009c, 0x009d, 0x009e, 0x009f, 0x00ba, 0x00be, 0x00c0, 0x00c4, 0xc007, 0xc008,
+		0xc009, 0xc00a, 0xc011, 0xc012, 0xc013, 0xc014, 0xc023, 0xc024, 0xc027, 0xc028, 0xc02b, 0xc02c, 0xc02f, 0xc030,
+		0xc060, 0xc061, 0xc072, 0xc073, 0xc076, 0xc077, 0xc09c, 0xc09d, 0xc09e, 0xc09f, 0xc0a0, 0xc0a1, 0xc0a2, 0xc0a3,
+		0xc0ac, 0xc0ad, 0xc0ae, 0xc0af, 0xcc13, 0xcc14, 0xcca8, 0xcca9, 0x1301, 0x1302, 0x1303, 0x1304, 0x1305,
+	}
+	jarmALPN     = []string{"http/0.9", "http/1.0", "http/1.1", "spdy/1", "spdy/2", "spdy/3", "h2", "h2c", "hq"}
+	jarmRareALPN = []string{"http/0.9", "http/1.0", "spdy/1", "spdy/2", "spdy/3", "h2c", "hq"}
+)
+
+// jarmProbe sends one client hello and describes the server hello as
+// "cipher|version|alpn|extensions", "|||" when the server doesn't answer
+// with one
+func (p *Prober) jarmProbe(host, addr string, spec jarmProbeSpec) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	conn, err := p.dial(ctx, addr)
+	if err != nil {
+		return "|||", err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if _, err := conn.Write(jarmHello(host, spec)); err != nil {
+		return "|||", nil
+	}
+
+	// The reference reads a single segment of up to 1484 bytes; reading on
+	// until the first record is complete gives the same answer more surely
+	buf := make([]byte, 0, 1484)
+	for len(buf) < cap(buf) {
+		n, err := conn.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil || len(buf) >= 5 && len(buf) >= 5+int(binary.BigEndian.Uint16(buf[3:5])) {
+			break
+		}
+	}
+	return readServerHello(buf), nil
+}
+
+func jarmHello(host string, spec jarmProbeSpec) []byte {
+	var hello bytes.Buffer
+	hello.Write(binary.BigEndian.AppendUint16(nil, spec.version))
+	hello.Write(random(32))
+	hello.WriteByte(32)
+	hello.Write(random(32)) // session id
+
+	var ciphers []uint16
+	for _, c := range jarmCiphers {
+		if !spec.noTLS13 || c>>8 != 0x13 {
+			ciphers = append(ciphers, c)
+		}
+	}
+	ciphers = jarmOrder(ciphers, spec.cipherOrder)
+	var suites []byte
+	if spec.grease {
+		suites = append(suites, grease()...)
+	}
+	for _, c := range ciphers {
+		suites = binary.BigEndian.AppendUint16(suites, c)
+	}
+	hello.Write(binary.BigEndian.AppendUint16(nil, uint16(len(suites))))
+	hello.Write(suites)
+	hello.Write([]byte{0x01, 0x00}) // compression methods: null
+	extensions := jarmExtensions(host, spec)
+	hello.Write(binary.BigEndian.AppendUint16(nil, uint16(len(extensions))))
+	hello.Write(extensions)
+
+	handshake := []byte{0x01, 0x00}
+	handshake = binary.BigEndian.AppendUint16(handshake, uint16(hello.Len()))
+	handshake = append(handshake, hello.Bytes()...)
+
+	record := []byte{0x16, 0x03, byte(spec.version)}
+	if spec.tls13 {
+		record[2] = 0x01
+	}
+	record = binary.BigEndian.AppendUint16(record, uint16(len(handshake)))
+	return append(record, handshake...)
+}
+
+func jarmExtensions(host string, spec jarmProbeSpec) []byte {
+	var ext []byte
+	if spec.grease {
+		ext = append(ext, grease()...)
+		ext = append(ext, 0x00, 0x00)
+	}
+	// server_name
+	ext = append(ext, 0x00, 0x00)
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(host)+5))
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(host)+3))
+	ext = append(ext, 0x00)
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(host)))
+	ext = append(ext, host...)
+
+	ext = append(ext,
+		0x00, 0x17, 0x00, 0x00, // extended_master_secret
+		0x00, 0x01, 0x00, 0x01, 0x01, // max_fragment_length
+		0xff, 0x01, 0x00, 0x01, 0x00, // renegotiation_info
+		0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18, 0x00, 0x19, // supported_groups
+		0x00, 0x0b, 0x00, 0x02, 0x01, 0x00, // ec_point_formats
+		0x00, 0x23, 0x00, 0x00, // session_ticket
+	)
+
+	alpns := jarmALPN
+	if spec.rareALPN {
+		alpns = jarmRareALPN
+	}
+	var protocols []byte
+	for _, alpn := range jarmOrder(alpns, spec.extensionOrder) {
+		protocols = append(protocols, byte(len(alpn)))
+		protocols = append(protocols, alpn...)
+	}
+	ext = append(ext, 0x00, 0x10)
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(protocols)+2))
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(protocols)))
+	ext = append(ext, protocols...)
+
+	ext = append(ext, 0x00, 0x0d, 0x00, 0x14, 0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01, 0x02, 0x01) // signature_algorithms
+
+	// key_share: an x25519 share of random bytes
+	var share []byte
+	if spec.grease {
+		share = append(grease(), 0x00, 0x01, 0x00)
+	}
+	share = append(share, 0x00, 0x1d, 0x00, 0x20)
+	share = append(share, random(32)...)
+	ext = append(ext, 0x00, 0x33)
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(share)+2))
+	ext = binary.BigEndian.AppendUint16(ext, uint16(len(share)))
+	ext = append(ext, share...)
+
+	ext = append(ext, 0x00, 0x2d, 0x00, 0x02, 0x01, 0x01) // psk_key_exchange_modes
+
+	if spec.supportedVersions != "" {
+		versions := []string{"\x03\x01", "\x03\x02", "\x03\x03"}
+		if spec.supportedVersions == "1.3" {
+			versions = append(versions, "\x03\x04")
+		}
+		var list []byte
+		if spec.grease {
+			list = grease()
+		}
+		for _, v := range jarmOrder(versions, spec.extensionOrder) {
+			list = append(list, v...)
+		}
+		ext = append(ext, 0x00, 0x2b)
+		ext = binary.BigEndian.AppendUint16(ext, uint16(len(list)+1))
+		ext = append(ext, byte(len(list)))
+		ext = append(ext, list...)
+	}
+	return ext
+}
+
+// jarmOrder reorders ciphers, ALPN protocols or versions as a probe asks
+func jarmOrder[T any](items []T, order string) []T {
+	n := len(items)
+	var out []T
+	switch order {
+	case "reverse":
+		for i := n - 1; i >= 0; i-- {
+			out = append(out, items[i])
+		}
+	case "bottom half":
+		out = append(out, items[(n+1)/2:]...)
+	case "top half":
+		// The reversed top half, with the middle item first when there is one
+		if n%2 == 1 {
+			out = append(out, items[n/2])
+		}
+		out = append(out, jarmOrder(jarmOrder(items, "reverse"), "bottom half")...)
+	case "middle out":
+		middle := n / 2
+		if n%2 == 1 {
+			out = append(out, items[middle])
+			for i := 1; i <= middle; i++ {
+				out = append(out, items[middle+i], items[middle-i])
+			}
+		} else {
+			for i := 1; i <= middle; i++ {
+				out = append(out, items[middle-1+i], items[middle-i])
+			}
+		}
+	default:
+		out = items
+	}
+	return out
+}
+
+// readServerHello describes a server hello as "cipher|version|alpn|extensions"
+func readServerHello(data []byte) string {
+	if len(data) < 44 || data[0] != 0x16 || data[5] != 0x02 {
+		return "|||"
+	}
+	helloLength := int(binary.BigEndian.Uint16(data[3:5]))
+	counter := int(data[43]) // session id length
+	if len(data) < counter+46 {
+		return "|||"
+	}
+	cipher := hex.EncodeToString(data[counter+44 : counter+46])
+	version := hex.EncodeToString(data[9:11])
+	return cipher + "|" + version + "|" + readExtensions(data, counter, helloLength)
+}
+
+// readExtensions gives the negotiated protocol and the extension types of a
+// server hello, "|" when they can't be read
+func readExtensions(data []byte, counter, helloLength int) string {
+	at := func(i, j int) []byte {
+		return data[min(i, len(data)):min(j, len(data))]
+	}
+	if len(data) <= counter+48 || data[counter+47] == 11 ||
+		bytes.Equal(at(counter+50, counter+53), []byte{0x0e, 0xac, 0x0b}) || bytes.Equal(at(82, 85), []byte{0x0f, 0xf0, 0x0b}) ||
+		counter+42 >= helloLength {
+		return "|"
+	}
+	count := 49 + counter
+	end := int(binary.BigEndian.Uint16(data[counter+47:counter+49])) + count - 1
+	var types []string
+	alpn := ""
+	for count < end {
+		if count+4 > len(data) {
+			return "|"
+		}
+		typ := data[count : count+2]
+		length := int(binary.BigEndian.Uint16(data[count+2 : count+4]))
+		value := at(count+4, count+4+length)
+		if bytes.Equal(typ, []byte{0x00, 0x10}) && alpn == "" && len(value) > 3 {
+			alpn = string(value[3:])
+		}
+		types = append(types, hex.EncodeToString(typ))
+		count += 4 + length
+	}
+	return alpn + "|" + strings.Join(types, "-")
+}
+
+// jarmHash folds the answers into the fingerprint: the cipher and version
+// of each, then a truncated SHA-256 of the protocols and extensions
+func jarmHash(answers []string) string {
+	var fuzzy strings.Builder
+	var rest strings.Builder
+	for _, answer := range answers {
+		parts := strings.SplitN(answer, "|", 4)
+		for len(parts) < 4 {
+			parts = append(parts, "")
+		}
+		fuzzy.WriteString(jarmCipherByte(parts[0]))
+		fuzzy.WriteString(jarmVersionByte(parts[1]))
+		rest.WriteString(parts[2])
+		rest.WriteString(parts[3])
+	}
+	sum := sha256.Sum256([]byte(rest.String()))
+	return fuzzy.String() + hex.EncodeToString(sum[:])[:32]
+}
+
+func jarmCipherByte(cipher string) string {
+	if cipher == "" {
+		return "00"
+	}
+	i := 0
+	for ; i < len(jarmHashCiphers); i++ {
+		if fmt.Sprintf("%04x", jarmHashCiphers[i]) == cipher {
+			break
+		}
+	}
+	return fmt.Sprintf("%02x", i+1)
+}
+
+func jarmVersionByte(version string) string {
+	if len(version) < 4 || version[3] < '0' || version[3] > '5' {
+		return "0"
+	}
+	return string("abcdef"[version[3]-'0'])
+}
+
+// grease returns one of the reserved GREASE values, which servers must ignore
+func grease() []byte {
+	b := random(1)[0]&0xf0 | 0x0a
+	return []byte{b, b}
+}
+
+func random(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
@@ -0,0 +1,61 @@
+package tlsprobe
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+)
+
+// minRSABits is the smallest RSA key CAs may issue for, and browsers accept
+const minRSABits = 2048
+
+// Key describes the public key of a certificate and how it is signed
+type Key struct {
+	Algorithm string `json:"algorithm"` // RSA, ECDSA or Ed25519
+	Bits      int    `json:"bits"`
+	// Curve is the named curve of ECDSA keys, e.g. P-256
+	Curve     string `json:"curve,omitempty"`
+	Signature string `json:"signature"` // e.g. SHA256-RSA
+}
+
+func keyOf(cert *x509.Certificate) Key {
+	k := Key{Algorithm: cert.PublicKeyAlgorithm.String(), Signature: cert.SignatureAlgorithm.String()}
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		k.Bits = pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		k.Bits = pub.Curve.Params().BitSize
+		k.Curve = pub.Curve.Params().Name
+	case ed25519.PublicKey:
+		k.Bits = 256
+	}
+	return k
+}
+
+// String describes the key, e.g. "ECDSA P-256, signed ECDSA-SHA256"
+func (k Key) String() string {
+	key := fmt.Sprintf("%s %d", k.Algorithm, k.Bits)
+	if k.Curve != "" {
+		key = k.Algorithm + " " + k.Curve
+	}
+	return key + ", signed " + k.Signature
+}
+
+// Weaknesses lists what makes the key or its signature weak: RSA keys
+// shorter than 2048 bits, and signatures over SHA-1 or MD5, which can be
+// forged
+func (k Key) Weaknesses() []string {
+	var weak []string
+	if k.Algorithm == "RSA" && k.Bits < minRSABits {
+		weak = append(weak, fmt.Sprintf("%d-bit RSA key, shorter than %d bits", k.Bits, minRSABits))
+	}
+	switch k.Signature {
+	case x509.SHA1WithRSA.String(), x509.ECDSAWithSHA1.String(), x509.DSAWithSHA1.String():
+		weak = append(weak, "SHA-1 signature")
+	case x509.MD5WithRSA.String():
+		weak = append(weak, "MD5 signature")
+	}
+	return weak
+}
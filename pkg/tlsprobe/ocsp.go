@@ -0,0 +1,165 @@
+package tlsprobe
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// oidMustStaple is the TLS Feature extension, which with status_request
+// asks clients to reject the certificate without a stapled response
+var oidMustStaple = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+
+// stapleSkew is how far a stapled response may seem to come from the future,
+// for clocks running behind the responder's
+const stapleSkew = 5 * time.Minute
+
+// OCSP is the revocation status of the leaf certificate, from the response
+// the server staples in the handshake and, when queried, the CA's responder
+type OCSP struct {
+	// Stapled is set when the server sends an OCSP response
+	Stapled bool `json:"stapled"`
+	// MustStaple is set when the certificate requires a stapled response
+	MustStaple bool `json:"must_staple,omitempty"`
+	// Status is good, revoked or unknown, from the stapled response
+	Status     string     `json:"status,omitempty"`
+	ThisUpdate *time.Time `json:"this_update,omitempty"`
+	NextUpdate *time.Time `json:"next_update,omitempty"`
+	// StapleError is why the stapled response can't be relied on: it doesn't
+	// parse, isn't for the certificate, or is outside its validity window
+	StapleError string `json:"staple_error,omitempty"`
+	// Responder is the answer of the CA's responder, when queried
+	Responder      *Revocation `json:"responder,omitempty"`
+	ResponderError string      `json:"responder_error,omitempty"`
+}
+
+// Revocation is the status a CA's OCSP responder gives a certificate
+type Revocation struct {
+	URL       string     `json:"url"`
+	Status    string     `json:"status"` // good, revoked or unknown
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the stapled response or the responder says the
+// certificate is revoked
+func (o *OCSP) Revoked() bool {
+	return o.Status == "revoked" || o.Responder != nil && o.Responder.Status == "revoked"
+}
+
+// Problems lists what is wrong with the revocation status and its stapling
+func (o *OCSP) Problems() []string {
+	var problems []string
+	if o.Revoked() {
+		revoked := "the certificate is revoked"
+		if r := o.Responder; r != nil && r.RevokedAt != nil {
+			revoked += " since " + r.RevokedAt.Format("2006-01-02")
+		}
+		problems = append(problems, revoked)
+	}
+	if o.StapleError != "" {
+		problems = append(problems, "stapled OCSP response: "+o.StapleError)
+	}
+	if o.MustStaple && !o.Stapled {
+		problems = append(problems, "the certificate requires OCSP stapling, but the server doesn't staple")
+	}
+	return problems
+}
+
+// checkStaple fills in the OCSP status from the response stapled in the
+// handshake, if any. Without the issuer, the response's signature isn't
+// checked.
+func checkStaple(info *Info, staple []byte, leaf, issuer *x509.Certificate, now time.Time) {
+	o := &OCSP{
+		Stapled:    len(staple) > 0,
+		MustStaple: slices.ContainsFunc(leaf.Extensions, func(ext pkix.Extension) bool { return ext.Id.Equal(oidMustStaple) }),
+	}
+	info.OCSP = o
+	if !o.Stapled {
+		return
+	}
+	resp, err := ocsp.ParseResponseForCert(staple, leaf, issuer)
+	if err != nil {
+		o.StapleError = err.Error()
+		return
+	}
+	o.Status = ocspStatus(resp.Status)
+	o.ThisUpdate = &resp.ThisUpdate
+	if !resp.NextUpdate.IsZero() {
+		o.NextUpdate = &resp.NextUpdate
+	}
+	switch {
+	case resp.ThisUpdate.After(now.Add(stapleSkew)):
+		o.StapleError = "not valid before " + resp.ThisUpdate.UTC().Format(time.RFC3339)
+	case !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(now):
+		o.StapleError = "expired " + resp.NextUpdate.UTC().Format(time.RFC3339)
+	}
+}
+
+// QueryOCSP asks the CA's OCSP responder named in the leaf certificate of a
+// probe for its revocation status
+func (p *Prober) QueryOCSP(info *Info) (*Revocation, error) {
+	leaf, issuer := info.leaf, info.issuer
+	switch {
+	case leaf == nil:
+		return nil, errNoCertificate
+	case len(leaf.OCSPServer) == 0:
+		return nil, errors.New("the certificate names no OCSP responder")
+	case issuer == nil:
+		return nil, errors.New("the issuer certificate is unknown")
+	}
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	url := leaf.OCSPServer[0]
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpResp, err := (&http.Client{Transport: p.Transport}).Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, httpResp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	r := &Revocation{URL: url, Status: ocspStatus(resp.Status)}
+	if resp.Status == ocsp.Revoked {
+		r.RevokedAt = &resp.RevokedAt
+	}
+	return r, nil
+}
+
+func ocspStatus(status int) string {
+	switch status {
+	case ocsp.Good:
+		return "good"
+	case ocsp.Revoked:
+		return "revoked"
+	}
+	return "unknown"
+}
@@ -0,0 +1,126 @@
+// Package tlsprobe connects to HTTPS endpoints and reports on the certificate they serve.
+package tlsprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/fixture"
+)
+
+var errNoCertificate = errors.New("no certificate presented")
+
+// Info describes the leaf certificate served by an endpoint
+type Info struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	DaysToExpiry int       `json:"days_to_expiry"`
+	DNSNames     []string  `json:"dns_names,omitempty"`
+	Version      string    `json:"version"`
+	Valid        bool      `json:"valid"`
+	VerifyError  string    `json:"verify_error,omitempty"`
+	// Wildcard is set when one of the names is a wildcard, e.g. *.example.com
+	Wildcard bool `json:"wildcard,omitempty"`
+	Key      Key  `json:"key"`
+	// Chain is the verified chain from the leaf to a root, or the served
+	// certificates when it doesn't verify
+	Chain []ChainCert `json:"chain"`
+	// MissingIntermediates is set when the chain only verifies with
+	// intermediates the server should have sent
+	MissingIntermediates bool  `json:"missing_intermediates,omitempty"`
+	OCSP                 *OCSP `json:"ocsp,omitempty"`
+
+	leaf, issuer *x509.Certificate
+}
+
+// Prober performs TLS handshakes against endpoints
+type Prober struct {
+	Timeout time.Duration
+	// DialContext connects to endpoints, e.g. through a proxy; nil dials directly
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Source returns the local address of QUIC connections, e.g.
+	// bind.Source.LocalAddr; nil lets the system choose
+	Source func(network, addr string) (net.Addr, error)
+	// NoQUIC skips the QUIC handshake, for connections that must go
+	// through a proxy
+	NoQUIC bool
+	// Wait is called before each QUIC handshake when set, e.g.
+	// ratelimit.Global.Wait; TLS handshakes wait in DialContext
+	Wait func(ctx context.Context) error
+	// Transport fetches intermediates missing from a chain; nil uses
+	// http.DefaultTransport
+	Transport http.RoundTripper
+	// Fixtures records and replays the handshakes; nil performs them live
+	Fixtures *fixture.Store
+}
+
+func NewProber() *Prober {
+	return &Prober{Timeout: 5 * time.Second}
+}
+
+// Probe connects to host:443 with SNI set to host and inspects the served certificate,
+// its chain and the OCSP response stapled to it.
+// Verification failures are reported in the result rather than as an error, so
+// expired or mismatched certificates can still be described.
+func (p *Prober) Probe(host string) (*Info, error) {
+	return p.probe(host, host)
+}
+
+// ProbeAddr is like Probe, connecting to one of the host's addresses, as
+// the endpoints behind a name may serve different certificates
+func (p *Prober) ProbeAddr(host, ip string) (*Info, error) {
+	return p.probe(host, ip)
+}
+
+func (p *Prober) probe(host, addr string) (*Info, error) {
+	state, err := p.handshake(addr, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, errNoCertificate
+	}
+	leaf := state.PeerCertificates[0]
+
+	info := &Info{
+		Subject:      leaf.Subject.CommonName,
+		Issuer:       issuerName(leaf),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		DaysToExpiry: int(time.Until(leaf.NotAfter).Hours() / 24),
+		DNSNames:     leaf.DNSNames,
+		Wildcard:     slices.ContainsFunc(leaf.DNSNames, func(name string) bool { return strings.HasPrefix(name, "*.") }),
+		Version:      tls.VersionName(state.Version),
+		Key:          keyOf(leaf),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	chain := p.verifyChain(ctx, info, host, state.PeerCertificates)
+	info.leaf = leaf
+	if len(chain) > 1 && leaf.CheckSignatureFrom(chain[1]) == nil {
+		info.issuer = chain[1]
+	}
+	checkStaple(info, state.OCSPResponse, leaf, info.issuer, time.Now())
+
+	return info, nil
+}
+
+func issuerName(cert *x509.Certificate) string {
+	if len(cert.Issuer.Organization) > 0 {
+		return cert.Issuer.Organization[0]
+	}
+	return cert.Issuer.CommonName
+}
@@ -0,0 +1,93 @@
+package tlsprobe
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// scanVersions are the versions a scan tries, newest first
+var scanVersions = []uint16{tls.VersionTLS13, tls.VersionTLS12, tls.VersionTLS11, tls.VersionTLS10}
+
+// Scan is which TLS versions an endpoint accepts and the cipher it picks
+// for each, when offered every cipher suite this client knows
+type Scan struct {
+	Versions []VersionSupport `json:"versions"`
+}
+
+// VersionSupport is the outcome of a handshake limited to one version
+type VersionSupport struct {
+	Version  string `json:"version"`
+	Accepted bool   `json:"accepted"`
+	Cipher   string `json:"cipher,omitempty"`
+	// WeakCipher is set for suites without forward secrecy, those with RSA
+	// key exchange, and those with broken ciphers such as RC4 and 3DES
+	WeakCipher bool `json:"weak_cipher,omitempty"`
+}
+
+// Issues lists the outdated versions and weak ciphers the endpoint accepts
+func (s *Scan) Issues() []string {
+	var issues []string
+	for _, v := range s.Versions {
+		if !v.Accepted {
+			continue
+		}
+		if v.Version == "TLS 1.0" || v.Version == "TLS 1.1" {
+			issues = append(issues, v.Version+" is accepted; browsers dropped it in 2020")
+		}
+		if v.WeakCipher {
+			issues = append(issues, fmt.Sprintf("%s negotiates the weak cipher %s", v.Version, v.Cipher))
+		}
+	}
+	if !slices.ContainsFunc(s.Versions, func(v VersionSupport) bool { return v.Accepted && v.Version == "TLS 1.3" }) {
+		issues = append(issues, "TLS 1.3 isn't supported")
+	}
+	return issues
+}
+
+// ScanAddr tries a handshake with host on addr for each TLS version. It
+// fails when the address doesn't accept connections or no version.
+func (p *Prober) ScanAddr(host, addr string) (*Scan, error) {
+	var suites []uint16
+	for _, suite := range slices.Concat(tls.CipherSuites(), tls.InsecureCipherSuites()) {
+		suites = append(suites, suite.ID)
+	}
+	scan := &Scan{}
+	var lastErr error
+	for _, version := range scanVersions {
+		v := VersionSupport{Version: tls.VersionName(version)}
+		state, err := p.handshake(addr, &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			MinVersion:         version,
+			MaxVersion:         version,
+			CipherSuites:       suites,
+		})
+		if errors.Is(err, errDial) {
+			return nil, err
+		}
+		if err == nil {
+			v.Accepted = true
+			v.Cipher = tls.CipherSuiteName(state.CipherSuite)
+			v.WeakCipher = weakCipher(state.CipherSuite)
+		} else {
+			lastErr = err
+		}
+		scan.Versions = append(scan.Versions, v)
+	}
+	if !slices.ContainsFunc(scan.Versions, func(v VersionSupport) bool { return v.Accepted }) {
+		return nil, lastErr
+	}
+	return scan, nil
+}
+
+// weakCipher reports whether a suite is one Go deems insecure or exchanges
+// keys with RSA, which Go still counts as secure but lacks forward secrecy
+func weakCipher(id uint16) bool {
+	if slices.ContainsFunc(tls.InsecureCipherSuites(), func(s *tls.CipherSuite) bool { return s.ID == id }) {
+		return true
+	}
+	return strings.HasPrefix(tls.CipherSuiteName(id), "TLS_RSA_")
+}
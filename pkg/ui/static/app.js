@@ -0,0 +1,189 @@
+"use strict";
+
+// The REST API is served next to the dashboard, one level up
+const apiBase = new URL("..", location.href).pathname;
+
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  Object.assign(e, attrs || {});
+  for (const c of children) {
+    e.append(c ?? "");
+  }
+  return e;
+}
+
+// The API key is kept in local storage and sent with every request
+let apiKey = localStorage.getItem("dnscrawler-key") || "";
+
+async function getJSON(url) {
+  const resp = await fetch(url, { headers: apiKey ? { Authorization: "Bearer " + apiKey } : {} });
+  const body = await resp.json();
+  if (resp.status === 401 || resp.status === 403) {
+    askKey(body.error);
+  }
+  if (!resp.ok) {
+    throw new Error(body.error || resp.statusText);
+  }
+  return body;
+}
+
+function askKey(reason) {
+  if (reason) {
+    document.getElementById("key-reason").textContent = reason + ". The key is kept in this browser only.";
+  }
+  location.hash = "key";
+}
+
+document.getElementById("key-form").addEventListener("submit", (ev) => {
+  ev.preventDefault();
+  apiKey = new FormData(ev.target).get("key").trim();
+  localStorage.setItem("dnscrawler-key", apiKey);
+  ev.target.reset();
+  location.hash = "";
+});
+
+function show(id) {
+  for (const s of document.querySelectorAll("main > section")) {
+    s.hidden = s.id !== id;
+  }
+}
+
+// days colors a remaining number of days by the group's alert thresholds
+function days(n, threshold) {
+  if (n === undefined || n === null) {
+    return el("span", { className: "dim" }, "–");
+  }
+  const cls = n < threshold.critical ? "critical" : n < threshold.warning ? "warning" : "ok";
+  return el("span", { className: cls }, n + " days");
+}
+
+function status(d) {
+  if (d.error) {
+    return el("span", { className: "critical" }, d.error);
+  }
+  if (!d.checked) {
+    return el("span", { className: "dim" }, "not checked yet");
+  }
+  if (!d.problems || d.problems.length === 0) {
+    return el("span", { className: "ok" }, "healthy");
+  }
+  return el("span", { className: d.registered ? "warning" : "critical" }, d.problems.join(", "));
+}
+
+async function loadOverview() {
+  show("overview");
+  const statusLine = document.getElementById("overview-status");
+  const container = document.getElementById("groups");
+  try {
+    const groups = await getJSON("api/groups");
+    container.replaceChildren();
+    statusLine.textContent = groups.length ? "" : "No groups configured. Add groups to the config file to monitor domains.";
+    for (const g of groups) {
+      const rows = g.domains.map((d) => el("tr", {},
+        el("td", {}, el("a", { href: "#domain/" + encodeURIComponent(d.domain) }, d.domain)),
+        el("td", {}, status(d)),
+        el("td", {}, d.registrar || ""),
+        el("td", {}, days(d.days_to_expiry, g.domain_expiry)),
+        el("td", {}, days(d.cert_days, g.cert_expiry)),
+        el("td", {}, d.last_alert ? el("span", { className: d.last_alert.severity }, d.last_alert.title) : ""),
+      ));
+      container.append(
+        el("h2", {}, g.name),
+        el("table", {},
+          el("thead", {}, el("tr", {},
+            el("th", {}, "Domain"), el("th", {}, "Status"), el("th", {}, "Registrar"),
+            el("th", {}, "Domain expiry"), el("th", {}, "Certificate expiry"), el("th", {}, "Last alert"))),
+          el("tbody", {}, ...rows)),
+      );
+    }
+  } catch (err) {
+    statusLine.textContent = "Failed to load groups: " + err.message;
+  }
+}
+
+async function loadDomain(name) {
+  show("detail");
+  document.getElementById("detail-title").textContent = name;
+  const tbody = document.querySelector("#history tbody");
+  const snapshot = document.getElementById("snapshot");
+  tbody.replaceChildren();
+  snapshot.textContent = "Loading…";
+  try {
+    const detail = await getJSON("api/domains/" + encodeURIComponent(name));
+    for (const a of detail.history.slice().reverse()) {
+      tbody.append(el("tr", {},
+        el("td", {}, new Date(a.time).toLocaleString()),
+        el("td", { className: a.severity }, a.severity),
+        el("td", {}, a.kind),
+        el("td", {}, a.title, ...(a.details || []).map((d) => el("div", { className: "dim" }, d))),
+      ));
+    }
+    if (detail.history.length === 0) {
+      tbody.append(el("tr", {}, el("td", { colSpan: 4, className: "dim" }, "No alerts")));
+    }
+    snapshot.textContent = detail.snapshot ? JSON.stringify(detail.snapshot, null, 2) : "No snapshot";
+  } catch (err) {
+    snapshot.textContent = err.message;
+  }
+}
+
+let crawlSource = null;
+
+// The crawl form streams lookups as they finish, then shows the full result
+document.getElementById("crawl-form").addEventListener("submit", (ev) => {
+  ev.preventDefault();
+  const form = new FormData(ev.target);
+  const params = new URLSearchParams();
+  for (const check of form.getAll("check")) {
+    params.append("check", check);
+  }
+  if (apiKey) {
+    // EventSource can't send headers
+    params.append("access_token", apiKey);
+  }
+  const domain = form.get("domain").trim();
+  const statusLine = document.getElementById("crawl-status");
+  const progress = document.getElementById("crawl-progress");
+  const result = document.getElementById("crawl-result");
+  statusLine.textContent = "Crawling " + domain + "…";
+  progress.replaceChildren();
+  result.textContent = "";
+
+  if (crawlSource) {
+    crawlSource.close();
+  }
+  const source = new EventSource(apiBase + "v1/crawl/" + encodeURIComponent(domain) + "/events?" + params);
+  crawlSource = source;
+  source.addEventListener("lookup", (e) => {
+    const l = JSON.parse(e.data);
+    progress.append(el("li", { className: l.error ? "warning" : "" },
+      `${l.kind} ${l.target} (${l.duration_ms} ms)` + (l.error ? ": " + l.error : "")));
+    progress.scrollTop = progress.scrollHeight;
+  });
+  source.addEventListener("result", (e) => {
+    source.close();
+    statusLine.textContent = "";
+    result.textContent = JSON.stringify(JSON.parse(e.data), null, 2);
+  });
+  // EventSource hides the response body, so rejected requests can't be told apart from lost connections
+  source.addEventListener("error", () => {
+    source.close();
+    if (!result.textContent) {
+      statusLine.textContent = "Crawl failed: invalid domain, missing API key or connection lost";
+    }
+  });
+});
+
+function route() {
+  const hash = decodeURIComponent(location.hash.slice(1));
+  if (hash === "crawl" || hash === "key") {
+    show(hash);
+  } else if (hash.startsWith("domain/")) {
+    loadDomain(hash.slice("domain/".length));
+  } else {
+    loadOverview();
+  }
+}
+
+window.addEventListener("hashchange", route);
+route();
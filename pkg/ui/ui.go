@@ -0,0 +1,147 @@
+// Package ui serves a small web dashboard for teams who don't use the CLI. It
+// shows the monitored groups with their latest snapshots and alert history,
+// and runs ad-hoc crawls through the REST API.
+package ui
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+
+	"github.com/auduny/dnscrawler/pkg/config"
+	"github.com/auduny/dnscrawler/pkg/crawler"
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/monitor"
+	"github.com/auduny/dnscrawler/pkg/notify"
+	"github.com/auduny/dnscrawler/pkg/state"
+)
+
+//go:embed static
+var static embed.FS
+
+// Server serves the dashboard and the JSON endpoints behind it
+type Server struct {
+	Groups []config.Group
+	// Alerts are the global alert settings, used to color expiry dates
+	Alerts config.Alerts
+	// Store holds the snapshots and history written by monitor and daemon
+	Store state.Store
+}
+
+// Group is a monitored group as shown in the overview
+type Group struct {
+	Name         string           `json:"name"`
+	DomainExpiry config.Threshold `json:"domain_expiry"`
+	CertExpiry   config.Threshold `json:"cert_expiry"`
+	Domains      []DomainSummary  `json:"domains"`
+}
+
+// DomainSummary condenses a domain's latest snapshot
+type DomainSummary struct {
+	Domain string `json:"domain"`
+	// Checked is false when the domain has no snapshot yet
+	Checked      bool          `json:"checked"`
+	Registered   bool          `json:"registered"`
+	Registrar    string        `json:"registrar,omitempty"`
+	DaysToExpiry *int          `json:"days_to_expiry,omitempty"`
+	CertDays     *int          `json:"cert_days,omitempty"`
+	Problems     []string      `json:"problems,omitempty"`
+	LastAlert    *notify.Alert `json:"last_alert,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// DomainDetail is a domain's latest snapshot with its alert history
+type DomainDetail struct {
+	Snapshot *crawler.Result `json:"snapshot"`
+	History  []notify.Alert  `json:"history"`
+}
+
+// Handler returns the dashboard handler. It expects to be mounted with its
+// prefix stripped, e.g. http.StripPrefix("/ui", h).
+func (s *Server) Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(files))
+	mux.HandleFunc("GET /api/groups", s.groups)
+	mux.HandleFunc("GET /api/domains/{domain}", s.domain)
+	return mux
+}
+
+func (s *Server) groups(w http.ResponseWriter, r *http.Request) {
+	groups := make([]Group, 0, len(s.Groups))
+	for _, g := range s.Groups {
+		settings := monitor.DefaultAlerts.Merge(s.Alerts).Merge(g.Alerts)
+		group := Group{
+			Name:         g.Name,
+			DomainExpiry: settings.DomainExpiry,
+			CertExpiry:   settings.CertExpiry,
+			Domains:      make([]DomainSummary, 0, len(g.Domains)),
+		}
+		for _, name := range g.Domains {
+			group.Domains = append(group.Domains, s.summary(name))
+		}
+		groups = append(groups, group)
+	}
+	writeJSON(w, http.StatusOK, groups)
+}
+
+func (s *Server) summary(name string) DomainSummary {
+	sum := DomainSummary{Domain: name}
+	snap, err := monitor.LoadSnapshot(s.Store, name)
+	if err != nil {
+		sum.Error = err.Error()
+		return sum
+	}
+	if history, err := monitor.LoadHistory(s.Store, name); err == nil && len(history) > 0 {
+		sum.LastAlert = &history[len(history)-1]
+	}
+	if snap == nil {
+		return sum
+	}
+
+	sum.Checked = true
+	sum.Registered = snap.Registered
+	sum.Problems = monitor.Health(snap)
+	if snap.Whois != nil && snap.Whois.Info != nil {
+		sum.Registrar = snap.Whois.Registrar
+		sum.DaysToExpiry = snap.Whois.DaysToExpiry
+	}
+	if snap.TLS != nil && snap.TLS.Info != nil {
+		days := snap.TLS.DaysToExpiry
+		sum.CertDays = &days
+	}
+	return sum
+}
+
+func (s *Server) domain(w http.ResponseWriter, r *http.Request) {
+	name, err := domain.Normalize(r.PathValue("domain"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	snap, err := monitor.LoadSnapshot(s.Store, name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	history, err := monitor.LoadHistory(s.Store, name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if snap == nil && history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "no snapshot for " + name})
+		return
+	}
+	if history == nil {
+		history = []notify.Alert{}
+	}
+	writeJSON(w, http.StatusOK, DomainDetail{Snapshot: snap, History: history})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
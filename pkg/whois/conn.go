@@ -0,0 +1,158 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+)
+
+// DefaultTimeout bounds connecting to a WHOIS server and reading its answer
+const DefaultTimeout = 10 * time.Second
+
+// DefaultMaxConns is how many connections are opened to one WHOIS server at once
+const DefaultMaxConns = 2
+
+// WithTimeout sets the connection and read timeout of every WHOIS query
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithMaxConns caps the concurrent connections per WHOIS server, so parallel
+// crawls queue instead of tripping the registries' abuse protection
+func WithMaxConns(n int) Option {
+	return func(c *Client) {
+		c.maxConns = n
+	}
+}
+
+// Dialer opens the TCP connections to WHOIS servers, e.g. through a proxy
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// WithDialer connects to WHOIS servers with d instead of directly, so
+// queries can go through a SOCKS5 or HTTP proxy
+func WithDialer(d Dialer) Option {
+	return func(c *Client) {
+		c.forward = d
+	}
+}
+
+// WithLimit takes a token of g before every connection to a WHOIS server,
+// referrals included
+func WithLimit(g *ratelimit.Global) Option {
+	return func(c *Client) {
+		c.limit = g
+	}
+}
+
+// WithPace calls wait with the host name of the WHOIS server before every
+// connection to it, IANA's and referred servers included, so the queries of
+// a lookup all take their turn; an error of wait fails the query
+func WithPace(wait func(server string) error) Option {
+	return func(c *Client) {
+		c.pace = wait
+	}
+}
+
+// serverDialer dials WHOIS servers, holding at most max connections to each
+// server until they are closed
+type serverDialer struct {
+	forward Dialer
+	max     int
+	limit   *ratelimit.Global
+	pace    func(server string) error // of WithPace
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newServerDialer(forward Dialer, max int, limit *ratelimit.Global, pace func(string) error) *serverDialer {
+	return &serverDialer{
+		forward: forward,
+		max:     max,
+		limit:   limit,
+		pace:    pace,
+		slots:   make(map[string]chan struct{}),
+	}
+}
+
+// Dial waits for a free slot for addr and connects; closing the connection frees the slot
+func (d *serverDialer) Dial(network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	host = strings.ToLower(host)
+	if d.pace != nil {
+		if err := d.pace(host); err != nil {
+			return nil, err
+		}
+	}
+	d.limit.Wait(context.Background())
+	slot := d.slot(host)
+	slot <- struct{}{}
+
+	conn, err := d.forward.Dial(network, addr)
+	if err != nil {
+		<-slot
+		return nil, err
+	}
+	return &slotConn{Conn: conn, release: func() { <-slot }}, nil
+}
+
+func (d *serverDialer) slot(host string) chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s, ok := d.slots[host]
+	if !ok {
+		s = make(chan struct{}, max(d.max, 1))
+		d.slots[host] = s
+	}
+	return s
+}
+
+// slotConn frees its dialer slot once, on the first Close
+type slotConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *slotConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
+
+// queryServer sends query to server (host or host:port, default port 43) and
+// returns the raw answer, without following referrals
+func (c *Client) queryServer(query, server string) ([]byte, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, "43")
+	}
+	conn, err := c.dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("whois: connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := conn.Write([]byte(query + "\r\n")); err != nil {
+		return nil, fmt.Errorf("whois: send to %s: %w", server, err)
+	}
+	answer, err := io.ReadAll(conn)
+	if err != nil && len(answer) == 0 {
+		return nil, fmt.Errorf("whois: read from %s: %w", server, err)
+	}
+	return answer, nil
+}
@@ -0,0 +1,169 @@
+package whois
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Object is a registry object looked up by its handle: a registrar, a
+// contact or an organisation
+type Object struct {
+	Handle       string   `json:"handle"`
+	Server       string   `json:"server"`
+	Type         string   `json:"type,omitempty"` // e.g. person, role, organization, registrar
+	Name         string   `json:"name,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	Email        string   `json:"email,omitempty"`
+	Phone        string   `json:"phone,omitempty"`
+	Address      []string `json:"address,omitempty"`
+	// Fields holds the first value of every key in the answer, keyed by
+	// lowercase key
+	Fields map[string]string `json:"fields,omitempty"`
+	// Parsed is set when the server's answer format is known; otherwise
+	// only Raw is filled in
+	Parsed bool   `json:"parsed"`
+	Raw    string `json:"raw"`
+}
+
+// ObjectParser extracts an object from a WHOIS server's answer to a handle query
+type ObjectParser interface {
+	ParseObject(raw string) *Object
+}
+
+// ErrNoHandleServer is returned by LookupHandle when no server is given and
+// the handle's suffix doesn't tell which registry issued it
+var ErrNoHandleServer = errors.New("no WHOIS server known for handle")
+
+// ErrHandleNotFound is returned by LookupHandle when a server whose format is
+// known answers without an object
+var ErrHandleNotFound = errors.New("handle not found")
+
+// handleSuffixes maps the suffix of a handle to the server of the registry
+// that issues handles with it
+var handleSuffixes = []struct{ suffix, server string }{
+	{"-NORID", noridServer},
+	{"-RIPE", "whois.ripe.net"},
+	{"-AP", "whois.apnic.net"},
+	{"-AFRINIC", "whois.afrinic.net"},
+	{"-ARIN", "whois.arin.net"},
+	{"-LACNIC", "whois.lacnic.net"},
+	{"-FRNIC", "whois.nic.fr"},
+	{"-DK", "whois.punktum.dk"},
+}
+
+// HandleServer returns the WHOIS server of the registry that issued a
+// handle, going by its suffix, e.g. whois.norid.no for "REG42-NORID"
+func HandleServer(handle string) (string, bool) {
+	handle = strings.ToUpper(strings.TrimSpace(handle))
+	for _, s := range handleSuffixes {
+		if strings.HasSuffix(handle, s.suffix) {
+			return s.server, true
+		}
+	}
+	return "", false
+}
+
+var (
+	objectParsersMu sync.RWMutex
+	objectParsers   = make(map[string]ObjectParser) // server -> parser, see parsers.go
+)
+
+// RegisterObjectParser makes p the parser for a server's answers to handle
+// queries, replacing the built-in one if there is one
+func RegisterObjectParser(server string, p ObjectParser) {
+	objectParsersMu.Lock()
+	defer objectParsersMu.Unlock()
+	objectParsers[strings.ToLower(server)] = p
+}
+
+// ObjectParserFor returns the parser for a server's answers to handle
+// queries, if its format is known
+func ObjectParserFor(server string) (ObjectParser, bool) {
+	objectParsersMu.RLock()
+	defer objectParsersMu.RUnlock()
+	p, ok := objectParsers[strings.ToLower(server)]
+	return p, ok
+}
+
+// LookupHandle queries a WHOIS server for the object with a handle, such as
+// a registrar, contact or nic-handle. With an empty server, the server is
+// picked by the handle's suffix (see HandleServer). Answers of servers
+// without an object parser are returned unparsed, in Raw.
+func (c *Client) LookupHandle(handle, server string) (*Object, error) {
+	handle = strings.TrimSpace(handle)
+	if server == "" {
+		var ok bool
+		if server, ok = HandleServer(handle); !ok {
+			return nil, fmt.Errorf("%w %s", ErrNoHandleServer, handle)
+		}
+	}
+	return lookupObject(c, handle, server)
+}
+
+func lookupObject(q Querier, handle, server string) (*Object, error) {
+	raw, err := q.Query(handle, server)
+	if err != nil {
+		return nil, err
+	}
+	obj := &Object{Handle: handle, Server: server, Raw: raw}
+	p, ok := ObjectParserFor(server)
+	if !ok {
+		return obj, nil
+	}
+	parsed := p.ParseObject(raw)
+	if len(parsed.Fields) == 0 {
+		return nil, fmt.Errorf("%s: %w: %s", server, ErrHandleNotFound, handle)
+	}
+	parsed.Handle, parsed.Server, parsed.Raw, parsed.Parsed = handle, server, raw, true
+	return parsed, nil
+}
+
+// ObjectFields is an ObjectParser for answers made of "key: value" lines.
+// Keys match as in Fields; Address collects the values of all its keys in
+// the order they appear.
+type ObjectFields struct {
+	// Classes are keys that give the object's type by appearing first, as
+	// in RPSL answers ("person: ...", "role: ...")
+	Classes      []string
+	Type         []string
+	Name         []string
+	Organization []string
+	Email        []string
+	Phone        []string
+	Address      []string
+}
+
+// ParseObject implements ObjectParser
+func (f ObjectFields) ParseObject(raw string) *Object {
+	obj := &Object{Fields: make(map[string]string)}
+	for i, e := range parseEntries(raw) {
+		if e.value == "" {
+			continue
+		}
+		if key := strings.ToLower(e.key); obj.Fields[key] == "" {
+			obj.Fields[key] = e.value
+		}
+		if i == 0 && e.matches(f.Classes) {
+			obj.Type = strings.ToLower(e.key)
+		}
+		switch {
+		case obj.Type == "" && e.matches(f.Type):
+			obj.Type = strings.ToLower(e.value)
+		case obj.Name == "" && e.matches(f.Name):
+			obj.Name = e.value
+		case obj.Organization == "" && e.matches(f.Organization):
+			obj.Organization = e.value
+		case obj.Email == "" && e.matches(f.Email):
+			obj.Email = e.value
+		case obj.Phone == "" && e.matches(f.Phone):
+			obj.Phone = e.value
+		case e.matches(f.Address):
+			obj.Address = append(obj.Address, e.value)
+		}
+	}
+	obj.Address = slices.Compact(obj.Address)
+	return obj
+}
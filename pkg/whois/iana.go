@@ -0,0 +1,116 @@
+package whois
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+)
+
+// ianaServer answers for the root zone database, one object per TLD
+const ianaServer = "whois.iana.org"
+
+// TLDRecord is a TLD's entry in IANA's root zone database
+type TLDRecord struct {
+	TLD string `json:"tld"`
+	// Organisation is the registry operator, e.g. "Norid A/S"
+	Organisation string `json:"organisation,omitempty"`
+	// WhoisServer is the registry's WHOIS server, empty for RDAP-only TLDs
+	WhoisServer string `json:"whois_server,omitempty"`
+	// Nameservers are the TLD's servers as delegated from the root
+	Nameservers []TLDNameserver `json:"nameservers,omitempty"`
+	// DS records of the TLD in the root zone
+	DS      []string `json:"ds,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Created string   `json:"created,omitempty"`
+	Changed string   `json:"changed,omitempty"`
+	// RegistrationURL is where the registry documents its registration policy
+	RegistrationURL string `json:"registration_url,omitempty"`
+}
+
+// TLDNameserver is a nameserver of a TLD with its glue addresses
+type TLDNameserver struct {
+	Name  string   `json:"name"`
+	Addrs []string `json:"addrs,omitempty"`
+}
+
+// tldEntry memoizes one root zone lookup, including a failed one, so a crawl
+// of many domains asks IANA once per TLD
+type tldEntry struct {
+	once   sync.Once
+	record *TLDRecord
+	err    error
+}
+
+// LookupTLD returns the root zone database entry of a TLD ("no", "com")
+func (c *Client) LookupTLD(tld string) (*TLDRecord, error) {
+	tld = domain.Canonical(strings.TrimPrefix(tld, "."))
+	v, _ := c.tlds.LoadOrStore(tld, &tldEntry{})
+	e := v.(*tldEntry)
+	e.once.Do(func() {
+		raw, err := c.Query(tld, ianaServer)
+		if err != nil {
+			e.err = err
+			return
+		}
+		e.record, e.err = parseIANA(raw, tld)
+	})
+	return e.record, e.err
+}
+
+// registry returns the operator of a TLD, or "" when IANA can't be asked
+func (c *Client) registry(tld string) string {
+	if rec, err := c.LookupTLD(tld); err == nil {
+		return rec.Organisation
+	}
+	return ""
+}
+
+// parseIANA parses whois.iana.org's "key: value" answer. Contacts follow the
+// TLD's own fields and repeat some keys, so only the first of each is kept.
+func parseIANA(raw, tld string) (*TLDRecord, error) {
+	rec := &TLDRecord{TLD: tld}
+	found := false
+	for _, line := range strings.Split(raw, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(key, "%") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "domain":
+			found = strings.EqualFold(value, tld)
+		case "organisation":
+			if rec.Organisation == "" {
+				rec.Organisation = value
+			}
+		case "nserver":
+			fields := strings.Fields(value)
+			if len(fields) > 0 {
+				rec.Nameservers = append(rec.Nameservers, TLDNameserver{
+					Name:  strings.ToLower(fields[0]),
+					Addrs: fields[1:],
+				})
+			}
+		case "ds-rdata":
+			rec.DS = append(rec.DS, value)
+		case "whois":
+			rec.WhoisServer = value
+		case "status":
+			rec.Status = value
+		case "created":
+			rec.Created = value
+		case "changed":
+			rec.Changed = value
+		case "remarks":
+			if url, ok := strings.CutPrefix(value, "Registration information:"); ok {
+				rec.RegistrationURL = strings.TrimSpace(url)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: no such TLD in the root zone database", tld)
+	}
+	return rec, nil
+}
@@ -0,0 +1,131 @@
+package whois
+
+import (
+	"strings"
+	"time"
+)
+
+// Stage is a step in the lifecycle of a domain's registration
+type Stage string
+
+const (
+	// StageRegistered is a domain within its registration period
+	StageRegistered Stage = "registered"
+	// StageExpired is a domain past its expiry date that the registrar
+	// hasn't renewed or deleted yet (the auto-renew grace period)
+	StageExpired Stage = "expired"
+	// StageRedemption is a deleted domain the registrant can still restore,
+	// for a fee, through its registrar (redemptionPeriod)
+	StageRedemption Stage = "redemption"
+	// StagePendingDelete is a domain past redemption, about to be released
+	// for registration (pendingDelete)
+	StagePendingDelete Stage = "pending-delete"
+)
+
+// Lifecycle is where a domain is in its lifecycle and, once it is past its
+// expiry date, when the registry is expected to release it. The drop dates
+// are estimates from the registry's usual policy (see DropPolicyFor), as
+// the registry doesn't publish them.
+type Lifecycle struct {
+	Stage Stage `json:"stage"`
+	// DropEarliest and DropLatest bound the expected release date
+	// (2006-01-02); empty for registered domains
+	DropEarliest string `json:"drop_earliest,omitempty"`
+	DropLatest   string `json:"drop_latest,omitempty"`
+}
+
+// DropPolicy is how long a registry keeps a domain after it expires, in days
+type DropPolicy struct {
+	// GraceMin and GraceMax bound the time after expiry until the domain is
+	// deleted, which is up to the registrar within the range
+	GraceMin, GraceMax int
+	// Redemption is the time a deleted domain can still be restored
+	Redemption int
+	// PendingDelete is the time from the end of redemption to the release
+	PendingDelete int
+}
+
+// icannDropPolicy is the policy for gTLDs: up to 45 days of auto-renew grace,
+// 30 days of redemption and 5 days pending delete
+var icannDropPolicy = DropPolicy{GraceMin: 0, GraceMax: 45, Redemption: 30, PendingDelete: 5}
+
+// dropPolicies are the registries whose policy differs from ICANN's
+var dropPolicies = map[string]DropPolicy{
+	"uk": {GraceMin: 90, GraceMax: 92}, // suspended at 30 days, cancelled at 90
+	"de": {Redemption: 30},             // DENIC redemption grace period
+	"nl": {Redemption: 40},             // SIDN quarantine
+	"eu": {Redemption: 40},             // EURid quarantine
+	"be": {Redemption: 40},             // DNS Belgium quarantine
+}
+
+// DropPolicyFor returns the drop policy of a TLD's registry, ICANN's for
+// TLDs not known to differ
+func DropPolicyFor(tld string) DropPolicy {
+	if p, ok := dropPolicies[strings.ToLower(strings.TrimPrefix(tld, "."))]; ok {
+		return p
+	}
+	return icannDropPolicy
+}
+
+// lifecycleStatus normalizes a status for matching, as registries write
+// "redemptionPeriod", "REDEMPTION PERIOD" and "redemption-period"
+func lifecycleStatus(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+}
+
+// lifecycle works out a domain's stage from its statuses and expiry date at
+// a time. It returns nil when neither tells anything.
+func (i *Info) lifecycle(tld string, now time.Time) *Lifecycle {
+	var redemption, pendingDelete bool
+	for _, s := range i.Status {
+		switch lifecycleStatus(s) {
+		case "redemptionperiod":
+			redemption = true
+		case "pendingdelete":
+			pendingDelete = true
+		}
+	}
+	expires, hasExpiry := i.ExpiryTime()
+	if !redemption && !pendingDelete && !hasExpiry {
+		return nil
+	}
+
+	p := DropPolicyFor(tld)
+	today := now.UTC().Truncate(24 * time.Hour)
+	days := func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+	var l Lifecycle
+	var earliest, latest time.Time
+	switch {
+	case redemption:
+		// gTLD registries show pendingDelete alongside redemptionPeriod, so
+		// redemption goes first. The deletion usually bumps the updated
+		// date; otherwise redemption may end any day.
+		l.Stage = StageRedemption
+		if updated, ok := parseDate(i.Updated); ok && !(hasExpiry && updated.Before(expires)) {
+			earliest = days(updated, p.Redemption+p.PendingDelete)
+			latest = earliest
+		} else {
+			earliest = days(today, p.PendingDelete)
+			latest = days(today, p.Redemption+p.PendingDelete)
+		}
+	case pendingDelete:
+		l.Stage = StagePendingDelete
+		earliest, latest = today, days(today, p.PendingDelete)
+	case !today.After(expires):
+		l.Stage = StageRegistered
+		return &l
+	default:
+		l.Stage = StageExpired
+		earliest = days(expires, p.GraceMin+p.Redemption+p.PendingDelete)
+		latest = days(expires, p.GraceMax+p.Redemption+p.PendingDelete)
+	}
+	// The registry is late, or the estimate was for a stage that is over
+	if earliest.Before(today) {
+		earliest = today
+	}
+	if latest.Before(earliest) {
+		latest = earliest
+	}
+	l.DropEarliest, l.DropLatest = earliest.Format("2006-01-02"), latest.Format("2006-01-02")
+	return &l
+}
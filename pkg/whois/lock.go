@@ -0,0 +1,67 @@
+package whois
+
+import "strings"
+
+// Locks tells how a domain is protected against hijacking, as read from its
+// EPP status codes (RFC 5731)
+type Locks struct {
+	// TransferLock is set by clientTransferProhibited: the registrar refuses
+	// transfers to another registrar until the owner lifts the lock
+	TransferLock bool `json:"transfer_lock"`
+	// RegistryLock is set by serverTransferProhibited and
+	// serverUpdateProhibited together: the registry itself refuses transfers
+	// and changes, such as of the nameservers, until the registrar unlocks
+	// the domain through an out-of-band procedure. A compromised registrar
+	// account can't lift it.
+	RegistryLock bool `json:"registry_lock"`
+	// RegistryLockPartial is set when only one of the two server statuses is
+	RegistryLockPartial bool `json:"registry_lock_partial,omitempty"`
+}
+
+// eppStatuses are the EPP domain status codes, lowercased
+var eppStatuses = map[string]bool{
+	"ok": true, "inactive": true,
+	"clientdeleteprohibited": true, "clienthold": true, "clientrenewprohibited": true,
+	"clienttransferprohibited": true, "clientupdateprohibited": true,
+	"serverdeleteprohibited": true, "serverhold": true, "serverrenewprohibited": true,
+	"servertransferprohibited": true, "serverupdateprohibited": true,
+	"pendingcreate": true, "pendingdelete": true, "pendingrenew": true,
+	"pendingtransfer": true, "pendingupdate": true, "pendingrestore": true,
+	"addperiod": true, "autorenewperiod": true, "renewperiod": true,
+	"transferperiod": true, "redemptionperiod": true,
+}
+
+// LocksFrom reads the locks from a domain's statuses. It returns nil when
+// none of them is an EPP status code: registries such as Norid and DENIC
+// publish other statuses or none, so the absence of a lock status says
+// nothing there.
+func LocksFrom(statuses []string) *Locks {
+	has := make(map[string]bool, len(statuses))
+	epp := false
+	for _, s := range statuses {
+		code := strings.ToLower(s)
+		has[code] = true
+		epp = epp || eppStatuses[code]
+	}
+	if !epp {
+		return nil
+	}
+	transfer, update := has["servertransferprohibited"], has["serverupdateprohibited"]
+	return &Locks{
+		TransferLock:        has["clienttransferprohibited"],
+		RegistryLock:        transfer && update,
+		RegistryLockPartial: transfer != update,
+	}
+}
+
+// Missing lists the locks a domain lacks, e.g. "no registry lock"
+func (l *Locks) Missing() []string {
+	var missing []string
+	if !l.TransferLock {
+		missing = append(missing, "no transfer lock (clientTransferProhibited)")
+	}
+	if !l.RegistryLock {
+		missing = append(missing, "no registry lock (serverTransferProhibited and serverUpdateProhibited)")
+	}
+	return missing
+}
@@ -1,47 +1,161 @@
 package whois
 
 import (
-	"io"
+	"cmp"
+	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/auduny/dnscrawler/pkg/domain"
+	"github.com/auduny/dnscrawler/pkg/fixture"
+	"github.com/auduny/dnscrawler/pkg/ratelimit"
+
 	"github.com/likexian/whois"
 	whoisparser "github.com/likexian/whois-parser"
 )
 
 type Info struct {
-	Registrar   string
-	Registry    string // TLD operator (separate from registrar)
-	Created     string
-	Updated     string
-	Expires     string
-	Status      []string
-	Registrant  string
-	NameServers []string
+	Registrar   string   `json:"registrar,omitempty"`
+	Registry    string   `json:"registry,omitempty"` // TLD operator (separate from registrar)
+	Created     string   `json:"created,omitempty"`
+	Updated     string   `json:"updated,omitempty"`
+	Expires     string   `json:"expires,omitempty"`
+	Status      []string `json:"status,omitempty"`
+	Registrant  string   `json:"registrant,omitempty"`
+	NameServers []string `json:"name_servers,omitempty"`
+
+	// RegistrantOrgNumber is the registrant's organization number, for
+	// registries that publish it (Norid, Traficom, Punktum dk)
+	RegistrantOrgNumber string `json:"registrant_org_number,omitempty"`
+	// TechContacts are the technical contacts, for registries that publish them
+	TechContacts []Contact `json:"tech_contacts,omitempty"`
+
+	// QueryError is set when the query failed and only the registry is known
+	QueryError string `json:"query_error,omitempty"`
+
+	// free is set by parsers when the registry reports the domain as free
+	free bool
+
+	// DaysToExpiry is derived from Expires; nil when the date is unknown
+	DaysToExpiry *int `json:"days_to_expiry,omitempty"`
+	// AgeDays is derived from Created; nil when the date is unknown
+	AgeDays *int `json:"age_days,omitempty"`
+	// Locks is derived from Status; nil when the registry doesn't publish
+	// EPP status codes
+	Locks *Locks `json:"locks,omitempty"`
+	// Lifecycle is derived from Status and Expires; nil when neither tells
+	// the domain's stage
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// Contact is a contact object of a registry
+type Contact struct {
+	Handle string `json:"handle,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Phone  string `json:"phone,omitempty"`
 }
 
-type Client struct{}
+// ExpiryTime parses Expires, returning false if it is missing or in an unknown format
+func (i *Info) ExpiryTime() (time.Time, bool) {
+	return parseDate(i.Expires)
+}
 
-func NewClient() *Client {
-	return &Client{}
+// CreatedTime parses Created, returning false if it is missing or in an unknown format
+func (i *Info) CreatedTime() (time.Time, bool) {
+	return parseDate(i.Created)
 }
 
-func (c *Client) Lookup(domain string) (*Info, error) {
-	tld := getTLD(domain)
+// setDerived fills in the fields computed from the parsed ones
+func (i *Info) setDerived(tld string) {
+	if t, ok := i.ExpiryTime(); ok {
+		days := int(time.Until(t).Hours() / 24)
+		i.DaysToExpiry = &days
+	}
+	if t, ok := i.CreatedTime(); ok {
+		days := int(time.Since(t).Hours() / 24)
+		i.AgeDays = &days
+	}
+	i.Locks = LocksFrom(i.Status)
+	i.Lifecycle = i.lifecycle(tld, time.Now())
+}
+
+// parseDate understands the formats produced by formatDate
+func parseDate(date string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "02-Jan-2006", "2-Jan-2006"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
 
-	// Get raw WHOIS data
-	rawWhois, err := whois.Whois(domain)
+type Client struct {
+	fixtures *fixture.Store
+	timeout  time.Duration
+	maxConns int
+	forward  Dialer
+	limit    *ratelimit.Global
+	pace     func(server string) error
+	dialer   *serverDialer
+	whois    *whois.Client
+	tlds     sync.Map // TLD -> *tldEntry
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithFixtures routes every WHOIS query through a fixture store for record/replay
+func WithFixtures(s *fixture.Store) Option {
+	return func(c *Client) {
+		c.fixtures = s
+	}
+}
+
+func init() {
+	// Errors of recorded answers match the same errors when replayed
+	fixture.RegisterErrors(ErrNotRegistered, ErrNoAvailabilityService, ErrNoHandleServer, ErrHandleNotFound)
+}
+
+// NewClient creates a client. It is safe for concurrent use, and the
+// per-server connection cap applies across all goroutines sharing it.
+func NewClient(opts ...Option) *Client {
+	c := &Client{timeout: DefaultTimeout, maxConns: DefaultMaxConns}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.forward == nil {
+		c.forward = &net.Dialer{Timeout: c.timeout}
+	}
+	c.dialer = newServerDialer(c.forward, c.maxConns, c.limit, c.pace)
+	c.whois = whois.NewClient().SetDialer(c.dialer).SetTimeout(c.timeout)
+	return c
+}
+
+func (c *Client) Lookup(name string) (*Info, error) {
+	name = domain.Canonical(name)
+	tld := domain.TLD(name)
+
+	// Get raw WHOIS data from the registry's server in the root zone
+	// database; left to itself the whois library asks IANA for every name
+	raw, err := c.fixtures.Do("whois", name, func() ([]byte, error) {
+		rec, err := c.LookupTLD(tld)
+		if err != nil {
+			return nil, err
+		}
+		if rec.WhoisServer == "" {
+			return nil, fmt.Errorf("%w: .%s", whois.ErrWhoisServerNotFound, tld)
+		}
+		text, err := c.whois.Whois(name, rec.WhoisServer)
+		return []byte(text), err
+	})
+	rawWhois := string(raw)
 	if err != nil {
 		// If WHOIS fails, return registry info only
-		info := &Info{}
-		if registry, ok := gTLDRegistries[tld]; ok {
-			info.Registry = registry
-		} else if registry, ok := ccTLDRegistries[tld]; ok {
-			info.Registry = registry
-		}
-		if info.Registry != "" {
-			return info, nil
+		if registry := c.registry(tld); registry != "" {
+			return &Info{Registry: registry, QueryError: err.Error()}, nil
 		}
 		return nil, err
 	}
@@ -50,7 +164,13 @@ func (c *Client) Lookup(domain string) (*Info, error) {
 	parsed, err := whoisparser.Parse(rawWhois)
 	if err != nil {
 		// Return partial info if parsing fails
-		return c.parseRawWhois(rawWhois, domain), nil
+		info := c.parseRawWhois(rawWhois, name)
+		if info.free {
+			return nil, ErrNotRegistered
+		}
+		c.enrich(info, rawWhois, tld)
+		info.setDerived(tld)
+		return info, nil
 	}
 
 	info := &Info{
@@ -74,388 +194,60 @@ func (c *Client) Lookup(domain string) (*Info, error) {
 
 	info.NameServers = parsed.Domain.NameServers
 
-	// Supplement with raw parsing for fields the parser missed
-	if info.Registrar == "" || info.Created == "" {
-		raw := c.parseRawWhois(rawWhois, domain)
-		if info.Registrar == "" {
-			info.Registrar = raw.Registrar
+	// Supplement with the registry's parser for fields the parser missed.
+	// The generic parser doesn't know organization numbers at all.
+	if info.Registrar == "" || info.Created == "" || info.Expires == "" || len(info.NameServers) == 0 || hasParser(tld) {
+		raw := c.parseRawWhois(rawWhois, name)
+		if raw.free {
+			return nil, ErrNotRegistered
 		}
-		if info.Created == "" {
-			info.Created = raw.Created
+		info.Registrar = cmp.Or(info.Registrar, raw.Registrar)
+		info.Registrant = cmp.Or(info.Registrant, raw.Registrant)
+		info.RegistrantOrgNumber = cmp.Or(info.RegistrantOrgNumber, raw.RegistrantOrgNumber)
+		info.Created = cmp.Or(info.Created, raw.Created)
+		info.Updated = cmp.Or(info.Updated, raw.Updated)
+		info.Expires = cmp.Or(info.Expires, raw.Expires)
+		if len(info.Status) == 0 {
+			info.Status = raw.Status
 		}
-		if info.Updated == "" {
-			info.Updated = raw.Updated
+		if len(info.NameServers) == 0 {
+			info.NameServers = raw.NameServers
 		}
 	}
 
-	// Resolve registrar handle to company name if needed
-	info.Registrar = c.resolveRegistrarHandle(info.Registrar, tld)
+	// Let the registry's parser resolve handles and add what it publishes
+	c.enrich(info, rawWhois, tld)
 
 	// Set registry info (TLD operator) - separate from registrar
-	if registry, ok := gTLDRegistries[tld]; ok {
-		info.Registry = registry
-	} else if registry, ok := ccTLDRegistries[tld]; ok {
-		info.Registry = registry
-	}
+	info.Registry = c.registry(tld)
 
+	info.setDerived(tld)
 	return info, nil
 }
 
-// resolveRegistrarHandle looks up registrar handles to get the actual company name
-func (c *Client) resolveRegistrarHandle(handle string, tld string) string {
-	if handle == "" {
-		return ""
-	}
-
-	// Norid (.no) - handles end with -NORID
-	if strings.HasSuffix(handle, "-NORID") && tld == "no" {
-		if name := c.lookupNoridRegistrar(handle); name != "" {
-			return name
-		}
-	}
-
-	// Nominet (.uk) - format is "Company Name [Tag = XXX]"
-	// Extract just the company name
-	if tld == "uk" && strings.Contains(handle, "[Tag = ") {
-		if idx := strings.Index(handle, " [Tag = "); idx > 0 {
-			return strings.TrimSpace(handle[:idx])
-		}
-	}
-
-	return handle
-}
-
-// lookupNoridRegistrar queries Norid's WHOIS for registrar details
-func (c *Client) lookupNoridRegistrar(handle string) string {
-	// Direct TCP connection to Norid's WHOIS server
-	// (the whois library doesn't reliably use custom servers)
-	conn, err := net.DialTimeout("tcp", "whois.norid.no:43", 5*time.Second)
-	if err != nil {
-		return ""
-	}
-	defer conn.Close()
-
-	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
-	_, err = conn.Write([]byte(handle + "\r\n"))
-	if err != nil {
-		return ""
-	}
-
-	response, err := io.ReadAll(conn)
-	if err != nil {
-		return ""
-	}
-
-	// Parse the response to find the registrar name
-	// Norid format: "Registrar Name.............: Company Name"
-	lines := strings.Split(string(response), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Registrar Name") && strings.Contains(line, ":") {
-			if idx := strings.LastIndex(line, ":"); idx > 0 {
-				name := strings.TrimSpace(line[idx+1:])
-				if name != "" {
-					return name
-				}
-			}
+// Query sends an arbitrary query to a WHOIS server and returns the raw response.
+// An empty server lets the whois library pick one (following IANA referrals);
+// otherwise the query goes to that server only, e.g. for registrar handles.
+func (c *Client) Query(query, server string) (string, error) {
+	raw, err := c.fixtures.Do("whois", server+" "+query, func() ([]byte, error) {
+		if server != "" {
+			return c.queryServer(query, server)
 		}
-	}
-
-	return ""
-}
-
-// gTLD registries - new generic TLDs (often RDAP-only, no traditional WHOIS)
-var gTLDRegistries = map[string]string{
-	// Google Registry
-	"dev":     "Google Registry",
-	"app":     "Google Registry",
-	"page":    "Google Registry",
-	"how":     "Google Registry",
-	"soy":     "Google Registry",
-	"chrome":  "Google Registry",
-	"google":  "Google Registry",
-	"gmail":   "Google Registry",
-	"youtube": "Google Registry",
-	"android": "Google Registry",
-	"docs":    "Google Registry",
-	"drive":   "Google Registry",
-	"play":    "Google Registry",
-	"new":     "Google Registry",
-	"day":     "Google Registry",
-	"rsvp":    "Google Registry",
-	"foo":     "Google Registry",
-	"zip":     "Google Registry",
-	"mov":     "Google Registry",
-	"nexus":   "Google Registry",
-	"dad":     "Google Registry",
-	"phd":     "Google Registry",
-	"prof":    "Google Registry",
-	"esq":     "Google Registry",
-	"meme":    "Google Registry",
-	"ing":     "Google Registry",
-
-	// Amazon Registry
-	"aws":     "Amazon Registry",
-	"amazon":  "Amazon Registry",
-	"audible": "Amazon Registry",
-	"book":    "Amazon Registry",
-	"bot":     "Amazon Registry",
-	"buy":     "Amazon Registry",
-	"fire":    "Amazon Registry",
-	"free":    "Amazon Registry",
-	"got":     "Amazon Registry",
-	"imdb":    "Amazon Registry",
-	"joy":     "Amazon Registry",
-	"kindle":  "Amazon Registry",
-	"like":    "Amazon Registry",
-	"mobile":  "Amazon Registry",
-	"now":     "Amazon Registry",
-	"pay":     "Amazon Registry",
-	"pin":     "Amazon Registry",
-	"prime":   "Amazon Registry",
-	"read":    "Amazon Registry",
-	"room":    "Amazon Registry",
-	"safe":    "Amazon Registry",
-	"save":    "Amazon Registry",
-	"silk":    "Amazon Registry",
-	"smile":   "Amazon Registry",
-	"spot":    "Amazon Registry",
-	"talk":    "Amazon Registry",
-	"tunes":   "Amazon Registry",
-	"wow":     "Amazon Registry",
-
-	// Cloudflare
-	"stream": "Cloudflare",
-
-	// Identity Digital (Donuts)
-	"tech":     "Identity Digital",
-	"online":   "Identity Digital",
-	"site":     "Identity Digital",
-	"store":    "Identity Digital",
-	"fun":      "Identity Digital",
-	"space":    "Identity Digital",
-	"live":     "Identity Digital",
-	"news":     "Identity Digital",
-	"world":    "Identity Digital",
-	"email":    "Identity Digital",
-	"cloud":    "Identity Digital",
-	"digital":  "Identity Digital",
-	"network":  "Identity Digital",
-	"software": "Identity Digital",
-	"systems":  "Identity Digital",
-	"agency":   "Identity Digital",
-	"studio":   "Identity Digital",
-	"design":   "Identity Digital",
-	"codes":    "Identity Digital",
-	"tools":    "Identity Digital",
-	"zone":     "Identity Digital",
-	"rocks":    "Identity Digital",
-	"run":      "Identity Digital",
-
-	// Radix
-	"website": "Radix",
-	"host":    "Radix",
-	"press":   "Radix",
-
-	// Other
-	"xyz":  "XYZ.COM",
-	"club": "Registry Services, LLC",
-	"top":  "Jiangsu Bangning",
-	"icu":  "ShortDot",
-	"cyou": "ShortDot",
-	"cfd":  "ShortDot",
-	"sbs":  "ShortDot",
-	"bond": "ShortDot",
-}
-
-// ccTLD registries - shown when WHOIS doesn't expose registrar info
-var ccTLDRegistries = map[string]string{
-	// Europe
-	"no": "Norid",
-	"dk": "Punktum dk",
-	"de": "DENIC",
-	"se": "Internetstiftelsen",
-	"fi": "Traficom",
-	"nl": "SIDN",
-	"be": "DNS Belgium",
-	"at": "nic.at",
-	"ch": "SWITCH",
-	"li": "SWITCH",
-	"pl": "NASK",
-	"cz": "CZ.NIC",
-	"sk": "SK-NIC",
-	"hu": "ISZT",
-	"ro": "ROTLD",
-	"bg": "Register.BG",
-	"hr": "CARNET",
-	"si": "ARNES",
-	"rs": "RNIDS",
-	"ua": "UA-NIC",
-	"ru": "CCTLD.RU",
-	"by": "BY-NIC",
-	"lt": "DOMREG",
-	"lv": "NIC.LV",
-	"ee": "EIS",
-	"is": "ISNIC",
-	"ie": "IEDR",
-	"pt": "DNS.PT",
-	"es": "Red.es",
-	"it": "NIC.IT",
-	"fr": "AFNIC",
-	"gr": "GRNET",
-	"eu": "EURid",
-	"uk": "Nominet",
-
-	// Asia
-	"jp": "JPRS",
-	"cn": "CNNIC",
-	"kr": "KISA",
-	"tw": "TWNIC",
-	"hk": "HKDNR",
-	"sg": "SGNIC",
-	"my": "MYNIC",
-	"th": "THNIC",
-	"vn": "VNNIC",
-	"id": "PANDI",
-	"ph": "dotPH",
-	"in": "NIXI",
-	"pk": "PKNIC",
-	"bd": "BTCL",
-	"np": "Mercantile",
-	"lk": "LK Domain Registry",
-	"kz": "KazNIC",
-	"uz": "UZINFOCOM",
-	"ae": "AEDA",
-	"sa": "SaudiNIC",
-	"il": "ISOC-IL",
-	"tr": "NIC.TR",
-	"ir": "IRNIC",
-
-	// Oceania
-	"au": "auDA",
-	"nz": "InternetNZ",
-	"fj": "DOMAINS.FJ",
-
-	// Americas
-	"ca": "CIRA",
-	"mx": "NIC Mexico",
-	"br": "Registro.br",
-	"ar": "NIC Argentina",
-	"cl": "NIC Chile",
-	"co": "CO Internet",
-	"pe": "NIC.PE",
-	"ve": "NIC.VE",
-	"uy": "NIC.UY",
-	"py": "NIC.PY",
-	"ec": "NIC.EC",
-
-	// Africa
-	"za": "ZADNA",
-	"ng": "NiRA",
-	"ke": "KENIC",
-	"eg": "EGNIC",
-	"ma": "ANRT",
-	"tn": "ATI",
-	"gh": "NIC.GH",
-	"tz": "tzNIC",
-	"ug": "REGISTRY.CO.UG",
-	"et": "ETNIC",
-	"mu": "NIC.MU",
+		text, err := c.whois.Whois(query)
+		return []byte(text), err
+	})
+	return string(raw), err
 }
 
-func (c *Client) parseRawWhois(raw string, domain string) *Info {
-	info := &Info{}
-	lines := strings.Split(raw, "\n")
-	tld := getTLD(domain)
-
-	// Set registry info
-	if registry, ok := gTLDRegistries[tld]; ok {
-		info.Registry = registry
-	} else if registry, ok := ccTLDRegistries[tld]; ok {
-		info.Registry = registry
-	}
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Standard WHOIS format
-		if strings.HasPrefix(line, "Registrar:") && info.Registrar == "" {
-			info.Registrar = strings.TrimSpace(strings.TrimPrefix(line, "Registrar:"))
-		} else if strings.HasPrefix(line, "Sponsoring Registrar:") && info.Registrar == "" {
-			info.Registrar = strings.TrimSpace(strings.TrimPrefix(line, "Sponsoring Registrar:"))
-		} else if strings.HasPrefix(line, "Creation Date:") && info.Created == "" {
-			info.Created = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Creation Date:")))
-		} else if strings.HasPrefix(line, "Registry Expiry Date:") && info.Expires == "" {
-			info.Expires = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Registry Expiry Date:")))
-		} else if strings.HasPrefix(line, "Domain Status:") {
-			status := strings.TrimSpace(strings.TrimPrefix(line, "Domain Status:"))
-			if idx := strings.Index(status, " "); idx > 0 {
-				status = status[:idx]
-			}
-			info.Status = append(info.Status, status)
-		}
-
-		// Norid (.no) format - registrar handle
-		if strings.HasPrefix(line, "Registrar Handle") && info.Registrar == "" {
-			// Format is "Registrar Handle...: REG123-NORID"
-			if idx := strings.LastIndex(line, ":"); idx > 0 {
-				info.Registrar = strings.TrimSpace(line[idx+1:])
-			}
-		}
-
-		// Generic date formats
-		if strings.HasPrefix(line, "Created:") && info.Created == "" {
-			info.Created = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Created:")))
-		} else if strings.HasPrefix(line, "Last updated:") && info.Updated == "" {
-			info.Updated = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Last updated:")))
-		}
-
-		// Punktum dk (.dk) format
-		if strings.HasPrefix(line, "Registered:") && info.Created == "" {
-			info.Created = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Registered:")))
-		} else if strings.HasPrefix(line, "Expires:") && info.Expires == "" {
-			info.Expires = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Expires:")))
-		}
-
-		// DENIC (.de) format
-		if strings.HasPrefix(line, "Changed:") && info.Updated == "" {
-			info.Updated = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "Changed:")))
-		}
-
-		// AFNIC (.fr) format
-		if strings.HasPrefix(line, "registrar:") && info.Registrar == "" {
-			info.Registrar = strings.TrimSpace(strings.TrimPrefix(line, "registrar:"))
-		} else if strings.HasPrefix(line, "created:") && info.Created == "" {
-			info.Created = formatDate(strings.TrimSpace(strings.TrimPrefix(line, "created:")))
-		}
-
-		// UK format
-		if strings.HasPrefix(line, "Registrar:") && info.Registrar == "" {
-			info.Registrar = strings.TrimSpace(strings.TrimPrefix(line, "Registrar:"))
-		} else if strings.HasPrefix(line, "Relevant dates:") {
-			// Skip header
-		} else if strings.Contains(line, "Registered on:") && info.Created == "" {
-			if idx := strings.Index(line, "Registered on:"); idx >= 0 {
-				info.Created = formatDate(strings.TrimSpace(line[idx+14:]))
-			}
-		} else if strings.Contains(line, "Expiry date:") && info.Expires == "" {
-			if idx := strings.Index(line, "Expiry date:"); idx >= 0 {
-				info.Expires = formatDate(strings.TrimSpace(line[idx+12:]))
-			}
-		}
-	}
-
+// parseRawWhois reads an answer with the parser of the domain's registry
+// (see ParserFor)
+func (c *Client) parseRawWhois(raw string, name string) *Info {
+	tld := domain.TLD(name)
+	info := ParserFor(tld).Parse(raw)
+	info.Registry = c.registry(tld)
 	return info
 }
 
-func getTLD(domain string) string {
-	parts := strings.Split(domain, ".")
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
-	}
-	return ""
-}
-
 func formatDate(date string) string {
 	if date == "" {
 		return ""
@@ -475,6 +267,13 @@ func formatDate(date string) string {
 		}
 	}
 
+	// Day.month.year, e.g. Traficom's "1.9.2004 12:00:00"
+	if day, _, _ := strings.Cut(date, " "); strings.Count(day, ".") == 2 {
+		if t, err := time.Parse("2.1.2006", day); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+
 	// ISO format: extract just the date part (YYYY-MM-DD)
 	if len(date) >= 10 && (strings.HasPrefix(date, "20") || strings.HasPrefix(date, "19")) {
 		return date[:10]
@@ -0,0 +1,124 @@
+package whois
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	noridServer = "whois.norid.no"
+	// noridDAS is Norid's domain availability service, a finger service
+	noridDAS = "finger.norid.no:79"
+)
+
+// norid parses answers of Norid, the .no registry. Its domain answer holds
+// handles of contact objects rather than the contacts themselves, so Enrich
+// looks up the registrar, holder and technical contacts by handle. Norid
+// answers whois for registered domains only; CheckAvailability asks its DAS,
+// which also knows domains that are blocked or registered without a
+// delegation.
+type norid struct {
+	Fields
+}
+
+var noridParser = norid{Fields{
+	Registrar: []string{"Registrar Handle"},
+	Created:   []string{"Created"},
+	Updated:   []string{"Last updated"},
+}}
+
+// noridObject reads Norid's registrar, contact and organization objects
+var noridObject = ObjectFields{
+	Type:    []string{"Type"},
+	Name:    []string{"Name", "Registrar Name"},
+	Email:   []string{"Email Address"},
+	Phone:   []string{"Phone Number"},
+	Address: []string{"Postal Address", "Postal Code", "Postal Area", "Country"},
+}
+
+// ParseObject implements ObjectParser. Registrar objects have no Type.
+func (norid) ParseObject(raw string) *Object {
+	obj := noridObject.ParseObject(raw)
+	if obj.Type == "" && obj.Fields["registrar name"] != "" {
+		obj.Type = "registrar"
+	}
+	return obj
+}
+
+// Enrich implements Enricher
+func (n norid) Enrich(info *Info, raw string, q Querier) {
+	for _, e := range parseEntries(raw) {
+		switch {
+		case e.matches([]string{"Registrar Handle"}) && info.Registrar == e.value:
+			if obj := n.lookup(e.value, q); obj != nil && obj.Name != "" {
+				info.Registrar = obj.Name
+			}
+		case e.matches([]string{"Holder Handle"}):
+			obj := n.lookup(e.value, q)
+			if obj == nil {
+				continue
+			}
+			if info.Registrant == "" {
+				info.Registrant = obj.Name
+			}
+			if obj.Fields["id type"] == "organization_number" {
+				info.RegistrantOrgNumber = obj.Fields["id number"]
+			}
+		case e.matches([]string{"Tech-c Handle"}):
+			contact := Contact{Handle: e.value}
+			if obj := n.lookup(e.value, q); obj != nil {
+				contact.Name = obj.Name
+				contact.Email = obj.Email
+				contact.Phone = obj.Phone
+			}
+			info.TechContacts = append(info.TechContacts, contact)
+		}
+	}
+}
+
+// lookup returns the object with a handle, or nil when it can't be looked up
+func (norid) lookup(handle string, q Querier) *Object {
+	if !strings.HasSuffix(handle, "-NORID") {
+		return nil
+	}
+	obj, err := lookupObject(q, handle, noridServer)
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// CheckAvailability implements AvailabilityChecker. DAS answers with a
+// line such as "example.no is delegated" or "example.no is available".
+func (norid) CheckAvailability(name string, q Querier) (*Availability, error) {
+	raw, err := q.Query(name, noridDAS)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(raw, "\n") {
+		_, status, ok := strings.Cut(strings.TrimSpace(line), name+" is ")
+		if !ok {
+			continue
+		}
+		status = strings.TrimSpace(strings.TrimSuffix(status, "."))
+		return &Availability{
+			Available: strings.HasPrefix(status, "available"),
+			Status:    status,
+			Source:    "Norid DAS",
+		}, nil
+	}
+	return nil, fmt.Errorf("Norid DAS: unexpected answer %q", strings.TrimSpace(raw))
+}
+
+// nominet parses answers of Nominet, the .uk registry, whose registrar
+// names end in their tag: "Example Ltd [Tag = EXAMPLE]"
+type nominet struct {
+	Fields
+}
+
+// Enrich implements Enricher, dropping the registrar tag
+func (nominet) Enrich(info *Info, raw string, q Querier) {
+	if name, _, ok := strings.Cut(info.Registrar, " [Tag = "); ok {
+		info.Registrar = strings.TrimSpace(name)
+	}
+}
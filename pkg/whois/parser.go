@@ -0,0 +1,234 @@
+package whois
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/auduny/dnscrawler/pkg/domain"
+)
+
+// Parser extracts registration data from a registry's raw WHOIS answer. The
+// crawler uses it when the generic parser can't read an answer or misses
+// fields, which is common for ccTLD registries with their own formats.
+type Parser interface {
+	Parse(raw string) *Info
+}
+
+// Querier sends a query to a WHOIS server; *Client implements it, routing
+// queries through its fixtures and connection limits
+type Querier interface {
+	Query(query, server string) (string, error)
+}
+
+// Enricher is implemented by parsers that complete parsed data with what a
+// registry publishes elsewhere, typically by looking up the handles in the
+// answer, e.g. a registrar handle to the registrar's name
+type Enricher interface {
+	Enrich(info *Info, raw string, q Querier)
+}
+
+// AvailabilityChecker is implemented by parsers of registries that run a
+// domain availability service (DAS), which answers for names that WHOIS
+// and DNS know nothing about
+type AvailabilityChecker interface {
+	CheckAvailability(name string, q Querier) (*Availability, error)
+}
+
+// Availability is a registry's answer on whether a domain can be registered
+type Availability struct {
+	Available bool `json:"available"`
+	// Status is the registry's wording, e.g. "delegated" or "blocked"
+	Status string `json:"status"`
+	// Source is the service that answered, e.g. "Norid DAS"
+	Source string `json:"source"`
+}
+
+// ErrNotRegistered is returned by Lookup when the registry answers that the
+// domain isn't registered
+var ErrNotRegistered = errors.New("domain not registered")
+
+// ErrNoAvailabilityService is returned by CheckAvailability for TLDs whose
+// registry has no availability service
+var ErrNoAvailabilityService = errors.New("registry has no availability service")
+
+// ParserFunc adapts a function to a Parser
+type ParserFunc func(raw string) *Info
+
+func (f ParserFunc) Parse(raw string) *Info {
+	return f(raw)
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[string]Parser) // TLD -> parser, see parsers.go
+)
+
+// RegisterParser makes p the parser for a TLD's WHOIS answers, replacing the
+// built-in one if there is one
+func RegisterParser(tld string, p Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[strings.ToLower(tld)] = p
+}
+
+// ParserFor returns the parser for a TLD's WHOIS answers: the registry's own
+// when one is registered, otherwise the parser for the ICANN format that
+// gTLD registries use
+func ParserFor(tld string) Parser {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	if p, ok := parsers[strings.ToLower(tld)]; ok {
+		return p
+	}
+	return icannFields
+}
+
+// hasParser reports whether a parser is registered for a TLD
+func hasParser(tld string) bool {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	_, ok := parsers[strings.ToLower(tld)]
+	return ok
+}
+
+// CheckAvailability asks the registry of a domain whether it can be registered
+func (c *Client) CheckAvailability(name string) (*Availability, error) {
+	name = domain.Canonical(name)
+	checker, ok := ParserFor(domain.TLD(name)).(AvailabilityChecker)
+	if !ok {
+		return nil, ErrNoAvailabilityService
+	}
+	return checker.CheckAvailability(name, c)
+}
+
+// enrich lets the registry's parser complete info, see Enricher
+func (c *Client) enrich(info *Info, raw, tld string) {
+	if e, ok := ParserFor(tld).(Enricher); ok {
+		e.Enrich(info, raw, c)
+	}
+}
+
+// Fields is a Parser for answers made of "key: value" lines, which covers
+// most registries. Each field lists the keys it is read from; keys match
+// case-insensitively, the first key found wins, and Status collects every
+// match.
+//
+// Keys may also be written with dot leaders ("Created.......: ...") and
+// values may be on the indented lines below a key that has none:
+//
+//	Registrar:
+//	    Example Registrar Ltd [Tag = EXAMPLE]
+//
+// Indented "key: value" lines belong to the section above them and match
+// both "key" and "section/key", so "registrar/organization" reads
+//
+//	Registrar
+//	  Organization: Example Registrar S.p.A.
+type Fields struct {
+	Registrar   []string
+	Created     []string
+	Updated     []string
+	Expires     []string
+	Status      []string
+	NameServers []string // the first word of the value is the name
+	Registrant  []string
+	// OrgNumber is the registrant's organization number, e.g. the business
+	// ID in Traficom's .fi answers
+	OrgNumber []string
+	// Free lists the Status values with which the registry answers for
+	// domains that aren't registered, e.g. DENIC's "free"
+	Free []string
+}
+
+// Parse implements Parser
+func (f Fields) Parse(raw string) *Info {
+	info := &Info{}
+	for _, e := range parseEntries(raw) {
+		if e.value == "" {
+			continue
+		}
+		switch {
+		case info.Registrar == "" && e.matches(f.Registrar):
+			info.Registrar = e.value
+		case info.Created == "" && e.matches(f.Created):
+			info.Created = formatDate(e.value)
+		case info.Updated == "" && e.matches(f.Updated):
+			info.Updated = formatDate(e.value)
+		case info.Expires == "" && e.matches(f.Expires):
+			info.Expires = formatDate(e.value)
+		case e.matches(f.Status):
+			info.Status = append(info.Status, e.value)
+			info.free = info.free || slices.ContainsFunc(f.Free, func(s string) bool { return strings.EqualFold(s, e.value) })
+		case e.matches(f.NameServers):
+			info.NameServers = append(info.NameServers, strings.ToLower(strings.Fields(e.value)[0]))
+		case info.Registrant == "" && e.matches(f.Registrant):
+			info.Registrant = e.value
+		case info.RegistrantOrgNumber == "" && e.matches(f.OrgNumber):
+			info.RegistrantOrgNumber = e.value
+		}
+	}
+	info.Status = parseStatus(info.Status)
+	return info
+}
+
+// entry is a key and its value from a WHOIS answer
+type entry struct {
+	section string // the unindented key an indented line belongs to
+	key     string
+	value   string
+}
+
+func (e entry) matches(keys []string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, e.key) || e.section != "" && strings.EqualFold(k, e.section+"/"+e.key) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEntries splits an answer into entries. A key without a value on its
+// line starts a section: the more indented lines below it belong to it, and
+// the first of them without a colon is the key's value.
+func parseEntries(raw string) []entry {
+	var entries []entry
+	section, sectionIndent, sectionIdx := "", 0, -1
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r", ""), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if sectionIdx >= 0 && indent <= sectionIndent {
+			section, sectionIdx = "", -1
+		}
+
+		key, value, hasColon := strings.Cut(trimmed, ":")
+		// Times and URLs contain colons too; a key is a short phrase
+		if hasColon && (strings.HasPrefix(value, "//") || len(key) > 40) {
+			hasColon = false
+		}
+		key = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(key), "."))
+		value = strings.TrimSpace(value)
+
+		switch {
+		case sectionIdx >= 0 && hasColon:
+			entries = append(entries, entry{section: section, key: key, value: value})
+		case sectionIdx >= 0:
+			if entries[sectionIdx].value == "" {
+				entries[sectionIdx].value = trimmed
+			}
+		default:
+			if !hasColon {
+				key, value = trimmed, ""
+			}
+			entries = append(entries, entry{key: key, value: value})
+			if value == "" {
+				section, sectionIndent, sectionIdx = key, indent, len(entries)-1
+			}
+		}
+	}
+	return entries
+}
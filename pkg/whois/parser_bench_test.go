@@ -0,0 +1,30 @@
+package whois
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// BenchmarkParsers parses the sample answer of every registry parser in
+// testdata/whois; TestParsers checks what they yield
+func BenchmarkParsers(b *testing.B) {
+	samples, err := filepath.Glob("testdata/whois/*.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, path := range samples {
+		tld := strings.TrimSuffix(filepath.Base(path), ".txt")
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(tld, func(b *testing.B) {
+			p := ParserFor(tld)
+			for b.Loop() {
+				p.Parse(string(raw))
+			}
+		})
+	}
+}
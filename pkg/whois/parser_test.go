@@ -0,0 +1,47 @@
+package whois
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParsers checks that the sample answer of every registry parser in
+// testdata/whois yields the fields its registry publishes
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		tld  string
+		want Info
+	}{
+		{"no", Info{Registrar: "REG42-NORID", Created: "1999-11-15", Updated: "2025-11-16"}},
+		{"dk", Info{Registrar: "Example Registrar ApS", Created: "1998-01-19", Expires: "2027-03-31"}},
+		{"de", Info{Updated: "2025-06-04"}},
+		{"fr", Info{Registrar: "OVH", Created: "2004-01-23", Updated: "2025-12-30", Expires: "2027-01-23"}},
+		{"uk", Info{Registrar: "Markmonitor Inc. t/a MarkMonitor Inc. [Tag = MARKMONITOR]", Created: "14-Feb-1999", Updated: "13-Jan-2026", Expires: "14-Feb-2027"}},
+		{"se", Info{Registrar: "Example Registrar AB", Created: "2000-05-17", Updated: "2025-04-30", Expires: "2027-05-17"}},
+		{"fi", Info{Registrar: "Example Registrar Oy", Created: "2004-09-01", Updated: "2025-08-14", Expires: "2027-08-31"}},
+		{"it", Info{Registrar: "Register.it s.p.a.", Created: "1996-01-29", Updated: "2026-02-14", Expires: "2027-01-29"}},
+		{"nl", Info{Registrar: "Hostnet B.V.", Created: "1999-05-27", Updated: "2025-08-26"}},
+	}
+
+	samples, err := filepath.Glob("testdata/whois/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != len(tests) {
+		t.Errorf("%d samples in testdata/whois, %d expected answers", len(samples), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.tld, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata/whois", tt.tld+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ParserFor(tt.tld).Parse(string(raw))
+			w := tt.want
+			if got.Registrar != w.Registrar || got.Created != w.Created || got.Updated != w.Updated || got.Expires != w.Expires {
+				t.Errorf("parsed %+v, want %+v", *got, w)
+			}
+		})
+	}
+}
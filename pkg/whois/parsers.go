@@ -0,0 +1,135 @@
+package whois
+
+// icannFields reads the format ICANN requires of gTLD registries, which many
+// ccTLDs follow as well
+var icannFields = Fields{
+	Registrar:   []string{"Registrar", "Sponsoring Registrar"},
+	Created:     []string{"Creation Date", "Created On"},
+	Updated:     []string{"Updated Date", "Last Updated On"},
+	Expires:     []string{"Registry Expiry Date", "Registrar Registration Expiration Date", "Expiration Date"},
+	Status:      []string{"Domain Status"},
+	NameServers: []string{"Name Server"},
+}
+
+// Registry parsers. To support another registry, add its TLD here with the
+// keys its answers use, and a sample answer to testdata/whois/<tld>.txt for
+// BenchmarkParsers. A registry whose format doesn't fit Fields can implement
+// Parser, or use ParserFunc; one that publishes more through handle lookups
+// or an availability service implements Enricher or AvailabilityChecker
+// too, like norid.
+func init() {
+	for tld, p := range map[string]Parser{
+		// Norid, see norid.go
+		"no": noridParser,
+		// Punktum dk. The registrant's CVR number is only in the answer
+		// when the registrant has chosen to publish it.
+		"dk": Fields{
+			Registrar:   []string{"Registrar"},
+			Created:     []string{"Registered"},
+			Expires:     []string{"Expires"},
+			Status:      []string{"Status"},
+			NameServers: []string{"Hostname"},
+			OrgNumber:   []string{"CVR", "CVR-nummer"},
+		},
+		// DENIC publishes little more than the status, the nameservers and
+		// the last change. Status is "connect" for delegated domains,
+		// "failed" when the nameservers failed DENIC's checks, and "free".
+		"de": Fields{
+			Updated:     []string{"Changed"},
+			Status:      []string{"Status"},
+			NameServers: []string{"Nserver"},
+			Free:        []string{"free"},
+		},
+		// AFNIC: the domain object comes first, followed by contact objects
+		// with keys of their own
+		"fr": Fields{
+			Registrar:   []string{"registrar"},
+			Created:     []string{"created"},
+			Updated:     []string{"last-update"},
+			Expires:     []string{"Expiry Date"},
+			Status:      []string{"status"},
+			NameServers: []string{"nserver"},
+		},
+		// Nominet: values on the indented lines below their key
+		"uk": nominet{Fields{
+			Registrar: []string{"Registrar"},
+			Created:   []string{"Registered on"},
+			Updated:   []string{"Last updated"},
+			Expires:   []string{"Expiry date"},
+		}},
+		// Internetstiftelsen, for .se and .nu
+		"se": iisFields,
+		"nu": iisFields,
+		// Traficom: dot leaders and day.month.year dates. Holders that are
+		// companies come with their business ID.
+		"fi": Fields{
+			Registrar:   []string{"registrar"},
+			Created:     []string{"created"},
+			Updated:     []string{"modified"},
+			Expires:     []string{"expires"},
+			Status:      []string{"status"},
+			NameServers: []string{"nserver"},
+			Registrant:  []string{"name"},
+			OrgNumber:   []string{"register number"},
+		},
+		// Registro.it: the registrar is a section
+		"it": Fields{
+			Registrar: []string{"Registrar/Organization"},
+			Created:   []string{"Created"},
+			Updated:   []string{"Last Update"},
+			Expires:   []string{"Expire Date"},
+			Status:    []string{"Status"},
+		},
+		// SIDN: the registrar's name and address below "Registrar:"
+		"nl": Fields{
+			Registrar: []string{"Registrar"},
+			Created:   []string{"Creation Date"},
+			Updated:   []string{"Updated Date"},
+			Status:    []string{"Status"},
+		},
+	} {
+		RegisterParser(tld, p)
+	}
+
+	for server, p := range map[string]ObjectParser{
+		noridServer:         noridParser,
+		"whois.ripe.net":    rpslObject,
+		"whois.apnic.net":   rpslObject,
+		"whois.afrinic.net": rpslObject,
+		"whois.nic.fr": ObjectFields{
+			Type:    []string{"type"},
+			Name:    []string{"contact"},
+			Email:   []string{"e-mail"},
+			Phone:   []string{"phone"},
+			Address: []string{"address", "country"},
+		},
+		"whois.punktum.dk": ObjectFields{
+			Name:    []string{"Name"},
+			Email:   []string{"Email"},
+			Phone:   []string{"Phone"},
+			Address: []string{"Address", "Postalcode", "City", "Country"},
+		},
+	} {
+		RegisterObjectParser(server, p)
+	}
+}
+
+// rpslObject reads the person, role and organisation objects of the
+// Regional Internet Registries, which answer in RPSL
+var rpslObject = ObjectFields{
+	Classes:      []string{"person", "role", "organisation", "irt", "mntner"},
+	Name:         []string{"person", "role", "irt", "mntner"},
+	Organization: []string{"org-name"},
+	Email:        []string{"e-mail", "abuse-mailbox"},
+	Phone:        []string{"phone"},
+	Address:      []string{"address"},
+}
+
+var iisFields = Fields{
+	Registrar:   []string{"registrar"},
+	Created:     []string{"created"},
+	Updated:     []string{"modified"},
+	Expires:     []string{"expires"},
+	Status:      []string{"status"},
+	NameServers: []string{"nserver"},
+}